@@ -0,0 +1,219 @@
+// Package audit exports what a cache holds, for data-protection audits.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+// Format selects the export format.
+type Format string
+
+const (
+	// FormatNDJSON writes one JSON object per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes a header row and one row per key.
+	FormatCSV Format = "csv"
+)
+
+// Options holds configuration for Export.
+type Options struct {
+	// Format selects the output format (default: FormatNDJSON)
+	Format Format
+
+	// Pattern selects keys with Redis glob syntax (default: "*")
+	Pattern string
+
+	// KeysPerSecond caps how fast keys are read from the backend
+	// (default: 1000, negative for unlimited)
+	KeysPerSecond int
+
+	// MaxKeys stops the export after this many keys (default: 0, all keys)
+	MaxKeys int
+
+	// IncludeValues reads each value to add a summary (default: false)
+	IncludeValues bool
+
+	// Unredacted summarizes values with their content instead of their
+	// type and a fingerprint (default: false, redacted)
+	Unredacted bool
+
+	// Summarize overrides how values are summarized (optional)
+	Summarize func(key string, value any) string
+}
+
+// Record is one exported key.
+type Record struct {
+	// Key is the cache key.
+	Key string `json:"key"`
+
+	// SizeBytes is the stored value size, or -1 for memory caches keeping
+	// values unserialized.
+	SizeBytes int64 `json:"size_bytes"`
+
+	// TTLSeconds is the remaining time to live (0 for keys without expiry).
+	TTLSeconds float64 `json:"ttl_seconds"`
+
+	// Value summarizes the value (only with IncludeValues).
+	Value string `json:"value,omitempty"`
+}
+
+// errMaxKeys stops the scan once MaxKeys records are written.
+var errMaxKeys = errors.New("max keys exported")
+
+// maxUnredactedSummary caps the length of unredacted value summaries.
+const maxUnredactedSummary = 256
+
+// Export streams a record for each key in c to w and returns the number of
+// records written. Keys are read at most KeysPerSecond at a time so audits
+// don't hurt the backend; reading values (IncludeValues) adds one Get per key.
+// Values are redacted by default: the summary holds the value's type and a
+// fingerprint that matches for equal values, not its content.
+func Export[T any](ctx context.Context, c cache.Cache[T], w io.Writer, opts *Options) (int, error) {
+	var cfg Options
+	if opts != nil {
+		cfg = *opts
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatNDJSON
+	}
+	if cfg.KeysPerSecond == 0 {
+		cfg.KeysPerSecond = 1000
+	}
+	if cfg.Summarize == nil {
+		cfg.Summarize = summarizeRedacted
+		if cfg.Unredacted {
+			cfg.Summarize = summarizeUnredacted
+		}
+	}
+
+	write, flush, err := newWriter(w, cfg.Format, cfg.IncludeValues)
+	if err != nil {
+		return 0, err
+	}
+
+	// Batches of a tenth of the rate keep pacing smooth
+	batchSize := 100
+	if cfg.KeysPerSecond > 0 {
+		batchSize = max(1, cfg.KeysPerSecond/10)
+	}
+
+	start := time.Now()
+	written := 0
+	err = cache.ScanKeys(ctx, c, &cache.ScanConfig{Pattern: cfg.Pattern, BatchSize: batchSize}, func(batch []cache.KeyInfo) error {
+		for _, info := range batch {
+			if cfg.MaxKeys > 0 && written == cfg.MaxKeys {
+				return errMaxKeys
+			}
+
+			record := Record{Key: info.Key, SizeBytes: info.Size, TTLSeconds: info.TTL.Seconds()}
+			if cfg.IncludeValues {
+				value, found, err := cache.GetWithError(ctx, c, info.Key)
+				if err != nil {
+					return err
+				}
+				if !found {
+					// Expired or removed since it was scanned
+					continue
+				}
+				record.Value = cfg.Summarize(info.Key, value)
+			}
+
+			if err := write(record); err != nil {
+				return err
+			}
+			written++
+		}
+		return pace(ctx, start, written, cfg.KeysPerSecond)
+	})
+	if errors.Is(err, errMaxKeys) {
+		err = nil
+	}
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+	return written, err
+}
+
+// pace waits until written keys fit within keysPerSecond since start.
+func pace(ctx context.Context, start time.Time, written, keysPerSecond int) error {
+	if keysPerSecond <= 0 {
+		return nil
+	}
+	due := start.Add(time.Duration(written) * time.Second / time.Duration(keysPerSecond))
+	wait := time.Until(due)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newWriter(w io.Writer, format Format, includeValues bool) (write func(Record) error, flush func() error, err error) {
+	switch format {
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		return func(r Record) error { return encoder.Encode(r) }, func() error { return nil }, nil
+
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		header := []string{"key", "size_bytes", "ttl_seconds"}
+		if includeValues {
+			header = append(header, "value")
+		}
+		if err := cw.Write(header); err != nil {
+			return nil, nil, err
+		}
+		write := func(r Record) error {
+			row := []string{
+				r.Key,
+				strconv.FormatInt(r.SizeBytes, 10),
+				strconv.FormatFloat(r.TTLSeconds, 'f', -1, 64),
+			}
+			if includeValues {
+				row = append(row, r.Value)
+			}
+			return cw.Write(row)
+		}
+		flush := func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		return write, flush, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// summarizeRedacted describes a value by its type and a fingerprint of its content.
+func summarizeRedacted(_ string, value any) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%+v", value))
+	return fmt.Sprintf("%T sha256:%s", value, hex.EncodeToString(sum[:6]))
+}
+
+// summarizeUnredacted prints a value, truncated to maxUnredactedSummary bytes.
+func summarizeUnredacted(_ string, value any) string {
+	summary := fmt.Sprintf("%+v", value)
+	if len(summary) > maxUnredactedSummary {
+		summary = summary[:maxUnredactedSummary] + "..."
+	}
+	return summary
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+type testUser struct {
+	ID    string
+	Email string
+}
+
+func newTestCache(t *testing.T, n int) cache.Cache[testUser] {
+	t.Helper()
+
+	c := cache.NewMemory[testUser](nil)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	for i := range n {
+		id := strconv.Itoa(i)
+		_ = c.Set(context.Background(), "user:"+id, testUser{ID: id, Email: id + "@example.com"}, time.Minute)
+	}
+	return c
+}
+
+func TestExportNDJSON(t *testing.T) {
+	c := newTestCache(t, 3)
+
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), c, &buf, &Options{IncludeValues: true})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 records, got %d (err=%v)", n, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	var record Record
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !strings.HasPrefix(record.Key, "user:") || record.SizeBytes != -1 || record.TTLSeconds <= 0 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+
+	// Values are redacted by default
+	if strings.Contains(buf.String(), "@example.com") {
+		t.Error("Expected values to be redacted")
+	}
+	if !strings.HasPrefix(record.Value, "audit.testUser sha256:") {
+		t.Errorf("Expected type and fingerprint, got %q", record.Value)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	c := newTestCache(t, 2)
+
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), c, &buf, &Options{Format: FormatCSV, IncludeValues: true, Unredacted: true})
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 records, got %d (err=%v)", n, err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != "key,size_bytes,ttl_seconds,value" {
+		t.Fatalf("Unexpected rows: %v", rows)
+	}
+	if !strings.Contains(rows[1][3], "@example.com") {
+		t.Errorf("Expected unredacted value, got %q", rows[1][3])
+	}
+}
+
+func TestExportRateLimit(t *testing.T) {
+	c := newTestCache(t, 20)
+
+	start := time.Now()
+	n, err := Export(context.Background(), c, &bytes.Buffer{}, &Options{KeysPerSecond: 100})
+	if err != nil || n != 20 {
+		t.Fatalf("Expected 20 records, got %d (err=%v)", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected 20 keys at 100/s to take about 200ms, took %v", elapsed)
+	}
+}
+
+func TestExportMaxKeys(t *testing.T) {
+	c := newTestCache(t, 10)
+
+	n, err := Export(context.Background(), c, &bytes.Buffer{}, &Options{MaxKeys: 4, KeysPerSecond: -1})
+	if err != nil || n != 4 {
+		t.Errorf("Expected 4 records, got %d (err=%v)", n, err)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	c := newTestCache(t, 1)
+
+	if _, err := Export(context.Background(), c, &bytes.Buffer{}, &Options{Format: "xml"}); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
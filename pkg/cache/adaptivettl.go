@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"time"
+)
+
+// AdaptiveTTLConfig makes the lifetime of memory cache entries follow how
+// often they are read: entries start shorter than the TTL they were set with
+// and double their lifetime every HitsPerDoubling hits, up to MaxTTL. Hot
+// entries then stay cached while rarely read ones make room, raising the hit
+// ratio a memory budget (MemoryConfig.MaxEntries) achieves.
+type AdaptiveTTLConfig struct {
+	// MinTTL is the lifetime of entries not read since they were set, never
+	// longer than the TTL they were set with (default: half that TTL)
+	MinTTL time.Duration
+
+	// MaxTTL bounds the lifetime hot entries are extended to (default: 4
+	// times the TTL they were set with)
+	MaxTTL time.Duration
+
+	// HitsPerDoubling is the number of hits that doubles the lifetime of an
+	// entry, restarting it from the hit (default: 4)
+	HitsPerDoubling int
+}
+
+// hitsPerDoubling returns HitsPerDoubling with its default applied.
+func (a *AdaptiveTTLConfig) hitsPerDoubling() uint64 {
+	if a.HitsPerDoubling <= 0 {
+		return 4
+	}
+	return uint64(a.HitsPerDoubling)
+}
+
+// lifetime returns the lifetime of an entry set with ttl after hits.
+func (a *AdaptiveTTLConfig) lifetime(ttl time.Duration, hits uint64) time.Duration {
+	lifetime := a.MinTTL
+	if lifetime <= 0 {
+		lifetime = ttl / 2
+	}
+	lifetime = max(min(lifetime, ttl), time.Millisecond)
+
+	maxTTL := a.MaxTTL
+	if maxTTL <= 0 {
+		maxTTL = 4 * ttl
+	}
+
+	for steps := hits / a.hitsPerDoubling(); steps > 0 && lifetime < maxTTL; steps-- {
+		lifetime *= 2
+	}
+	return min(lifetime, maxTTL)
+}
+
+// adaptTTL restarts the lifetime of item, read under key with hits, when the
+// hit doubles it.
+func (c *memoryCache[T]) adaptTTL(key string, item *memoryItem[T], hits uint64) {
+	adaptive := c.config.AdaptiveTTL
+	if item.setTTL <= 0 || hits%adaptive.hitsPerDoubling() != 0 {
+		return
+	}
+
+	// Like Expire, ttlcache can't change the TTL of an entry in place; skip
+	// when a write replaced the item meanwhile
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+	if current, err := c.cache.Get(key); err != nil || current != item {
+		return
+	}
+
+	lifetime := adaptive.lifetime(item.setTTL, hits)
+	renewed := newMemoryItem(item.value, lifetime, time.Now())
+	renewed.setTTL = item.setTTL
+	renewed.hits.Store(item.hits.Load())
+	_ = c.cache.SetWithTTL(key, renewed, lifetime)
+}
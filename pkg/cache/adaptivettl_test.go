@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveTTLLifetime(t *testing.T) {
+	config := &AdaptiveTTLConfig{MaxTTL: 3 * time.Minute, HitsPerDoubling: 2}
+
+	tests := []struct {
+		hits uint64
+		want time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 2 * time.Minute},
+		{6, 3 * time.Minute},
+		{1000, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := config.lifetime(time.Minute, tt.hits); got != tt.want {
+			t.Errorf("lifetime after %d hits: expected %v, got %v", tt.hits, tt.want, got)
+		}
+	}
+
+	// MinTTL never makes an entry outlive the TTL it was set with
+	config = &AdaptiveTTLConfig{MinTTL: time.Hour}
+	if got := config.lifetime(time.Minute, 0); got != time.Minute {
+		t.Errorf("Expected MinTTL to be capped by the TTL, got %v", got)
+	}
+	if got := config.lifetime(time.Minute, 100); got != 4*time.Minute {
+		t.Errorf("Expected the default MaxTTL of 4 times the TTL, got %v", got)
+	}
+}
+
+func TestAdaptiveTTLMemory(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{
+		AdaptiveTTL: &AdaptiveTTLConfig{MaxTTL: 10 * time.Minute, HitsPerDoubling: 2},
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "hot", TestUser{ID: "hot"}, 2*time.Minute)
+	_ = cache.Set(ctx, "cold", TestUser{ID: "cold"}, 2*time.Minute)
+
+	// Entries not read yet expire sooner than they were set to
+	if ttl, _, _ := TTL(ctx, cache, "cold"); ttl > time.Minute || ttl < 59*time.Second {
+		t.Errorf("Expected about a minute left for cold, got %v", ttl)
+	}
+
+	for range 6 {
+		if _, found := cache.Get(ctx, "hot"); !found {
+			t.Fatal("Expected hot to be found")
+		}
+	}
+	if ttl, _, _ := TTL(ctx, cache, "hot"); ttl <= 7*time.Minute || ttl > 8*time.Minute {
+		t.Errorf("Expected hot to be extended to 8 minutes, got %v", ttl)
+	}
+	if got, _ := cache.Get(ctx, "hot"); got.ID != "hot" {
+		t.Errorf("Expected the value to survive extension, got %+v", got)
+	}
+}
+
+func TestAdaptiveTTLExpiresColdEntries(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{
+		AdaptiveTTL: &AdaptiveTTLConfig{MinTTL: 20 * time.Millisecond, HitsPerDoubling: 1},
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "cold", TestUser{ID: "cold"}, time.Minute)
+	waitFor(t, func() bool {
+		_, found, _ := TTL(ctx, cache, "cold")
+		return !found
+	})
+}
+
+func TestAdaptiveTTLKeepsEntriesWithoutExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{AdaptiveTTL: &AdaptiveTTLConfig{}})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "forever", TestUser{ID: "forever"}, 0)
+	for range 10 {
+		cache.Get(ctx, "forever")
+	}
+	if ttl, found, _ := TTL(ctx, cache, "forever"); !found || ttl != 0 {
+		t.Errorf("Expected forever not to expire, got %v, %v", ttl, found)
+	}
+}
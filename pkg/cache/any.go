@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// AnyResolver resolves the message types stored in an AnyCache,
+// e.g. protoregistry.GlobalTypes or a custom *protoregistry.Types.
+type AnyResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// AnyCache stores heterogeneous proto messages in a single cache by wrapping
+// them in anypb.Any, and resolves the concrete message type on Get through a
+// type registry. It implements Cache[proto.Message].
+type AnyCache struct {
+	cache    Cache[*anypb.Any]
+	resolver AnyResolver
+}
+
+// NewAnyCache creates an AnyCache on top of a cache of anypb.Any values, e.g.
+// one created with NewDistributed[*anypb.Any]. Message types are resolved with
+// resolver, or protoregistry.GlobalTypes when nil.
+func NewAnyCache(cache Cache[*anypb.Any], resolver AnyResolver) *AnyCache {
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	return &AnyCache{
+		cache:    cache,
+		resolver: resolver,
+	}
+}
+
+// Get retrieves a message and unpacks it into its concrete type.
+// Returns false on a miss or when the stored type is not known to the resolver.
+func (c *AnyCache) Get(ctx context.Context, key string) (proto.Message, bool) {
+	wrapped, found := c.cache.Get(ctx, key)
+	if !found || wrapped == nil {
+		return nil, false
+	}
+
+	msg, err := anypb.UnmarshalNew(wrapped, proto.UnmarshalOptions{Resolver: c.resolver})
+	if err != nil {
+		// Unknown or incompatible type - treat as cache miss
+		return nil, false
+	}
+
+	return msg, true
+}
+
+// Exists reports whether key is stored, including messages of types the
+// resolver doesn't know, which Get reports as misses.
+func (c *AnyCache) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.cache, key)
+}
+
+// TTL reports the lifetime left to key in the underlying cache.
+func (c *AnyCache) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.cache, key)
+}
+
+// Expire changes the lifetime of key in the underlying cache.
+func (c *AnyCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.cache, key, ttl)
+}
+
+// SetIfAbsent wraps value in anypb.Any and stores it unless key is stored.
+func (c *AnyCache) SetIfAbsent(ctx context.Context, key string, value proto.Message, ttl time.Duration) (bool, error) {
+	wrapped, err := anypb.New(value)
+	if err != nil {
+		return false, err
+	}
+	return SetIfAbsent(ctx, c.cache, key, wrapped, ttl)
+}
+
+// CompareAndSwap wraps old and new in anypb.Any and stores new if key holds
+// old.
+func (c *AnyCache) CompareAndSwap(ctx context.Context, key string, old, new proto.Message, ttl time.Duration) (bool, error) {
+	wrappedOld, err := anypb.New(old)
+	if err != nil {
+		return false, err
+	}
+	wrappedNew, err := anypb.New(new)
+	if err != nil {
+		return false, err
+	}
+	return CompareAndSwap(ctx, c.cache, key, wrappedOld, wrappedNew, ttl)
+}
+
+func (c *AnyCache) GetDel(ctx context.Context, key string) (proto.Message, bool, error) {
+	wrapped, found, err := GetDel(ctx, c.cache, key)
+	if err != nil || !found || wrapped == nil {
+		return nil, false, err
+	}
+
+	msg, err := anypb.UnmarshalNew(wrapped, proto.UnmarshalOptions{Resolver: c.resolver})
+	if err != nil {
+		// The entry is gone either way; report it like Get does
+		return nil, false, nil
+	}
+	return msg, true, nil
+}
+
+// Set wraps value in anypb.Any and stores it with the specified TTL.
+func (c *AnyCache) Set(ctx context.Context, key string, value proto.Message, ttl time.Duration) error {
+	wrapped, err := anypb.New(value)
+	if err != nil {
+		return err
+	}
+	return c.cache.Set(ctx, key, wrapped, ttl)
+}
+
+// Delete removes a value from the cache.
+func (c *AnyCache) Delete(ctx context.Context, key string) error {
+	return c.cache.Delete(ctx, key)
+}
+
+// Close closes the underlying cache.
+func (c *AnyCache) Close() error {
+	return c.cache.Close()
+}
+
+// Clear removes every entry of the underlying cache when it supports it,
+// returning ErrNotClearable otherwise.
+func (c *AnyCache) Clear(ctx context.Context) error {
+	return Clear(ctx, c.cache)
+}
+
+// Ping checks the underlying cache when it supports health checks.
+func (c *AnyCache) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.cache)
+}
+
+// GetAs retrieves a message from an AnyCache and returns it as M.
+// Returns false on a miss or when the stored message is of a different type.
+func GetAs[M proto.Message](ctx context.Context, c *AnyCache, key string) (M, bool) {
+	var zero M
+
+	msg, found := c.Get(ctx, key)
+	if !found {
+		return zero, false
+	}
+
+	typed, ok := msg.(M)
+	if !ok {
+		return zero, false
+	}
+	return typed, true
+}
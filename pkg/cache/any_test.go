@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAnyCache(t *testing.T) {
+	cache := NewAnyCache(NewMemory[*anypb.Any](nil), nil)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	// Test Set of different message types
+	if err := cache.Set(ctx, "name", wrapperspb.String("John"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Set(ctx, "timeout", durationpb.New(time.Second), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Test Get resolves concrete types
+	msg, found := cache.Get(ctx, "name")
+	if !found {
+		t.Fatal("Expected to find name")
+	}
+	if !proto.Equal(msg, wrapperspb.String("John")) {
+		t.Errorf("Expected StringValue John, got %v", msg)
+	}
+
+	// Test GetAs with matching type
+	timeout, found := GetAs[*durationpb.Duration](ctx, cache, "timeout")
+	if !found {
+		t.Fatal("Expected to find timeout")
+	}
+	if timeout.AsDuration() != time.Second {
+		t.Errorf("Expected 1s, got %v", timeout.AsDuration())
+	}
+
+	// Test GetAs with mismatching type
+	if _, found := GetAs[*wrapperspb.StringValue](ctx, cache, "timeout"); found {
+		t.Error("Expected GetAs with wrong type to miss")
+	}
+
+	// Test Delete
+	if err := cache.Delete(ctx, "name"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "name"); found {
+		t.Error("Expected name to be deleted")
+	}
+}
+
+func TestAnyCacheUnknownType(t *testing.T) {
+	inner := NewMemory[*anypb.Any](nil)
+	cache := NewAnyCache(inner, nil)
+	defer cache.Close()
+
+	ctx := context.Background()
+	unknown := &anypb.Any{TypeUrl: "type.googleapis.com/does.not.Exist"}
+	if err := inner.Set(ctx, "unknown", unknown, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if _, found := cache.Get(ctx, "unknown"); found {
+		t.Error("Expected unknown type to be treated as a miss")
+	}
+}
@@ -0,0 +1,391 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull is returned by asynchronous writers when their queue is full
+// and the QueueFullError policy is configured.
+var ErrQueueFull = errors.New("write queue is full")
+
+// ErrWriterClosed is returned when writing to an asynchronous writer after
+// Close. It wraps ErrClosed, so retries and outage detection treat it as final.
+var ErrWriterClosed = fmt.Errorf("writer: %w", ErrClosed)
+
+// QueueFullPolicy decides what an asynchronous writer does when its queue is full.
+type QueueFullPolicy string
+
+const (
+	// QueueFullBlock blocks the caller until there is room or its context is done.
+	QueueFullBlock QueueFullPolicy = "block"
+	// QueueFullDropOldest discards the oldest queued write to make room.
+	QueueFullDropOldest QueueFullPolicy = "drop-oldest"
+	// QueueFullError rejects the write with ErrQueueFull.
+	QueueFullError QueueFullPolicy = "error"
+)
+
+// AsyncConfig holds configuration for an asynchronous cache writer.
+type AsyncConfig struct {
+	// QueueSize is the maximum number of queued writes (default: 1024)
+	QueueSize int
+
+	// Workers is the number of goroutines applying writes (default: 1).
+	// With more than one worker, writes to the same key may be applied out of order.
+	Workers int
+
+	// OnQueueFull selects the behavior when the queue is full (default: QueueFullBlock)
+	OnQueueFull QueueFullPolicy
+
+	// WriteTimeout bounds each background write (default: 0, no timeout)
+	WriteTimeout time.Duration
+
+	// OnError is called when a background write fails (optional)
+	OnError func(op Operation, key string, err error)
+}
+
+// QueueStats describes the state of an asynchronous write queue.
+type QueueStats struct {
+	// Depth is the number of writes waiting in the queue.
+	Depth int
+	// Capacity is the maximum queue size.
+	Capacity int
+	// Enqueued counts writes accepted into the queue.
+	Enqueued uint64
+	// Written counts writes applied successfully.
+	Written uint64
+	// Failed counts writes whose backend call returned an error.
+	Failed uint64
+	// Dropped counts queued writes discarded by QueueFullDropOldest.
+	Dropped uint64
+	// Rejected counts writes refused with ErrQueueFull.
+	Rejected uint64
+}
+
+// asyncWrite is a queued Set or Delete.
+type asyncWrite[T any] struct {
+	op    Operation
+	key   string
+	value T
+	ttl   time.Duration
+	// writtenAt orders the write for DistributedConfig.RejectOlderWrites
+	writtenAt time.Time
+	// seq numbers the write in enqueue order, for Flush
+	seq uint64
+}
+
+// asyncWaiter is a Flush waiting for the writes up to seq to finish.
+type asyncWaiter struct {
+	seq  uint64
+	done chan struct{}
+}
+
+// AsyncCache applies Set and Delete to the wrapped cache in the background,
+// returning to the caller as soon as the write is queued. Get reads from the
+// wrapped cache directly, so queued writes are not visible until applied.
+type AsyncCache[T any] struct {
+	next   Cache[T]
+	config AsyncConfig
+	queue  chan asyncWrite[T]
+
+	closeMu sync.RWMutex
+	closed  bool
+
+	mu sync.Mutex
+	// seq is the number of the last enqueued write; every write up to
+	// finished is done, and later writes done out of order are in ahead
+	seq      uint64
+	finished uint64
+	ahead    map[uint64]struct{}
+	waiters  []asyncWaiter
+
+	workers sync.WaitGroup
+	stats   struct {
+		enqueued, written, failed, dropped, rejected atomic.Uint64
+	}
+}
+
+// NewAsync wraps a cache so that writes are applied asynchronously.
+// Close flushes the queue before closing the wrapped cache.
+func NewAsync[T any](cache Cache[T], config *AsyncConfig) *AsyncCache[T] {
+	var cfg AsyncConfig
+	if config != nil {
+		cfg = *config
+	}
+	ensureAsyncDefaults(&cfg)
+
+	c := &AsyncCache[T]{
+		next:   cache,
+		config: cfg,
+		queue:  make(chan asyncWrite[T], cfg.QueueSize),
+		ahead:  make(map[uint64]struct{}),
+	}
+
+	c.workers.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go c.work()
+	}
+	return c
+}
+
+func ensureAsyncDefaults(config *AsyncConfig) {
+	if config.QueueSize == 0 {
+		config.QueueSize = 1024
+	}
+	if config.Workers == 0 {
+		config.Workers = 1
+	}
+	if config.OnQueueFull == "" {
+		config.OnQueueFull = QueueFullBlock
+	}
+}
+
+func (c *AsyncCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *AsyncCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+// Exists reports whether key is stored in the wrapped cache; queued writes
+// are not taken into account.
+func (c *AsyncCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *AsyncCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+// Expire queues a change of the lifetime of key, applied after the writes
+// queued before it.
+func (c *AsyncCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return c.enqueue(ctx, asyncWrite[T]{op: OperationExpire, key: key, ttl: ttl})
+}
+
+// SetIfAbsent waits for the writes queued so far, whose outcome it depends on,
+// and then writes synchronously, since its result must be returned.
+func (c *AsyncCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if err := c.Flush(ctx); err != nil {
+		return false, err
+	}
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+// CompareAndSwap waits for the writes queued so far, like SetIfAbsent, and
+// then writes synchronously.
+func (c *AsyncCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if err := c.Flush(ctx); err != nil {
+		return false, err
+	}
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *AsyncCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	if err := c.Flush(ctx); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return GetDel(ctx, c.next, key)
+}
+
+// Set queues a write of value and returns once it is queued.
+func (c *AsyncCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.enqueue(ctx, asyncWrite[T]{op: OperationSet, key: key, value: value, ttl: ttl, writtenAt: writeTimestampOf(ctx)})
+}
+
+// Delete queues a removal of key and returns once it is queued.
+func (c *AsyncCache[T]) Delete(ctx context.Context, key string) error {
+	return c.enqueue(ctx, asyncWrite[T]{op: OperationDelete, key: key})
+}
+
+// Flush blocks until all writes queued so far have been applied, or until
+// ctx is done. Writes queued after the call don't hold it up.
+func (c *AsyncCache[T]) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	if c.finished == c.seq {
+		c.mu.Unlock()
+		return nil
+	}
+	waiter := asyncWaiter{seq: c.seq, done: make(chan struct{})}
+	c.waiters = append(c.waiters, waiter)
+	c.mu.Unlock()
+
+	select {
+	case <-waiter.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// QueueStats returns a snapshot of the queue depth and write counters.
+func (c *AsyncCache[T]) QueueStats() QueueStats {
+	return QueueStats{
+		Depth:    len(c.queue),
+		Capacity: cap(c.queue),
+		Enqueued: c.stats.enqueued.Load(),
+		Written:  c.stats.written.Load(),
+		Failed:   c.stats.failed.Load(),
+		Dropped:  c.stats.dropped.Load(),
+		Rejected: c.stats.rejected.Load(),
+	}
+}
+
+// Clear waits for the writes queued so far to be applied, so none lands
+// afterwards, then clears the wrapped cache.
+func (c *AsyncCache[T]) Clear(ctx context.Context) error {
+	if err := c.Flush(ctx); err != nil {
+		return err
+	}
+	return Clear(ctx, c.next)
+}
+
+// Close stops accepting writes, waits for queued writes to be applied and
+// closes the wrapped cache.
+func (c *AsyncCache[T]) Close() error {
+	c.closeMu.Lock()
+	if c.closed {
+		c.closeMu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.queue)
+	c.closeMu.Unlock()
+
+	c.workers.Wait()
+	return c.next.Close()
+}
+
+func (c *AsyncCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *AsyncCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+func (c *AsyncCache[T]) enqueue(ctx context.Context, write asyncWrite[T]) error {
+	// The read lock keeps Close from closing the queue under a blocked sender
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+
+	if c.closed {
+		return ErrWriterClosed
+	}
+
+	// Number the write before sending so a worker can never finish it first
+	c.mu.Lock()
+	c.seq++
+	write.seq = c.seq
+	c.mu.Unlock()
+
+	switch c.config.OnQueueFull {
+	case QueueFullError:
+		select {
+		case c.queue <- write:
+		default:
+			c.stats.rejected.Add(1)
+			c.done(write.seq)
+			return ErrQueueFull
+		}
+
+	case QueueFullDropOldest:
+		for sent := false; !sent; {
+			select {
+			case c.queue <- write:
+				sent = true
+			default:
+				select {
+				case dropped := <-c.queue:
+					c.stats.dropped.Add(1)
+					c.done(dropped.seq)
+				default:
+				}
+			}
+		}
+
+	default:
+		select {
+		case c.queue <- write:
+		case <-ctx.Done():
+			c.done(write.seq)
+			return ctx.Err()
+		}
+	}
+
+	c.stats.enqueued.Add(1)
+	return nil
+}
+
+func (c *AsyncCache[T]) work() {
+	defer c.workers.Done()
+
+	for write := range c.queue {
+		c.apply(write)
+		c.done(write.seq)
+	}
+}
+
+func (c *AsyncCache[T]) apply(write asyncWrite[T]) {
+	ctx := context.Background()
+	if c.config.WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.WriteTimeout)
+		defer cancel()
+	}
+
+	var err error
+	switch write.op {
+	case OperationSet:
+		err = c.next.Set(WithWriteTimestamp(ctx, write.writtenAt), write.key, write.value, write.ttl)
+	case OperationDelete:
+		err = c.next.Delete(ctx, write.key)
+	case OperationExpire:
+		err = Expire(ctx, c.next, write.key, write.ttl)
+	}
+
+	if err != nil {
+		c.stats.failed.Add(1)
+		if c.config.OnError != nil {
+			c.config.OnError(write.op, write.key, err)
+		}
+		return
+	}
+	c.stats.written.Add(1)
+}
+
+// done marks the write numbered seq as finished and releases the Flush
+// waiters whose writes are all finished.
+func (c *AsyncCache[T]) done(seq uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if seq != c.finished+1 {
+		c.ahead[seq] = struct{}{}
+		return
+	}
+	c.finished = seq
+	for {
+		if _, ok := c.ahead[c.finished+1]; !ok {
+			break
+		}
+		delete(c.ahead, c.finished+1)
+		c.finished++
+	}
+
+	waiting := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.seq <= c.finished {
+			close(waiter.done)
+		} else {
+			waiting = append(waiting, waiter)
+		}
+	}
+	c.waiters = waiting
+}
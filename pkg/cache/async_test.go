@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingCache blocks every Set until release is closed.
+type blockingCache[T any] struct {
+	Cache[T]
+	release chan struct{}
+}
+
+func (c *blockingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	<-c.release
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+// keyGatedCache blocks Sets of the keys with a gate until the gate is closed.
+type keyGatedCache[T any] struct {
+	Cache[T]
+	gates map[string]chan struct{}
+}
+
+func (c *keyGatedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if gate, ok := c.gates[key]; ok {
+		<-gate
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestAsyncCache(t *testing.T) {
+	cache := NewAsync(NewMemory[TestUser](nil), nil)
+	defer cache.Close()
+
+	ctx := context.Background()
+	user := TestUser{ID: "123", Name: "John"}
+
+	// Test Set is applied after Flush
+	if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	retrieved, found := cache.Get(ctx, "key1")
+	if !found || retrieved.ID != user.ID {
+		t.Errorf("Expected %+v after Flush, got %+v (found=%v)", user, retrieved, found)
+	}
+
+	// Test Delete is applied after Flush
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted after Flush")
+	}
+
+	stats := cache.QueueStats()
+	if stats.Enqueued != 2 || stats.Written != 2 || stats.Depth != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestAsyncCacheQueueFullPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error", func(t *testing.T) {
+		inner := &blockingCache[TestUser]{Cache: NewMemory[TestUser](nil), release: make(chan struct{})}
+		cache := NewAsync[TestUser](inner, &AsyncConfig{QueueSize: 1, OnQueueFull: QueueFullError})
+		defer cache.Close()
+		defer close(inner.release)
+
+		// One write is taken by the worker, one fills the queue
+		_ = cache.Set(ctx, "key1", TestUser{}, time.Minute)
+		waitForDepth(t, cache, 0)
+		_ = cache.Set(ctx, "key2", TestUser{}, time.Minute)
+
+		err := cache.Set(ctx, "key3", TestUser{}, time.Minute)
+		if !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Expected ErrQueueFull, got: %v", err)
+		}
+		if stats := cache.QueueStats(); stats.Rejected != 1 || stats.Depth != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		inner := &blockingCache[TestUser]{Cache: NewMemory[TestUser](nil), release: make(chan struct{})}
+		cache := NewAsync[TestUser](inner, &AsyncConfig{QueueSize: 1, OnQueueFull: QueueFullDropOldest})
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute)
+		waitForDepth(t, cache, 0)
+		_ = cache.Set(ctx, "key2", TestUser{ID: "2"}, time.Minute)
+		if err := cache.Set(ctx, "key3", TestUser{ID: "3"}, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+
+		close(inner.release)
+		if err := cache.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+
+		if _, found := cache.Get(ctx, "key2"); found {
+			t.Error("Expected oldest queued write key2 to be dropped")
+		}
+		if _, found := cache.Get(ctx, "key3"); !found {
+			t.Error("Expected newest write key3 to be applied")
+		}
+		if stats := cache.QueueStats(); stats.Dropped != 1 || stats.Written != 2 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		inner := &blockingCache[TestUser]{Cache: NewMemory[TestUser](nil), release: make(chan struct{})}
+		cache := NewAsync[TestUser](inner, &AsyncConfig{QueueSize: 1})
+		defer cache.Close()
+		defer close(inner.release)
+
+		_ = cache.Set(ctx, "key1", TestUser{}, time.Minute)
+		waitForDepth(t, cache, 0)
+		_ = cache.Set(ctx, "key2", TestUser{}, time.Minute)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		err := cache.Set(timeoutCtx, "key3", TestUser{}, time.Minute)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+
+		// Flush honors its context while writes are stuck
+		if err := cache.Flush(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected Flush to time out, got: %v", err)
+		}
+	})
+}
+
+func TestAsyncCacheFlushIgnoresLaterWrites(t *testing.T) {
+	ctx := context.Background()
+	inner := &keyGatedCache[TestUser]{
+		Cache: NewMemory[TestUser](nil),
+		gates: map[string]chan struct{}{"before": make(chan struct{}), "after": make(chan struct{})},
+	}
+	cache := NewAsync[TestUser](inner, &AsyncConfig{Workers: 2})
+	defer cache.Close()
+	defer close(inner.gates["after"])
+
+	_ = cache.Set(ctx, "before", TestUser{ID: "1"}, time.Minute)
+
+	flushed := make(chan error, 1)
+	go func() { flushed <- cache.Flush(ctx) }()
+	waitFor(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.waiters) == 1
+	})
+
+	// A write queued after Flush stays pending, as under steady load
+	_ = cache.Set(ctx, "after", TestUser{ID: "2"}, time.Minute)
+	close(inner.gates["before"])
+
+	select {
+	case err := <-flushed:
+		if err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Flush to return once the writes queued before it were applied")
+	}
+	if _, found := cache.Get(ctx, "before"); !found {
+		t.Error("Expected the write queued before Flush to be applied")
+	}
+}
+
+func TestAsyncCacheClose(t *testing.T) {
+	inner := NewMemory[TestUser](nil)
+	cache := NewAsync(inner, nil)
+
+	ctx := context.Background()
+	_ = cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute)
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if stats := cache.QueueStats(); stats.Written != 1 {
+		t.Errorf("Expected Close to drain the queue, got %+v", stats)
+	}
+	if err := cache.Set(ctx, "key2", TestUser{}, time.Minute); !errors.Is(err, ErrWriterClosed) || !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrWriterClosed wrapping ErrClosed, got: %v", err)
+	}
+}
+
+// waitForDepth waits until a worker has picked writes off the queue.
+func waitForDepth[T any](t *testing.T, cache *AsyncCache[T], depth int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for cache.QueueStats().Depth != depth {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for queue depth %d", depth)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
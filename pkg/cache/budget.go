@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// budgetCache answers reads slower than a latency budget with a miss.
+type budgetCache[T any] struct {
+	next   Cache[T]
+	budget time.Duration
+}
+
+// WithReadBudget wraps a cache so that every read is bounded by budget, see
+// GetWithinBudget. A budget of 0 or less returns cache unchanged.
+func WithReadBudget[T any](cache Cache[T], budget time.Duration) Cache[T] {
+	if budget <= 0 {
+		return cache
+	}
+	return &budgetCache[T]{
+		next:   cache,
+		budget: budget,
+	}
+}
+
+// GetWithinBudget reads key like GetWithError, but answers with a miss once
+// budget has elapsed, so a slow cache never costs more than it saves on the
+// request path. The backend read is cancelled through its context when the
+// budget runs out; backends that ignore the context finish in the background.
+// Errors returned within the budget are reported as usual, and ctx's error
+// when ctx is done before the budget runs out.
+func GetWithinBudget[T any](ctx context.Context, cache Cache[T], key string, budget time.Duration) (T, bool, error) {
+	if budget <= 0 {
+		return GetWithError(ctx, cache, key)
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	results := make(chan hedgeResult[T], 1)
+	go func() {
+		value, found, err := GetWithError(readCtx, cache, key)
+		results <- hedgeResult[T]{value: value, found: found, err: err}
+	}()
+
+	select {
+	case r := <-results:
+		if readCtx.Err() != nil && !r.found {
+			if err := ctx.Err(); err != nil {
+				return r.value, false, err
+			}
+			// The backend gave up because of the budget, not because it failed
+			noteDecision(ctx, DecisionTimeout)
+			return r.value, false, nil
+		}
+		return r.value, r.found, r.err
+	case <-readCtx.Done():
+		var zero T
+		if err := ctx.Err(); err != nil {
+			return zero, false, err
+		}
+		noteDecision(ctx, DecisionTimeout)
+		return zero, false, nil
+	}
+}
+
+func (c *budgetCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := GetWithinBudget(ctx, c.next, key, c.budget)
+	return value, found
+}
+
+func (c *budgetCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithinBudget(ctx, c.next, key, c.budget)
+}
+
+// GetMulti reads the keys within the budget, like GetWithinBudget: once it
+// has elapsed, the keys not read yet are misses, or fail with ctx's error when
+// ctx is done.
+func (c *budgetCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	if c.budget <= 0 {
+		return GetMulti(ctx, c.next, keys...)
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, c.budget)
+	defer cancel()
+
+	read := make(chan []KeyResult[T], 1)
+	go func() {
+		read <- GetMulti(readCtx, c.next, keys...)
+	}()
+
+	select {
+	case results := <-read:
+		if err := ctx.Err(); err != nil {
+			// Keys missed because the caller gave up aren't misses
+			for i, r := range results {
+				if !r.Found && r.Err == nil {
+					results[i].Err = err
+				}
+			}
+			return results
+		}
+		if readCtx.Err() != nil {
+			// The backend gave up on the keys it failed because of the budget
+			noteDecision(ctx, DecisionTimeout)
+			for i, r := range results {
+				if r.Err != nil {
+					results[i] = KeyResult[T]{Key: r.Key}
+				}
+			}
+		}
+		return results
+	case <-readCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return keyResults[T](keys, err)
+		}
+		noteDecision(ctx, DecisionTimeout)
+		return keyResults[T](keys, nil)
+	}
+}
+
+func (c *budgetCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *budgetCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *budgetCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *budgetCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *budgetCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+// GetDel isn't bounded by the budget: answering a slow GetDel as a miss
+// would lose a value it may still remove.
+func (c *budgetCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *budgetCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *budgetCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *budgetCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *budgetCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *budgetCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *budgetCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *budgetCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetWithinBudget(t *testing.T) {
+	ctx := context.Background()
+	slow := &gatedCache[TestUser]{Cache: NewMemory[TestUser](nil), gate: make(chan struct{})}
+	defer close(slow.gate)
+	_ = slow.Cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	// Test a read slower than the budget is answered as a miss
+	start := time.Now()
+	_, found, err := GetWithinBudget(ctx, slow, "key1", 20*time.Millisecond)
+	if found || err != nil {
+		t.Errorf("Expected a miss without error, got %v, %v", found, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the read to give up after the budget, took %v", elapsed)
+	}
+
+	// Test a read within the budget is answered normally
+	fast := NewMemory[TestUser](nil)
+	defer fast.Close()
+	_ = fast.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+	if user, found, err := GetWithinBudget(ctx, fast, "key1", time.Second); err != nil || !found || user.ID != "123" {
+		t.Errorf("Expected key1, got %v, %v, %v", user, found, err)
+	}
+}
+
+func TestReadBudgetReportsCancellation(t *testing.T) {
+	slow := &gatedCache[TestUser]{Cache: NewMemory[TestUser](nil), gate: make(chan struct{})}
+	defer close(slow.gate)
+	cache := WithReadBudget[TestUser](slow, time.Second)
+
+	// Test a caller giving up within the budget gets its error, not a miss
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := GetWithError(ctx, cache, "key1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline, got %v", err)
+	}
+	for _, r := range GetMulti(ctx, cache, "key1", "key2") {
+		if !errors.Is(r.Err, context.DeadlineExceeded) {
+			t.Errorf("Expected the caller's deadline for %s, got %v", r.Key, r.Err)
+		}
+	}
+}
+
+func TestReadBudgetDecision(t *testing.T) {
+	slow := &gatedCache[TestUser]{Cache: NewMemory[TestUser](nil), gate: make(chan struct{})}
+	defer close(slow.gate)
+	cache := WithDecisionTracing(WithReadBudget[TestUser](slow, 10*time.Millisecond), "users", "memory")
+
+	events := decisionEvents(t, func(ctx context.Context) {
+		cache.Get(ctx, "key1")
+	})
+	if len(events) != 1 || events[0][0] != string(DecisionTimeout) {
+		t.Errorf("Expected a timeout decision, got %v", events)
+	}
+}
+
+func TestWithReadBudgetDisabled(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	if WithReadBudget(cache, 0) != cache {
+		t.Error("Expected a zero budget to return the cache unchanged")
+	}
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+type noStoreKey struct{}
+
+type maxAgeKey struct{}
+
+// WithNoStore returns a context asking cache layers that store values on
+// behalf of the caller (tiered promotion, loaders) not to store anything for
+// this operation, mirroring Cache-Control: no-store. Values are still read.
+func WithNoStore(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noStoreKey{}, true)
+}
+
+// WithMaxAge returns a context capping the TTL of values stored by cache
+// layers that honor directives, mirroring Cache-Control: max-age.
+// A max age of zero or less behaves like WithNoStore.
+func WithMaxAge(ctx context.Context, maxAge time.Duration) context.Context {
+	return context.WithValue(ctx, maxAgeKey{}, maxAge)
+}
+
+// directiveTTL applies the context's cache-control directives to ttl.
+// It returns false when the value must not be stored.
+func directiveTTL(ctx context.Context, ttl time.Duration) (time.Duration, bool) {
+	if noStore, _ := ctx.Value(noStoreKey{}).(bool); noStore {
+		return 0, false
+	}
+
+	maxAge, ok := ctx.Value(maxAgeKey{}).(time.Duration)
+	if !ok {
+		return ttl, true
+	}
+	if maxAge <= 0 {
+		return 0, false
+	}
+	// A ttl of 0 means no expiry, which max-age caps as well
+	if ttl <= 0 || ttl > maxAge {
+		return maxAge, true
+	}
+	return ttl, true
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDirectiveTTL(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		ttl       time.Duration
+		wantTTL   time.Duration
+		wantStore bool
+	}{
+		{"no directives", ctx, time.Hour, time.Hour, true},
+		{"no-store", WithNoStore(ctx), time.Hour, 0, false},
+		{"max-age caps ttl", WithMaxAge(ctx, time.Minute), time.Hour, time.Minute, true},
+		{"max-age keeps shorter ttl", WithMaxAge(ctx, time.Minute), time.Second, time.Second, true},
+		{"max-age caps no expiry", WithMaxAge(ctx, time.Minute), 0, time.Minute, true},
+		{"max-age zero", WithMaxAge(ctx, 0), time.Hour, 0, false},
+		{"no-store wins over max-age", WithMaxAge(WithNoStore(ctx), time.Minute), time.Hour, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttl, store := directiveTTL(tt.ctx, tt.ttl)
+			if ttl != tt.wantTTL || store != tt.wantStore {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tt.wantTTL, tt.wantStore, ttl, store)
+			}
+		})
+	}
+}
+
+func TestTieredCacheHonorsDirectives(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, nil)
+	defer cache.Close()
+
+	// Test no-store doesn't skip explicit writes, which would leave the old value behind
+	_ = cache.Set(ctx, "key1", TestUser{ID: "old"}, time.Hour)
+	_ = cache.Set(WithNoStore(ctx), "key1", TestUser{ID: "123"}, time.Hour)
+	if user, found := l2.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Errorf("Expected no-store Set to write L2, got %v, %v", user, found)
+	}
+	if user, found := l1.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Errorf("Expected no-store Set to write L1, got %v, %v", user, found)
+	}
+
+	// Test no-store skips promotions
+	_ = l2.Set(ctx, "key2", TestUser{ID: "456"}, time.Hour)
+	if _, found := cache.Get(WithNoStore(ctx), "key2"); !found {
+		t.Error("Expected no-store Get to still read")
+	}
+	if _, found := l1.Get(ctx, "key2"); found {
+		t.Error("Expected no-store Get not to promote into L1")
+	}
+
+	// Test max-age caps the TTL
+	_ = cache.Set(WithMaxAge(ctx, 10*time.Second), "key3", TestUser{ID: "789"}, time.Hour)
+	mc, _ := memoryCacheOf(l2)
+	for _, entry := range mc.hottest(10) {
+		if entry.key == "key3" && entry.ttl > 10*time.Second {
+			t.Errorf("Expected TTL capped at 10s, got %v", entry.ttl)
+		}
+	}
+}
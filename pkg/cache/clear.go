@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotClearable is returned by Clear for caches that can't remove every
+// entry at once.
+var ErrNotClearable = errors.New("cache does not support clearing")
+
+// Clear removes every entry of cache, e.g. to reset it between tests or from
+// admin tooling. Returns ErrNotClearable for caches that don't implement
+// Clearer.
+//
+// Caches wrapped WithPrefix only remove their namespace's keys, by scanning
+// for and deleting them, which is not atomic: keys written during the scan may
+// survive. Without a prefix, distributed caches return ErrNotClearable unless
+// DistributedConfig.AllowFlushDB lets them flush their whole Redis/Valkey
+// database, including data written through other clients.
+func Clear[T any](ctx context.Context, cache Cache[T]) error {
+	if c, ok := cache.(Clearer); ok {
+		return c.Clear(ctx)
+	}
+	return ErrNotClearable
+}
+
+// clearBatchSize is the number of keys unlinked per SCAN page by Clear.
+const clearBatchSize = 500
+
+// prefixClearer is implemented by backends that remove the keys of a prefix
+// natively.
+type prefixClearer interface {
+	clearPrefix(ctx context.Context, prefix string) error
+}
+
+// unlinkMatching removes the keys matching pattern with one pipeline of UNLINKs
+// per SCAN page, on every master of a cluster.
+func unlinkMatching(ctx context.Context, client redis.UniversalClient, pattern string) error {
+	return scanRedis(ctx, client, pattern, clearBatchSize, func(keys []string) error {
+		pipe := client.Pipeline()
+		for _, key := range keys {
+			pipe.Unlink(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		return closedErr(err)
+	})
+}
+
+// flushRedis removes every key of the database client is connected to, on
+// every master of a cluster.
+func flushRedis(ctx context.Context, client redis.UniversalClient) error {
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		return closedErr(cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return node.FlushDBAsync(ctx).Err()
+		}))
+	}
+	return closedErr(client.FlushDBAsync(ctx).Err())
+}
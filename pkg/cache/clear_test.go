@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// unclearableCache only exposes the Cache methods of the cache it embeds,
+// hiding its optional interfaces.
+type unclearableCache[T any] struct {
+	Cache[T]
+}
+
+func TestClearMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+			_ = cache.Set(ctx, "b", TestUser{ID: "b"}, 0)
+			_ = flushAll(ctx, cache)
+
+			if err := Clear(ctx, cache); err != nil {
+				t.Fatalf("Clear failed: %v", err)
+			}
+			for _, key := range []string{"a", "b"} {
+				if _, found := cache.Get(ctx, key); found {
+					t.Errorf("Expected %s to be cleared", key)
+				}
+			}
+
+			// Test the cache keeps working
+			_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+			_ = flushAll(ctx, cache)
+			if _, found := cache.Get(ctx, "a"); !found {
+				t.Error("Expected a Set after Clear to be stored")
+			}
+		})
+	}
+}
+
+func TestClearPrefixOnlyClearsNamespace(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	billing := WithPrefix(backend, "billing:")
+	_ = billing.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = backend.Set(ctx, "other:a", TestUser{ID: "a"}, time.Minute)
+
+	if err := Clear(ctx, billing); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := billing.Get(ctx, "a"); found {
+		t.Error("Expected the namespace to be cleared")
+	}
+	if _, found := backend.Get(ctx, "other:a"); !found {
+		t.Error("Expected keys outside the namespace to survive")
+	}
+}
+
+func TestClearThroughFactoryStack(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New[TestUser](&Config{
+		Type:             TypeMemory,
+		KeyPrefix:        "svc:",
+		SkipWriteIfEqual: true,
+		WriteDampening:   time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+
+	user := TestUser{ID: "1"}
+	_ = cache.Set(ctx, "1", user, time.Minute)
+	if err := Clear(ctx, cache); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Error("Expected the entry to be cleared")
+	}
+
+	// Skip-equal and dampening state is cleared too, so the same value is written again
+	_ = cache.Set(ctx, "1", user, time.Minute)
+	if _, found := cache.Get(ctx, "1"); !found {
+		t.Error("Expected the rewrite after Clear not to be skipped")
+	}
+}
+
+func TestClearDryRunOnlyRecords(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+	_ = backend.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+
+	var ops []DryRunOperation
+	cache := NewDryRun(backend, func(_ context.Context, op DryRunOperation) { ops = append(ops, op) })
+	if err := Clear(ctx, cache); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := backend.Get(ctx, "a"); !found {
+		t.Error("Expected dry-run Clear to leave the backend alone")
+	}
+	if len(ops) != 1 || ops[0].Operation != OperationClear {
+		t.Errorf("Expected a recorded Clear, got %+v", ops)
+	}
+}
+
+func TestClearTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if err := Clear(ctx, cache); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := l1.Get(ctx, "a"); found {
+		t.Error("Expected L1 to be cleared")
+	}
+	if _, found := l2.Get(ctx, "a"); found {
+		t.Error("Expected L2 to be cleared")
+	}
+}
+
+func TestClearDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if err := Clear(ctx, cache); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "a"); found {
+		t.Error("Expected the entry to be cleared")
+	}
+	if err := cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute); err != nil {
+		t.Errorf("Expected Set after Clear to succeed, got: %v", err)
+	}
+}
+
+func TestClearNotSupported(t *testing.T) {
+	ctx := context.Background()
+	backend := &unclearableCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	defer backend.Close()
+
+	if err := Clear[TestUser](ctx, backend); !errors.Is(err, ErrNotClearable) {
+		t.Errorf("Expected ErrNotClearable, got: %v", err)
+	}
+	if err := Clear(ctx, WithPrefix[TestUser](backend, "p:")); !errors.Is(err, ErrNotClearable) {
+		t.Errorf("Expected ErrNotClearable through a prefix, got: %v", err)
+	}
+	if err := Clear(ctx, WithDefaultTTL[TestUser](backend, time.Minute)); !errors.Is(err, ErrNotClearable) {
+		t.Errorf("Expected ErrNotClearable through a decorator, got: %v", err)
+	}
+}
+
+func TestClearDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	backend, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer backend.Close()
+
+	billing := WithPrefix(backend, "billing:")
+	_ = billing.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = backend.Set(ctx, "other", TestUser{ID: "o"}, time.Minute)
+
+	// Test a prefixed cache only clears its namespace
+	if err := Clear(ctx, billing); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := billing.Get(ctx, "a"); found {
+		t.Error("Expected the namespace to be cleared")
+	}
+	if _, found := backend.Get(ctx, "other"); !found {
+		t.Error("Expected keys outside the namespace to survive")
+	}
+
+	// Test an unprefixed cache refuses to flush the database by default
+	if err := Clear(ctx, backend); !errors.Is(err, ErrNotClearable) {
+		t.Fatalf("Expected ErrNotClearable, got: %v", err)
+	}
+	if _, found := backend.Get(ctx, "other"); !found {
+		t.Error("Expected the database to be left alone")
+	}
+
+	// Test AllowFlushDB opts into flushing the database
+	flushing, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr, AllowFlushDB: true})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer flushing.Close()
+	if err := Clear(ctx, flushing); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := backend.Get(ctx, "other"); found {
+		t.Error("Expected the database to be flushed")
+	}
+}
@@ -0,0 +1,347 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jellydator/ttlcache/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidateChannel is where Redis/Valkey publishes the keys changed under
+// client tracking.
+const invalidateChannel = "__redis__:invalidate"
+
+// ClientSideCacheConfig holds configuration for server-assisted client-side
+// caching.
+type ClientSideCacheConfig struct {
+	// MaxEntries bounds the number of values kept locally (default: 10000)
+	MaxEntries int
+
+	// MaxStaleness bounds how long a value is served locally, as a safety net
+	// for invalidations lost while reconnecting (default: 1m)
+	MaxStaleness time.Duration
+}
+
+// serverTracking owns the connection receiving the server's invalidation
+// messages and enables tracking, redirected to it, on data connections.
+type serverTracking struct {
+	listener *redis.Client
+	pubsub   *redis.PubSub
+	// redirect is the client ID of the listener's current connection
+	redirect atomic.Int64
+	done     chan struct{}
+	closeErr error
+	closing  sync.Once
+	timeout  time.Duration
+	// retracking runs retrack after listener reconnects
+	retracking sync.WaitGroup
+
+	mu sync.Mutex
+	// evict is called with the invalidated keys, nil for all keys
+	evict func(keys []string)
+	// data is the client of the tracked data connections
+	data redis.UniversalClient
+}
+
+// newServerTracking connects the invalidation listener, returning nil when
+// client-side caching is disabled.
+func newServerTracking(config *DistributedConfig) (*serverTracking, error) {
+	if config == nil || config.ClientSideCache == nil {
+		return nil, nil
+	}
+	if config.Client != nil {
+		return nil, errors.New("ClientSideCache requires the cache to create its own client")
+	}
+	ensureDistributedDefaults(config)
+
+	t := &serverTracking{done: make(chan struct{}), timeout: config.DialTimeout + config.ReadTimeout}
+	t.listener = redis.NewClient(&redis.Options{
+		Addr:         config.Addr,
+		Password:     config.Password,
+		PoolSize:     1,
+		MaxRetries:   config.MaxRetries,
+		DialTimeout:  config.DialTimeout,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		OnConnect:    t.onListenerConnect,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.DialTimeout)
+	defer cancel()
+	t.pubsub = t.listener.Subscribe(ctx, invalidateChannel)
+	if _, err := t.pubsub.Receive(ctx); err != nil {
+		_ = t.pubsub.Close()
+		_ = t.listener.Close()
+		return nil, fmt.Errorf("subscribing to invalidations: %w", err)
+	}
+
+	go t.receive()
+	return t, nil
+}
+
+// onListenerConnect records the listener's client ID. A reconnect changes
+// it, leaving data connections opened earlier redirecting to a closed client:
+// every local value is dropped and those connections are moved over by
+// retrack.
+func (t *serverTracking) onListenerConnect(ctx context.Context, cn *redis.Conn) error {
+	id, err := cn.ClientID(ctx).Result()
+	if err != nil {
+		return err
+	}
+	if previous := t.redirect.Swap(id); previous != 0 {
+		t.invalidate(nil)
+		t.retracking.Add(1)
+		go func() {
+			defer t.retracking.Done()
+			t.retrack(previous, id)
+		}()
+	}
+	return nil
+}
+
+// retrack moves the data connections redirecting to the listener's previous
+// connection over to the current one. Tracking of another connection can't be
+// changed, so they are killed and the pool reopens them through connectHook;
+// the connection issuing the kills is re-tracked instead.
+func (t *serverTracking) retrack(previous, current int64) {
+	t.mu.Lock()
+	data := t.data
+	t.mu.Unlock()
+	if data == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	clients, err := data.ClientList(ctx).Result()
+	if err == nil {
+		redirect := "redir=" + strconv.FormatInt(previous, 10)
+		_, err = data.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Do(ctx, "CLIENT", "TRACKING", "ON", "REDIRECT", current)
+			for _, line := range strings.Split(clients, "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 0 || !strings.HasPrefix(fields[0], "id=") || !slices.Contains(fields, redirect) {
+					continue
+				}
+				// SKIPME, on by default, spares the connection re-tracked above
+				pipe.ClientKillByFilter(ctx, "ID", strings.TrimPrefix(fields[0], "id="))
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		// MaxStaleness bounds what is read through them
+		slog.Default().WarnContext(ctx, "cache: re-tracking connections after a reconnect failed", "error", err)
+	}
+	// Drop what was read through those connections meanwhile
+	t.invalidate(nil)
+}
+
+// connectHook returns the hook enabling tracking on data connections, nil
+// when client-side caching is disabled.
+func (t *serverTracking) connectHook() func(ctx context.Context, cn *redis.Conn) error {
+	if t == nil {
+		return nil
+	}
+	return func(ctx context.Context, cn *redis.Conn) error {
+		return cn.Do(ctx, "CLIENT", "TRACKING", "ON", "REDIRECT", t.redirect.Load()).Err()
+	}
+}
+
+func (t *serverTracking) receive() {
+	defer close(t.done)
+	for msg := range t.pubsub.Channel() {
+		if msg.Channel != invalidateChannel {
+			continue
+		}
+		// A flush invalidates without keys
+		t.invalidate(msg.PayloadSlice)
+	}
+}
+
+func (t *serverTracking) invalidate(keys []string) {
+	t.mu.Lock()
+	evict := t.evict
+	t.mu.Unlock()
+	if evict != nil {
+		evict(keys)
+	}
+}
+
+func (t *serverTracking) close() error {
+	if t == nil {
+		return nil
+	}
+	t.closing.Do(func() {
+		err := t.pubsub.Close()
+		<-t.done
+		t.retracking.Wait()
+		t.closeErr = errors.Join(err, t.listener.Close())
+	})
+	return t.closeErr
+}
+
+// clientSideCache keeps the values read from a distributed cache locally
+// until the server reports them changed.
+type clientSideCache[T any] struct {
+	next     Cache[T]
+	tracking *serverTracking
+	local    *ttlcache.Cache
+	ttl      time.Duration
+	// epoch advances on every invalidation, so a value read while one
+	// arrived isn't stored locally
+	epoch atomic.Uint64
+
+	closeOnce sync.Once
+}
+
+// withClientSideCache wraps cache with the local layer fed by tracking,
+// returning cache unchanged when tracking is nil.
+func withClientSideCache[T any](cache Cache[T], tracking *serverTracking, config *ClientSideCacheConfig) Cache[T] {
+	if tracking == nil {
+		return cache
+	}
+
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	ttl := config.MaxStaleness
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	local := ttlcache.NewCache()
+	local.SkipTTLExtensionOnHit(true)
+	local.SetCacheSizeLimit(maxEntries)
+
+	c := &clientSideCache[T]{next: cache, tracking: tracking, local: local, ttl: ttl}
+	data, _ := redisClientOf(cache)
+	tracking.mu.Lock()
+	tracking.evict = c.evict
+	tracking.data = data
+	tracking.mu.Unlock()
+	return c
+}
+
+func (c *clientSideCache[T]) evict(keys []string) {
+	c.epoch.Add(1)
+	if keys == nil {
+		_ = c.local.Purge()
+		return
+	}
+	for _, key := range keys {
+		_ = c.local.Remove(key)
+	}
+}
+
+func (c *clientSideCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *clientSideCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if cached, err := c.local.Get(key); err == nil {
+		if value, ok := cached.(T); ok {
+			return value, true, nil
+		}
+	}
+
+	epoch := c.epoch.Load()
+	value, found, err := GetWithError(ctx, c.next, key)
+	if found && err == nil && c.epoch.Load() == epoch {
+		// The read registered key for tracking on its connection
+		_ = c.local.SetWithTTL(key, value, c.ttl)
+	}
+	return value, found, err
+}
+
+func (c *clientSideCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := c.local.Get(key); err == nil {
+		return true, nil
+	}
+	return Exists(ctx, c.next, key)
+}
+
+// TTL reports the lifetime left in Redis/Valkey, which bounds the local copy.
+func (c *clientSideCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *clientSideCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	err := Expire(ctx, c.next, key, ttl)
+	_ = c.local.Remove(key)
+	return err
+}
+
+func (c *clientSideCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	_ = c.local.Remove(key)
+	return stored, err
+}
+
+func (c *clientSideCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	_ = c.local.Remove(key)
+	return swapped, err
+}
+
+func (c *clientSideCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetDel(ctx, c.next, key)
+	_ = c.local.Remove(key)
+	return value, found, err
+}
+
+// Set writes to the server only: a value not read back isn't tracked, so it
+// mustn't be served locally.
+func (c *clientSideCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	err := c.next.Set(ctx, key, value, ttl)
+	_ = c.local.Remove(key)
+	return err
+}
+
+func (c *clientSideCache[T]) Delete(ctx context.Context, key string) error {
+	err := c.next.Delete(ctx, key)
+	_ = c.local.Remove(key)
+	return err
+}
+
+func (c *clientSideCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	err := deleteMulti(ctx, c.next, keys)
+	for _, key := range keys {
+		_ = c.local.Remove(key)
+	}
+	return err
+}
+
+func (c *clientSideCache[T]) Clear(ctx context.Context) error {
+	err := Clear(ctx, c.next)
+	// The server reports a flush too, but don't serve local values meanwhile
+	c.evict(nil)
+	return err
+}
+
+func (c *clientSideCache[T]) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = errors.Join(c.tracking.close(), c.local.Close())
+	})
+	return errors.Join(err, c.next.Close())
+}
+
+func (c *clientSideCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *clientSideCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
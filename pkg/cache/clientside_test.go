@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// readCountingCache counts the reads reaching the wrapped cache.
+type readCountingCache[T any] struct {
+	Cache[T]
+	reads int
+}
+
+func (c *readCountingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	c.reads++
+	return c.Cache.Get(ctx, key)
+}
+
+func TestClientSideCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	backend := &readCountingCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	tracking := &serverTracking{}
+	cache := withClientSideCache[TestUser](backend, tracking, &ClientSideCacheConfig{})
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "b", TestUser{ID: "2"}, time.Minute)
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	if backend.reads != 2 {
+		t.Fatalf("Expected repeated reads served locally, got %d backend reads", backend.reads)
+	}
+
+	// Test server invalidations evict the listed keys only
+	tracking.invalidate([]string{"a"})
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	if backend.reads != 3 {
+		t.Errorf("Expected only a to be read again, got %d backend reads", backend.reads)
+	}
+
+	// Test a flush evicts everything
+	tracking.invalidate(nil)
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	if backend.reads != 5 {
+		t.Errorf("Expected both keys read again after a flush, got %d backend reads", backend.reads)
+	}
+
+	// Test local writes aren't served locally before being read back
+	_ = cache.Set(ctx, "a", TestUser{ID: "3"}, time.Minute)
+	if user, _ := cache.Get(ctx, "a"); user.ID != "3" || backend.reads != 6 {
+		t.Errorf("Expected the new value from the backend, got %+v after %d reads", user, backend.reads)
+	}
+}
+
+func TestClientSideCacheRequiresOwnClient(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	defer client.Close()
+
+	_, err := NewDistributedGeneric[TestUser](&DistributedConfig{
+		Client:          client,
+		ClientSideCache: &ClientSideCacheConfig{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "ClientSideCache") {
+		t.Errorf("Expected ClientSideCache to reject a shared client, got: %v", err)
+	}
+}
+
+func TestClientSideCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	local, err := NewDistributedGeneric[TestUser](&DistributedConfig{
+		Addr:            addr,
+		ClientSideCache: &ClientSideCacheConfig{MaxStaleness: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer local.Close()
+
+	other := redis.NewClient(&redis.Options{Addr: addr})
+	defer other.Close()
+
+	_ = local.Set(ctx, "user:1", TestUser{ID: "1", Name: "old"}, time.Hour)
+	if user, found := local.Get(ctx, "user:1"); !found || user.Name != "old" {
+		t.Fatalf("Expected old, got %+v (found=%v)", user, found)
+	}
+
+	// Test another client's write evicts the local copy despite MaxStaleness
+	if err := other.Set(ctx, "user:1", `{"id":"1","name":"new"}`, time.Hour).Err(); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		user, _ := local.Get(ctx, "user:1")
+		return user.Name == "new"
+	})
+
+	_ = other.Del(ctx, "user:1")
+	waitFor(t, func() bool {
+		_, found := local.Get(ctx, "user:1")
+		return !found
+	})
+}
+
+func TestClientSideCacheRetracksAfterListenerReconnect(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	local, err := NewDistributedGeneric[TestUser](&DistributedConfig{
+		Addr:            addr,
+		ClientSideCache: &ClientSideCacheConfig{MaxStaleness: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer local.Close()
+	tracking := local.(*clientSideCache[TestUser]).tracking
+
+	other := redis.NewClient(&redis.Options{Addr: addr})
+	defer other.Close()
+
+	_ = local.Set(ctx, "user:1", TestUser{ID: "1", Name: "old"}, time.Hour)
+	local.Get(ctx, "user:1")
+
+	// Test data connections follow the listener to its new connection
+	previous := tracking.redirect.Load()
+	if err := other.ClientKillByFilter(ctx, "ID", strconv.FormatInt(previous, 10)).Err(); err != nil {
+		t.Fatalf("Killing the listener failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		clients, err := other.ClientList(ctx).Result()
+		return err == nil && tracking.redirect.Load() != previous &&
+			!strings.Contains(clients, "redir="+strconv.FormatInt(previous, 10)+" ")
+	})
+
+	if user, _ := local.Get(ctx, "user:1"); user.Name != "old" {
+		t.Fatalf("Expected old, got %+v", user)
+	}
+	if err := other.Set(ctx, "user:1", `{"id":"1","name":"new"}`, time.Hour).Err(); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		user, _ := local.Get(ctx, "user:1")
+		return user.Name == "new"
+	})
+}
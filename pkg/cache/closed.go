@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/jellydator/ttlcache/v2"
+	"github.com/redis/go-redis/v9"
+	berrors "go.etcd.io/bbolt/errors"
+)
+
+// ErrClosed is returned by operations issued on a cache after Close.
+// Get reports such reads as misses; GetWithError returns ErrClosed.
+var ErrClosed = errors.New("cache is closed")
+
+// closeGuard makes Close idempotent and lets operations detect a closed cache.
+type closeGuard struct {
+	closed atomic.Bool
+}
+
+// close marks the cache closed. It returns true only for the first call, which
+// is the one that must release resources.
+func (g *closeGuard) close() bool {
+	return g.closed.CompareAndSwap(false, true)
+}
+
+func (g *closeGuard) isClosed() bool {
+	return g.closed.Load()
+}
+
+// closedErr maps the backends' own closed errors, returned by operations that
+// raced with Close, to ErrClosed.
+func closedErr(err error) error {
+	if errors.Is(err, redis.ErrClosed) || errors.Is(err, ttlcache.ErrClosed) ||
+		errors.Is(err, berrors.ErrDatabaseNotOpen) {
+		return ErrClosed
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOperationsAfterClose(t *testing.T) {
+	ctx := context.Background()
+
+	caches := map[string]Cache[TestUser]{
+		"Memory":      NewMemory[TestUser](nil),
+		"NoOp":        NewNoOp[TestUser](),
+		"Distributed": newUnreachableDistributedCache(t, DegradedPolicy{}),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			// Test Close is idempotent
+			for i := 0; i < 3; i++ {
+				if err := cache.Close(); err != nil {
+					t.Fatalf("Close #%d failed: %v", i+1, err)
+				}
+			}
+
+			if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute); !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected ErrClosed from Set, got: %v", err)
+			}
+			if err := cache.Delete(ctx, "key1"); !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected ErrClosed from Delete, got: %v", err)
+			}
+			if _, found := cache.Get(ctx, "key1"); found {
+				t.Error("Expected miss from Get")
+			}
+		})
+	}
+
+	// Test error-aware reads and batch deletes report ErrClosed
+	for _, name := range []string{"Memory", "Distributed"} {
+		cache := caches[name]
+		if _, _, err := GetWithError(ctx, cache, "key1"); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: expected ErrClosed from GetWithError, got: %v", name, err)
+		}
+		if err := deleteMulti(ctx, cache, []string{"key1"}); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: expected ErrClosed from DeleteMulti, got: %v", name, err)
+		}
+	}
+}
+
+func TestCloseConcurrentWithOperations(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+				if err != nil && !errors.Is(err, ErrClosed) {
+					t.Errorf("Expected nil or ErrClosed, got: %v", err)
+					return
+				}
+				cache.Get(ctx, "key1")
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.Close(); err != nil {
+				t.Errorf("Close failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
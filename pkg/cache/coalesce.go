@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// coalescingCache collects Deletes issued within a window into one backend call.
+type coalescingCache[T any] struct {
+	next   Cache[T]
+	window time.Duration
+
+	mu    sync.Mutex
+	batch *deleteBatch
+}
+
+// deleteBatch is a set of keys removed together once its window closes.
+type deleteBatch struct {
+	keys map[string]struct{}
+	done chan struct{}
+	err  error
+}
+
+// NewCoalescedDeletes wraps a cache so that Deletes issued within window of the
+// first one are sent to the backend as a single DeleteMulti, with repeated keys
+// removed once. This reduces invalidation amplification from chatty write
+// paths. Delete still blocks until its batch is applied and returns the
+// batch's error, so callers keep read-your-deletes semantics at the cost of up
+// to window of added latency.
+func NewCoalescedDeletes[T any](cache Cache[T], window time.Duration) Cache[T] {
+	return &coalescingCache[T]{
+		next:   cache,
+		window: window,
+	}
+}
+
+func (c *coalescingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *coalescingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *coalescingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *coalescingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *coalescingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *coalescingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *coalescingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	if stored {
+		c.keep(key)
+	}
+	return stored, err
+}
+
+func (c *coalescingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	if swapped {
+		c.keep(key)
+	}
+	return swapped, err
+}
+
+func (c *coalescingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *coalescingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		// The pending delete still has to remove the old value
+		return err
+	}
+	c.keep(key)
+	return nil
+}
+
+// keep drops key from the pending batch after a write stored it: a write after
+// a pending delete wins.
+func (c *coalescingCache[T]) keep(key string) {
+	c.mu.Lock()
+	if c.batch != nil {
+		delete(c.batch.keys, key)
+	}
+	c.mu.Unlock()
+}
+
+func (c *coalescingCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *coalescingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	b := c.batch
+	if b == nil {
+		b = &deleteBatch{
+			keys: make(map[string]struct{}),
+			done: make(chan struct{}),
+		}
+		c.batch = b
+		time.AfterFunc(c.window, func() { c.flush(b) })
+	}
+	for _, key := range keys {
+		b.keys[key] = struct{}{}
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush applies b unless it has already been applied.
+func (c *coalescingCache[T]) flush(b *deleteBatch) {
+	c.mu.Lock()
+	if c.batch != b {
+		c.mu.Unlock()
+		return
+	}
+	c.batch = nil
+	keys := make([]string, 0, len(b.keys))
+	for key := range b.keys {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	if len(keys) > 0 {
+		// Not tied to any one caller's context - the batch serves them all
+		b.err = deleteMulti(context.Background(), c.next, keys)
+	}
+	close(b.done)
+}
+
+func (c *coalescingCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *coalescingCache[T]) Close() error {
+	c.mu.Lock()
+	b := c.batch
+	c.mu.Unlock()
+
+	if b != nil {
+		c.flush(b)
+	}
+	return c.next.Close()
+}
+
+func (c *coalescingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *coalescingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecordingCache records the key batches passed to DeleteMulti.
+type batchRecordingCache[T any] struct {
+	Cache[T]
+
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (c *batchRecordingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	c.batches = append(c.batches, sorted)
+	c.mu.Unlock()
+	return deleteMulti(ctx, c.Cache, keys)
+}
+
+func TestCoalescedDeletes(t *testing.T) {
+	ctx := context.Background()
+	inner := &batchRecordingCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	cache := NewCoalescedDeletes[TestUser](inner, 20*time.Millisecond)
+	defer cache.Close()
+
+	for _, key := range []string{"key1", "key2"} {
+		_ = cache.Set(ctx, key, TestUser{ID: "123"}, time.Minute)
+	}
+
+	// Test a burst of deletes becomes one backend call
+	var wg sync.WaitGroup
+	for _, key := range []string{"key1", "key2", "key1", "key1"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.Delete(ctx, key); err != nil {
+				t.Errorf("Delete failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(inner.batches) != 1 || !slices.Equal(inner.batches[0], []string{"key1", "key2"}) {
+		t.Errorf("Expected one batch of [key1 key2], got %v", inner.batches)
+	}
+
+	// Test Delete returns only once the batch is applied
+	for _, key := range []string{"key1", "key2"} {
+		if _, found := cache.Get(ctx, key); found {
+			t.Errorf("Expected %s to be deleted", key)
+		}
+	}
+}
+
+func TestCoalescedDeletesSetWins(t *testing.T) {
+	ctx := context.Background()
+	cache := NewCoalescedDeletes(NewMemory[TestUser](nil), 50*time.Millisecond)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "old"}, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = cache.Delete(ctx, "key1")
+	}()
+
+	// Test a write issued while the delete is pending survives the batch
+	time.Sleep(10 * time.Millisecond)
+	_ = cache.Set(ctx, "key1", TestUser{ID: "new"}, time.Minute)
+	<-done
+
+	if user, found := cache.Get(ctx, "key1"); !found || user.ID != "new" {
+		t.Errorf("Expected the later write to survive, got %v, %v", user, found)
+	}
+}
+
+func TestCoalescedDeletesFailedSetKeepsDelete(t *testing.T) {
+	ctx := context.Background()
+	inner := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	cache := NewCoalescedDeletes[TestUser](inner, 50*time.Millisecond)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "old"}, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = cache.Delete(ctx, "key1")
+	}()
+
+	// Test a write failing while the delete is pending doesn't cancel it
+	time.Sleep(10 * time.Millisecond)
+	inner.down.Store(true)
+	if err := cache.Set(ctx, "key1", TestUser{ID: "new"}, time.Minute); err == nil {
+		t.Fatal("Expected the write to fail")
+	}
+	inner.down.Store(false)
+	<-done
+
+	if user, found := cache.Get(ctx, "key1"); found {
+		t.Errorf("Expected the pending delete to remove the old value, got %v", user)
+	}
+}
+
+func TestCoalescedDeletesCloseFlushes(t *testing.T) {
+	ctx := context.Background()
+	inner := &batchRecordingCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	cache := NewCoalescedDeletes[TestUser](inner, time.Hour)
+
+	done := make(chan error)
+	go func() {
+		done <- cache.Delete(ctx, "key1")
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	_ = cache.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if len(inner.batches) != 1 {
+		t.Errorf("Expected Close to flush the pending batch, got %v", inner.batches)
+	}
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// CompareAndSwap stores new under key with ttl if the key still holds old,
+// reporting whether it was stored, for optimistic-concurrency updates of
+// cached aggregates: read a value, compute its update and retry from the read
+// when another writer got there first:
+//
+//	for {
+//		old, found, err := cache.GetWithError(ctx, carts, cartID)
+//		// ... handle err and !found
+//		swapped, err := cache.CompareAndSwap(ctx, carts, cartID, old, old.With(item), time.Hour)
+//		if err != nil || swapped {
+//			break
+//		}
+//	}
+//
+// Missing keys are never swapped. Caches storing serialized values compare
+// the serialized forms, so old should be the value read rather than an
+// equivalent one built by the caller; memory caches compare with proto.Equal
+// for proto messages and reflect.DeepEqual otherwise.
+//
+// Caches that don't implement Swapper are read with GetWithError and then
+// written with Set, so a write landing in between may be overwritten; only
+// rely on CompareAndSwap for concurrency control with caches that implement
+// it.
+func CompareAndSwap[T any](ctx context.Context, cache Cache[T], key string, old, new T, ttl time.Duration) (bool, error) {
+	if s, ok := cache.(Swapper[T]); ok {
+		return s.CompareAndSwap(ctx, key, old, new, ttl)
+	}
+
+	current, found, err := GetWithError(ctx, cache, key)
+	if err != nil || !found || !valuesEqual(current, old) {
+		return false, err
+	}
+	if err := cache.Set(ctx, key, new, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
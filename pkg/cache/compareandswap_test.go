@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counter is an aggregate updated with optimistic concurrency in tests.
+type counter struct {
+	N int
+}
+
+func TestCompareAndSwapMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			first := TestUser{ID: "1", Name: "Ann"}
+			second := TestUser{ID: "2", Name: "Bob"}
+			_ = cache.Set(ctx, "a", first, time.Minute)
+			_ = flushAll(ctx, cache)
+
+			if swapped, err := CompareAndSwap(ctx, cache, "a", second, first, time.Minute); err != nil || swapped {
+				t.Errorf("Expected CompareAndSwap to fail for a stale value, got %v, %v", swapped, err)
+			}
+			if swapped, err := CompareAndSwap(ctx, cache, "a", first, second, time.Minute); err != nil || !swapped {
+				t.Fatalf("Expected CompareAndSwap to swap a, got %v, %v", swapped, err)
+			}
+			_ = flushAll(ctx, cache)
+			if got, found := cache.Get(ctx, "a"); !found || got != second {
+				t.Errorf("Expected %+v, got %+v (found=%v)", second, got, found)
+			}
+
+			if swapped, err := CompareAndSwap(ctx, cache, "missing", first, second, time.Minute); err != nil || swapped {
+				t.Errorf("Expected CompareAndSwap to skip a missing key, got %v, %v", swapped, err)
+			}
+		})
+	}
+}
+
+func TestCompareAndSwapConcurrentIncrements(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[counter](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+			_ = cache.Set(ctx, "count", counter{}, 0)
+
+			var wg sync.WaitGroup
+			for range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						old, _, err := GetWithError(ctx, cache, "count")
+						if err != nil {
+							t.Errorf("Get failed: %v", err)
+							return
+						}
+						swapped, err := CompareAndSwap(ctx, cache, "count", old, counter{N: old.N + 1}, 0)
+						if err != nil {
+							t.Errorf("CompareAndSwap failed: %v", err)
+							return
+						}
+						if swapped {
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got, _ := cache.Get(ctx, "count"); got.N != 20 {
+				t.Errorf("Expected no lost updates, got %d", got.N)
+			}
+		})
+	}
+}
+
+func TestCompareAndSwapFallsBackToGet(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	backend := &unclearableCache[TestUser]{Cache: inner}
+	defer backend.Close()
+
+	first := TestUser{ID: "1"}
+	_ = backend.Set(ctx, "a", first, time.Minute)
+	if swapped, err := CompareAndSwap[TestUser](ctx, backend, "a", TestUser{ID: "0"}, TestUser{ID: "2"}, time.Minute); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to fail for a stale value, got %v, %v", swapped, err)
+	}
+	if swapped, err := CompareAndSwap[TestUser](ctx, backend, "a", first, TestUser{ID: "2"}, time.Minute); err != nil || !swapped {
+		t.Errorf("Expected CompareAndSwap to swap a, got %v, %v", swapped, err)
+	}
+	if got, _ := inner.Get(ctx, "a"); got.ID != "2" {
+		t.Errorf("Expected the new value, got %+v", got)
+	}
+}
+
+func TestCompareAndSwapTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	first := TestUser{ID: "1"}
+	_ = cache.Set(ctx, "a", first, time.Hour)
+
+	// Another instance changed L2, so this instance's L1 copy is stale
+	_ = l2.Set(ctx, "a", TestUser{ID: "other"}, time.Hour)
+	if swapped, err := CompareAndSwap(ctx, cache, "a", first, TestUser{ID: "2"}, time.Hour); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to fail against L2, got %v, %v", swapped, err)
+	}
+	if _, found := l1.Get(ctx, "a"); found {
+		t.Error("Expected the stale L1 copy to be removed")
+	}
+
+	if swapped, err := CompareAndSwap(ctx, cache, "a", TestUser{ID: "other"}, TestUser{ID: "2"}, time.Hour); err != nil || !swapped {
+		t.Fatalf("Expected CompareAndSwap to swap a, got %v, %v", swapped, err)
+	}
+	if got, _ := l1.Get(ctx, "a"); got.ID != "2" {
+		t.Errorf("Expected the L1 copy to be updated, got %+v", got)
+	}
+	if ttl, _, _ := TTL(ctx, l1, "a"); ttl > time.Minute {
+		t.Errorf("Expected the L1 copy to be capped, got %v", ttl)
+	}
+}
+
+func TestCompareAndSwapThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	cache := WithDefaultTTL(WithPrefix(backend, "p:"), time.Hour)
+	_ = cache.Set(ctx, "a", TestUser{ID: "1"}, time.Minute)
+	if swapped, err := CompareAndSwap(ctx, cache, "a", TestUser{ID: "1"}, TestUser{ID: "2"}, 0); err != nil || !swapped {
+		t.Fatalf("Expected CompareAndSwap to swap a, got %v, %v", swapped, err)
+	}
+	if ttl, _, _ := TTL(ctx, backend, "p:a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected the default TTL on the prefixed key, got %v", ttl)
+	}
+}
+
+func TestCompareAndSwapDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	first := TestUser{ID: "1", Name: "Ann"}
+	_ = cache.Set(ctx, "a", first, time.Minute)
+	if swapped, _ := CompareAndSwap(ctx, cache, "a", TestUser{ID: "0"}, TestUser{ID: "2"}, time.Minute); swapped {
+		t.Error("Expected CompareAndSwap to fail for a stale value")
+	}
+	if swapped, err := CompareAndSwap(ctx, cache, "a", first, TestUser{ID: "2"}, time.Hour); err != nil || !swapped {
+		t.Fatalf("Expected CompareAndSwap to swap a, got %v, %v", swapped, err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected the new TTL, got %v", ttl)
+	}
+	if got, _ := cache.Get(ctx, "a"); got.ID != "2" {
+		t.Errorf("Expected the new value, got %+v", got)
+	}
+}
+
+func TestCompareAndSwapDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	for _, rejectOlder := range []bool{false, true} {
+		cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr, RejectOlderWrites: rejectOlder})
+		if err != nil {
+			t.Fatalf("Failed to create cache: %v", err)
+		}
+
+		first := TestUser{ID: "1"}
+		_ = cache.Set(ctx, "a", first, time.Minute)
+		if swapped, err := CompareAndSwap(ctx, cache, "a", TestUser{ID: "0"}, TestUser{ID: "2"}, time.Minute); err != nil || swapped {
+			t.Errorf("Expected CompareAndSwap to fail for a stale value, got %v, %v", swapped, err)
+		}
+		// Stamped values are compared without their stamp
+		if swapped, err := CompareAndSwap(ctx, cache, "a", first, TestUser{ID: "2"}, time.Hour); err != nil || !swapped {
+			t.Errorf("Expected CompareAndSwap to swap a (rejectOlder=%v), got %v, %v", rejectOlder, swapped, err)
+		}
+		if got, _ := cache.Get(ctx, "a"); got.ID != "2" {
+			t.Errorf("Expected the new value, got %+v", got)
+		}
+		if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= 59*time.Minute {
+			t.Errorf("Expected the new TTL, got %v", ttl)
+		}
+		_ = cache.Delete(ctx, "a")
+		_ = cache.Close()
+	}
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Headers of the compressions added by CompressedSerializer, next to the raw
+// and zstd headers shared with ZstdSerializer.
+const (
+	compressedHeaderGzip   byte = 'g'
+	compressedHeaderSnappy byte = 's'
+)
+
+// CompressedSerializer compresses the output of another serializer with gzip,
+// snappy or zstd. Every payload carries a header naming its compression, so
+// readers decode entries written with any algorithm (including by
+// ZstdSerializer without a dictionary) and the algorithm can be changed
+// without flushing the cache.
+type CompressedSerializer struct {
+	inner Serializer
+	algo  CompressionType
+	// minSize is the payload size below which compression is not attempted,
+	// set from DistributedConfig.CompressionMinSize
+	minSize int
+
+	gzipWriters sync.Pool
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+}
+
+// NewCompressedSerializer creates a serializer that compresses the output of
+// inner with algo.
+func NewCompressedSerializer(inner Serializer, algo CompressionType) (*CompressedSerializer, error) {
+	if inner == nil {
+		return nil, errors.New("inner serializer cannot be nil")
+	}
+	switch algo {
+	case CompressionGzip, CompressionSnappy, CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unknown compression type: %q", algo)
+	}
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompressedSerializer{
+		inner:       inner,
+		algo:        algo,
+		zstdEncoder: encoder,
+		zstdDecoder: decoder,
+	}, nil
+}
+
+// Serialize converts a value to bytes with the inner serializer and compresses
+// them. Payloads that don't shrink are stored
+// uncompressed behind a header marking them as raw.
+func (c *CompressedSerializer) Serialize(v interface{}) ([]byte, error) {
+	data, err := c.inner.Serialize(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) >= c.minSize {
+		out, err := c.compress(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(out) <= len(data) {
+			return out, nil
+		}
+	}
+
+	out := make([]byte, 1+len(data))
+	out[0] = compressedHeaderRaw
+	copy(out[1:], data)
+	return out, nil
+}
+
+// compress returns data compressed with the configured algorithm, header included.
+func (c *CompressedSerializer) compress(data []byte) ([]byte, error) {
+	switch c.algo {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		buf.WriteByte(compressedHeaderGzip)
+		w, _ := c.gzipWriters.Get().(*gzip.Writer)
+		if w == nil {
+			w = gzip.NewWriter(&buf)
+		} else {
+			w.Reset(&buf)
+		}
+		defer c.gzipWriters.Put(w)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	case CompressionSnappy:
+		out := make([]byte, 1+snappy.MaxEncodedLen(len(data)))
+		out[0] = compressedHeaderSnappy
+		return out[:1+len(snappy.Encode(out[1:], data))], nil
+
+	default:
+		out := make([]byte, 1, len(data)/2+1)
+		out[0] = compressedHeaderZstd
+		return c.zstdEncoder.EncodeAll(data, out), nil
+	}
+}
+
+// Deserialize decompresses bytes and converts them back to a value with the inner serializer.
+func (c *CompressedSerializer) Deserialize(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return errors.New("data is empty")
+	}
+
+	var raw []byte
+	var err error
+	switch data[0] {
+	case compressedHeaderRaw:
+		raw = data[1:]
+	case compressedHeaderGzip:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(data[1:])); err != nil {
+			return err
+		}
+		raw, err = io.ReadAll(r)
+	case compressedHeaderSnappy:
+		raw, err = snappy.Decode(nil, data[1:])
+	case compressedHeaderZstd:
+		raw, err = c.zstdDecoder.DecodeAll(data[1:], nil)
+	default:
+		return errors.New("data is not compressed")
+	}
+	if err != nil {
+		return err
+	}
+	return c.inner.Deserialize(raw, v)
+}
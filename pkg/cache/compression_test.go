@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCompressedSerializer(t *testing.T) {
+	user := TestUser{ID: "123", Name: strings.Repeat("John ", 100)}
+	headers := map[CompressionType]byte{
+		CompressionGzip:   compressedHeaderGzip,
+		CompressionSnappy: compressedHeaderSnappy,
+		CompressionZstd:   compressedHeaderZstd,
+	}
+
+	for algo, header := range headers {
+		t.Run(string(algo), func(t *testing.T) {
+			serializer, err := NewCompressedSerializer(&JSONSerializer{}, algo)
+			if err != nil {
+				t.Fatalf("Failed to create serializer: %v", err)
+			}
+
+			data, err := serializer.Serialize(user)
+			if err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+			if data[0] != header {
+				t.Errorf("Expected header %q, got %q", header, data[0])
+			}
+			if len(data) >= len(user.Name) {
+				t.Errorf("Expected compressed payload, got %d bytes", len(data))
+			}
+
+			var retrieved TestUser
+			if err := serializer.Deserialize(data, &retrieved); err != nil {
+				t.Fatalf("Deserialize failed: %v", err)
+			}
+			if retrieved != user {
+				t.Errorf("Expected %+v, got %+v", user, retrieved)
+			}
+		})
+	}
+}
+
+func TestCompressedSerializerReadsAnyAlgorithm(t *testing.T) {
+	user := TestUser{ID: "123", Name: strings.Repeat("John ", 100)}
+	reader, _ := NewCompressedSerializer(&JSONSerializer{}, CompressionSnappy)
+
+	// Test entries written with another algorithm or by ZstdSerializer stay readable
+	gzipWriter, _ := NewCompressedSerializer(&JSONSerializer{}, CompressionGzip)
+	zstdWriter, _ := NewZstdSerializer(&JSONSerializer{}, nil)
+	for _, writer := range []Serializer{gzipWriter, zstdWriter} {
+		data, err := writer.Serialize(user)
+		if err != nil {
+			t.Fatalf("Serialize failed: %v", err)
+		}
+		var retrieved TestUser
+		if err := reader.Deserialize(data, &retrieved); err != nil {
+			t.Fatalf("Deserialize of %T output failed: %v", writer, err)
+		}
+		if retrieved != user {
+			t.Errorf("Expected %+v, got %+v", user, retrieved)
+		}
+	}
+
+	// Test uncompressed input is rejected
+	var retrieved TestUser
+	if err := reader.Deserialize([]byte(`{"id":"123"}`), &retrieved); err == nil {
+		t.Error("Expected error for uncompressed input")
+	}
+}
+
+func TestCompressedSerializerStoresRaw(t *testing.T) {
+	serializer, _ := NewCompressedSerializer(&JSONSerializer{}, CompressionGzip)
+
+	// Test payloads that would grow are stored raw
+	data, err := serializer.Serialize(TestUser{ID: "1"})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if data[0] != compressedHeaderRaw {
+		t.Errorf("Expected raw header for a tiny payload, got %q", data[0])
+	}
+
+	// Test payloads below the minimum size are not compressed
+	serializer.minSize = 1 << 20
+	data, _ = serializer.Serialize(TestUser{Name: strings.Repeat("John ", 100)})
+	if data[0] != compressedHeaderRaw {
+		t.Errorf("Expected raw header below the minimum size, got %q", data[0])
+	}
+
+	var retrieved TestUser
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved.Name != strings.Repeat("John ", 100) {
+		t.Errorf("Unexpected value: %+v", retrieved)
+	}
+}
+
+func TestCompressedSerializerInvalid(t *testing.T) {
+	if _, err := NewCompressedSerializer(nil, CompressionGzip); err == nil {
+		t.Error("Expected error for nil inner serializer")
+	}
+	if _, err := NewCompressedSerializer(&JSONSerializer{}, CompressionType("lz4")); err == nil {
+		t.Error("Expected error for unknown compression type")
+	}
+}
+
+func TestCompressedProtobuf(t *testing.T) {
+	serializer, err := NewCompressedSerializer(protobufSerializer{}, CompressionZstd)
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+
+	msg := wrapperspb.String(strings.Repeat("John ", 100))
+	data, err := serializer.Serialize(msg)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	// Test the message pointer is allocated as the generic cache requires
+	var retrieved *wrapperspb.StringValue
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if !proto.Equal(retrieved, msg) {
+		t.Errorf("Expected %v, got %v", msg, retrieved)
+	}
+
+	if !usesGenericProtoPath(&DistributedConfig{Compression: CompressionGzip}) {
+		t.Error("Expected compressed proto caches to use the generic implementation")
+	}
+}
+
+func TestCompressedDistributedCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := New[*wrapperspb.StringValue](&Config{
+		Type:        TypeDistributed,
+		Distributed: &DistributedConfig{Addr: addr, Compression: CompressionSnappy},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	value := strings.Repeat("hello ", 100)
+	if err := cache.Set(ctx, "key1", wrapperspb.String(value), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, found := cache.Get(ctx, "key1"); !found || got.GetValue() != value {
+		t.Errorf("Expected the stored value, got %v (found=%v)", got, found)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	if raw, _ := client.Get(ctx, "key1").Bytes(); len(raw) == 0 || raw[0] != compressedHeaderSnappy || len(raw) >= len(value) {
+		t.Errorf("Expected a snappy-compressed entry, got %d bytes", len(raw))
+	}
+}
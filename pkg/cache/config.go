@@ -1,9 +1,13 @@
 package cache
 
 import (
+	"database/sql"
+	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config holds common configuration for all cache types.
@@ -11,11 +15,143 @@ type Config struct {
 	// Type specifies which cache implementation to use.
 	Type CacheType
 
+	// Name identifies the cache in telemetry (optional, e.g. "users")
+	Name string
+
+	// KeyPrefix is prepended to every key before it reaches the backend, e.g.
+	// "billing:", so services sharing one backend can't collide. MaxKeyLength
+	// applies to keys without the prefix (optional)
+	KeyPrefix string
+
+	// MeterProvider receives lifecycle metrics for caches created by New
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+
 	// Memory-specific configuration (only used when Type is TypeMemory)
 	Memory *MemoryConfig
 
 	// Distributed-specific configuration (only used when Type is TypeDistributed)
 	Distributed *DistributedConfig
+
+	// Tiered-specific configuration (only used when Type is TypeTiered, which
+	// also uses Memory for L1 and Distributed for L2)
+	Tiered *TieredConfig
+
+	// Disk-specific configuration (only used when Type is TypeDisk)
+	Disk *DiskConfig
+
+	// SQL-specific configuration (only used when Type is TypeSQL)
+	SQL *SQLConfig
+
+	// DynamoDB-specific configuration (only used when Type is TypeDynamoDB)
+	DynamoDB *DynamoDBConfig
+
+	// ForceSerialization overrides how backends that store bytes (distributed,
+	// tiered L2, disk, SQL, DynamoDB and the FreeCache engine) serialize
+	// values, instead of choosing proto.Marshal for proto message pointers
+	// and JSON otherwise: e.g. SerializationJSON keeps proto messages
+	// readable, SerializationProtobuf requires them. A Serializer set in a
+	// backend's configuration takes precedence (optional)
+	ForceSerialization SerializationType
+
+	// DefaultTTL is applied to Sets and GetOrSet loads with a TTL of 0, which
+	// otherwise store entries without expiry (default: 0, no default)
+	DefaultTTL time.Duration
+
+	// ReadBudget bounds every read: reads slower than this are answered as
+	// misses (default: 0, unbounded)
+	ReadBudget time.Duration
+
+	// Hedging issues a second read when a read is slower than a delay,
+	// bounded by a budget (optional)
+	Hedging *HedgingConfig
+
+	// Retry retries failed operations with exponential backoff; its Logger
+	// defaults to Logger (optional)
+	Retry *RetryConfig
+
+	// Shedding caps the operations in flight or per second against the
+	// backend, answering reads beyond the limit as misses (optional)
+	Shedding *SheddingConfig
+
+	// Quota accounts the bytes written against a per-namespace budget shared
+	// by every instance, for caches on a shared Redis/Valkey (optional)
+	Quota *QuotaConfig
+
+	// TraceDecisions annotates the caller's span with a "cache.decision" event
+	// for every read (default: false)
+	TraceDecisions bool
+
+	// TraceOperations runs every operation in a "cache.<Method>" span with
+	// the cache name, backend, serializer, key hash and hit/miss, whatever
+	// the backend (default: false)
+	TraceOperations bool
+
+	// TracerProvider creates the spans of TraceOperations
+	// (default: the global OpenTelemetry tracer provider)
+	TracerProvider trace.TracerProvider
+
+	// Logger logs failed operations, including the read failures backends
+	// answer as misses, and operations slower than SlowOperationThreshold;
+	// it is passed on to Distributed unless that sets its own (optional)
+	Logger *slog.Logger
+
+	// SlowOperationThreshold is the duration above which Logger logs an
+	// operation as slow (default: 0, slow operations aren't logged)
+	SlowOperationThreshold time.Duration
+
+	// Disabled turns caching off without changing code, e.g. per
+	// environment: no backend is created, every read misses and writes are
+	// dropped. A warning is logged on creation, and reads are counted by the
+	// outcome they would have had in the "cache.disabled.reads" metric
+	// ("would_hit" or "would_miss"), by remembering the keys of the last
+	// 10000 writes without their values (default: false)
+	Disabled bool
+
+	// DryRun makes Set and Delete log what they would do without touching
+	// the backend, while Get keeps reading normally (default: false)
+	DryRun bool
+
+	// WriteDampening skips Sets of a key that was already written with an
+	// equal value and TTL within this interval (default: 0, disabled)
+	WriteDampening time.Duration
+
+	// DeleteCoalescing sends Deletes issued within this window as a single
+	// backend call (default: 0, disabled)
+	DeleteCoalescing time.Duration
+
+	// SkipWriteIfEqual skips Sets whose value is unchanged since this process
+	// last wrote the key (default: false)
+	SkipWriteIfEqual bool
+
+	// KeyFromContext derives the effective key from the operation's context,
+	// applied to every key regardless of backend (optional)
+	KeyFromContext KeyFromContextFunc
+
+	// Loading makes GetOrSet deduplicate concurrent loads of the same key and
+	// optionally bound concurrent loads (optional)
+	Loading *LoadingConfig
+
+	// RefreshAhead reloads hot entries loaded through GetOrSet in the
+	// background before they expire (optional)
+	RefreshAhead *RefreshAheadConfig
+
+	// MaxKeyLength is the maximum key length in bytes, checked on every
+	// operation (default: 0, unlimited)
+	MaxKeyLength int
+
+	// LongKeys decides what happens to keys over MaxKeyLength
+	// (default: KeyLengthReject)
+	LongKeys KeyLengthPolicy
+
+	// NilValues decides how nil values (e.g. nil pointers) are treated
+	// (default: NilValueStore)
+	NilValues NilValuePolicy
+
+	// EmptyValues decides how Sets of zero values (e.g. "", 0, a zero struct)
+	// are treated; NilValues takes precedence for nil values
+	// (default: EmptyValueStore)
+	EmptyValues EmptyValuePolicy
 }
 
 // MemoryConfig holds configuration for in-memory cache.
@@ -23,6 +159,134 @@ type MemoryConfig struct {
 	// SkipTTLExtensionOnHit prevents TTL from being reset on cache hits.
 	// Default: true
 	SkipTTLExtensionOnHit bool
+
+	// TrackHits counts hits per entry so HandOff can transfer the hottest
+	// entries first (default: false)
+	TrackHits bool
+
+	// AdaptiveTTL adapts the lifetime of MemoryEngineTTLCache entries to how
+	// often they are hit, replacing the extension controlled by
+	// SkipTTLExtensionOnHit; other engines ignore it (optional)
+	AdaptiveTTL *AdaptiveTTLConfig
+
+	// HonorContextCancellation fails operations whose context is already
+	// done (reads as misses). Set it to false to serve them anyway: they are
+	// local and cheap, and e.g. logging during request teardown shouldn't see
+	// spurious misses (default: nil, meaning true)
+	HonorContextCancellation *bool
+
+	// Engine selects the in-memory implementation (default: MemoryEngineTTLCache)
+	Engine MemoryEngine
+
+	// MaxEntries bounds the number of entries (default: 100000 for
+	// MemoryEngineRistretto, unbounded for MemoryEngineTTLCache, which
+	// evicts the entries closest to expiring first)
+	MaxEntries int
+
+	// SizeBytes is the memory pre-allocated by MemoryEngineFreeCache
+	// (default: 64MiB)
+	SizeBytes int
+
+	// Serializer encodes values for MemoryEngineFreeCache
+	// (default: proto.Marshal for proto messages, JSON otherwise)
+	Serializer Serializer
+
+	// OnEvent is called when a MemoryEngineTTLCache entry is replaced,
+	// evicted or expired, or a write hits the closed cache; evictions and
+	// expiries are reported from ttlcache's goroutines. MemoryStatsOf counts
+	// the same conditions (optional)
+	OnEvent func(MemoryEvent)
+}
+
+// MemoryEngine selects the implementation behind in-memory caches.
+type MemoryEngine string
+
+const (
+	// MemoryEngineTTLCache uses ttlcache: exact TTLs, read-your-writes, a single lock.
+	MemoryEngineTTLCache MemoryEngine = "ttlcache"
+
+	// MemoryEngineRistretto uses Ristretto: TinyLFU admission and cost-based
+	// eviction with high concurrent throughput. Writes are applied
+	// asynchronously and may be rejected by the admission policy.
+	MemoryEngineRistretto MemoryEngine = "ristretto"
+
+	// MemoryEngineFreeCache uses FreeCache: values are serialized into
+	// pre-allocated segments, avoiding GC pressure from millions of pointers.
+	// Values are copies, so mutating a value read from the cache doesn't
+	// change the cached entry.
+	MemoryEngineFreeCache MemoryEngine = "freecache"
+)
+
+// DiskConfig holds configuration for the disk-persistent cache.
+type DiskConfig struct {
+	// Path is the database file, created if missing (required)
+	Path string
+
+	// Bucket namespaces the entries within the file (default: "cache")
+	Bucket string
+
+	// Serializer encodes values (default: proto.Marshal for proto messages,
+	// JSON otherwise)
+	Serializer Serializer
+
+	// CleanupInterval is how often expired entries are removed from the file
+	// (default: 1m, negative disables)
+	CleanupInterval time.Duration
+
+	// OpenTimeout bounds waiting for the file lock held by another process
+	// (default: 1s)
+	OpenTimeout time.Duration
+}
+
+// SQLConfig holds configuration for the SQL-backed cache.
+type SQLConfig struct {
+	// DB is the database handle, owned by the caller (required)
+	DB *sql.DB
+
+	// Dialect selects the SQL flavor (default: SQLDialectPostgres)
+	Dialect SQLDialect
+
+	// Table stores the entries (default: "cache_entries")
+	Table string
+
+	// CreateTable creates the table and its expiry index if missing
+	// (default: false)
+	CreateTable bool
+
+	// Serializer encodes values (default: proto.Marshal for proto messages,
+	// JSON otherwise)
+	Serializer Serializer
+
+	// PurgeInterval is how often expired rows are deleted
+	// (default: 1m, negative disables)
+	PurgeInterval time.Duration
+}
+
+// DynamoDBConfig holds configuration for the DynamoDB-backed cache.
+type DynamoDBConfig struct {
+	// Client is the DynamoDB client, e.g. *dynamodb.Client (required)
+	Client DynamoDBAPI
+
+	// Table stores the entries (required)
+	Table string
+
+	// KeyAttribute is the table's string partition key (default: "pk")
+	KeyAttribute string
+
+	// ValueAttribute holds the serialized value (default: "value")
+	ValueAttribute string
+
+	// TTLAttribute holds the expiry in unix seconds; enable it as the table's
+	// TTL attribute so DynamoDB deletes expired items (default: "expires_at")
+	TTLAttribute string
+
+	// ConsistentRead makes reads see all prior writes, at twice the read
+	// capacity cost (default: false, eventually consistent)
+	ConsistentRead bool
+
+	// Serializer encodes values (default: proto.Marshal for proto messages,
+	// JSON otherwise)
+	Serializer Serializer
 }
 
 // DistributedConfig holds configuration for distributed cache.
@@ -66,10 +330,50 @@ type DistributedConfig struct {
 	// Serializer allows custom serialization (overrides SerializationType if set)
 	Serializer Serializer
 
+	// Compression compresses serialized values, cutting Redis memory and
+	// bandwidth for large payloads. Switching algorithms keeps existing entries
+	// readable; enabling it turns them into misses (default: CompressionNone)
+	Compression CompressionType
+
+	// CompressionMinSize is the serialized size in bytes below which values
+	// are stored uncompressed (default: 0, always compress)
+	CompressionMinSize int
+
+	// SerializerChain wraps the serializer, after Compression, with each
+	// wrapper in order, e.g. []SerializerWrapper{WithTypeChecking(nil), encrypt}
+	// (optional)
+	SerializerChain []SerializerWrapper
+
+	// RejectOlderWrites stamps values with their write time and makes Set fail
+	// with ErrOlderWrite instead of overwriting a value written later, so
+	// out-of-order writers can't replace newer state. Enabling it keeps
+	// existing entries readable; disabling it turns stamped entries into
+	// decode errors (default: false)
+	RejectOlderWrites bool
+
+	// AllowFlushDB lets Clear on a cache without a KeyPrefix flush the whole
+	// database with FLUSHDB, including data written through other clients.
+	// Without it, such a Clear fails with ErrNotClearable (default: false)
+	AllowFlushDB bool
+
 	// Client allows providing a pre-configured Redis/Valkey client.
 	// When set, the cache will reuse this client instead of creating its own.
 	// The cache will not close the shared client when Close is called, and
 	// EnableTracing/EnableMetrics will be ignored (instrument shared clients
 	// yourself before passing them in).
 	Client redis.UniversalClient
+
+	// Degraded declares how reads behave when the backend or the serializer
+	// fails (default: treat both as a cache miss)
+	Degraded DegradedPolicy
+
+	// Logger logs the read failures Degraded answers as misses (or with stale
+	// values), which are otherwise invisible (optional)
+	Logger *slog.Logger
+
+	// ClientSideCache keeps values read from the server in process memory,
+	// evicted by the server's invalidation messages (client tracking) when
+	// another client changes them. Requires the cache to create its own
+	// client (optional)
+	ClientSideCache *ClientSideCacheConfig
 }
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// dampenedCache suppresses repeated Sets of the same value within an interval.
+type dampenedCache[T any] struct {
+	next       Cache[T]
+	interval   time.Duration
+	serializer Serializer
+	now        func() time.Time
+
+	mu        sync.Mutex
+	lastWrite map[string]dampenedWrite
+	lastPrune time.Time
+}
+
+// dampenedWrite records when a key was last written, the hash of the value
+// and the TTL.
+type dampenedWrite struct {
+	at  time.Time
+	sum [sha256.Size]byte
+	ttl time.Duration
+}
+
+// NewDampened wraps a cache so that a Set of a key that was already written
+// with an equal value and TTL less than interval ago is skipped (write
+// dampening). This protects the backend from hot values that are rewritten
+// thousands of times a minute. Values are compared by a hash of their
+// serialized form, like NewSkipEqualWrites does, using serializer (JSON when
+// nil), so a pointer mutated in place and Set again still goes through.
+//
+// Dampening is per process. A Set carrying a different value or TTL always
+// goes through, so the backend never serves a stale value. Every other
+// write of the key (Delete, Expire, SetIfAbsent, CompareAndSwap, GetDel)
+// clears its dampening state so the next Set always goes through.
+func NewDampened[T any](cache Cache[T], interval time.Duration, serializer Serializer) Cache[T] {
+	if serializer == nil {
+		serializer = NewJSONSerializer()
+	}
+	return &dampenedCache[T]{
+		next:       cache,
+		interval:   interval,
+		serializer: serializer,
+		now:        time.Now,
+		lastWrite:  make(map[string]dampenedWrite),
+		lastPrune:  time.Now(),
+	}
+}
+
+func (c *dampenedCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *dampenedCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *dampenedCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *dampenedCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *dampenedCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *dampenedCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	defer c.forget(key)
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *dampenedCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	defer c.forget(key)
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *dampenedCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	defer c.forget(key)
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *dampenedCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	defer c.forget(key)
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *dampenedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	sum, ok := valueSum(c.serializer, value)
+	if !ok {
+		// Can't compare - always write
+		defer c.forget(key)
+		return c.next.Set(ctx, key, value, ttl)
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	if last, ok := c.lastWrite[key]; ok && now.Sub(last.at) < c.interval && last.sum == sum && last.ttl == ttl {
+		c.mu.Unlock()
+		return nil
+	}
+	c.lastWrite[key] = dampenedWrite{at: now, sum: sum, ttl: ttl}
+	c.pruneLocked(now)
+	c.mu.Unlock()
+
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		// Let the next attempt through instead of dampening a failed write
+		c.forget(key)
+		return err
+	}
+	return nil
+}
+
+func (c *dampenedCache[T]) Delete(ctx context.Context, key string) error {
+	defer c.forget(key)
+	return c.next.Delete(ctx, key)
+}
+
+func (c *dampenedCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	defer func() {
+		c.mu.Lock()
+		for _, key := range keys {
+			delete(c.lastWrite, key)
+		}
+		c.mu.Unlock()
+	}()
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *dampenedCache[T]) Clear(ctx context.Context) error {
+	defer func() {
+		c.mu.Lock()
+		clear(c.lastWrite)
+		c.mu.Unlock()
+	}()
+	return Clear(ctx, c.next)
+}
+
+func (c *dampenedCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *dampenedCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *dampenedCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// pruneLocked drops write records older than the interval, at most once per interval.
+func (c *dampenedCache[T]) pruneLocked(now time.Time) {
+	if now.Sub(c.lastPrune) < c.interval {
+		return
+	}
+	for key, last := range c.lastWrite {
+		if now.Sub(last.at) >= c.interval {
+			delete(c.lastWrite, key)
+		}
+	}
+	c.lastPrune = now
+}
+
+// forget clears the dampening state of key once it was written otherwise
+// than by Set.
+func (c *dampenedCache[T]) forget(key string) {
+	c.mu.Lock()
+	delete(c.lastWrite, key)
+	c.mu.Unlock()
+}
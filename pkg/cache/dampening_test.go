@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDampenedCache(t *testing.T) {
+	inner := NewMemory[TestUser](nil)
+	defer inner.Close()
+
+	now := time.Now()
+	cache := NewDampened(inner, time.Second, nil).(*dampenedCache[TestUser])
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// First Set goes through
+	if err := cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Second Set of an equal value within the interval is dampened
+	if err := inner.Set(ctx, "key1", TestUser{ID: "1", Name: "direct"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	retrieved, _ := cache.Get(ctx, "key1")
+	if retrieved.Name != "direct" {
+		t.Errorf("Expected dampened Set to be skipped, got %+v", retrieved)
+	}
+
+	// A changed value within the interval goes through
+	if err := cache.Set(ctx, "key1", TestUser{ID: "2"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	retrieved, _ = cache.Get(ctx, "key1")
+	if retrieved.ID != "2" {
+		t.Errorf("Expected changed value to be written, got ID %s", retrieved.ID)
+	}
+
+	// Other keys are not affected
+	if err := cache.Set(ctx, "key2", TestUser{ID: "3"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key2"); !found {
+		t.Error("Expected key2 to be written")
+	}
+
+	// After the interval the write goes through again
+	now = now.Add(time.Second)
+	if err := cache.Set(ctx, "key1", TestUser{ID: "4"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	retrieved, _ = cache.Get(ctx, "key1")
+	if retrieved.ID != "4" {
+		t.Errorf("Expected Set after interval to be written, got ID %s", retrieved.ID)
+	}
+
+	// Delete resets dampening for the key
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{ID: "5"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	retrieved, _ = cache.Get(ctx, "key1")
+	if retrieved.ID != "5" {
+		t.Errorf("Expected Set after Delete to be written, got ID %s", retrieved.ID)
+	}
+}
+
+func TestDampenedCacheComparesSerializedValues(t *testing.T) {
+	inner := &countingCache[*TestUser]{Cache: NewMemory[*TestUser](nil)}
+	defer inner.Close()
+
+	now := time.Now()
+	cache := NewDampened[*TestUser](inner, time.Second, nil).(*dampenedCache[*TestUser])
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// A pointer mutated in place and Set again goes through
+	user := &TestUser{ID: "1"}
+	_ = cache.Set(ctx, "key1", user, time.Minute)
+	user.Name = "changed"
+	_ = cache.Set(ctx, "key1", user, time.Minute)
+	if inner.sets != 2 {
+		t.Errorf("Expected the mutated value to be written, got %d writes", inner.sets)
+	}
+
+	// A Set only changing the TTL goes through
+	_ = cache.Set(ctx, "key1", user, time.Hour)
+	if inner.sets != 3 {
+		t.Errorf("Expected the new TTL to be written, got %d writes", inner.sets)
+	}
+
+	// An equal value and TTL is dampened
+	_ = cache.Set(ctx, "key1", &TestUser{ID: "1", Name: "changed"}, time.Hour)
+	if inner.sets != 3 {
+		t.Errorf("Expected the repeated write to be dampened, got %d writes", inner.sets)
+	}
+}
+
+func TestDampenedCacheForgetsOtherWrites(t *testing.T) {
+	inner := NewMemory[TestUser](nil)
+	defer inner.Close()
+
+	now := time.Now()
+	cache := NewDampened(inner, time.Second, nil).(*dampenedCache[TestUser])
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute)
+	if swapped, err := CompareAndSwap(ctx, cache, "key1", TestUser{ID: "1"}, TestUser{ID: "2"}, time.Minute); err != nil || !swapped {
+		t.Fatalf("Expected the swap to succeed, got %v, %v", swapped, err)
+	}
+	_ = cache.Set(ctx, "key1", TestUser{ID: "1"}, time.Minute)
+	if retrieved, _ := inner.Get(ctx, "key1"); retrieved.ID != "1" {
+		t.Errorf("Expected the Set after a swap to be written, got %+v", retrieved)
+	}
+}
+
+func TestDampenedCachePrunesOldRecords(t *testing.T) {
+	inner := NewNoOp[TestUser]()
+
+	now := time.Now()
+	cache := NewDampened(inner, time.Second, nil).(*dampenedCache[TestUser])
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_ = cache.Set(ctx, "key1", TestUser{}, time.Minute)
+	_ = cache.Set(ctx, "key2", TestUser{}, time.Minute)
+
+	now = now.Add(2 * time.Second)
+	_ = cache.Set(ctx, "key3", TestUser{}, time.Minute)
+
+	if len(cache.lastWrite) != 1 {
+		t.Errorf("Expected stale write records to be pruned, got %d records", len(cache.lastWrite))
+	}
+}
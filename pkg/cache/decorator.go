@@ -0,0 +1,42 @@
+package cache
+
+import "context"
+
+// pingNext forwards a health check to the wrapped cache when it supports one.
+// Caches without a health check are considered healthy.
+func pingNext[T any](ctx context.Context, next Cache[T]) error {
+	if hc, ok := next.(HealthChecker); ok {
+		return hc.Ping(ctx)
+	}
+	return nil
+}
+
+// deleteMulti removes keys from cache in one call when it implements BatchDeleter,
+// falling back to one Delete per key otherwise, which reports the keys that
+// failed with a *BatchError.
+func deleteMulti[T any](ctx context.Context, cache Cache[T], keys []string) error {
+	if bd, ok := cache.(BatchDeleter); ok {
+		return bd.DeleteMulti(ctx, keys...)
+	}
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		errs[i] = cache.Delete(ctx, key)
+	}
+	return batchError(keys, errs)
+}
+
+// flushAll flushes the first Flusher found in cache or the caches it wraps.
+// Caches without buffered writes need no flushing.
+func flushAll[T any](ctx context.Context, cache Cache[T]) error {
+	for cache != nil {
+		if f, ok := cache.(Flusher); ok {
+			return f.Flush(ctx)
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// defaultConfig holds the configuration set by SetDefaults, nil until then.
+var defaultConfig atomic.Pointer[Config]
+
+// SetDefaults sets the configuration every cache created by New starts from,
+// so an organization can establish timeouts, serialization and observability
+// settings in one place, e.g. in a shared package imported by its services:
+//
+//	cache.SetDefaults(cache.Config{
+//		MeterProvider: meterProvider,
+//		Distributed: &cache.DistributedConfig{
+//			ReadTimeout:   500 * time.Millisecond,
+//			EnableTracing: true,
+//		},
+//		ForceSerialization: cache.SerializationJSON,
+//	})
+//
+//	users, err := cache.New[User](&cache.Config{
+//		Type:        cache.TypeDistributed,
+//		Name:        "users",
+//		Distributed: &cache.DistributedConfig{Addr: addr}, // keeps the 500ms timeout
+//	})
+//
+// Each field a cache's configuration leaves at its zero value takes the
+// default, including the fields of backend configurations such as
+// Distributed, which are merged field by field. A default can therefore only
+// be overridden by a non-zero value: a cache can't turn off a boolean set by
+// the defaults. Set it before creating caches; caches already created keep
+// their configuration. SetDefaults(Config{}) removes the defaults.
+func SetDefaults(config Config) {
+	if reflect.ValueOf(config).IsZero() {
+		defaultConfig.Store(nil)
+		return
+	}
+	defaultConfig.Store(&config)
+}
+
+// withDefaults returns a copy of config with the defaults set by SetDefaults
+// filling its zero fields, or config itself without defaults. Nested
+// configurations are copied, so neither config nor the defaults are changed
+// by backends adjusting theirs.
+func withDefaults(config *Config) *Config {
+	defaults := defaultConfig.Load()
+	if defaults == nil {
+		return config
+	}
+
+	merged := *config
+	mergeDefaults(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(defaults).Elem())
+	return &merged
+}
+
+// configPkgPath identifies the configuration structs mergeDefaults recurses
+// into; other pointers, such as *sql.DB, are defaults like any other value.
+var configPkgPath = reflect.TypeFor[Config]().PkgPath()
+
+// mergeDefaults sets the zero fields of the struct dst to those of defaults,
+// recursing into the configuration structs of this package.
+func mergeDefaults(dst, defaults reflect.Value) {
+	for i := range dst.NumField() {
+		field, def := dst.Field(i), defaults.Field(i)
+		if !field.CanSet() || def.IsZero() {
+			continue
+		}
+
+		switch {
+		case isConfigStruct(field.Type()):
+			mergeDefaults(field, def)
+		case field.Kind() == reflect.Pointer && isConfigStruct(field.Type().Elem()):
+			merged := reflect.New(field.Type().Elem())
+			if !field.IsNil() {
+				merged.Elem().Set(field.Elem())
+			}
+			mergeDefaults(merged.Elem(), def.Elem())
+			field.Set(merged)
+		case field.IsZero():
+			field.Set(def)
+		}
+	}
+}
+
+func isConfigStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == configPkgPath
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestWithDefaultsMergesFields(t *testing.T) {
+	SetDefaults(Config{
+		KeyPrefix:  "org:",
+		DefaultTTL: time.Minute,
+		Distributed: &DistributedConfig{
+			ReadTimeout:   500 * time.Millisecond,
+			EnableTracing: true,
+		},
+	})
+	t.Cleanup(func() { SetDefaults(Config{}) })
+
+	config := &Config{
+		Type:        TypeDistributed,
+		DefaultTTL:  time.Hour,
+		Distributed: &DistributedConfig{Addr: "localhost:6379"},
+	}
+	merged := withDefaults(config)
+
+	if merged.KeyPrefix != "org:" || merged.DefaultTTL != time.Hour {
+		t.Errorf("Expected the default prefix and the cache's TTL, got %q and %v", merged.KeyPrefix, merged.DefaultTTL)
+	}
+	d := merged.Distributed
+	if d.Addr != "localhost:6379" || d.ReadTimeout != 500*time.Millisecond || !d.EnableTracing {
+		t.Errorf("Expected the distributed configs to be merged, got %+v", d)
+	}
+	if config.Distributed.ReadTimeout != 0 || defaultConfig.Load().Distributed.Addr != "" {
+		t.Error("Expected neither the config nor the defaults to change")
+	}
+}
+
+func TestWithDefaultsCopiesNestedConfigs(t *testing.T) {
+	db := &sql.DB{}
+	SetDefaults(Config{SQL: &SQLConfig{DB: db, Table: "shared"}})
+	t.Cleanup(func() { SetDefaults(Config{}) })
+
+	merged := withDefaults(&Config{Type: TypeSQL})
+	if merged.SQL == defaultConfig.Load().SQL {
+		t.Error("Expected the SQL config to be copied")
+	}
+	if merged.SQL.DB != db || merged.SQL.Table != "shared" {
+		t.Errorf("Expected the default handle and table, got %+v", merged.SQL)
+	}
+}
+
+func TestWithoutDefaults(t *testing.T) {
+	config := &Config{Type: TypeMemory}
+	if withDefaults(config) != config {
+		t.Error("Expected the config to be used as is without defaults")
+	}
+}
+
+func TestNewAppliesDefaults(t *testing.T) {
+	ctx := context.Background()
+	SetDefaults(Config{KeyPrefix: "org:", DefaultTTL: time.Minute})
+	t.Cleanup(func() { SetDefaults(Config{}) })
+
+	cache, err := New[TestUser](&Config{Type: TypeMemory})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, 0)
+	var seen []KeyInfo
+	_ = ScanKeys(ctx, cache, nil, func(batch []KeyInfo) error {
+		seen = append(seen, batch...)
+		return nil
+	})
+	if len(seen) != 1 || seen[0].TTL <= 0 || seen[0].TTL > time.Minute {
+		t.Errorf("Expected the entry to get the default TTL, got %+v", seen)
+	}
+	mc, _ := memoryCacheOf(cache)
+	if item, _ := mc.lookup("org:1"); item == nil {
+		t.Error("Expected the entry under the default prefix")
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// defaultTTLCache applies a default TTL to writes without one.
+type defaultTTLCache[T any] struct {
+	next Cache[T]
+	ttl  time.Duration
+}
+
+// WithDefaultTTL wraps a cache so that Sets and GetOrSet loads with a TTL of 0
+// are stored with ttl instead of without expiry, keeping durations out of call
+// sites. A ttl of 0 or less returns cache unchanged.
+func WithDefaultTTL[T any](cache Cache[T], ttl time.Duration) Cache[T] {
+	if ttl <= 0 {
+		return cache
+	}
+	return &defaultTTLCache[T]{
+		next: cache,
+		ttl:  ttl,
+	}
+}
+
+func (c *defaultTTLCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *defaultTTLCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *defaultTTLCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *defaultTTLCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *defaultTTLCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *defaultTTLCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, c.effective(ttl))
+}
+
+func (c *defaultTTLCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, c.effective(ttl))
+}
+
+func (c *defaultTTLCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, c.effective(ttl))
+}
+
+func (c *defaultTTLCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *defaultTTLCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, c.effective(ttl))
+}
+
+func (c *defaultTTLCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, c.effective(ttl), load)
+}
+
+func (c *defaultTTLCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *defaultTTLCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *defaultTTLCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *defaultTTLCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *defaultTTLCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *defaultTTLCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+func (c *defaultTTLCache[T]) effective(ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		return c.ttl
+	}
+	return ttl
+}
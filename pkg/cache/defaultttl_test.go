@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDefaultTTL(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New[TestUser](&Config{Type: TypeMemory, DefaultTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+	mc, _ := memoryCacheOf(cache)
+
+	ttlOf := func(key string) time.Duration {
+		t.Helper()
+		for _, entry := range mc.hottest(10) {
+			if entry.key == key {
+				return entry.ttl
+			}
+		}
+		t.Fatalf("Expected %s to be cached", key)
+		return 0
+	}
+
+	// Test a TTL of 0 gets the default
+	_ = cache.Set(ctx, "default", TestUser{ID: "1"}, 0)
+	if ttl := ttlOf("default"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected the default TTL of 1m, got %v", ttl)
+	}
+
+	// Test explicit TTLs are kept
+	_ = cache.Set(ctx, "explicit", TestUser{ID: "2"}, time.Hour)
+	if ttl := ttlOf("explicit"); ttl <= time.Minute {
+		t.Errorf("Expected the explicit TTL of 1h, got %v", ttl)
+	}
+
+	// Test loads get the default too
+	_, _ = GetOrSet(ctx, cache, "loaded", 0, func(context.Context) (TestUser, error) {
+		return TestUser{ID: "3"}, nil
+	})
+	if ttl := ttlOf("loaded"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected the default TTL of 1m for a load, got %v", ttl)
+	}
+}
+
+func TestWithDefaultTTLDisabled(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	if WithDefaultTTL(cache, 0) != cache {
+		t.Error("Expected a zero default to return the cache unchanged")
+	}
+}
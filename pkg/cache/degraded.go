@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jellydator/ttlcache/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BackendDownPolicy decides what a read does when the backend fails.
+type BackendDownPolicy string
+
+const (
+	// BackendDownMiss treats the failed read as a cache miss.
+	BackendDownMiss BackendDownPolicy = "miss"
+	// BackendDownServeStale serves the last value this process read or wrote
+	// for the key, if it is not older than StaleTTL, and a miss otherwise.
+	BackendDownServeStale BackendDownPolicy = "serve-stale"
+	// BackendDownError reports the backend error to error-aware callers.
+	BackendDownError BackendDownPolicy = "error"
+)
+
+// SerializerErrorPolicy decides what a read does when a stored value can't be decoded.
+type SerializerErrorPolicy string
+
+const (
+	// SerializerErrorMiss treats the undecodable value as a cache miss.
+	SerializerErrorMiss SerializerErrorPolicy = "miss"
+	// SerializerErrorDelete removes the undecodable value and reports a miss.
+	SerializerErrorDelete SerializerErrorPolicy = "delete"
+	// SerializerErrorError reports the decode error to error-aware callers.
+	SerializerErrorError SerializerErrorPolicy = "error"
+)
+
+// SetSerializerErrorPolicy decides what a write does when a value can't be encoded.
+type SetSerializerErrorPolicy string
+
+const (
+	// SetSerializerErrorFail returns the encode error from Set.
+	SetSerializerErrorFail SetSerializerErrorPolicy = "error"
+	// SetSerializerErrorSkip skips caching the value, records a
+	// "cache.set_skipped" span event and a warning log, and returns nil.
+	SetSerializerErrorSkip SetSerializerErrorPolicy = "skip"
+)
+
+// setSkippedEventName is the span event recorded for skipped writes.
+const setSkippedEventName = "cache.set_skipped"
+
+// DegradedPolicy declares how reads behave when the backend or the serializer
+// fails, and how writes behave when a value can't be encoded. Plain Get always
+// reports failures as misses; the "error" choices are reported by error-aware
+// read paths.
+type DegradedPolicy struct {
+	// OnBackendDown applies when the backend returns an error (default: BackendDownMiss)
+	OnBackendDown BackendDownPolicy
+
+	// OnSerializerError applies when a stored value can't be decoded (default: SerializerErrorMiss)
+	OnSerializerError SerializerErrorPolicy
+
+	// OnSetSerializerError applies when Set can't encode a value
+	// (default: SetSerializerErrorFail)
+	OnSetSerializerError SetSerializerErrorPolicy
+
+	// StaleTTL is how long values are kept for BackendDownServeStale (default: 5m)
+	StaleTTL time.Duration
+
+	// StaleMaxEntries caps the number of values kept for BackendDownServeStale (default: 10000)
+	StaleMaxEntries int
+}
+
+// degradedHandler evaluates a DegradedPolicy for one cache.
+type degradedHandler[T any] struct {
+	policy DegradedPolicy
+	stale  *ttlcache.Cache // only set for BackendDownServeStale
+	logger *slog.Logger    // logs the failures reads hide, if set
+}
+
+func newDegradedHandler[T any](policy DegradedPolicy, logger *slog.Logger) *degradedHandler[T] {
+	if policy.OnBackendDown == "" {
+		policy.OnBackendDown = BackendDownMiss
+	}
+	if policy.OnSerializerError == "" {
+		policy.OnSerializerError = SerializerErrorMiss
+	}
+	if policy.OnSetSerializerError == "" {
+		policy.OnSetSerializerError = SetSerializerErrorFail
+	}
+	if policy.StaleTTL == 0 {
+		policy.StaleTTL = 5 * time.Minute
+	}
+	if policy.StaleMaxEntries == 0 {
+		policy.StaleMaxEntries = 10000
+	}
+
+	h := &degradedHandler[T]{policy: policy, logger: logger}
+	if policy.OnBackendDown == BackendDownServeStale {
+		h.stale = ttlcache.NewCache()
+		h.stale.SkipTTLExtensionOnHit(true)
+		h.stale.SetCacheSizeLimit(policy.StaleMaxEntries)
+	}
+	return h
+}
+
+// backendDown resolves a read that failed with a backend error.
+func (h *degradedHandler[T]) backendDown(ctx context.Context, key string, err error) (T, bool, error) {
+	var zero T
+
+	if h.policy.OnBackendDown != BackendDownError {
+		h.logHidden(ctx, "cache: backend read failed", key, string(h.policy.OnBackendDown), err)
+	}
+
+	switch h.policy.OnBackendDown {
+	case BackendDownServeStale:
+		if value, getErr := h.stale.Get(key); getErr == nil {
+			if typed, ok := value.(T); ok {
+				noteDecision(ctx, DecisionStale)
+				return typed, true, nil
+			}
+		}
+		return zero, false, nil
+	case BackendDownError:
+		return zero, false, err
+	default:
+		return zero, false, nil
+	}
+}
+
+// serializerError resolves a read whose stored value could not be decoded.
+// remove deletes the value from the backend for SerializerErrorDelete.
+func (h *degradedHandler[T]) serializerError(ctx context.Context, key string, err error, remove func(ctx context.Context, key string) error) (T, bool, error) {
+	var zero T
+
+	if h.policy.OnSerializerError != SerializerErrorError {
+		h.logHidden(ctx, "cache: decoding cached value failed", key, string(h.policy.OnSerializerError), err)
+	}
+
+	switch h.policy.OnSerializerError {
+	case SerializerErrorDelete:
+		// Best effort - the value is unusable either way
+		_ = remove(ctx, key)
+		return zero, false, nil
+	case SerializerErrorError:
+		return zero, false, err
+	default:
+		return zero, false, nil
+	}
+}
+
+// setSerializerError resolves a write whose value could not be encoded.
+func (h *degradedHandler[T]) setSerializerError(ctx context.Context, key string, err error) error {
+	if h.policy.OnSetSerializerError != SetSerializerErrorSkip {
+		return err
+	}
+
+	trace.SpanFromContext(ctx).AddEvent(setSkippedEventName, trace.WithAttributes(
+		attribute.String("cache.key", key),
+		attribute.String("error", err.Error()),
+	))
+	logger := h.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.WarnContext(ctx, "cache: skipped write of unencodable value",
+		slog.String("key", key),
+		slog.Any("error", err),
+	)
+	return nil
+}
+
+// logHidden logs a failure the policy hides from the caller, if a logger is set.
+func (h *degradedHandler[T]) logHidden(ctx context.Context, msg, key, policy string, err error) {
+	if h.logger != nil {
+		h.logger.WarnContext(ctx, msg,
+			slog.String("key", key),
+			slog.String("policy", policy),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// remember records a value read from or written to the backend for serve-stale.
+func (h *degradedHandler[T]) remember(key string, value T) {
+	if h.stale != nil {
+		_ = h.stale.SetWithTTL(key, value, h.policy.StaleTTL)
+	}
+}
+
+// forget drops the stale copy of a deleted key.
+func (h *degradedHandler[T]) forget(key string) {
+	if h.stale != nil {
+		_ = h.stale.Remove(key)
+	}
+}
+
+// forgetAll drops every stale copy after the backend was cleared.
+func (h *degradedHandler[T]) forgetAll() {
+	if h.stale != nil {
+		_ = h.stale.Purge()
+	}
+}
+
+func (h *degradedHandler[T]) close() {
+	if h.stale != nil {
+		_ = h.stale.Close()
+	}
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newUnreachableDistributedCache returns a generic distributed cache whose
+// backend is down, bypassing the connection check done by the constructor.
+func newUnreachableDistributedCache(t *testing.T, policy DegradedPolicy) *distributedGenericCache[TestUser] {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		MaxRetries:  -1,
+		DialTimeout: 100 * time.Millisecond,
+	})
+	cache := &distributedGenericCache[TestUser]{
+		client:     client,
+		serializer: &JSONSerializer{},
+		ownsClient: true,
+		degraded:   newDegradedHandler[TestUser](policy, nil),
+	}
+	t.Cleanup(func() {
+		_ = cache.Close()
+	})
+	return cache
+}
+
+func TestDegradedPolicyBackendDown(t *testing.T) {
+	ctx := context.Background()
+	user := TestUser{ID: "123", Name: "John"}
+
+	t.Run("Miss", func(t *testing.T) {
+		cache := newUnreachableDistributedCache(t, DegradedPolicy{})
+
+		_, found, err := cache.GetWithError(ctx, "key1")
+		if found || err != nil {
+			t.Errorf("Expected plain miss, got found=%v err=%v", found, err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		cache := newUnreachableDistributedCache(t, DegradedPolicy{OnBackendDown: BackendDownError})
+
+		_, found, err := cache.GetWithError(ctx, "key1")
+		if found || err == nil {
+			t.Errorf("Expected backend error, got found=%v err=%v", found, err)
+		}
+
+		// Plain Get still reports a miss
+		if _, found := cache.Get(ctx, "key1"); found {
+			t.Error("Expected Get to report a miss")
+		}
+	})
+
+	t.Run("ServeStale", func(t *testing.T) {
+		cache := newUnreachableDistributedCache(t, DegradedPolicy{OnBackendDown: BackendDownServeStale})
+		cache.degraded.remember("key1", user)
+
+		retrieved, found := cache.Get(ctx, "key1")
+		if !found || retrieved != user {
+			t.Errorf("Expected stale %+v, got %+v (found=%v)", user, retrieved, found)
+		}
+
+		// Unknown keys are still misses
+		if _, found := cache.Get(ctx, "key2"); found {
+			t.Error("Expected miss for key without stale copy")
+		}
+
+		// Deleted keys are no longer served stale
+		_ = cache.Delete(ctx, "key1")
+		if _, found := cache.Get(ctx, "key1"); found {
+			t.Error("Expected deleted key not to be served stale")
+		}
+	})
+}
+
+func TestDegradedPolicySerializerError(t *testing.T) {
+	ctx := context.Background()
+	decodeErr := errors.New("decode failed")
+
+	tests := []struct {
+		name        string
+		policy      SerializerErrorPolicy
+		wantErr     bool
+		wantRemoved bool
+	}{
+		{name: "Miss", policy: SerializerErrorMiss},
+		{name: "Delete", policy: SerializerErrorDelete, wantRemoved: true},
+		{name: "Error", policy: SerializerErrorError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newDegradedHandler[TestUser](DegradedPolicy{OnSerializerError: tt.policy}, nil)
+
+			removed := false
+			remove := func(context.Context, string) error {
+				removed = true
+				return nil
+			}
+
+			_, found, err := handler.serializerError(ctx, "key1", decodeErr, remove)
+			if found {
+				t.Error("Expected a miss")
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got: %v", tt.wantErr, err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("Expected removed=%v, got %v", tt.wantRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestDegradedPolicySetSerializerError(t *testing.T) {
+	encodeErr := errors.New("encode failed")
+
+	t.Run("Fail", func(t *testing.T) {
+		handler := newDegradedHandler[TestUser](DegradedPolicy{}, nil)
+
+		if err := handler.setSerializerError(context.Background(), "key1", encodeErr); !errors.Is(err, encodeErr) {
+			t.Errorf("Expected encode error, got: %v", err)
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		handler := newDegradedHandler[TestUser](DegradedPolicy{OnSetSerializerError: SetSerializerErrorSkip}, nil)
+
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+		err := handler.setSerializerError(ctx, "key1", encodeErr)
+		span.End()
+
+		if err != nil {
+			t.Errorf("Expected skipped write to succeed, got: %v", err)
+		}
+		events := recorder.Ended()[0].Events()
+		if len(events) != 1 || events[0].Name != setSkippedEventName {
+			t.Errorf("Expected one %s event, got %+v", setSkippedEventName, events)
+		}
+	})
+}
+
+func TestDistributedSetSerializerErrorSkip(t *testing.T) {
+	ctx := context.Background()
+
+	cache := newUnreachableDistributedCache(t, DegradedPolicy{OnSetSerializerError: SetSerializerErrorSkip})
+	cache.serializer = failingSerializer{}
+
+	// The backend is never reached, so a nil error means the write was skipped
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute); err != nil {
+		t.Errorf("Expected skipped write to succeed, got: %v", err)
+	}
+}
+
+// failingSerializer fails every encode and decode.
+type failingSerializer struct{}
+
+func (failingSerializer) Serialize(interface{}) ([]byte, error) {
+	return nil, errors.New("encode failed")
+}
+
+func (failingSerializer) Deserialize([]byte, interface{}) error {
+	return errors.New("decode failed")
+}
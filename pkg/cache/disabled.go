@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jellydator/ttlcache/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// disabledShadowKeys bounds the keys a disabled cache remembers to tell
+// would-be hits from misses.
+const disabledShadowKeys = 10000
+
+// disabledCache stands in for a cache turned off by Config.Disabled: every
+// read misses and writes are dropped, but the keys written are remembered
+// without their values, so reads are counted as the hits or misses they
+// would have been.
+type disabledCache[T any] struct {
+	shadow *ttlcache.Cache
+	reads  metric.Int64Counter
+	attrs  []attribute.KeyValue
+	closed closeGuard
+}
+
+func newDisabledCache[T any](config *Config) *disabledCache[T] {
+	provider := config.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	reads, _ := provider.Meter(instrumentationName).Int64Counter("cache.disabled.reads",
+		metric.WithDescription("Number of reads of a disabled cache by the outcome they would have had"))
+
+	shadow := ttlcache.NewCache()
+	shadow.SkipTTLExtensionOnHit(true)
+	shadow.SetCacheSizeLimit(disabledShadowKeys)
+
+	slog.Default().Warn("cache: caching disabled, reads miss and writes are dropped",
+		slog.String("name", config.Name),
+		slog.String("type", string(config.Type)))
+
+	return &disabledCache[T]{
+		shadow: shadow,
+		reads:  reads,
+		attrs: []attribute.KeyValue{
+			attribute.String("cache.name", config.Name),
+			attribute.String("cache.type", string(config.Type)),
+		},
+	}
+}
+
+func (c *disabledCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, _, _ := c.GetWithError(ctx, key)
+	return value, false
+}
+
+func (c *disabledCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	outcome := "would_miss"
+	if _, err := c.shadow.Get(key); err == nil {
+		outcome = "would_hit"
+	}
+	if c.reads != nil {
+		attrs := append([]attribute.KeyValue{attribute.String("outcome", outcome)}, c.attrs...)
+		c.reads.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	return zero, false, nil
+}
+
+func (c *disabledCache[T]) Exists(_ context.Context, _ string) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	return false, nil
+}
+
+func (c *disabledCache[T]) TTL(_ context.Context, _ string) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+	return 0, false, nil
+}
+
+func (c *disabledCache[T]) Expire(_ context.Context, key string, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	// Keep would-be hits in step with the lifetime the entry would have had
+	if _, err := c.shadow.Get(key); err == nil {
+		_ = c.shadow.SetWithTTL(key, struct{}{}, ttl)
+	}
+	return ErrKeyNotFound
+}
+
+func (c *disabledCache[T]) Set(_ context.Context, key string, _ T, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return closedErr(c.shadow.SetWithTTL(key, struct{}{}, ttl))
+}
+
+// SetIfAbsent records the key like Set when it would have been stored, but
+// reports it as not stored, like every write of a disabled cache.
+func (c *disabledCache[T]) SetIfAbsent(_ context.Context, key string, _ T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	if _, err := c.shadow.Get(key); err == nil {
+		return false, nil
+	}
+	return false, closedErr(c.shadow.SetWithTTL(key, struct{}{}, ttl))
+}
+
+// CompareAndSwap never swaps: a disabled cache holds no values to compare.
+func (c *disabledCache[T]) CompareAndSwap(_ context.Context, _ string, _, _ T, _ time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	return false, nil
+}
+
+// GetDel counts the read like GetWithError and forgets the key, as a
+// consumed entry would be gone.
+func (c *disabledCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, _, err := c.GetWithError(ctx, key)
+	if err != nil {
+		return value, false, err
+	}
+	_ = c.shadow.Remove(key)
+	return value, false, nil
+}
+
+func (c *disabledCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *disabledCache[T]) DeleteMulti(_ context.Context, keys ...string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	for _, key := range keys {
+		_ = c.shadow.Remove(key)
+	}
+	return nil
+}
+
+func (c *disabledCache[T]) Clear(_ context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return closedErr(c.shadow.Purge())
+}
+
+// Ping reports a disabled cache as healthy: there is no backend to fail.
+func (c *disabledCache[T]) Ping(_ context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (c *disabledCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
+	return c.shadow.Close()
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestDisabledCache(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// Disabled caches don't reach their backend, so an unreachable one is fine
+	cache, err := New[TestUser](&Config{
+		Type:          TypeDistributed,
+		Distributed:   &DistributedConfig{Addr: "localhost:1"},
+		Quota:         &QuotaConfig{Namespace: "users", BytesPerWindow: 1024},
+		Name:          "users",
+		Disabled:      true,
+		MeterProvider: provider,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute); err != nil {
+		t.Errorf("Set failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "a"); found {
+		t.Error("Expected a disabled cache to miss")
+	}
+	_, _ = cache.Get(ctx, "b")
+	_ = cache.Delete(ctx, "a")
+	_, _ = cache.Get(ctx, "a")
+
+	if got := collectCounter(t, reader, "cache.disabled.reads", "would_hit"); got != 1 {
+		t.Errorf("Expected 1 would-be hit, got %d", got)
+	}
+	if got := collectCounter(t, reader, "cache.disabled.reads", "would_miss"); got != 2 {
+		t.Errorf("Expected 2 would-be misses, got %d", got)
+	}
+
+	if err := Expire(ctx, cache, "a", time.Minute); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+	if err := Clear(ctx, cache); err != nil {
+		t.Errorf("Clear failed: %v", err)
+	}
+	if err := HealthCheckerOf(cache).Ping(ctx); err != nil {
+		t.Errorf("Expected a disabled cache to be healthy, got %v", err)
+	}
+}
+
+func TestDisabledCacheWouldBeHitsExpire(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	cache, err := New[TestUser](&Config{Type: TypeMemory, Disabled: true, MeterProvider: provider})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, _ = cache.Get(ctx, "a")
+
+	if got := collectCounter(t, reader, "cache.disabled.reads", "would_hit"); got != 0 {
+		t.Errorf("Expected an expired key not to count as a would-be hit, got %d", got)
+	}
+}
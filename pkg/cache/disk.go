@@ -0,0 +1,533 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// expiryHeaderSize is the size of the expiry prefix stored before each value.
+const expiryHeaderSize = 8
+
+// diskCache is a cache persisted to a local bbolt file. Each value is stored
+// as an 8-byte big-endian expiry (unix nanoseconds, 0 for none) followed by
+// the encoded value. Expired entries are misses and are removed periodically.
+type diskCache[T any] struct {
+	db     *bolt.DB
+	bucket []byte
+	codec  valueCodec[T]
+	closed closeGuard
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewDisk creates a cache persisted to config.Path. Entries written before a
+// restart are served again once the file is reopened, until they expire.
+func NewDisk[T any](config *DiskConfig) (Cache[T], error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if config.Path == "" {
+		return nil, errors.New("disk cache path is required")
+	}
+	if err := checkValueType[T](config.Serializer); err != nil {
+		return nil, err
+	}
+
+	bucket := config.Bucket
+	if bucket == "" {
+		bucket = "cache"
+	}
+	openTimeout := config.OpenTimeout
+	if openTimeout == 0 {
+		openTimeout = time.Second
+	}
+	cleanupInterval := config.CleanupInterval
+	if cleanupInterval == 0 {
+		cleanupInterval = time.Minute
+	}
+
+	db, err := bolt.Open(config.Path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open disk cache: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create disk cache bucket: %w", err)
+	}
+
+	c := &diskCache[T]{
+		db:     db,
+		bucket: []byte(bucket),
+		codec:  newValueCodec[T](config.Serializer),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go c.cleanupLoop(cleanupInterval)
+	} else {
+		close(c.done)
+	}
+	return c, nil
+}
+
+func (c *diskCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *diskCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return zero, false, nil
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	var data []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		stored := tx.Bucket(c.bucket).Get([]byte(key))
+		if stored == nil || expired(stored, time.Now()) {
+			return nil
+		}
+		// bbolt memory is only valid for the life of the transaction
+		data = append([]byte(nil), stored[expiryHeaderSize:]...)
+		return nil
+	})
+	if err != nil {
+		return zero, false, closedErr(err)
+	}
+	if data == nil {
+		return zero, false, nil
+	}
+
+	value, err := c.codec.decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+func (c *diskCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	var found bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		stored := tx.Bucket(c.bucket).Get([]byte(key))
+		found = stored != nil && !expired(stored, time.Now())
+		return nil
+	})
+	return found, closedErr(err)
+}
+
+func (c *diskCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return 0, false, ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	var (
+		ttl   time.Duration
+		found bool
+	)
+	err := c.db.View(func(tx *bolt.Tx) error {
+		now := time.Now()
+		stored := tx.Bucket(c.bucket).Get([]byte(key))
+		if stored == nil || expired(stored, now) {
+			return nil
+		}
+		found = true
+		if expiry := int64(binary.BigEndian.Uint64(stored)); expiry != 0 {
+			ttl = time.Unix(0, expiry).Sub(now)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, closedErr(err)
+	}
+	return ttl, found, nil
+}
+
+func (c *diskCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	return closedErr(c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		now := time.Now()
+		stored := bucket.Get([]byte(key))
+		if stored == nil || expired(stored, now) {
+			return ErrKeyNotFound
+		}
+		// bbolt memory is only valid for the life of the transaction and
+		// must not be modified
+		renewed := append([]byte(nil), stored...)
+		var expiry uint64
+		if ttl > 0 {
+			expiry = uint64(now.Add(ttl).UnixNano())
+		}
+		binary.BigEndian.PutUint64(renewed, expiry)
+		return bucket.Put([]byte(key), renewed)
+	}))
+}
+
+// GetDel removes key and returns its value in one transaction.
+func (c *diskCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return zero, false, ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	var data []byte
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		stored := bucket.Get([]byte(key))
+		if stored == nil {
+			return nil
+		}
+		if !expired(stored, time.Now()) {
+			data = append([]byte(nil), stored[expiryHeaderSize:]...)
+		}
+		return bucket.Delete([]byte(key))
+	})
+	if err != nil {
+		return zero, false, closedErr(err)
+	}
+	if data == nil {
+		return zero, false, nil
+	}
+
+	value, err := c.codec.decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+func (c *diskCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return err
+	}
+
+	stored := make([]byte, expiryHeaderSize+len(data))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(stored, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	copy(stored[expiryHeaderSize:], data)
+
+	return closedErr(c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(c.bucket).Put([]byte(key), stored)
+	}))
+}
+
+func (c *diskCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	var stored bool
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		now := time.Now()
+		if current := bucket.Get([]byte(key)); current != nil && !expired(current, now) {
+			return nil
+		}
+
+		entry := make([]byte, expiryHeaderSize+len(data))
+		if ttl > 0 {
+			binary.BigEndian.PutUint64(entry, uint64(now.Add(ttl).UnixNano()))
+		}
+		copy(entry[expiryHeaderSize:], data)
+		stored = true
+		return bucket.Put([]byte(key), entry)
+	})
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return stored, nil
+}
+
+// CompareAndSwap stores new if the serialized value of key equals that of old.
+func (c *diskCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	oldData, err := c.codec.encode(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.encode(new)
+	if err != nil {
+		return false, err
+	}
+
+	var swapped bool
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		now := time.Now()
+		current := bucket.Get([]byte(key))
+		if current == nil || expired(current, now) || !bytes.Equal(current[expiryHeaderSize:], oldData) {
+			return nil
+		}
+
+		entry := make([]byte, expiryHeaderSize+len(newData))
+		if ttl > 0 {
+			binary.BigEndian.PutUint64(entry, uint64(now.Add(ttl).UnixNano()))
+		}
+		copy(entry[expiryHeaderSize:], newData)
+		swapped = true
+		return bucket.Put([]byte(key), entry)
+	})
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return swapped, nil
+}
+
+func (c *diskCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *diskCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	return closedErr(c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		for _, key := range keys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// Clear replaces the bucket with an empty one in a single transaction.
+func (c *diskCache[T]) Clear(ctx context.Context) error {
+	// Check if context is cancelled
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	return closedErr(c.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(c.bucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(c.bucket)
+		return err
+	}))
+}
+
+func (c *diskCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
+	close(c.stop)
+	<-c.done
+	return c.db.Close()
+}
+
+// cleanupLoop removes expired entries until the cache is closed.
+func (c *diskCache[T]) cleanupLoop(interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			// Best effort - a failed sweep is retried on the next tick
+			_ = c.removeExpired()
+		}
+	}
+}
+
+// removeExpired deletes every expired entry in a single transaction.
+func (c *diskCache[T]) removeExpired() error {
+	now := time.Now()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(c.bucket).Cursor()
+		for key, stored := cursor.First(); key != nil; {
+			if expired(stored, now) {
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+				// Delete moves the cursor, so re-seek to the following key
+				key, stored = cursor.Seek(key)
+				continue
+			}
+			key, stored = cursor.Next()
+		}
+		return nil
+	})
+}
+
+// scanKeys enumerates live keys in key order, one read transaction per
+// batch so a long scan doesn't hold one open.
+func (c *diskCache[T]) scanKeys(ctx context.Context, cfg ScanConfig, fn func([]KeyInfo) error) error {
+	var after []byte
+	for {
+		if c.closed.isClosed() {
+			return ErrClosed
+		}
+
+		batch := make([]KeyInfo, 0, cfg.BatchSize)
+		var more bool
+		err := c.db.View(func(tx *bolt.Tx) error {
+			now := time.Now()
+			cursor := tx.Bucket(c.bucket).Cursor()
+			key, stored := cursor.First()
+			if after != nil {
+				key, stored = cursor.Seek(after)
+				if bytes.Equal(key, after) {
+					key, stored = cursor.Next()
+				}
+			}
+			for ; key != nil; key, stored = cursor.Next() {
+				if len(batch) == cfg.BatchSize {
+					more = true
+					break
+				}
+				after = append(after[:0], key...)
+				if expired(stored, now) {
+					continue
+				}
+				matched, err := path.Match(cfg.Pattern, string(key))
+				if err != nil {
+					return err
+				}
+				if !matched {
+					continue
+				}
+
+				info := KeyInfo{Key: string(key), Size: int64(len(stored) - expiryHeaderSize)}
+				if expiry := int64(binary.BigEndian.Uint64(stored)); expiry != 0 {
+					info.TTL = time.Unix(0, expiry).Sub(now)
+				}
+				batch = append(batch, info)
+			}
+			return nil
+		})
+		if err != nil {
+			return closedErr(err)
+		}
+
+		if len(batch) > 0 {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// expired reports whether a stored value's expiry has passed.
+func expired(stored []byte, now time.Time) bool {
+	if len(stored) < expiryHeaderSize {
+		return true
+	}
+	expiry := int64(binary.BigEndian.Uint64(stored))
+	return expiry != 0 && now.UnixNano() >= expiry
+}
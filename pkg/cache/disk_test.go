@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestDiskCache(t *testing.T, config *DiskConfig) Cache[TestUser] {
+	t.Helper()
+
+	if config.Path == "" {
+		config.Path = filepath.Join(t.TempDir(), "cache.db")
+	}
+	cache, err := NewDisk[TestUser](config)
+	if err != nil {
+		t.Fatalf("NewDisk failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cache.Close()
+	})
+	return cache
+}
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+	user := TestUser{ID: "123", Name: "John"}
+
+	if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, found := cache.Get(ctx, "key1"); !found || got != user {
+		t.Errorf("Expected %+v, got %+v (found=%v)", user, got, found)
+	}
+
+	// Test Delete
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted")
+	}
+
+	// Test expiry
+	_ = cache.Set(ctx, "short", user, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, found := cache.Get(ctx, "short"); found {
+		t.Error("Expected expired entry to be a miss")
+	}
+}
+
+func TestDiskCacheSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.db")
+	user := TestUser{ID: "123", Name: "John"}
+
+	cache, err := NewDisk[TestUser](&DiskConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewDisk failed: %v", err)
+	}
+	_ = cache.Set(ctx, "key1", user, time.Hour)
+	_ = cache.Set(ctx, "forever", user, 0)
+	_ = cache.Close()
+
+	reopened := newTestDiskCache(t, &DiskConfig{Path: path})
+	for _, key := range []string{"key1", "forever"} {
+		if got, found := reopened.Get(ctx, key); !found || got != user {
+			t.Errorf("Expected %s to survive restart, got %+v (found=%v)", key, got, found)
+		}
+	}
+}
+
+func TestDiskCacheRemoveExpired(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{CleanupInterval: -1})
+	disk := cache.(*diskCache[TestUser])
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		_ = cache.Set(ctx, key, TestUser{ID: key}, time.Millisecond)
+	}
+	_ = cache.Set(ctx, "kept", TestUser{ID: "kept"}, time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if err := disk.removeExpired(); err != nil {
+		t.Fatalf("removeExpired failed: %v", err)
+	}
+
+	var remaining int
+	_ = disk.db.View(func(tx *bolt.Tx) error {
+		remaining = tx.Bucket(disk.bucket).Stats().KeyN
+		return nil
+	})
+	if remaining != 1 {
+		t.Errorf("Expected only the live entry to remain, got %d entries", remaining)
+	}
+}
+
+func TestDiskCacheClose(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Close()
+	if err := cache.Close(); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{}, time.Minute); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+}
+
+func TestNewDiskRequiresPath(t *testing.T) {
+	if _, err := NewDisk[TestUser](&DiskConfig{}); err == nil {
+		t.Error("Expected error for missing path")
+	}
+	if _, err := NewDisk[TestUser](nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+}
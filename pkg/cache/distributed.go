@@ -13,15 +13,48 @@ import (
 
 // distributedCache is a distributed cache implementation for proto messages.
 type distributedCache[T any] struct {
-	client     redis.UniversalClient
-	ownsClient bool
+	client      redis.UniversalClient
+	ownsClient  bool
+	rejectOlder bool
+	allowFlush  bool
+	degraded    *degradedHandler[T]
+	closed      closeGuard
+	stats       statsCounters
 }
 
 // distributedGenericCache is a distributed cache implementation for any type.
 type distributedGenericCache[T any] struct {
-	client     redis.UniversalClient
-	serializer Serializer
-	ownsClient bool
+	client      redis.UniversalClient
+	serializer  Serializer
+	ownsClient  bool
+	rejectOlder bool
+	allowFlush  bool
+	degraded    *degradedHandler[T]
+	closed      closeGuard
+	stats       statsCounters
+}
+
+// ErrNotDistributed is returned by helpers that require a cache backed by Redis/Valkey.
+var ErrNotDistributed = errors.New("cache is not backed by a distributed backend")
+
+// redisBacked is implemented by caches backed by a Redis/Valkey client.
+type redisBacked interface {
+	redisClient() redis.UniversalClient
+}
+
+// redisClientOf returns the Redis/Valkey client behind cache, looking through decorators.
+func redisClientOf[T any](cache Cache[T]) (redis.UniversalClient, error) {
+	for cache != nil {
+		if rb, ok := cache.(redisBacked); ok && rb.redisClient() != nil {
+			return rb.redisClient(), nil
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return nil, ErrNotDistributed
 }
 
 func ensureDistributedDefaults(config *DistributedConfig) {
@@ -51,7 +84,7 @@ func pingRedisClient(client redis.UniversalClient, timeout time.Duration) error
 	return client.Ping(ctx).Err()
 }
 
-func buildRedisClient(config *DistributedConfig) (redis.UniversalClient, bool, error) {
+func buildRedisClient(config *DistributedConfig, onConnect func(context.Context, *redis.Conn) error) (redis.UniversalClient, bool, error) {
 	if config == nil {
 		return nil, false, errors.New("config cannot be nil")
 	}
@@ -75,6 +108,7 @@ func buildRedisClient(config *DistributedConfig) (redis.UniversalClient, bool, e
 		DialTimeout:  config.DialTimeout,
 		ReadTimeout:  config.ReadTimeout,
 		WriteTimeout: config.WriteTimeout,
+		OnConnect:    onConnect,
 	})
 
 	// Enable OpenTelemetry instrumentation only when we own the client
@@ -99,6 +133,140 @@ func buildRedisClient(config *DistributedConfig) (redis.UniversalClient, bool, e
 	return client, true, nil
 }
 
+// deleteKeys removes keys in a single MULTI/EXEC transaction.
+// Cluster clients run one transaction per hash slot.
+func deleteKeys(ctx context.Context, client redis.UniversalClient, keys []string) error {
+	if client == nil || len(keys) == 0 {
+		return nil
+	}
+
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
+
+// redisTTL reports the lifetime left to key with PTTL, which replies -2 for
+// missing keys and -1 for keys without expiry.
+func redisTTL(ctx context.Context, client redis.UniversalClient, key string) (time.Duration, bool, error) {
+	ttl, err := client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, closedErr(err)
+	}
+	switch {
+	case ttl == -2:
+		return 0, false, nil
+	case ttl < 0:
+		return 0, true, nil
+	}
+	// Keys expiring within the millisecond must not read as not expiring
+	return max(ttl, time.Millisecond), true, nil
+}
+
+// redisExpire sets the lifetime of key with PEXPIRE, or PERSIST for no
+// expiry.
+func redisExpire(ctx context.Context, client redis.UniversalClient, key string, ttl time.Duration) error {
+	if ttl > 0 {
+		ok, err := client.PExpire(ctx, key, ttl).Result()
+		if err != nil {
+			return closedErr(err)
+		}
+		if !ok {
+			return ErrKeyNotFound
+		}
+		return nil
+	}
+
+	ok, err := client.Persist(ctx, key).Result()
+	if err != nil || ok {
+		return closedErr(err)
+	}
+	// PERSIST also replies 0 for keys that exist without expiry
+	n, err := client.Exists(ctx, key).Result()
+	if err != nil {
+		return closedErr(err)
+	}
+	if n == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// redisSetIfAbsent stores data with SET NX, stamped with its write time when
+// rejectOlder is set so later ordered writes can compare against it.
+func redisSetIfAbsent(ctx context.Context, client redis.UniversalClient, key string, data []byte, ttl time.Duration, rejectOlder bool) (bool, error) {
+	if rejectOlder {
+		data = stampValue(writeTimestampOf(ctx), data)
+	}
+	stored, err := client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return stored, nil
+}
+
+// redisGetDel removes key with GETDEL and returns what it held, without its
+// write-time stamp when rejectOlder is set.
+func redisGetDel(ctx context.Context, client redis.UniversalClient, key string, rejectOlder bool) ([]byte, bool, error) {
+	data, err := client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, closedErr(err)
+	}
+	if rejectOlder {
+		data = unstampValue(data)
+	}
+	return data, true, nil
+}
+
+// compareAndSwapScript replaces the value of a key if it equals the old one,
+// ignoring write-time stamps, returning 0 when the key is missing or holds
+// another value.
+//
+// KEYS[1] key, ARGV[1] old value, ARGV[2] new value, ARGV[3] TTL in ms (0
+// for none), ARGV[4] stamp magic
+var compareAndSwapScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+if #current >= 12 and string.sub(current, 1, 4) == ARGV[4] then
+	current = string.sub(current, 13)
+end
+if current ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// redisCompareAndSwap stores newData if key holds oldData, stamped with its
+// write time when rejectOlder is set.
+func redisCompareAndSwap(ctx context.Context, client redis.UniversalClient, key string, oldData, newData []byte, ttl time.Duration, rejectOlder bool) (bool, error) {
+	ttlMillis := ttl.Milliseconds()
+	if ttl > 0 && ttlMillis == 0 {
+		ttlMillis = 1
+	}
+	if rejectOlder {
+		newData = stampValue(writeTimestampOf(ctx), newData)
+	}
+	swapped, err := compareAndSwapScript.Run(ctx, client, []string{key},
+		oldData, newData, ttlMillis, timestampMagic).Int()
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return swapped == 1, nil
+}
+
 // NewDistributed creates a new distributed cache for proto messages.
 // This is a convenience function for creating distributed caches directly.
 func NewDistributed[T proto.Message](config *DistributedConfig) (Cache[T], error) {
@@ -108,15 +276,27 @@ func NewDistributed[T proto.Message](config *DistributedConfig) (Cache[T], error
 // NewDistributedForProto creates a new distributed cache for proto messages.
 // This is an internal function used by the factory.
 func NewDistributedForProto[T proto.Message](config *DistributedConfig) (Cache[T], error) {
-	client, ownsClient, err := buildRedisClient(config)
+	if usesGenericProtoPath(config) {
+		return NewDistributedGeneric[T](config)
+	}
+
+	tracking, err := newServerTracking(config)
+	if err != nil {
+		return nil, err
+	}
+	client, ownsClient, err := buildRedisClient(config, tracking.connectHook())
 	if err != nil {
+		_ = tracking.close()
 		return nil, err
 	}
 
-	return &distributedCache[T]{
-		client:     client,
-		ownsClient: ownsClient,
-	}, nil
+	return withClientSideCache[T](&distributedCache[T]{
+		client:      client,
+		ownsClient:  ownsClient,
+		rejectOlder: config.RejectOlderWrites,
+		allowFlush:  config.AllowFlushDB,
+		degraded:    newDegradedHandler[T](config.Degraded, config.Logger),
+	}, tracking, config.ClientSideCache), nil
 }
 
 // NewDistributedGeneric creates a new distributed cache for any type.
@@ -126,12 +306,19 @@ func NewDistributedGeneric[T any](config *DistributedConfig) (Cache[T], error) {
 		return nil, errors.New("config cannot be nil")
 	}
 
+	if err := checkValueType[T](config.Serializer); err != nil {
+		return nil, err
+	}
+
 	// Set up serialization
 	var serializer Serializer
 	var err error
 
 	if config.Serializer != nil {
 		serializer = config.Serializer
+	} else if isProtoType[T]() && (config.SerializationType == "" || config.SerializationType == SerializationProtobuf) {
+		// Proto messages reach here when compressed or chained
+		serializer = protobufSerializer{}
 	} else {
 		// Default to JSON if no serialization type specified
 		serializationType := config.SerializationType
@@ -144,22 +331,46 @@ func NewDistributedGeneric[T any](config *DistributedConfig) (Cache[T], error) {
 		}
 	}
 
-	client, ownsClient, err := buildRedisClient(config)
+	if config.Compression != CompressionNone {
+		compressed, err := NewCompressedSerializer(serializer, config.Compression)
+		if err != nil {
+			return nil, err
+		}
+		compressed.minSize = config.CompressionMinSize
+		serializer = compressed
+	}
+
+	serializer, err = ChainSerializer(serializer, config.SerializerChain...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracking, err := newServerTracking(config)
+	if err != nil {
+		return nil, err
+	}
+	client, ownsClient, err := buildRedisClient(config, tracking.connectHook())
 	if err != nil {
+		_ = tracking.close()
 		return nil, err
 	}
 
-	return &distributedGenericCache[T]{
-		client:     client,
-		serializer: serializer,
-		ownsClient: ownsClient,
-	}, nil
+	return withClientSideCache[T](&distributedGenericCache[T]{
+		client:      client,
+		serializer:  serializer,
+		ownsClient:  ownsClient,
+		rejectOlder: config.RejectOlderWrites,
+		allowFlush:  config.AllowFlushDB,
+		degraded:    newDegradedHandler[T](config.Degraded, config.Logger),
+	}, tracking, config.ClientSideCache), nil
 }
 
-// isProtoMessage checks if a type implements proto.Message using reflection
-func isProtoMessage(v interface{}) bool {
-	_, ok := v.(proto.Message)
-	return ok
+// usesGenericProtoPath reports whether proto messages must go through the
+// generic implementation, which supports custom serializers, protojson,
+// compression and serializer chains.
+func usesGenericProtoPath(config *DistributedConfig) bool {
+	return config != nil && (config.Serializer != nil || config.SerializationType == SerializationProtoJSON ||
+		config.Compression != CompressionNone || len(config.SerializerChain) > 0)
 }
 
 // createDistributedCacheForProto creates a distributed cache for proto messages
@@ -168,31 +379,67 @@ func createDistributedCacheForProto[T any](config *DistributedConfig) (Cache[T],
 		return nil, errors.New("config cannot be nil")
 	}
 
-	client, ownsClient, err := buildRedisClient(config)
+	tracking, err := newServerTracking(config)
+	if err != nil {
+		return nil, err
+	}
+	client, ownsClient, err := buildRedisClient(config, tracking.connectHook())
 	if err != nil {
+		_ = tracking.close()
 		return nil, err
 	}
 
-	return &distributedCache[T]{
-		client:     client,
-		ownsClient: ownsClient,
-	}, nil
+	return withClientSideCache[T](&distributedCache[T]{
+		client:      client,
+		ownsClient:  ownsClient,
+		rejectOlder: config.RejectOlderWrites,
+		allowFlush:  config.AllowFlushDB,
+		degraded:    newDegradedHandler[T](config.Degraded, config.Logger),
+	}, tracking, config.ClientSideCache), nil
 }
 
 // Methods for distributedCache (proto messages)
 
 func (c *distributedCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+// GetWithError reads a value, applying the degraded-mode policy to backend and decode failures.
+func (c *distributedCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
 	var zero T
 
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
 	if c.client == nil {
-		return zero, false
+		return zero, false, nil
 	}
 
 	// Get the serialized data
 	data, err := c.client.Get(ctx, key).Bytes()
+	return c.decodeReply(ctx, key, data, err)
+}
+
+// decodeReply turns the reply to a GET of key into a value, applying the
+// degraded-mode policy to backend and decode failures.
+func (c *distributedCache[T]) decodeReply(ctx context.Context, key string, data []byte, err error) (T, bool, error) {
+	var zero T
+
+	if errors.Is(err, redis.Nil) {
+		c.stats.read(false, nil)
+		return zero, false, nil
+	}
+	if errors.Is(err, redis.ErrClosed) {
+		return zero, false, ErrClosed
+	}
 	if err != nil {
-		// Key not found or other error - treat as cache miss
-		return zero, false
+		c.stats.read(false, err)
+		return c.degraded.backendDown(ctx, key, err)
+	}
+	if c.rejectOlder {
+		data = unstampValue(data)
 	}
 
 	// Check if T is a proto.Message
@@ -202,18 +449,110 @@ func (c *distributedCache[T]) Get(ctx context.Context, key string) (T, bool) {
 
 		// Deserialize the proto message
 		if err := proto.Unmarshal(data, any(result).(proto.Message)); err != nil {
-			// Failed to deserialize - treat as cache miss
-			return zero, false
+			c.stats.read(false, err)
+			return c.degraded.serializerError(ctx, key, err, c.Delete)
 		}
 
-		return result, true
+		c.stats.read(true, nil)
+		c.degraded.remember(key, result)
+		return result, true, nil
 	}
 
 	// This should not happen if we're using this cache correctly
-	return zero, false
+	return zero, false, nil
+}
+
+// GetMulti reads keys in one pipelined round trip, applying the
+// degraded-mode policy to each key on its own.
+func (c *distributedCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	if c.closed.isClosed() {
+		return keyResults[T](keys, ErrClosed)
+	}
+
+	if c.client == nil {
+		return keyResults[T](keys, nil)
+	}
+	return getPipelined(ctx, c.client, keys, c.decodeReply)
+}
+
+func (c *distributedCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	n, err := c.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return n > 0, nil
+}
+
+func (c *distributedCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	if c.client == nil {
+		return 0, false, nil
+	}
+
+	return redisTTL(ctx, c.client, key)
+}
+
+func (c *distributedCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.client == nil {
+		return ErrKeyNotFound
+	}
+
+	return redisExpire(ctx, c.client, key, ttl)
+}
+
+// GetDel removes key and returns its value with GETDEL. Unlike GetWithError
+// it never serves a stale copy when the backend fails, since the value
+// wasn't consumed.
+func (c *distributedCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	if c.client == nil {
+		return zero, false, nil
+	}
+
+	data, found, err := redisGetDel(ctx, c.client, key, c.rejectOlder)
+	if err != nil {
+		return zero, false, err
+	}
+	c.degraded.forget(key)
+	if !found {
+		return zero, false, nil
+	}
+
+	if _, ok := any(zero).(proto.Message); !ok {
+		return zero, false, errors.New("distributedCache can only be used with proto.Message types")
+	}
+	result := reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+	if err := proto.Unmarshal(data, any(result).(proto.Message)); err != nil {
+		return zero, false, err
+	}
+	return result, true, nil
 }
 
 func (c *distributedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
 	if c.client == nil {
 		return nil
 	}
@@ -223,26 +562,149 @@ func (c *distributedCache[T]) Set(ctx context.Context, key string, value T, ttl
 		// Serialize the proto message
 		data, err := proto.Marshal(protoMsg)
 		if err != nil {
-			return err
+			return c.degraded.setSerializerError(ctx, key, err)
 		}
 
 		// Store with TTL
-		return c.client.Set(ctx, key, data, ttl).Err()
+		err = writeValue(ctx, c.client, key, data, ttl, c.rejectOlder)
+		c.stats.wrote(err)
+		if err != nil {
+			return err
+		}
+		c.degraded.remember(key, value)
+		return nil
 	}
 
 	// This should not happen if we're using this cache correctly
 	return errors.New("distributedCache can only be used with proto.Message types")
 }
 
+func (c *distributedCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	protoMsg, ok := any(value).(proto.Message)
+	if !ok {
+		return false, errors.New("distributedCache can only be used with proto.Message types")
+	}
+	data, err := proto.Marshal(protoMsg)
+	if err != nil {
+		return false, c.degraded.setSerializerError(ctx, key, err)
+	}
+
+	stored, err := redisSetIfAbsent(ctx, c.client, key, data, ttl, c.rejectOlder)
+	if stored {
+		c.degraded.remember(key, value)
+	}
+	return stored, err
+}
+
+func (c *distributedCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	oldMsg, ok := any(old).(proto.Message)
+	if !ok {
+		return false, errors.New("distributedCache can only be used with proto.Message types")
+	}
+	// The stored bytes are compared, so marshal both the way Set does
+	oldData, err := proto.Marshal(oldMsg)
+	if err != nil {
+		return false, err
+	}
+	newData, err := proto.Marshal(any(new).(proto.Message))
+	if err != nil {
+		return false, c.degraded.setSerializerError(ctx, key, err)
+	}
+
+	swapped, err := redisCompareAndSwap(ctx, c.client, key, oldData, newData, ttl, c.rejectOlder)
+	if swapped {
+		c.degraded.remember(key, new)
+	}
+	return swapped, err
+}
+
 func (c *distributedCache[T]) Delete(ctx context.Context, key string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.client == nil {
+		return nil
+	}
+
+	c.degraded.forget(key)
+	err := closedErr(c.client.Del(ctx, key).Err())
+	c.stats.deleted(1, err)
+	return err
+}
+
+func (c *distributedCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		c.degraded.forget(key)
+	}
+	err := closedErr(deleteKeys(ctx, c.client, keys))
+	c.stats.deleted(len(keys), err)
+	return err
+}
+
+// Clear flushes the whole Redis/Valkey database when AllowFlushDB is set, and
+// fails with ErrNotClearable otherwise; wrap the cache WithPrefix to only
+// clear its namespace.
+func (c *distributedCache[T]) Clear(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if !c.allowFlush {
+		return ErrNotClearable
+	}
+
 	if c.client == nil {
 		return nil
 	}
 
-	return c.client.Del(ctx, key).Err()
+	c.degraded.forgetAll()
+	return flushRedis(ctx, c.client)
 }
 
+// clearPrefix removes the keys starting with prefix with SCAN and UNLINK.
+func (c *distributedCache[T]) clearPrefix(ctx context.Context, prefix string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.client == nil {
+		return nil
+	}
+
+	c.degraded.forgetAll()
+	return unlinkMatching(ctx, c.client, globReplacer.Replace(prefix)+"*")
+}
+
+// Close releases the cache. It is idempotent and safe to call concurrently
+// with other operations, which fail with ErrClosed afterwards. A client
+// passed in DistributedConfig.Client is left open.
 func (c *distributedCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
+
+	c.degraded.close()
 	if c.client != nil && c.ownsClient {
 		return c.client.Close()
 	}
@@ -250,26 +712,68 @@ func (c *distributedCache[T]) Close() error {
 }
 
 func (c *distributedCache[T]) Ping(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
 	if c.client == nil {
 		return nil
 	}
-	return c.client.Ping(ctx).Err()
+	return closedErr(c.client.Ping(ctx).Err())
+}
+
+// Stats returns the traffic counters of this client; the server's own
+// evictions aren't reported.
+func (c *distributedCache[T]) Stats() CacheStats {
+	return c.stats.snapshot()
+}
+
+func (c *distributedCache[T]) redisClient() redis.UniversalClient {
+	return c.client
 }
 
 // Methods for distributedGenericCache (any type)
 
 func (c *distributedGenericCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+// GetWithError reads a value, applying the degraded-mode policy to backend and decode failures.
+func (c *distributedGenericCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
 	var zero T
 
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
 	if c.client == nil {
-		return zero, false
+		return zero, false, nil
 	}
 
 	// Get the serialized data
 	data, err := c.client.Get(ctx, key).Bytes()
+	return c.decodeReply(ctx, key, data, err)
+}
+
+// decodeReply turns the reply to a GET of key into a value, applying the
+// degraded-mode policy to backend and decode failures.
+func (c *distributedGenericCache[T]) decodeReply(ctx context.Context, key string, data []byte, err error) (T, bool, error) {
+	var zero T
+
+	if errors.Is(err, redis.Nil) {
+		c.stats.read(false, nil)
+		return zero, false, nil
+	}
+	if errors.Is(err, redis.ErrClosed) {
+		return zero, false, ErrClosed
+	}
 	if err != nil {
-		// Key not found or other error - treat as cache miss
-		return zero, false
+		c.stats.read(false, err)
+		return c.degraded.backendDown(ctx, key, err)
+	}
+	if c.rejectOlder {
+		data = unstampValue(data)
 	}
 
 	// Create a new instance of T
@@ -277,14 +781,103 @@ func (c *distributedGenericCache[T]) Get(ctx context.Context, key string) (T, bo
 
 	// Deserialize the data
 	if err := c.serializer.Deserialize(data, &result); err != nil {
-		// Failed to deserialize - treat as cache miss
-		return zero, false
+		c.stats.read(false, err)
+		return c.degraded.serializerError(ctx, key, err, c.Delete)
+	}
+
+	c.stats.read(true, nil)
+	c.degraded.remember(key, result)
+	return result, true, nil
+}
+
+// GetMulti reads keys in one pipelined round trip, applying the
+// degraded-mode policy to each key on its own.
+func (c *distributedGenericCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	if c.closed.isClosed() {
+		return keyResults[T](keys, ErrClosed)
+	}
+
+	if c.client == nil {
+		return keyResults[T](keys, nil)
+	}
+	return getPipelined(ctx, c.client, keys, c.decodeReply)
+}
+
+func (c *distributedGenericCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	n, err := c.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, closedErr(err)
+	}
+	return n > 0, nil
+}
+
+func (c *distributedGenericCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	if c.client == nil {
+		return 0, false, nil
 	}
 
-	return result, true
+	return redisTTL(ctx, c.client, key)
+}
+
+func (c *distributedGenericCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.client == nil {
+		return ErrKeyNotFound
+	}
+
+	return redisExpire(ctx, c.client, key, ttl)
+}
+
+// GetDel removes key and returns its value with GETDEL. Unlike GetWithError
+// it never serves a stale copy when the backend fails, since the value
+// wasn't consumed.
+func (c *distributedGenericCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	if c.client == nil {
+		return zero, false, nil
+	}
+
+	data, found, err := redisGetDel(ctx, c.client, key, c.rejectOlder)
+	if err != nil {
+		return zero, false, err
+	}
+	c.degraded.forget(key)
+	if !found {
+		return zero, false, nil
+	}
+
+	var result T
+	if err := c.serializer.Deserialize(data, &result); err != nil {
+		return zero, false, err
+	}
+	return result, true, nil
 }
 
 func (c *distributedGenericCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
 	if c.client == nil {
 		return nil
 	}
@@ -292,22 +885,136 @@ func (c *distributedGenericCache[T]) Set(ctx context.Context, key string, value
 	// Serialize the value
 	data, err := c.serializer.Serialize(value)
 	if err != nil {
-		return err
+		return c.degraded.setSerializerError(ctx, key, err)
 	}
 
 	// Store with TTL
-	return c.client.Set(ctx, key, data, ttl).Err()
+	err = writeValue(ctx, c.client, key, data, ttl, c.rejectOlder)
+	c.stats.wrote(err)
+	if err != nil {
+		return err
+	}
+	c.degraded.remember(key, value)
+	return nil
+}
+
+func (c *distributedGenericCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	data, err := c.serializer.Serialize(value)
+	if err != nil {
+		return false, c.degraded.setSerializerError(ctx, key, err)
+	}
+
+	stored, err := redisSetIfAbsent(ctx, c.client, key, data, ttl, c.rejectOlder)
+	if stored {
+		c.degraded.remember(key, value)
+	}
+	return stored, err
+}
+
+func (c *distributedGenericCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.client == nil {
+		return false, nil
+	}
+
+	oldData, err := c.serializer.Serialize(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.serializer.Serialize(new)
+	if err != nil {
+		return false, c.degraded.setSerializerError(ctx, key, err)
+	}
+
+	swapped, err := redisCompareAndSwap(ctx, c.client, key, oldData, newData, ttl, c.rejectOlder)
+	if swapped {
+		c.degraded.remember(key, new)
+	}
+	return swapped, err
 }
 
 func (c *distributedGenericCache[T]) Delete(ctx context.Context, key string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
 	if c.client == nil {
 		return nil
 	}
 
-	return c.client.Del(ctx, key).Err()
+	c.degraded.forget(key)
+	err := closedErr(c.client.Del(ctx, key).Err())
+	c.stats.deleted(1, err)
+	return err
+}
+
+func (c *distributedGenericCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		c.degraded.forget(key)
+	}
+	err := closedErr(deleteKeys(ctx, c.client, keys))
+	c.stats.deleted(len(keys), err)
+	return err
 }
 
+// Clear flushes the whole Redis/Valkey database when AllowFlushDB is set, and
+// fails with ErrNotClearable otherwise; wrap the cache WithPrefix to only
+// clear its namespace.
+func (c *distributedGenericCache[T]) Clear(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if !c.allowFlush {
+		return ErrNotClearable
+	}
+
+	if c.client == nil {
+		return nil
+	}
+
+	c.degraded.forgetAll()
+	return flushRedis(ctx, c.client)
+}
+
+// clearPrefix removes the keys starting with prefix with SCAN and UNLINK.
+func (c *distributedGenericCache[T]) clearPrefix(ctx context.Context, prefix string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.client == nil {
+		return nil
+	}
+
+	c.degraded.forgetAll()
+	return unlinkMatching(ctx, c.client, globReplacer.Replace(prefix)+"*")
+}
+
+// Close releases the cache. It is idempotent and safe to call concurrently
+// with other operations, which fail with ErrClosed afterwards. A client
+// passed in DistributedConfig.Client is left open.
 func (c *distributedGenericCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
+
+	c.degraded.close()
 	if c.client != nil && c.ownsClient {
 		return c.client.Close()
 	}
@@ -315,8 +1022,22 @@ func (c *distributedGenericCache[T]) Close() error {
 }
 
 func (c *distributedGenericCache[T]) Ping(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
 	if c.client == nil {
 		return nil
 	}
-	return c.client.Ping(ctx).Err()
+	return closedErr(c.client.Ping(ctx).Err())
+}
+
+// Stats returns the traffic counters of this client; the server's own
+// evictions aren't reported.
+func (c *distributedGenericCache[T]) Stats() CacheStats {
+	return c.stats.snapshot()
+}
+
+func (c *distributedGenericCache[T]) redisClient() redis.UniversalClient {
+	return c.client
 }
@@ -346,6 +346,46 @@ func testCacheOperations(
 	}
 }
 
+// startValkey starts a Valkey container for the duration of the test and returns its address.
+func startValkey(t *testing.T) string {
+	t.Helper()
+
+	// Skip if Docker is not available
+	if !isDockerAvailable() {
+		t.Skip("Docker not available, skipping testcontainers test")
+	}
+
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "valkey/valkey:7.2-alpine",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections"),
+	}
+
+	valkeyContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Valkey container: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = valkeyContainer.Terminate(ctx)
+	})
+
+	host, err := valkeyContainer.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := valkeyContainer.MappedPort(ctx, "6379")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	return host + ":" + port.Port()
+}
+
 // Helper function to check if Docker is available
 func isDockerAvailable() bool {
 	// Simple check - try to create a container request
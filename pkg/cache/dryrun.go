@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DryRunOperation describes a write that a dry-run cache skipped.
+type DryRunOperation struct {
+	// Operation is OperationSet, OperationSetIfAbsent,
+	// OperationCompareAndSwap, OperationDelete, OperationExpire or
+	// OperationClear.
+	Operation Operation
+
+	// Key is the key the operation would have touched (empty for Clear).
+	Key string
+
+	// TTL is the TTL the value would have been stored with (Set,
+	// SetIfAbsent and CompareAndSwap) or the new lifetime (Expire).
+	TTL time.Duration
+}
+
+// DryRunRecorder receives every operation a dry-run cache skips.
+type DryRunRecorder func(ctx context.Context, op DryRunOperation)
+
+// dryRunCache forwards reads to the wrapped cache but only records writes.
+type dryRunCache[T any] struct {
+	next   Cache[T]
+	record DryRunRecorder
+}
+
+// NewDryRun wraps a cache so that Set, Delete and Clear are reported to record
+// instead of being executed, while Get keeps reading from the wrapped cache.
+// This is useful for validating new invalidation logic against production
+// traffic. When record is nil, operations are logged with slog.Default().
+func NewDryRun[T any](cache Cache[T], record DryRunRecorder) Cache[T] {
+	if record == nil {
+		record = logDryRunOperation
+	}
+	return &dryRunCache[T]{
+		next:   cache,
+		record: record,
+	}
+}
+
+func logDryRunOperation(ctx context.Context, op DryRunOperation) {
+	slog.Default().InfoContext(ctx, "cache dry-run: skipped operation",
+		slog.String("operation", string(op.Operation)),
+		slog.String("key", op.Key),
+		slog.Duration("ttl", op.TTL),
+	)
+}
+
+func (c *dryRunCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *dryRunCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *dryRunCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *dryRunCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *dryRunCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *dryRunCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	c.record(ctx, DryRunOperation{Operation: OperationExpire, Key: key, TTL: ttl})
+	return nil
+}
+
+// SetIfAbsent reports whether the value would have been stored.
+func (c *dryRunCache[T]) SetIfAbsent(ctx context.Context, key string, _ T, ttl time.Duration) (bool, error) {
+	c.record(ctx, DryRunOperation{Operation: OperationSetIfAbsent, Key: key, TTL: ttl})
+	found, err := Exists(ctx, c.next, key)
+	return err == nil && !found, err
+}
+
+// CompareAndSwap reports whether the value would have been swapped.
+func (c *dryRunCache[T]) CompareAndSwap(ctx context.Context, key string, old, _ T, ttl time.Duration) (bool, error) {
+	c.record(ctx, DryRunOperation{Operation: OperationCompareAndSwap, Key: key, TTL: ttl})
+	current, found, err := GetWithError(ctx, c.next, key)
+	return err == nil && found && valuesEqual(current, old), err
+}
+
+// GetDel records the delete and reads the value without removing it.
+func (c *dryRunCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	c.record(ctx, DryRunOperation{Operation: OperationGetDel, Key: key})
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *dryRunCache[T]) Set(ctx context.Context, key string, _ T, ttl time.Duration) error {
+	c.record(ctx, DryRunOperation{Operation: OperationSet, Key: key, TTL: ttl})
+	return nil
+}
+
+func (c *dryRunCache[T]) Delete(ctx context.Context, key string) error {
+	c.record(ctx, DryRunOperation{Operation: OperationDelete, Key: key})
+	return nil
+}
+
+func (c *dryRunCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.record(ctx, DryRunOperation{Operation: OperationDelete, Key: key})
+	}
+	return nil
+}
+
+func (c *dryRunCache[T]) Clear(ctx context.Context) error {
+	c.record(ctx, DryRunOperation{Operation: OperationClear})
+	return nil
+}
+
+func (c *dryRunCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *dryRunCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *dryRunCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
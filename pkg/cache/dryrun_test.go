@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDryRunCache(t *testing.T) {
+	inner := NewMemory[TestUser](nil)
+	defer inner.Close()
+
+	ctx := context.Background()
+	user := TestUser{ID: "123", Name: "John"}
+
+	// Seed the backend directly so Get has something to read
+	if err := inner.Set(ctx, "existing", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	var recorded []DryRunOperation
+	cache := NewDryRun(inner, func(_ context.Context, op DryRunOperation) {
+		recorded = append(recorded, op)
+	})
+
+	// Test Set is recorded but not executed
+	err := cache.Set(ctx, "key1", user, time.Minute)
+	if err != nil {
+		t.Errorf("Set failed: %v", err)
+	}
+	if _, found := inner.Get(ctx, "key1"); found {
+		t.Error("Expected dry-run Set not to reach the backend")
+	}
+
+	// Test Delete is recorded but not executed
+	err = cache.Delete(ctx, "existing")
+	if err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+
+	// Test Get reads from the backend
+	retrieved, found := cache.Get(ctx, "existing")
+	if !found {
+		t.Error("Expected dry-run Delete not to remove existing key")
+	}
+	if retrieved.ID != user.ID {
+		t.Errorf("Expected ID %s, got %s", user.ID, retrieved.ID)
+	}
+
+	expected := []DryRunOperation{
+		{Operation: OperationSet, Key: "key1", TTL: time.Minute},
+		{Operation: OperationDelete, Key: "existing"},
+	}
+	if len(recorded) != len(expected) {
+		t.Fatalf("Expected %d recorded operations, got %d", len(expected), len(recorded))
+	}
+	for i := range expected {
+		if recorded[i] != expected[i] {
+			t.Errorf("Expected operation %+v, got %+v", expected[i], recorded[i])
+		}
+	}
+}
+
+func TestFactoryDryRun(t *testing.T) {
+	cache, err := New[TestUser](&Config{Type: TypeMemory, DryRun: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute); err != nil {
+		t.Errorf("Set failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected dry-run factory cache not to store values")
+	}
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotDurable is returned by SetDurable when the write was not acknowledged
+// by enough replicas in time, or the cache could not issue a durable write.
+// The value may still have been written to the primary.
+var ErrNotDurable = errors.New("write was not acknowledged by enough replicas")
+
+// DurabilityOptions holds the replication requirements of SetDurable.
+type DurabilityOptions struct {
+	// Replicas is the number of replicas that must acknowledge the write (default: 1)
+	Replicas int
+
+	// Timeout is how long to wait for the acknowledgements (default: 1s)
+	Timeout time.Duration
+}
+
+// durabilityRequest asks the distributed backend, through the context, to
+// wait for replication after a write.
+type durabilityRequest struct {
+	options DurabilityOptions
+	issued  atomic.Bool
+}
+
+type durabilityKey struct{}
+
+// SetDurable stores value like Set, then blocks until options.Replicas
+// replicas acknowledged the write with Redis WAIT, trading latency for
+// durability. Use it for the rare entries that act as a short-lived source of
+// truth, such as idempotency keys. WAIT makes losing an acknowledged write on
+// failover unlikely, but not impossible.
+//
+// Returns ErrNotDurable when too few replicas acknowledged in time, or when no
+// durable write was issued: the backend isn't Redis/Valkey (or a Redis Ring),
+// or a decorator skipped or deferred the write (e.g. SkipWriteIfEqual,
+// WriteDampening, DryRun or asynchronous writes).
+func SetDurable[T any](ctx context.Context, cache Cache[T], key string, value T, ttl time.Duration, options *DurabilityOptions) error {
+	var opts DurabilityOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Replicas <= 0 {
+		opts.Replicas = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Second
+	}
+
+	request := &durabilityRequest{options: opts}
+	if err := cache.Set(context.WithValue(ctx, durabilityKey{}, request), key, value, ttl); err != nil {
+		return err
+	}
+	if !request.issued.Load() {
+		return fmt.Errorf("%w: the cache did not issue a durable write", ErrNotDurable)
+	}
+	return nil
+}
+
+// writeValue stores data under key, waiting for replication when the write
+// is part of a SetDurable call.
+func writeValue(ctx context.Context, client redis.UniversalClient, key string, data []byte, ttl time.Duration, rejectOlder bool) error {
+	request, ok := ctx.Value(durabilityKey{}).(*durabilityRequest)
+	if !ok {
+		if rejectOlder {
+			return setIfNewer(ctx, client, key, data, ttl)
+		}
+		return closedErr(client.Set(ctx, key, data, ttl).Err())
+	}
+
+	// WAIT counts the writes issued on its own connection, so both commands
+	// must run on one connection to the primary owning the key
+	var node *redis.Client
+	switch c := client.(type) {
+	case *redis.Client:
+		node = c
+	case *redis.ClusterClient:
+		var err error
+		if node, err = c.MasterForKey(ctx, key); err != nil {
+			return closedErr(err)
+		}
+	default:
+		if rejectOlder {
+			return setIfNewer(ctx, client, key, data, ttl)
+		}
+		return closedErr(client.Set(ctx, key, data, ttl).Err())
+	}
+
+	conn := node.Conn()
+	defer conn.Close()
+
+	if rejectOlder {
+		if err := setIfNewer(ctx, conn, key, data, ttl); err != nil {
+			return err
+		}
+	} else if err := conn.Set(ctx, key, data, ttl).Err(); err != nil {
+		return closedErr(err)
+	}
+	request.issued.Store(true)
+
+	acked, err := conn.Wait(ctx, request.options.Replicas, request.options.Timeout).Result()
+	if err != nil {
+		return closedErr(err)
+	}
+	if int(acked) < request.options.Replicas {
+		return fmt.Errorf("%w: %d of %d replicas acknowledged", ErrNotDurable, acked, request.options.Replicas)
+	}
+	return nil
+}
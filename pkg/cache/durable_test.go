@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetDurableRequiresDistributedCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	err := SetDurable(ctx, cache, "key1", TestUser{ID: "123"}, time.Minute, nil)
+	if !errors.Is(err, ErrNotDurable) {
+		t.Errorf("Expected ErrNotDurable, got: %v", err)
+	}
+}
+
+func TestSetDurableWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := New[TestUser](&Config{
+		Type:        TypeDistributed,
+		Distributed: &DistributedConfig{Addr: addr},
+		KeyPrefix:   "idempotency:",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	// Test a standalone server without replicas can't acknowledge the write
+	start := time.Now()
+	err = SetDurable(ctx, cache, "key1", TestUser{ID: "123"}, time.Minute, &DurabilityOptions{Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, ErrNotDurable) || !strings.Contains(err.Error(), "0 of 1") {
+		t.Errorf("Expected ErrNotDurable with 0 of 1 replicas, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected SetDurable to wait for replicas, returned after %v", elapsed)
+	}
+
+	// Test the value was still written to the primary
+	if user, found := cache.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Errorf("Expected key1 on the primary, got %v, %v", user, found)
+	}
+}
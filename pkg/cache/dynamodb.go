@@ -0,0 +1,440 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// dynamoDBBatchSize is the maximum number of requests in one BatchWriteItem call.
+const dynamoDBBatchSize = 25
+
+// dynamoDBBatchAttempts bounds the retries of unprocessed batch deletes.
+const dynamoDBBatchAttempts = 5
+
+// dynamoDBBatchBackoff is the wait before the first retry of unprocessed
+// batch deletes, doubling with each retry, as DynamoDB recommends for
+// throttled batches.
+const dynamoDBBatchBackoff = 50 * time.Millisecond
+
+// DynamoDBAPI is the subset of the DynamoDB client used by the DynamoDB
+// cache; *dynamodb.Client implements it.
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// dynamoDBCache stores entries as items of a DynamoDB table. Expiry is a
+// number attribute holding unix seconds, meant to be the table's TTL
+// attribute; since DynamoDB deletes expired items lazily, reads also treat
+// expired items as misses.
+type dynamoDBCache[T any] struct {
+	client         DynamoDBAPI
+	table          *string
+	keyAttribute   string
+	valueAttribute string
+	ttlAttribute   string
+	consistentRead *bool
+	codec          valueCodec[T]
+	closed         closeGuard
+}
+
+// NewDynamoDB creates a cache stored in a DynamoDB table whose partition key
+// is a string attribute named KeyAttribute. The client is owned by the caller.
+func NewDynamoDB[T any](config *DynamoDBConfig) (Cache[T], error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if config.Client == nil {
+		return nil, errors.New("DynamoDB client is required")
+	}
+	if config.Table == "" {
+		return nil, errors.New("DynamoDB table is required")
+	}
+	if err := checkValueType[T](config.Serializer); err != nil {
+		return nil, err
+	}
+
+	c := &dynamoDBCache[T]{
+		client:         config.Client,
+		table:          aws.String(config.Table),
+		keyAttribute:   config.KeyAttribute,
+		valueAttribute: config.ValueAttribute,
+		ttlAttribute:   config.TTLAttribute,
+		consistentRead: aws.Bool(config.ConsistentRead),
+		codec:          newValueCodec[T](config.Serializer),
+	}
+	if c.keyAttribute == "" {
+		c.keyAttribute = "pk"
+	}
+	if c.valueAttribute == "" {
+		c.valueAttribute = "value"
+	}
+	if c.ttlAttribute == "" {
+		c.ttlAttribute = "expires_at"
+	}
+	return c, nil
+}
+
+func (c *dynamoDBCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *dynamoDBCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	out, err := c.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      c.table,
+		Key:            c.key(key),
+		ConsistentRead: c.consistentRead,
+	})
+	if err != nil {
+		return zero, false, err
+	}
+	if out.Item == nil || c.expired(out.Item, time.Now()) {
+		return zero, false, nil
+	}
+
+	data, ok := out.Item[c.valueAttribute].(*types.AttributeValueMemberB)
+	if !ok {
+		return zero, false, fmt.Errorf("item %q has no binary %s attribute", key, c.valueAttribute)
+	}
+	value, err := c.codec.decode(data.Value)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// Exists only fetches the key and TTL attributes, leaving the value behind.
+func (c *dynamoDBCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	item, err := c.getWithoutValue(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return item != nil && !c.expired(item, time.Now()), nil
+}
+
+// TTL reports the lifetime left to key, in whole seconds like the TTL
+// attribute stores it. Like Exists, it leaves the value behind.
+func (c *dynamoDBCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	item, err := c.getWithoutValue(ctx, key)
+	if err != nil {
+		return 0, false, err
+	}
+	now := time.Now()
+	if item == nil || c.expired(item, now) {
+		return 0, false, nil
+	}
+	expiresAt, ok := c.expiresAt(item)
+	if !ok {
+		return 0, true, nil
+	}
+	return expiresAt.Sub(now), true, nil
+}
+
+// Expire writes the stored item back with a new TTL attribute, since the
+// DynamoDBAPI subset has no UpdateItem. The value isn't re-serialized, but a
+// Set racing with it may be overwritten.
+func (c *dynamoDBCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	out, err := c.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      c.table,
+		Key:            c.key(key),
+		ConsistentRead: c.consistentRead,
+	})
+	if err != nil {
+		return err
+	}
+	if out.Item == nil || c.expired(out.Item, time.Now()) {
+		return ErrKeyNotFound
+	}
+
+	item := out.Item
+	delete(item, c.ttlAttribute)
+	if ttl > 0 {
+		// Rounded up like Set
+		expiresAt := time.Now().Add(ttl + time.Second - 1).Unix()
+		item[c.ttlAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)}
+	}
+	_, err = c.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: c.table,
+		Item:      item,
+		// Don't bring back an item deleted meanwhile
+		ConditionExpression:      aws.String("attribute_exists(#key)"),
+		ExpressionAttributeNames: map[string]string{"#key": c.keyAttribute},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// getWithoutValue fetches the key and TTL attributes of an item.
+func (c *dynamoDBCache[T]) getWithoutValue(ctx context.Context, key string) (map[string]types.AttributeValue, error) {
+	out, err := c.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:                c.table,
+		Key:                      c.key(key),
+		ConsistentRead:           c.consistentRead,
+		ProjectionExpression:     aws.String("#key, #ttl"),
+		ExpressionAttributeNames: map[string]string{"#key": c.keyAttribute, "#ttl": c.ttlAttribute},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Item, nil
+}
+
+// Set stores the serialized value. Items are limited to 400KB by DynamoDB.
+func (c *dynamoDBCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: c.table,
+		Item:      c.item(key, data, ttl),
+	})
+	return err
+}
+
+// SetIfAbsent puts the item on condition that no item exists for key or the
+// stored one has expired, since DynamoDB may not have deleted it yet.
+func (c *dynamoDBCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                c.table,
+		Item:                     c.item(key, data, ttl),
+		ConditionExpression:      aws.String("attribute_not_exists(#key) OR #ttl <= :now"),
+		ExpressionAttributeNames: map[string]string{"#key": c.keyAttribute, "#ttl": c.ttlAttribute},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwap puts the item on condition that the stored one holds the
+// serialized old value and hasn't expired.
+func (c *dynamoDBCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	oldData, err := c.codec.encode(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.encode(new)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                c.table,
+		Item:                     c.item(key, newData, ttl),
+		ConditionExpression:      aws.String("#value = :old AND (attribute_not_exists(#ttl) OR #ttl > :now)"),
+		ExpressionAttributeNames: map[string]string{"#value": c.valueAttribute, "#ttl": c.ttlAttribute},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":old": &types.AttributeValueMemberB{Value: oldData},
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// item builds the item storing data under key with ttl.
+func (c *dynamoDBCache[T]) item(key string, data []byte, ttl time.Duration) map[string]types.AttributeValue {
+	item := c.key(key)
+	item[c.valueAttribute] = &types.AttributeValueMemberB{Value: data}
+	if ttl > 0 {
+		// TTL attributes have second precision; round up so short TTLs don't expire at once
+		expiresAt := time.Now().Add(ttl + time.Second - 1).Unix()
+		item[c.ttlAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)}
+	}
+	return item
+}
+
+func (c *dynamoDBCache[T]) Delete(ctx context.Context, key string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	_, err := c.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: c.table,
+		Key:       c.key(key),
+	})
+	return err
+}
+
+// GetDel deletes the item and returns the value it held, so only one of
+// several concurrent callers gets it. Expired items not yet removed by
+// DynamoDB are deleted as misses.
+func (c *dynamoDBCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	out, err := c.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    c.table,
+		Key:          c.key(key),
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return zero, false, err
+	}
+	if out.Attributes == nil || c.expired(out.Attributes, time.Now()) {
+		return zero, false, nil
+	}
+
+	data, ok := out.Attributes[c.valueAttribute].(*types.AttributeValueMemberB)
+	if !ok {
+		return zero, false, fmt.Errorf("item %q has no binary %s attribute", key, c.valueAttribute)
+	}
+	value, err := c.codec.decode(data.Value)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// DeleteMulti removes keys in batches of 25, retrying items DynamoDB reports
+// as unprocessed with a doubling backoff. Repeated keys are removed once,
+// since DynamoDB rejects batches holding a key twice.
+func (c *dynamoDBCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	keys = uniqueKeys(keys)
+	for start := 0; start < len(keys); start += dynamoDBBatchSize {
+		batch := keys[start:min(start+dynamoDBBatchSize, len(keys))]
+
+		requests := make([]types.WriteRequest, len(batch))
+		for i, key := range batch {
+			requests[i] = types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: c.key(key)}}
+		}
+		pending := map[string][]types.WriteRequest{*c.table: requests}
+
+		wait := dynamoDBBatchBackoff
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt == dynamoDBBatchAttempts {
+				return fmt.Errorf("DynamoDB left %d deletes unprocessed", len(pending[*c.table]))
+			}
+			if attempt > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+				wait *= 2
+			}
+			out, err := c.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return err
+			}
+			pending = out.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// uniqueKeys returns keys without repeats, in order of first appearance.
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]struct{}, len(keys))
+	unique := keys[:0:0]
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// Close marks the cache closed. The client is left as is.
+func (c *dynamoDBCache[T]) Close() error {
+	c.closed.close()
+	return nil
+}
+
+func (c *dynamoDBCache[T]) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		c.keyAttribute: &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+// expired reports whether an item's TTL attribute has passed.
+func (c *dynamoDBCache[T]) expired(item map[string]types.AttributeValue, now time.Time) bool {
+	expiresAt, ok := c.expiresAt(item)
+	return ok && !now.Before(expiresAt)
+}
+
+// expiresAt returns the expiry in an item's TTL attribute, if it has one.
+func (c *dynamoDBCache[T]) expiresAt(item map[string]types.AttributeValue) (time.Time, bool) {
+	attr, ok := item[c.ttlAttribute].(*types.AttributeValueMemberN)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(attr.Value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
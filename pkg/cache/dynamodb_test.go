@@ -0,0 +1,363 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDB is an in-memory DynamoDBAPI keyed by the "pk" attribute.
+// It reports the first batch delete of each call as unprocessed when
+// unprocessOnce is set.
+type fakeDynamoDB struct {
+	mu            sync.Mutex
+	items         map[string]map[string]types.AttributeValue
+	batchCalls    int
+	unprocessOnce bool
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func pkOf(key map[string]types.AttributeValue) string {
+	return key["pk"].(*types.AttributeValueMemberS).Value
+}
+
+func (f *fakeDynamoDB) GetItem(_ context.Context, params *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: f.items[pkOf(params.Key)]}, nil
+}
+
+func (f *fakeDynamoDB) PutItem(_ context.Context, params *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pk := pkOf(params.Item)
+	if !f.conditionHolds(params, f.items[pk]) {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	f.items[pk] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// conditionHolds evaluates the condition expressions the cache uses against
+// the stored item.
+func (f *fakeDynamoDB) conditionHolds(params *dynamodb.PutItemInput, stored map[string]types.AttributeValue) bool {
+	switch aws.ToString(params.ConditionExpression) {
+	case "":
+		return true
+	case "attribute_exists(#key)":
+		return stored != nil
+	case "attribute_not_exists(#key) OR #ttl <= :now":
+		if stored == nil {
+			return true
+		}
+		expiresAt, ok := fakeExpiry(params, stored)
+		return ok && expiresAt <= fakeNow(params)
+	case "#value = :old AND (attribute_not_exists(#ttl) OR #ttl > :now)":
+		if stored == nil {
+			return false
+		}
+		value, _ := stored[params.ExpressionAttributeNames["#value"]].(*types.AttributeValueMemberB)
+		old := params.ExpressionAttributeValues[":old"].(*types.AttributeValueMemberB)
+		if value == nil || !bytes.Equal(value.Value, old.Value) {
+			return false
+		}
+		expiresAt, ok := fakeExpiry(params, stored)
+		return !ok || expiresAt > fakeNow(params)
+	}
+	panic("unexpected condition expression: " + aws.ToString(params.ConditionExpression))
+}
+
+// fakeExpiry returns the TTL attribute of a stored item, if it has one.
+func fakeExpiry(params *dynamodb.PutItemInput, stored map[string]types.AttributeValue) (int64, bool) {
+	expiry, ok := stored[params.ExpressionAttributeNames["#ttl"]].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, false
+	}
+	seconds, _ := strconv.ParseInt(expiry.Value, 10, 64)
+	return seconds, true
+}
+
+// fakeNow returns the :now value of a condition.
+func fakeNow(params *dynamodb.PutItemInput) int64 {
+	now := params.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN)
+	seconds, _ := strconv.ParseInt(now.Value, 10, 64)
+	return seconds
+}
+
+func (f *fakeDynamoDB) DeleteItem(_ context.Context, params *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pk := pkOf(params.Key)
+	out := &dynamodb.DeleteItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		out.Attributes = f.items[pk]
+	}
+	delete(f.items, pk)
+	return out, nil
+}
+
+func (f *fakeDynamoDB) BatchWriteItem(_ context.Context, params *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batchCalls++
+
+	unprocessed := make(map[string][]types.WriteRequest)
+	for table, requests := range params.RequestItems {
+		if len(requests) > dynamoDBBatchSize {
+			return nil, errors.New("too many requests in batch")
+		}
+		seen := make(map[string]bool, len(requests))
+		for _, request := range requests {
+			pk := pkOf(request.DeleteRequest.Key)
+			if seen[pk] {
+				return nil, errors.New("provided list of item keys contains duplicates")
+			}
+			seen[pk] = true
+		}
+		for i, request := range requests {
+			if f.unprocessOnce && i == 0 {
+				f.unprocessOnce = false
+				unprocessed[table] = append(unprocessed[table], request)
+				continue
+			}
+			delete(f.items, pkOf(request.DeleteRequest.Key))
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{UnprocessedItems: unprocessed}, nil
+}
+
+func TestDynamoDBCache(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, err := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	if err != nil {
+		t.Fatalf("NewDynamoDB failed: %v", err)
+	}
+	defer cache.Close()
+
+	user := TestUser{ID: "123", Name: "John"}
+	if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, found := cache.Get(ctx, "key1"); !found || got != user {
+		t.Errorf("Expected %+v, got %+v (found=%v)", user, got, found)
+	}
+	if _, ok := client.items["key1"]["expires_at"]; !ok {
+		t.Error("Expected TTL attribute to be set")
+	}
+
+	// Test entries without TTL have no TTL attribute
+	_ = cache.Set(ctx, "forever", user, 0)
+	if _, ok := client.items["forever"]["expires_at"]; ok {
+		t.Error("Expected no TTL attribute")
+	}
+
+	// Test Delete
+	_ = cache.Delete(ctx, "key1")
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted")
+	}
+}
+
+func TestDynamoDBCacheExpiredItemIsMiss(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	defer cache.Close()
+
+	// DynamoDB may serve items up to days after their TTL passed
+	_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	client.items["key1"]["expires_at"] = &types.AttributeValueMemberN{Value: past}
+
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected expired item to be a miss")
+	}
+	if _, found, _ := TTL(ctx, cache, "key1"); found {
+		t.Error("Expected expired item to have no TTL")
+	}
+}
+
+func TestDynamoDBCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: newFakeDynamoDB(), Table: "cache"})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+	_ = cache.Set(ctx, "forever", TestUser{ID: "456"}, 0)
+
+	if ttl, found, err := TTL(ctx, cache, "key1"); err != nil || !found || ttl < 59*time.Second || ttl > 61*time.Second {
+		t.Errorf("Expected about a minute left for key1, got %v, %v, %v", ttl, found, err)
+	}
+	if ttl, found, err := TTL(ctx, cache, "forever"); err != nil || !found || ttl != 0 {
+		t.Errorf("Expected forever not to expire, got %v, %v, %v", ttl, found, err)
+	}
+}
+
+func TestDynamoDBCacheExpire(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: newFakeDynamoDB(), Table: "cache"})
+	defer cache.Close()
+
+	user := TestUser{ID: "123", Name: "John"}
+	_ = cache.Set(ctx, "key1", user, time.Minute)
+	if err := Expire(ctx, cache, "key1", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "key1"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left, got %v", ttl)
+	}
+	if err := Expire(ctx, cache, "key1", 0); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, found, _ := TTL(ctx, cache, "key1"); !found || ttl != 0 {
+		t.Errorf("Expected key1 not to expire, got %v, %v", ttl, found)
+	}
+	if got, found := cache.Get(ctx, "key1"); !found || got != user {
+		t.Errorf("Expected the value to survive Expire, got %+v, %v", got, found)
+	}
+	if err := Expire(ctx, cache, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestDynamoDBCacheSetIfAbsent(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	defer cache.Close()
+
+	first := TestUser{ID: "1", Name: "John"}
+	second := TestUser{ID: "2", Name: "Jane"}
+	if stored, err := SetIfAbsent(ctx, cache, "token", first, time.Minute); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store token, got %v, %v", stored, err)
+	}
+	if stored, err := SetIfAbsent(ctx, cache, "token", second, time.Minute); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep token, got %v, %v", stored, err)
+	}
+	if got, _ := cache.Get(ctx, "token"); got != first {
+		t.Errorf("Expected %+v, got %+v", first, got)
+	}
+
+	// Expired items DynamoDB hasn't deleted yet are replaced
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	client.items["token"]["expires_at"] = &types.AttributeValueMemberN{Value: past}
+	if stored, err := SetIfAbsent(ctx, cache, "token", second, time.Minute); err != nil || !stored {
+		t.Errorf("Expected SetIfAbsent to replace the expired token, got %v, %v", stored, err)
+	}
+	if got, _ := cache.Get(ctx, "token"); got != second {
+		t.Errorf("Expected %+v, got %+v", second, got)
+	}
+
+	// Items without expiry are never replaced
+	_ = cache.Set(ctx, "forever", first, 0)
+	if stored, err := SetIfAbsent(ctx, cache, "forever", second, 0); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep forever, got %v, %v", stored, err)
+	}
+}
+
+func TestDynamoDBCacheCompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	defer cache.Close()
+
+	first := TestUser{ID: "1", Name: "John"}
+	second := TestUser{ID: "2", Name: "Jane"}
+	_ = cache.Set(ctx, "cart", first, time.Minute)
+
+	if swapped, err := CompareAndSwap(ctx, cache, "cart", second, first, time.Minute); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to fail for a stale value, got %v, %v", swapped, err)
+	}
+	if swapped, err := CompareAndSwap(ctx, cache, "cart", first, second, time.Minute); err != nil || !swapped {
+		t.Errorf("Expected CompareAndSwap to swap cart, got %v, %v", swapped, err)
+	}
+	if got, _ := cache.Get(ctx, "cart"); got != second {
+		t.Errorf("Expected %+v, got %+v", second, got)
+	}
+
+	// Expired items DynamoDB hasn't deleted yet are misses
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	client.items["cart"]["expires_at"] = &types.AttributeValueMemberN{Value: past}
+	if swapped, err := CompareAndSwap(ctx, cache, "cart", second, first, time.Minute); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to skip the expired cart, got %v, %v", swapped, err)
+	}
+	if swapped, err := CompareAndSwap(ctx, cache, "missing", first, second, time.Minute); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to skip a missing key, got %v, %v", swapped, err)
+	}
+}
+
+func TestDynamoDBCacheGetDel(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	defer cache.Close()
+
+	user := TestUser{ID: "1", Name: "John"}
+	_ = cache.Set(ctx, "otp", user, time.Minute)
+	if got, found, err := GetDel(ctx, cache, "otp"); err != nil || !found || got != user {
+		t.Errorf("Expected GetDel to return %+v, got %+v (found=%v, err=%v)", user, got, found, err)
+	}
+	if _, found, err := GetDel(ctx, cache, "otp"); err != nil || found {
+		t.Errorf("Expected the token to be consumed, got found=%v, err=%v", found, err)
+	}
+
+	// Expired items DynamoDB hasn't deleted yet are removed as misses
+	_ = cache.Set(ctx, "otp", user, time.Minute)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	client.items["otp"]["expires_at"] = &types.AttributeValueMemberN{Value: past}
+	if _, found, err := GetDel(ctx, cache, "otp"); err != nil || found {
+		t.Errorf("Expected the expired token to be a miss, got found=%v, err=%v", found, err)
+	}
+	if _, ok := client.items["otp"]; ok {
+		t.Error("Expected the expired item to be deleted")
+	}
+}
+
+func TestDynamoDBCacheDeleteMulti(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeDynamoDB()
+	cache, _ := NewDynamoDB[TestUser](&DynamoDBConfig{Client: client, Table: "cache"})
+	defer cache.Close()
+
+	keys := make([]string, 30)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+		_ = cache.Set(ctx, keys[i], TestUser{ID: keys[i]}, time.Minute)
+	}
+
+	// Repeated keys are sent once, as DynamoDB rejects duplicates in a batch
+	keys = append(keys, keys[29], keys[28])
+
+	client.unprocessOnce = true
+	if err := deleteMulti(ctx, cache, keys); err != nil {
+		t.Fatalf("DeleteMulti failed: %v", err)
+	}
+	if len(client.items) != 0 {
+		t.Errorf("Expected all items deleted, %d left", len(client.items))
+	}
+	// Two batches plus one retry of the unprocessed delete
+	if client.batchCalls != 3 {
+		t.Errorf("Expected 3 batch calls, got %d", client.batchCalls)
+	}
+}
+
+func TestNewDynamoDBValidation(t *testing.T) {
+	if _, err := NewDynamoDB[TestUser](&DynamoDBConfig{Table: "cache"}); err == nil {
+		t.Error("Expected error for missing client")
+	}
+	if _, err := NewDynamoDB[TestUser](&DynamoDBConfig{Client: newFakeDynamoDB()}); err == nil {
+		t.Error("Expected error for missing table")
+	}
+}
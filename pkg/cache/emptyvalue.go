@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// EmptyValuePolicy decides how a cache treats Sets of the zero value of T
+// (e.g. "", 0, a zero struct or a nil pointer).
+type EmptyValuePolicy string
+
+const (
+	// EmptyValueStore caches zero values like any other value.
+	EmptyValueStore EmptyValuePolicy = "store"
+	// EmptyValueSkip ignores Sets of zero values, leaving any previous value in place.
+	EmptyValueSkip EmptyValuePolicy = "skip"
+	// EmptyValueDelete makes a Set of a zero value behave as Delete.
+	EmptyValueDelete EmptyValuePolicy = "delete"
+)
+
+// emptyValueCache applies an EmptyValuePolicy to the wrapped cache.
+type emptyValueCache[T any] struct {
+	next   Cache[T]
+	policy EmptyValuePolicy
+}
+
+// WithEmptyValuePolicy wraps a cache to apply policy to Sets of zero values,
+// the same way for every backend. Non-nil empty slices, maps and proto
+// messages are not zero values and are always stored.
+func WithEmptyValuePolicy[T any](cache Cache[T], policy EmptyValuePolicy) Cache[T] {
+	if policy == "" || policy == EmptyValueStore {
+		return cache
+	}
+	return &emptyValueCache[T]{
+		next:   cache,
+		policy: policy,
+	}
+}
+
+func (c *emptyValueCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *emptyValueCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *emptyValueCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *emptyValueCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *emptyValueCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *emptyValueCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+// SetIfAbsent never stores zero values: skipped or deleted, a zero value
+// leaves the key absent, so there is nothing to store.
+func (c *emptyValueCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if isZero(value) {
+		return false, nil
+	}
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+// CompareAndSwap never stores zero values, like SetIfAbsent.
+func (c *emptyValueCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if isZero(new) {
+		return false, nil
+	}
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *emptyValueCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *emptyValueCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if !isZero(value) {
+		return c.next.Set(ctx, key, value, ttl)
+	}
+
+	if c.policy == EmptyValueSkip {
+		return nil
+	}
+	return deleteMulti(ctx, c.next, []string{key})
+}
+
+func (c *emptyValueCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *emptyValueCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *emptyValueCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *emptyValueCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *emptyValueCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *emptyValueCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// isZero reports whether v is the zero value of its type.
+func isZero(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
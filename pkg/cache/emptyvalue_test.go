@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEmptyValuePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Store", func(t *testing.T) {
+		cache, _ := New[string](&Config{Type: TypeMemory})
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "key1", "", time.Minute)
+		if value, found := cache.Get(ctx, "key1"); !found || value != "" {
+			t.Errorf("Expected (\"\", true), got (%q, %v)", value, found)
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		cache, _ := New[TestUser](&Config{Type: TypeMemory, EmptyValues: EmptyValueSkip})
+		defer cache.Close()
+
+		user := TestUser{ID: "123"}
+		_ = cache.Set(ctx, "key1", user, time.Minute)
+		if err := cache.Set(ctx, "key1", TestUser{}, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if value, found := cache.Get(ctx, "key1"); !found || value != user {
+			t.Errorf("Expected previous value to remain, got (%+v, %v)", value, found)
+		}
+		if _, found := cache.Get(ctx, "key2"); found {
+			t.Error("Expected zero value not to be stored")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		cache, _ := New[int](&Config{Type: TypeMemory, EmptyValues: EmptyValueDelete})
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "key1", 42, time.Minute)
+		if err := cache.Set(ctx, "key1", 0, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, found := cache.Get(ctx, "key1"); found {
+			t.Error("Expected zero Set to remove key1")
+		}
+	})
+
+	t.Run("NilPolicyTakesPrecedence", func(t *testing.T) {
+		cache, _ := New[*TestUser](&Config{Type: TypeMemory, EmptyValues: EmptyValueSkip, NilValues: NilValueReject})
+		defer cache.Close()
+
+		if err := cache.Set(ctx, "key1", nil, time.Minute); err == nil {
+			t.Error("Expected nil Set to be rejected")
+		}
+	})
+}
+
+func TestIsZero(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{name: "nil", value: nil, want: true},
+		{name: "empty string", value: "", want: true},
+		{name: "zero struct", value: TestUser{}, want: true},
+		{name: "nil pointer", value: (*TestUser)(nil), want: true},
+		{name: "empty slice", value: []string{}, want: false},
+		{name: "pointer to zero struct", value: &TestUser{}, want: false},
+		{name: "non-zero struct", value: TestUser{ID: "1"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isZero(tt.value); got != tt.want {
+				t.Errorf("isZero(%#v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jellydator/ttlcache/v2"
+)
+
+// ErrNotFound is returned by EntityCache.GetByID when the entity does not exist.
+// Loaders should return it (or wrap it) for missing entities so the miss can be
+// negatively cached.
+var ErrNotFound = errors.New("entity not found")
+
+// EntityLoader loads a single entity by ID from the source of truth.
+type EntityLoader[T any] func(ctx context.Context, id string) (T, error)
+
+// EntityConfig holds configuration for an EntityCache.
+type EntityConfig[T any] struct {
+	// EntityType is the first key element, e.g. "user" for keys like "user:123" (required)
+	EntityType string
+
+	// Loader loads entities missing from the cache (required)
+	Loader EntityLoader[T]
+
+	// TTL is how long loaded entities are cached (default: 5m)
+	TTL time.Duration
+
+	// NotFoundTTL is how long a missing entity is remembered in-process before
+	// the loader is asked again (default: 30s, negative disables negative caching)
+	NotFoundTTL time.Duration
+
+	// NotFoundMaxEntries bounds the number of remembered missing IDs (default: 10000)
+	NotFoundMaxEntries int
+}
+
+// EntityCache is a get-through cache for the "cache one entity by its ID"
+// pattern. It builds keys as "<EntityType>:<id>", loads misses through the
+// loader and remembers missing entities so repeated lookups of unknown IDs
+// don't reach the source of truth. Loads go through GetOrSet, so a cache
+// created with NewLoading also deduplicates concurrent loads of the same ID:
+//
+//	users, err := cache.NewEntityCache(userCache, &cache.EntityConfig[*User]{
+//		EntityType: "user",
+//		Loader:     repo.GetUser,
+//	})
+//	user, err := users.GetByID(ctx, "123")
+type EntityCache[T any] struct {
+	cache  Cache[T]
+	config EntityConfig[T]
+
+	notFound *ttlcache.Cache // nil when negative caching is disabled
+}
+
+// NewEntityCache creates an EntityCache on top of cache.
+func NewEntityCache[T any](cache Cache[T], config *EntityConfig[T]) (*EntityCache[T], error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if config.EntityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+	if config.Loader == nil {
+		return nil, errors.New("loader cannot be nil")
+	}
+
+	cfg := *config
+	if cfg.TTL == 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+	if cfg.NotFoundTTL == 0 {
+		cfg.NotFoundTTL = 30 * time.Second
+	}
+	if cfg.NotFoundMaxEntries == 0 {
+		cfg.NotFoundMaxEntries = 10000
+	}
+
+	e := &EntityCache[T]{
+		cache:  cache,
+		config: cfg,
+	}
+	if cfg.NotFoundTTL > 0 {
+		e.notFound = ttlcache.NewCache()
+		e.notFound.SkipTTLExtensionOnHit(true)
+		e.notFound.SetCacheSizeLimit(cfg.NotFoundMaxEntries)
+	}
+	return e, nil
+}
+
+// Key returns the cache key for id.
+func (e *EntityCache[T]) Key(id string) string {
+	return e.config.EntityType + ":" + id
+}
+
+// GetByID returns the entity with the given ID, loading and caching it on a miss.
+// Returns an error wrapping ErrNotFound if the entity does not exist.
+func (e *EntityCache[T]) GetByID(ctx context.Context, id string) (T, error) {
+	var zero T
+	key := e.Key(id)
+
+	if e.notFound != nil {
+		if _, err := e.notFound.Get(key); err == nil {
+			return zero, ErrNotFound
+		}
+	}
+
+	value, err := GetOrSet(ctx, e.cache, key, e.config.TTL, func(ctx context.Context) (T, error) {
+		return e.config.Loader(ctx, id)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && e.notFound != nil {
+			_ = e.notFound.SetWithTTL(key, struct{}{}, e.config.NotFoundTTL)
+		}
+		return zero, err
+	}
+	return value, nil
+}
+
+// InvalidateID removes the entity with the given ID from the cache, including
+// a remembered not-found result.
+func (e *EntityCache[T]) InvalidateID(ctx context.Context, id string) error {
+	key := e.Key(id)
+	if e.notFound != nil {
+		_ = e.notFound.Remove(key)
+	}
+	return e.cache.Delete(ctx, key)
+}
+
+// Close releases the not-found store. The underlying cache is not closed.
+func (e *EntityCache[T]) Close() error {
+	if e.notFound != nil {
+		return e.notFound.Close()
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEntityCache(t *testing.T) {
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(_ context.Context, id string) (TestUser, error) {
+		loads++
+		if id == "missing" {
+			return TestUser{}, fmt.Errorf("user %s: %w", id, ErrNotFound)
+		}
+		if id == "broken" {
+			return TestUser{}, errors.New("database unavailable")
+		}
+		return TestUser{ID: id}, nil
+	}
+
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	users, err := NewEntityCache(backend, &EntityConfig[TestUser]{
+		EntityType: "user",
+		Loader:     loader,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create entity cache: %v", err)
+	}
+	defer users.Close()
+
+	// Test get-through and key construction
+	user, err := users.GetByID(ctx, "123")
+	if err != nil || user.ID != "123" {
+		t.Fatalf("Unexpected result: %v, %v", user, err)
+	}
+	if _, found := backend.Get(ctx, "user:123"); !found {
+		t.Error("Expected entity to be cached under user:123")
+	}
+	_, _ = users.GetByID(ctx, "123")
+	if loads != 1 {
+		t.Errorf("Expected 1 load, got %d", loads)
+	}
+
+	// Test negative caching
+	loads = 0
+	for i := 0; i < 3; i++ {
+		if _, err := users.GetByID(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected missing entity to be loaded once, got %d", loads)
+	}
+
+	// Test other errors are not cached
+	loads = 0
+	_, _ = users.GetByID(ctx, "broken")
+	_, _ = users.GetByID(ctx, "broken")
+	if loads != 2 {
+		t.Errorf("Expected loader errors not to be cached, got %d loads", loads)
+	}
+
+	// Test InvalidateID clears both positive and negative entries
+	loads = 0
+	_ = users.InvalidateID(ctx, "123")
+	_ = users.InvalidateID(ctx, "missing")
+	_, _ = users.GetByID(ctx, "123")
+	_, _ = users.GetByID(ctx, "missing")
+	if loads != 2 {
+		t.Errorf("Expected reloads after InvalidateID, got %d", loads)
+	}
+}
+
+func TestNewEntityCacheValidation(t *testing.T) {
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	if _, err := NewEntityCache(backend, nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+	if _, err := NewEntityCache(backend, &EntityConfig[TestUser]{EntityType: "user"}); err == nil {
+		t.Error("Expected error for missing loader")
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+import "context"
+
+// Exists reports whether key is stored in cache without decoding its value,
+// e.g. with Redis EXISTS, so probing a key costs less than a Get. Caches that
+// don't implement ExistenceChecker are probed with GetWithError.
+//
+// Like Get, Exists may extend the TTL of memory cache entries when
+// MemoryConfig.SkipTTLExtensionOnHit is false.
+func Exists[T any](ctx context.Context, cache Cache[T], key string) (bool, error) {
+	if ec, ok := cache.(ExistenceChecker); ok {
+		return ec.Exists(ctx, key)
+	}
+	_, found, err := GetWithError(ctx, cache, key)
+	return found, err
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExistsMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+			_ = flushAll(ctx, cache)
+
+			if found, err := Exists(ctx, cache, "a"); err != nil || !found {
+				t.Errorf("Expected a to exist, got %v, %v", found, err)
+			}
+			if found, err := Exists(ctx, cache, "missing"); err != nil || found {
+				t.Errorf("Expected missing not to exist, got %v, %v", found, err)
+			}
+		})
+	}
+}
+
+func TestExistsThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[*TestUser](nil)
+	defer backend.Close()
+
+	cache := WithNilValuePolicy(WithPrefix(backend, "p:"), NilValueMiss)
+	_ = cache.Set(ctx, "a", &TestUser{ID: "a"}, time.Minute)
+	_ = backend.Set(ctx, "p:nil", nil, time.Minute)
+
+	if found, _ := Exists(ctx, cache, "a"); !found {
+		t.Error("Expected a to exist under the prefix")
+	}
+	if found, _ := Exists(ctx, backend, "p:a"); !found {
+		t.Error("Expected the prefixed key in the backend")
+	}
+	if found, _ := Exists(ctx, cache, "nil"); found {
+		t.Error("Expected a stored nil not to exist under NilValueMiss")
+	}
+}
+
+func TestExistsFallsBackToGet(t *testing.T) {
+	ctx := context.Background()
+	backend := &unclearableCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	defer backend.Close()
+
+	_ = backend.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if found, err := Exists[TestUser](ctx, backend, "a"); err != nil || !found {
+		t.Errorf("Expected a to exist, got %v, %v", found, err)
+	}
+}
+
+func TestExistsTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, nil)
+	defer cache.Close()
+
+	_ = l2.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if found, _ := Exists(ctx, cache, "a"); !found {
+		t.Error("Expected a key only in L2 to exist")
+	}
+	if _, found := l1.Get(ctx, "a"); found {
+		t.Error("Expected Exists not to promote into L1")
+	}
+}
+
+func TestExistsDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "short", TestUser{ID: "s"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if found, err := Exists(ctx, cache, "a"); err != nil || !found {
+		t.Errorf("Expected a to exist, got %v, %v", found, err)
+	}
+	if found, _ := Exists(ctx, cache, "short"); found {
+		t.Error("Expected an expired entry not to exist")
+	}
+}
+
+func TestExistsDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if found, err := Exists(ctx, cache, "a"); err != nil || !found {
+		t.Errorf("Expected a to exist, got %v, %v", found, err)
+	}
+	if found, err := Exists(ctx, cache, "missing"); err != nil || found {
+		t.Errorf("Expected missing not to exist, got %v, %v", found, err)
+	}
+}
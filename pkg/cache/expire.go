@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrKeyNotFound is returned by Expire for keys that aren't stored.
+var ErrKeyNotFound = errors.New("cache key not found")
+
+// Expire sets the lifetime left to the entry under key to ttl without
+// re-serializing its value, e.g. with Redis PEXPIRE, for sliding expiration
+// of sessions. A ttl of 0 removes the expiry, as in Set, and a negative ttl
+// deletes the entry. Returns ErrKeyNotFound when key isn't stored, so callers
+// learn that the entry already expired.
+//
+// Caches that don't implement Expirer are rewritten with GetWithError and
+// Set, which may overwrite a value written concurrently.
+func Expire[T any](ctx context.Context, cache Cache[T], key string, ttl time.Duration) error {
+	if ttl < 0 {
+		return cache.Delete(ctx, key)
+	}
+	if e, ok := cache.(Expirer); ok {
+		return e.Expire(ctx, key, ttl)
+	}
+
+	value, found, err := GetWithError(ctx, cache, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrKeyNotFound
+	}
+	return cache.Set(ctx, key, value, ttl)
+}
@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpireMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			user := TestUser{ID: "a", Name: "Ann"}
+			_ = cache.Set(ctx, "a", user, time.Minute)
+			_ = flushAll(ctx, cache)
+
+			if err := Expire(ctx, cache, "a", time.Hour); err != nil {
+				t.Fatalf("Expire failed: %v", err)
+			}
+			_ = flushAll(ctx, cache)
+			if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= 59*time.Minute {
+				t.Errorf("Expected about an hour left, got %v", ttl)
+			}
+			if got, found := cache.Get(ctx, "a"); !found || got != user {
+				t.Errorf("Expected the value to survive Expire, got %+v, %v", got, found)
+			}
+
+			if err := Expire(ctx, cache, "a", 0); err != nil {
+				t.Fatalf("Expire failed: %v", err)
+			}
+			_ = flushAll(ctx, cache)
+			if ttl, found, _ := TTL(ctx, cache, "a"); !found || ttl != 0 {
+				t.Errorf("Expected a not to expire, got %v, %v", ttl, found)
+			}
+
+			if err := Expire(ctx, cache, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+				t.Errorf("Expected ErrKeyNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExpireShortens(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Hour)
+	if err := Expire(ctx, cache, "a", 20*time.Millisecond); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		_, found := cache.Get(ctx, "a")
+		return !found
+	})
+}
+
+func TestExpireNegativeDeletes(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Hour)
+	if err := Expire(ctx, cache, "a", -1); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "a"); found {
+		t.Error("Expected a negative TTL to delete the entry")
+	}
+}
+
+func TestExpireFallsBackToSet(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	backend := &unclearableCache[TestUser]{Cache: inner}
+	defer backend.Close()
+
+	_ = backend.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if err := Expire[TestUser](ctx, backend, "a", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, inner, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left, got %v", ttl)
+	}
+	if err := Expire[TestUser](ctx, backend, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestExpireThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	cache := WithDefaultTTL(WithPrefix(backend, "p:"), time.Hour)
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+
+	// 0 means the default TTL, as in Set
+	if err := Expire(ctx, cache, "a", 0); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, backend, "p:a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected the default TTL on the prefixed key, got %v", ttl)
+	}
+}
+
+func TestExpireTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, 10*time.Minute)
+	if err := Expire(ctx, cache, "a", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, l2, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left in L2, got %v", ttl)
+	}
+	if ttl, _, _ := TTL(ctx, l1, "a"); ttl > time.Minute {
+		t.Errorf("Expected the L1 copy to stay capped, got %v", ttl)
+	}
+
+	// An entry gone from L2 must not be served from L1 any longer
+	_ = l2.Delete(ctx, "a")
+	if err := Expire(ctx, cache, "a", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+	if _, found := l1.Get(ctx, "a"); found {
+		t.Error("Expected the L1 copy to be removed")
+	}
+}
+
+func TestExpireSkipEqualWrites(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := NewSkipEqualWrites[TestUser](inner, nil)
+	defer cache.Close()
+
+	user := TestUser{ID: "a"}
+	_ = cache.Set(ctx, "a", user, time.Hour)
+	_ = Expire(ctx, cache, "a", 20*time.Millisecond)
+	waitFor(t, func() bool {
+		_, found := inner.Get(ctx, "a")
+		return !found
+	})
+
+	// The write is no longer known to be stored, so it isn't skipped
+	_ = cache.Set(ctx, "a", user, time.Hour)
+	if _, found := inner.Get(ctx, "a"); !found {
+		t.Error("Expected an equal write after the shortened expiry to be stored")
+	}
+}
+
+func TestExpireAsyncKeepsOrder(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := NewAsync(inner, nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = Expire(ctx, cache, "a", time.Hour)
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, inner, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected the queued Expire to apply after the Set, got %v", ttl)
+	}
+}
+
+func TestExpireDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	user := TestUser{ID: "a", Name: "Ann"}
+	_ = cache.Set(ctx, "a", user, time.Minute)
+	if err := Expire(ctx, cache, "a", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left, got %v", ttl)
+	}
+	if got, found := cache.Get(ctx, "a"); !found || got != user {
+		t.Errorf("Expected the value to survive Expire, got %+v, %v", got, found)
+	}
+	if err := Expire(ctx, cache, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestExpireDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if err := Expire(ctx, cache, "a", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left, got %v", ttl)
+	}
+
+	// Removing the expiry twice must not report the key as missing
+	for range 2 {
+		if err := Expire(ctx, cache, "a", 0); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+	}
+	if ttl, found, _ := TTL(ctx, cache, "a"); !found || ttl != 0 {
+		t.Errorf("Expected a not to expire, got %v, %v", ttl, found)
+	}
+
+	if err := Expire(ctx, cache, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// expiredChannels are the keyevent channels Redis/Valkey publishes expired
+// keys on, one per database.
+const expiredChannels = "__keyevent@*__:expired"
+
+// ExpiryHandler handles the expiration of a key, e.g. by enqueuing the
+// recomputation of the report it cached. Returning an error delivers the key
+// again.
+type ExpiryHandler func(ctx context.Context, key string) error
+
+// ExpiryListenerConfig holds configuration for an ExpiryListener.
+type ExpiryListenerConfig struct {
+	// Patterns select the keys whose expirations are delivered, as
+	// path.Match patterns matched against the keys of the backend, prefix
+	// included, e.g. "reports:*" (default: every key)
+	Patterns []string
+
+	// Client receives the keyspace notifications of distributed caches
+	// (default: the client of the cache)
+	Client redis.UniversalClient
+
+	// ConfigureNotifications enables expired-key notifications on the
+	// server with CONFIG SET notify-keyspace-events Ex; without it they must
+	// be enabled in the server's configuration, since managed services
+	// often refuse CONFIG (default: false)
+	ConfigureNotifications bool
+
+	// QueueSize is the number of expirations waiting for a worker; beyond
+	// it, observing expirations waits (default: 1024)
+	QueueSize int
+
+	// Workers is the number of goroutines calling the handler (default: 1)
+	Workers int
+
+	// MaxAttempts is the number of deliveries of a key whose handler fails,
+	// the first one included (default: 5)
+	MaxAttempts int
+
+	// RetryBackoff is the wait before the first redelivery, doubling with
+	// each one (default: 1s)
+	RetryBackoff time.Duration
+
+	// HandlerTimeout bounds each call of the handler (default: 0, no timeout)
+	HandlerTimeout time.Duration
+
+	// Logger logs expirations dropped after MaxAttempts (default: slog.Default())
+	Logger *slog.Logger
+}
+
+// ExpiryListener calls an ExpiryHandler with the keys of a cache that
+// expire, bridging cache expirations to application schedulers. Memory
+// caches report the expirations ttlcache removes; distributed caches those
+// Redis/Valkey publishes as keyspace notifications.
+//
+// Delivery is at-least-once for the expirations the listener observes: a
+// key is delivered until its handler succeeds or MaxAttempts deliveries
+// failed, and a handler that fails after doing part of its work sees the key
+// again, so handlers must be idempotent. Every instance listening to a shared
+// Redis/Valkey receives every expiration, and should claim the follow-up
+// work (e.g. with SET NX) when it must run once. Expirations are not
+// observed while the listener isn't running: Redis/Valkey doesn't keep
+// notifications, so those published during a disconnection or a restart are
+// lost, and keys expiring unread may be reported late, when the server's
+// expiry cycle finds them. Work that must happen regardless should also be
+// reconciled periodically. On Redis Cluster, only the expirations of the
+// node serving the subscription are observed.
+type ExpiryListener struct {
+	handler ExpiryHandler
+	config  ExpiryListenerConfig
+	queue   chan string
+
+	pubsub   *redis.PubSub
+	unlisten func()
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	receiving sync.WaitGroup
+	workers   sync.WaitGroup
+}
+
+// ListenExpirations starts calling handler with the keys of cache that
+// expire and match config.Patterns, looking through decorators for the
+// Redis/Valkey client of distributed and tiered caches, or for a memory cache
+// using MemoryEngineTTLCache. Call Close to stop; it doesn't close cache.
+func ListenExpirations[T any](cache Cache[T], handler ExpiryHandler, config *ExpiryListenerConfig) (*ExpiryListener, error) {
+	if handler == nil {
+		return nil, errors.New("expiry listener requires a handler")
+	}
+
+	var cfg ExpiryListenerConfig
+	if config != nil {
+		cfg = *config
+	}
+	for _, pattern := range cfg.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid expiry pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	l := &ExpiryListener{
+		handler: handler,
+		config:  cfg,
+		queue:   make(chan string, cfg.QueueSize),
+		stop:    make(chan struct{}),
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client, _ = redisClientOf(cache)
+	}
+	switch mc, err := memoryCacheOf(cache); {
+	case client != nil:
+		if err := l.subscribe(client); err != nil {
+			return nil, err
+		}
+	case err == nil && mc.cache != nil:
+		l.unlisten = mc.counters.expiries.add(l)
+	default:
+		return nil, fmt.Errorf("expiry listener requires a Client or a memory cache: %w", ErrNotDistributed)
+	}
+
+	l.workers.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go l.work()
+	}
+	return l, nil
+}
+
+// expiryListeners are the ExpiryListeners watching a memory cache.
+type expiryListeners struct {
+	mu        sync.RWMutex
+	listeners map[*ExpiryListener]struct{}
+}
+
+// add delivers the keys that expire to l until the returned function is
+// called.
+func (e *expiryListeners) add(l *ExpiryListener) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.listeners == nil {
+		e.listeners = make(map[*ExpiryListener]struct{})
+	}
+	e.listeners[l] = struct{}{}
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.listeners, l)
+	}
+}
+
+func (e *expiryListeners) notify(key string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for l := range e.listeners {
+		l.observe(key)
+	}
+}
+
+// subscribe receives the expired-key notifications of client.
+func (l *ExpiryListener) subscribe(client redis.UniversalClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if l.config.ConfigureNotifications {
+		if err := client.ConfigSet(ctx, "notify-keyspace-events", "Ex").Err(); err != nil {
+			return fmt.Errorf("enabling keyspace notifications: %w", err)
+		}
+	}
+
+	l.pubsub = client.PSubscribe(ctx, expiredChannels)
+	// Wait for the confirmation so no expiration is missed after returning
+	if _, err := l.pubsub.Receive(ctx); err != nil {
+		_ = l.pubsub.Close()
+		return fmt.Errorf("subscribing to expired keys: %w", err)
+	}
+
+	l.receiving.Add(1)
+	go func() {
+		defer l.receiving.Done()
+		// The channel is closed by pubsub.Close; go-redis resubscribes on
+		// reconnects meanwhile
+		for msg := range l.pubsub.Channel() {
+			l.observe(msg.Payload)
+		}
+	}()
+	return nil
+}
+
+// observe queues the expiration of key if it matches the patterns, waiting
+// for room unless the listener is closed.
+func (l *ExpiryListener) observe(key string) {
+	if !l.matches(key) {
+		return
+	}
+	select {
+	case l.queue <- key:
+	case <-l.stop:
+	}
+}
+
+func (l *ExpiryListener) matches(key string) bool {
+	if len(l.config.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range l.config.Patterns {
+		// Patterns were validated by ListenExpirations
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *ExpiryListener) work() {
+	defer l.workers.Done()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case key := <-l.queue:
+			l.deliver(key)
+		}
+	}
+}
+
+// deliver calls the handler with key until it succeeds, MaxAttempts
+// deliveries failed or the listener is closed.
+func (l *ExpiryListener) deliver(key string) {
+	wait := l.config.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := l.handle(key)
+		if err == nil {
+			return
+		}
+		if attempt == l.config.MaxAttempts {
+			l.config.Logger.Warn("cache: dropping expiration after failed deliveries",
+				slog.String("key", key),
+				slog.Int("attempts", attempt),
+				slog.Any("error", err),
+			)
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-l.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+func (l *ExpiryListener) handle(key string) error {
+	ctx := context.Background()
+	if l.config.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.config.HandlerTimeout)
+		defer cancel()
+	}
+	return l.handler(ctx, key)
+}
+
+// Close stops observing expirations and waits for running handlers to
+// return. Expirations queued or waiting for a redelivery are dropped. The
+// client and the cache are not closed.
+func (l *ExpiryListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.stop)
+		if l.unlisten != nil {
+			l.unlisten()
+		}
+		if l.pubsub != nil {
+			err = l.pubsub.Close()
+		}
+		l.receiving.Wait()
+		l.workers.Wait()
+	})
+	return err
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// expiryRecorder records the keys delivered to an ExpiryHandler.
+type expiryRecorder struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *expiryRecorder) handle(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *expiryRecorder) delivered() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.keys...)
+}
+
+func TestListenExpirationsMemory(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	var recorder expiryRecorder
+	listener, err := ListenExpirations(memory, recorder.handle, &ExpiryListenerConfig{
+		Patterns: []string{"report:*"},
+	})
+	if err != nil {
+		t.Fatalf("ListenExpirations failed: %v", err)
+	}
+	defer listener.Close()
+
+	_ = memory.Set(ctx, "report:1", TestUser{ID: "1"}, 20*time.Millisecond)
+	_ = memory.Set(ctx, "user:1", TestUser{ID: "1"}, 20*time.Millisecond)
+
+	waitFor(t, func() bool { return len(recorder.delivered()) > 0 })
+	time.Sleep(50 * time.Millisecond)
+	if keys := recorder.delivered(); len(keys) != 1 || keys[0] != "report:1" {
+		t.Errorf("Expected only report:1 to be delivered, got %v", keys)
+	}
+}
+
+func TestListenExpirationsRedelivers(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	var mu sync.Mutex
+	attempts := 0
+	listener, err := ListenExpirations(memory, func(context.Context, string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			return errors.New("scheduler unavailable")
+		}
+		return nil
+	}, &ExpiryListenerConfig{RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("ListenExpirations failed: %v", err)
+	}
+	defer listener.Close()
+
+	_ = memory.Set(ctx, "report:1", TestUser{ID: "1"}, 10*time.Millisecond)
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts == 3
+	})
+}
+
+func TestListenExpirationsRequiresBackend(t *testing.T) {
+	noop := NewNoOp[TestUser]()
+	if _, err := ListenExpirations(noop, (&expiryRecorder{}).handle, nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got %v", err)
+	}
+
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+	if _, err := ListenExpirations(memory, (&expiryRecorder{}).handle, &ExpiryListenerConfig{Patterns: []string{"["}}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func TestListenExpirationsWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	var recorder expiryRecorder
+	listener, err := ListenExpirations(NewNoOp[TestUser](), recorder.handle, &ExpiryListenerConfig{
+		Client:                 client,
+		ConfigureNotifications: true,
+		Patterns:               []string{"report:*"},
+	})
+	if err != nil {
+		t.Fatalf("ListenExpirations failed: %v", err)
+	}
+	defer listener.Close()
+
+	_ = client.Set(ctx, "report:1", "x", 50*time.Millisecond).Err()
+	_ = client.Set(ctx, "user:1", "x", 50*time.Millisecond).Err()
+
+	waitFor(t, func() bool { return len(recorder.delivered()) > 0 })
+	if keys := recorder.delivered(); len(keys) != 1 || keys[0] != "report:1" {
+		t.Errorf("Expected only report:1 to be delivered, got %v", keys)
+	}
+}
@@ -5,27 +5,232 @@ import (
 	"fmt"
 )
 
-// New creates a new cache based on the provided configuration.
+// New creates a new cache based on the provided configuration, with the
+// defaults set by SetDefaults filling the fields it leaves unset.
 // This is the recommended way to create caches as it handles all the setup.
 func New[T any](config *Config) (Cache[T], error) {
 	if config == nil {
 		return nil, errors.New("config cannot be nil")
 	}
 
+	config, err := withForcedSerialization[T](withDefaults(config))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := newLifecycleMetrics(config)
+
+	var cache Cache[T]
+	if config.Disabled {
+		cache = newDisabledCache[T](config)
+	} else {
+		cache, err = newBackend[T](config)
+	}
+	metrics.recordCreation(err)
+	if err != nil {
+		return nil, err
+	}
+
+	cache = WithPrefix(cache, config.KeyPrefix)
+
+	if config.Retry != nil {
+		retry := *config.Retry
+		if retry.Logger == nil {
+			retry.Logger = config.Logger
+		}
+		cache = NewRetrying(cache, &retry)
+	}
+
+	if config.Hedging != nil {
+		cache = NewHedged(cache, config.Hedging)
+	}
+
+	if config.Shedding != nil {
+		shedding := *config.Shedding
+		if shedding.MeterProvider == nil {
+			shedding.MeterProvider = config.MeterProvider
+		}
+		cache = NewShedding(cache, &shedding)
+	}
+
+	cache = WithReadBudget(cache, config.ReadBudget)
+	cache = WithMaxKeyLength(cache, config.MaxKeyLength, config.LongKeys)
+	cache = WithEmptyValuePolicy(cache, config.EmptyValues)
+	cache = WithNilValuePolicy(cache, config.NilValues)
+
+	// Disabled caches have no Redis/Valkey to account quotas in
+	if config.Quota != nil && !config.Disabled {
+		quota := *config.Quota
+		if quota.MeterProvider == nil {
+			quota.MeterProvider = config.MeterProvider
+		}
+		if quota.Serializer == nil && config.Distributed != nil {
+			quota.Serializer = config.Distributed.Serializer
+		}
+		quotaCache, err := NewQuota(cache, &quota)
+		if err != nil {
+			_ = cache.Close()
+			return nil, err
+		}
+		cache = quotaCache
+	}
+
+	// Equal-value checks hash values the way the backend serializes them
+	var serializer Serializer
+	if config.Distributed != nil {
+		serializer = config.Distributed.Serializer
+	}
+
+	if config.SkipWriteIfEqual {
+		cache = NewSkipEqualWrites(cache, serializer)
+	}
+
+	if config.WriteDampening > 0 {
+		cache = NewDampened(cache, config.WriteDampening, serializer)
+	}
+
+	if config.DeleteCoalescing > 0 {
+		cache = NewCoalescedDeletes(cache, config.DeleteCoalescing)
+	}
+
+	if config.DryRun {
+		cache = NewDryRun(cache, nil)
+	}
+
+	cache = WithDefaultTTL(cache, config.DefaultTTL)
+
+	if config.Loading != nil {
+		cache = NewLoading(cache, config.Loading)
+	}
+
+	// Refreshing would only load values a disabled cache drops
+	if config.RefreshAhead != nil && !config.Disabled {
+		cache = NewRefreshAhead(cache, config.RefreshAhead)
+	}
+
+	// Applied last so every other layer sees the effective key
+	if config.KeyFromContext != nil {
+		cache = WithKeyFromContext(cache, config.KeyFromContext)
+	}
+
+	if config.Logger != nil {
+		cache = WithLogging(cache, &LoggingConfig{
+			Logger:        config.Logger,
+			Name:          config.Name,
+			SlowThreshold: config.SlowOperationThreshold,
+		})
+	}
+
+	if config.TraceOperations {
+		cache = WithSpans(cache, &SpanConfig{
+			Name:           config.Name,
+			Backend:        string(config.Type),
+			Serializer:     serializerNameOf[T](config),
+			TracerProvider: config.TracerProvider,
+		})
+	}
+
+	if config.TraceDecisions {
+		cache = WithDecisionTracing(cache, config.Name, string(config.Type))
+	}
+
+	source := config.Name
+	if source == "" {
+		source = string(config.Type)
+	}
+	cache = WithKeyTracing(cache, source)
+
+	return &lifecycleCache[T]{next: cache, metrics: metrics}, nil
+}
+
+// withForcedSerialization returns a copy of config whose backend
+// configurations use the serializer selected by ForceSerialization, unless
+// they set their own.
+func withForcedSerialization[T any](config *Config) (*Config, error) {
+	var serializer Serializer
+	switch config.ForceSerialization {
+	case "":
+		return config, nil
+	case SerializationProtobuf:
+		if !isProtoType[T]() {
+			var zero T
+			return nil, fmt.Errorf("%w: protobuf serialization requires a proto message pointer, got %T", ErrUnsupportedValueType, zero)
+		}
+		serializer = protobufSerializer{}
+	default:
+		var err error
+		if serializer, err = NewSerializer(config.ForceSerialization); err != nil {
+			return nil, err
+		}
+	}
+
+	forced := *config
+	if forced.Memory != nil && forced.Memory.Serializer == nil {
+		memory := *forced.Memory
+		memory.Serializer = serializer
+		forced.Memory = &memory
+	}
+	if forced.Distributed != nil && forced.Distributed.Serializer == nil {
+		distributed := *forced.Distributed
+		distributed.Serializer = serializer
+		forced.Distributed = &distributed
+	}
+	if forced.Disk != nil && forced.Disk.Serializer == nil {
+		disk := *forced.Disk
+		disk.Serializer = serializer
+		forced.Disk = &disk
+	}
+	if forced.SQL != nil && forced.SQL.Serializer == nil {
+		sql := *forced.SQL
+		sql.Serializer = serializer
+		forced.SQL = &sql
+	}
+	if forced.DynamoDB != nil && forced.DynamoDB.Serializer == nil {
+		dynamoDB := *forced.DynamoDB
+		dynamoDB.Serializer = serializer
+		forced.DynamoDB = &dynamoDB
+	}
+	return &forced, nil
+}
+
+// newBackend creates the backend selected by config.Type without any decorators.
+func newBackend[T any](config *Config) (Cache[T], error) {
 	switch config.Type {
 	case TypeMemory:
+		if config.Memory != nil && config.Memory.Engine == MemoryEngineFreeCache {
+			if err := checkValueType[T](config.Memory.Serializer); err != nil {
+				return nil, err
+			}
+		}
 		return NewMemory[T](config.Memory), nil
 
 	case TypeDistributed:
-		// For distributed cache, we need to check if T is a proto.Message
-		var zero T
-		if isProtoMessage(zero) {
-			// Use the protobuf-specific implementation
-			return createDistributedCacheForProto[T](config.Distributed)
-		} else {
-			// Use the generic implementation for non-proto types
-			return NewDistributedGeneric[T](config.Distributed)
+		return newDistributedBackend[T](distributedConfigOf(config))
+
+	case TypeTiered:
+		l2, err := newDistributedBackend[T](distributedConfigOf(config))
+		if err != nil {
+			return nil, err
+		}
+		tiered := config.Tiered
+		if tiered != nil && tiered.ReadRepair != nil && tiered.ReadRepair.MeterProvider == nil {
+			// Report divergences alongside the lifecycle metrics
+			repair := *tiered.ReadRepair
+			repair.MeterProvider = config.MeterProvider
+			withRepair := *tiered
+			withRepair.ReadRepair = &repair
+			tiered = &withRepair
 		}
+		return NewTiered(NewMemory[T](config.Memory), l2, tiered), nil
+
+	case TypeDisk:
+		return NewDisk[T](config.Disk)
+
+	case TypeSQL:
+		return NewSQL[T](config.SQL)
+
+	case TypeDynamoDB:
+		return NewDynamoDB[T](config.DynamoDB)
 
 	case TypeNoOp:
 		return NewNoOp[T](), nil
@@ -34,3 +239,25 @@ func New[T any](config *Config) (Cache[T], error) {
 		return nil, fmt.Errorf("unknown cache type: %s", config.Type)
 	}
 }
+
+// distributedConfigOf returns the distributed configuration of config,
+// copied to log with config's Logger unless it sets its own.
+func distributedConfigOf(config *Config) *DistributedConfig {
+	if config.Distributed == nil || config.Distributed.Logger != nil || config.Logger == nil {
+		return config.Distributed
+	}
+	distributed := *config.Distributed
+	distributed.Logger = config.Logger
+	return &distributed
+}
+
+// newDistributedBackend creates the distributed implementation matching T.
+func newDistributedBackend[T any](config *DistributedConfig) (Cache[T], error) {
+	// For distributed cache, we need to check if T is a proto.Message
+	if isProtoType[T]() && !usesGenericProtoPath(config) {
+		// Use the protobuf-specific implementation
+		return createDistributedCacheForProto[T](config)
+	}
+	// Use the generic implementation for non-proto types
+	return NewDistributedGeneric[T](config)
+}
@@ -1,7 +1,15 @@
 package cache
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestFactoryNew(t *testing.T) {
@@ -31,6 +39,23 @@ func TestFactoryNew(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "disk cache",
+			config: &Config{
+				Type: TypeDisk,
+				Disk: &DiskConfig{Path: filepath.Join(t.TempDir(), "cache.db")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "disk cache without path",
+			config: &Config{
+				Type: TypeDisk,
+				Disk: &DiskConfig{},
+			},
+			wantErr:  true,
+			errorMsg: "disk cache path is required",
+		},
 		{
 			name: "unknown cache type",
 			config: &Config{
@@ -88,3 +113,121 @@ func TestConvenienceFunctions(t *testing.T) {
 	}
 	_ = cache3.Close()
 }
+
+func TestFactoryTypeParameters(t *testing.T) {
+	ctx := context.Background()
+	diskConfig := func(t *testing.T) *Config {
+		return &Config{Type: TypeDisk, Disk: &DiskConfig{Path: filepath.Join(t.TempDir(), "cache.db")}}
+	}
+
+	t.Run("proto pointer", func(t *testing.T) {
+		cache, err := New[*wrapperspb.StringValue](diskConfig(t))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "a", wrapperspb.String("hello"), time.Minute)
+		if got, found := cache.Get(ctx, "a"); !found || got.GetValue() != "hello" {
+			t.Errorf("Expected hello, got %v, %v", got, found)
+		}
+	})
+
+	t.Run("plain value", func(t *testing.T) {
+		cache, err := New[TestUser](diskConfig(t))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer cache.Close()
+
+		user := TestUser{ID: "1", Name: "Ann"}
+		_ = cache.Set(ctx, "a", user, time.Minute)
+		if got, found := cache.Get(ctx, "a"); !found || got != user {
+			t.Errorf("Expected %+v, got %+v, %v", user, got, found)
+		}
+	})
+
+	t.Run("proto value", func(t *testing.T) {
+		_, err := New[wrapperspb.StringValue](diskConfig(t))
+		if !errors.Is(err, ErrUnsupportedValueType) || !strings.Contains(err.Error(), "by pointer") {
+			t.Errorf("Expected proto message values to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		_, err := New[proto.Message](&Config{Type: TypeMemory, Memory: &MemoryConfig{Engine: MemoryEngineFreeCache}})
+		if !errors.Is(err, ErrUnsupportedValueType) {
+			t.Errorf("Expected interface type parameters to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("interface without serialization", func(t *testing.T) {
+		// Nothing is serialized in ttlcache, so any type works
+		cache, err := New[proto.Message](&Config{Type: TypeMemory})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "a", wrapperspb.String("hello"), time.Minute)
+		if got, found := cache.Get(ctx, "a"); !found || !proto.Equal(got, wrapperspb.String("hello")) {
+			t.Errorf("Expected hello, got %v, %v", got, found)
+		}
+	})
+
+	t.Run("interface with serializer", func(t *testing.T) {
+		config := diskConfig(t)
+		config.Disk.Serializer = NewJSONSerializer()
+		cache, err := New[any](config)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		_ = cache.Close()
+	})
+}
+
+func TestFactoryForceSerialization(t *testing.T) {
+	ctx := context.Background()
+	config := &Config{
+		Type:               TypeDisk,
+		Disk:               &DiskConfig{Path: filepath.Join(t.TempDir(), "cache.db")},
+		Memory:             &MemoryConfig{Engine: MemoryEngineFreeCache},
+		ForceSerialization: SerializationProtoJSON,
+	}
+
+	forced, err := withForcedSerialization[*wrapperspb.StringValue](config)
+	if err != nil {
+		t.Fatalf("withForcedSerialization failed: %v", err)
+	}
+	if _, ok := forced.Disk.Serializer.(*ProtoJSONSerializer); !ok {
+		t.Errorf("Expected the disk cache to use protojson, got %T", forced.Disk.Serializer)
+	}
+	if _, ok := forced.Memory.Serializer.(*ProtoJSONSerializer); !ok {
+		t.Errorf("Expected FreeCache to use protojson, got %T", forced.Memory.Serializer)
+	}
+	if config.Disk.Serializer != nil || config.Memory.Serializer != nil {
+		t.Error("Expected the caller's configuration to be left alone")
+	}
+
+	cache, err := New[*wrapperspb.StringValue](config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+	_ = cache.Set(ctx, "a", wrapperspb.String("hello"), time.Minute)
+	if got, found := cache.Get(ctx, "a"); !found || got.GetValue() != "hello" {
+		t.Errorf("Expected hello, got %v, %v", got, found)
+	}
+
+	// A backend's own Serializer takes precedence
+	own := NewJSONSerializer()
+	config.Disk.Serializer = own
+	if forced, _ := withForcedSerialization[*wrapperspb.StringValue](config); forced.Disk.Serializer != own {
+		t.Errorf("Expected the disk cache's own serializer, got %T", forced.Disk.Serializer)
+	}
+
+	// Protobuf requires proto messages
+	if _, err := New[TestUser](&Config{Type: TypeMemory, ForceSerialization: SerializationProtobuf}); !errors.Is(err, ErrUnsupportedValueType) {
+		t.Errorf("Expected protobuf serialization of a plain struct to be rejected, got %v", err)
+	}
+}
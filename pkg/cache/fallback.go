@@ -0,0 +1,400 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTooManyDirtyKeys is returned by writes to a failed-over fallback cache
+// once MaxDirtyKeys keys were written during the outage.
+var ErrTooManyDirtyKeys = errors.New("fallback: too many keys written during the outage")
+
+// FallbackConfig holds configuration for fallback caches.
+type FallbackConfig struct {
+	// FailureThreshold is the number of consecutive primary failures that
+	// switch to the secondary (default: 1)
+	FailureThreshold int
+
+	// ProbeInterval is how often the primary is probed while the secondary
+	// is in use (default: 5s)
+	ProbeInterval time.Duration
+
+	// ProbeTimeout bounds every probe, including the resync of the keys
+	// written during the outage (default: 1s)
+	ProbeTimeout time.Duration
+
+	// MaxDirtyKeys caps the keys written during an outage, which are deleted
+	// from the primary on recovery; writes of further keys fail with
+	// ErrTooManyDirtyKeys until the primary recovers (default: 10000)
+	MaxDirtyKeys int
+
+	// OnStateChange is called when the cache switches to the secondary, with
+	// the primary's error, and back, with a nil error (optional)
+	OnStateChange func(failedOver bool, err error)
+
+	// Logger logs the switches (default: slog.Default())
+	Logger *slog.Logger
+}
+
+// fallbackCache serves from a secondary cache while the primary is down.
+type fallbackCache[T any] struct {
+	primary   Cache[T]
+	secondary Cache[T]
+	config    FallbackConfig
+
+	failedOver atomic.Bool
+	failures   atomic.Int32
+
+	// outage is held for reading by writes to the secondary and for writing
+	// by recovery, so no write lands in the secondary once its keys are resynced
+	outage sync.RWMutex
+
+	// mu guards switching back and forth, and dirty
+	mu        sync.Mutex
+	dirty     map[string]struct{} // keys written to the secondary during the outage
+	stop      chan struct{}
+	done      chan struct{}
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewFallback creates a cache that uses primary (e.g. Redis) and falls back
+// to secondary (typically a memory or no-op cache) when primary fails, so a
+// Redis outage degrades features instead of breaking them. After
+// FailureThreshold consecutive failures every read and write goes to
+// secondary, and primary is probed every ProbeInterval with its health check.
+// Once it answers, the keys written during the outage are deleted from
+// primary, which may hold stale values for them, and from secondary, and
+// primary is used again. Recovery never clears either cache.
+//
+// Failures are the errors primary reports to error-aware callers: configure
+// distributed caches with BackendDownError so outages aren't answered as
+// misses. ErrClosed and context cancellation aren't failures. Close closes
+// both caches.
+func NewFallback[T any](primary, secondary Cache[T], config *FallbackConfig) Cache[T] {
+	var cfg FallbackConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.ProbeInterval == 0 {
+		cfg.ProbeInterval = 5 * time.Second
+	}
+	if cfg.ProbeTimeout == 0 {
+		cfg.ProbeTimeout = time.Second
+	}
+	if cfg.MaxDirtyKeys == 0 {
+		cfg.MaxDirtyKeys = 10000
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	return &fallbackCache[T]{
+		primary:   primary,
+		secondary: secondary,
+		config:    cfg,
+		dirty:     make(map[string]struct{}),
+	}
+}
+
+// fallbackLoadError marks errors of GetOrSet loaders, which say nothing
+// about the primary.
+type fallbackLoadError struct {
+	err error
+}
+
+func (e fallbackLoadError) Error() string {
+	return e.err.Error()
+}
+
+func (e fallbackLoadError) Unwrap() error {
+	return e.err
+}
+
+// isOutage reports whether err from the primary counts as a failure.
+func isOutage(ctx context.Context, err error) bool {
+	var loadErr fallbackLoadError
+	return ctx.Err() == nil && !errors.As(err, &loadErr) &&
+		!errors.Is(err, ErrClosed) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// fallback runs fn on the primary, or on the secondary while failed over or
+// once the primary's failure makes the cache fail over. Writes to the
+// secondary record their keys.
+func fallback[T, R any](ctx context.Context, c *fallbackCache[T], keys []string, write bool, fn func(Cache[T]) (R, error)) (R, error) {
+	if !c.failedOver.Load() {
+		result, err := fn(c.primary)
+		if err == nil {
+			c.failures.Store(0)
+			return result, nil
+		}
+		if !isOutage(ctx, err) || !c.fail(err) {
+			return result, err
+		}
+	}
+
+	if !write {
+		return fn(c.secondary)
+	}
+
+	c.outage.RLock()
+	defer c.outage.RUnlock()
+	failedOver, err := c.markDirty(keys)
+	if !failedOver {
+		// Recovered meanwhile
+		return fn(c.primary)
+	}
+	if err != nil {
+		var zero R
+		return zero, err
+	}
+	return fn(c.secondary)
+}
+
+// fail records a primary failure, failing over once FailureThreshold is
+// reached, and reports whether the cache is failed over.
+func (c *fallbackCache[T]) fail(err error) bool {
+	if int(c.failures.Add(1)) < c.config.FailureThreshold {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.failedOver.Load() {
+		return c.failedOver.Load()
+	}
+	c.failedOver.Store(true)
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.probe(c.stop, c.done)
+
+	c.config.Logger.Warn("cache: primary failed, falling back to secondary", slog.Any("error", err))
+	if c.config.OnStateChange != nil {
+		c.config.OnStateChange(true, err)
+	}
+	return true
+}
+
+// markDirty records keys written to the secondary, to delete them from the
+// primary on recovery. It reports whether the cache is still failed over, and
+// ErrTooManyDirtyKeys when the keys would exceed MaxDirtyKeys.
+func (c *fallbackCache[T]) markDirty(keys []string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.failedOver.Load() {
+		return false, nil
+	}
+
+	added := 0
+	for _, key := range keys {
+		if _, ok := c.dirty[key]; !ok {
+			added++
+		}
+	}
+	if len(c.dirty)+added > c.config.MaxDirtyKeys {
+		return true, ErrTooManyDirtyKeys
+	}
+	for _, key := range keys {
+		c.dirty[key] = struct{}{}
+	}
+	return true, nil
+}
+
+// probe checks the primary every ProbeInterval until it recovers or stop is
+// closed.
+func (c *fallbackCache[T]) probe(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.config.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if c.tryRecover() {
+			return
+		}
+	}
+}
+
+// tryRecover switches back to the primary if it is healthy and the keys written
+// during the outage could be removed from it.
+func (c *fallbackCache[T]) tryRecover() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.ProbeTimeout)
+	defer cancel()
+
+	if err := pingNext(ctx, c.primary); err != nil {
+		return false
+	}
+
+	// Wait for writes to the secondary in progress and hold off new ones
+	c.outage.Lock()
+	defer c.outage.Unlock()
+
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.dirty))
+	for key := range c.dirty {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	if len(keys) > 0 {
+		if err := deleteMulti(ctx, c.primary, keys); err != nil {
+			return false
+		}
+		// Best effort - the secondary is only read during the next outage
+		_ = deleteMulti(ctx, c.secondary, keys)
+	}
+
+	c.mu.Lock()
+	clear(c.dirty)
+	c.failures.Store(0)
+	c.failedOver.Store(false)
+	c.mu.Unlock()
+
+	c.config.Logger.Info("cache: primary recovered")
+	if c.config.OnStateChange != nil {
+		c.config.OnStateChange(false, nil)
+	}
+	return true
+}
+
+func (c *fallbackCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *fallbackCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	r, err := fallback(ctx, c, nil, false, func(cache Cache[T]) (readResult[T], error) {
+		value, found, err := GetWithError(ctx, cache, key)
+		return readResult[T]{value, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (c *fallbackCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	value, err := fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (T, error) {
+		return GetOrSet(ctx, cache, key, ttl, func(ctx context.Context) (T, error) {
+			value, err := load(ctx)
+			if err != nil {
+				return value, fallbackLoadError{err}
+			}
+			return value, nil
+		})
+	})
+	var loadErr fallbackLoadError
+	if errors.As(err, &loadErr) {
+		err = loadErr.err
+	}
+	return value, err
+}
+
+func (c *fallbackCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return fallback(ctx, c, nil, false, func(cache Cache[T]) (bool, error) {
+		return Exists(ctx, cache, key)
+	})
+}
+
+func (c *fallbackCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	r, err := fallback(ctx, c, nil, false, func(cache Cache[T]) (readResult[time.Duration], error) {
+		ttl, found, err := TTL(ctx, cache, key)
+		return readResult[time.Duration]{ttl, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (c *fallbackCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	_, err := fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (struct{}, error) {
+		return struct{}{}, Expire(ctx, cache, key, ttl)
+	})
+	return err
+}
+
+func (c *fallbackCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (bool, error) {
+		return SetIfAbsent(ctx, cache, key, value, ttl)
+	})
+}
+
+func (c *fallbackCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (bool, error) {
+		return CompareAndSwap(ctx, cache, key, old, new, ttl)
+	})
+}
+
+func (c *fallbackCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	r, err := fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (readResult[T], error) {
+		value, found, err := GetDel(ctx, cache, key)
+		return readResult[T]{value, found}, err
+	})
+	return r.value, r.found, err
+}
+
+func (c *fallbackCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	_, err := fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (struct{}, error) {
+		return struct{}{}, cache.Set(ctx, key, value, ttl)
+	})
+	return err
+}
+
+func (c *fallbackCache[T]) Delete(ctx context.Context, key string) error {
+	_, err := fallback(ctx, c, []string{key}, true, func(cache Cache[T]) (struct{}, error) {
+		return struct{}{}, cache.Delete(ctx, key)
+	})
+	return err
+}
+
+func (c *fallbackCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	_, err := fallback(ctx, c, keys, true, func(cache Cache[T]) (struct{}, error) {
+		return struct{}{}, deleteMulti(ctx, cache, keys)
+	})
+	return err
+}
+
+// Clear clears both caches. The primary's failure is returned rather than
+// failing over: recovery only resyncs the keys written during the outage, so
+// a Clear served by the secondary alone would be lost.
+func (c *fallbackCache[T]) Clear(ctx context.Context) error {
+	return errors.Join(Clear(ctx, c.primary), Clear(ctx, c.secondary))
+}
+
+// Close stops probing the primary and closes both caches.
+func (c *fallbackCache[T]) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		stop, done := c.stop, c.done
+		c.mu.Unlock()
+
+		if stop != nil {
+			close(stop)
+			<-done
+		}
+		err = errors.Join(c.primary.Close(), c.secondary.Close())
+	})
+	return err
+}
+
+// unwrap exposes the primary so helpers needing the Redis client keep working.
+func (c *fallbackCache[T]) unwrap() Cache[T] {
+	return c.primary
+}
+
+// Ping reports the health of the cache in use: a failed-over cache is
+// healthy as long as its secondary is.
+func (c *fallbackCache[T]) Ping(ctx context.Context) error {
+	if c.failedOver.Load() {
+		return pingNext(ctx, c.secondary)
+	}
+	return pingNext(ctx, c.primary)
+}
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errOutage = errors.New("backend down")
+
+// outageCache fails reads, writes and health checks while down.
+type outageCache[T any] struct {
+	Cache[T]
+	down atomic.Bool
+}
+
+func (c *outageCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if c.down.Load() {
+		var zero T
+		return zero, false, errOutage
+	}
+	return GetWithError(ctx, c.Cache, key)
+}
+
+func (c *outageCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.down.Load() {
+		return errOutage
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func (c *outageCache[T]) Clear(ctx context.Context) error {
+	if c.down.Load() {
+		return errOutage
+	}
+	return Clear(ctx, c.Cache)
+}
+
+func (c *outageCache[T]) Ping(context.Context) error {
+	if c.down.Load() {
+		return errOutage
+	}
+	return nil
+}
+
+func TestFallbackFailsOverAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	secondary := NewMemory[TestUser](nil)
+
+	var changes atomic.Int32
+	cache := NewFallback[TestUser](primary, secondary, &FallbackConfig{
+		ProbeInterval: 10 * time.Millisecond,
+		OnStateChange: func(bool, error) { changes.Add(1) },
+	})
+	defer cache.Close()
+
+	_ = primary.Cache.Set(ctx, "1", TestUser{ID: "old"}, time.Minute)
+	primary.down.Store(true)
+
+	// The failing write fails over and lands in the secondary
+	if err := cache.Set(ctx, "1", TestUser{ID: "new"}, time.Minute); err != nil {
+		t.Fatalf("Expected the write to fall back, got %v", err)
+	}
+	if value, found, err := GetWithError(ctx, cache, "1"); err != nil || !found || value.ID != "new" {
+		t.Fatalf("Expected the secondary's value, got %+v found=%v err=%v", value, found, err)
+	}
+
+	primary.down.Store(false)
+	waitFor(t, func() bool { return changes.Load() == 2 })
+
+	// The primary's stale value was removed on recovery
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Error("Expected the key written during the outage to be removed from the primary")
+	}
+	if _, found := secondary.Get(ctx, "1"); found {
+		t.Error("Expected the key to be removed from the secondary on recovery")
+	}
+}
+
+func TestFallbackFailureThreshold(t *testing.T) {
+	ctx := context.Background()
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	primary.down.Store(true)
+	cache := NewFallback[TestUser](primary, NewMemory[TestUser](nil), &FallbackConfig{
+		FailureThreshold: 2,
+		ProbeInterval:    time.Hour,
+	})
+	defer cache.Close()
+
+	if _, _, err := GetWithError(ctx, cache, "1"); !errors.Is(err, errOutage) {
+		t.Errorf("Expected the first failure to be reported, got %v", err)
+	}
+	if _, _, err := GetWithError(ctx, cache, "1"); err != nil {
+		t.Errorf("Expected the second failure to fail over, got %v", err)
+	}
+}
+
+func TestFallbackKeepsLoaderErrors(t *testing.T) {
+	ctx := context.Background()
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	var changes atomic.Int32
+	cache := NewFallback[TestUser](primary, NewMemory[TestUser](nil), &FallbackConfig{
+		OnStateChange: func(bool, error) { changes.Add(1) },
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("origin down")
+	_, err := GetOrSet(ctx, cache, "1", time.Minute, func(context.Context) (TestUser, error) {
+		return TestUser{}, loadErr
+	})
+	if err != loadErr {
+		t.Errorf("Expected the loader's error, got %v", err)
+	}
+	if changes.Load() != 0 {
+		t.Error("Expected loader errors not to fail over")
+	}
+}
+
+func TestFallbackCapsDirtyKeys(t *testing.T) {
+	ctx := context.Background()
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	c := NewFallback[TestUser](primary, NewMemory[TestUser](nil), &FallbackConfig{
+		ProbeInterval: time.Hour,
+		MaxDirtyKeys:  1,
+	}).(*fallbackCache[TestUser])
+	defer c.Close()
+
+	primary.down.Store(true)
+	if err := c.Set(ctx, "1", TestUser{}, time.Minute); err != nil {
+		t.Fatalf("Expected the write to fall back, got %v", err)
+	}
+	if err := c.Set(ctx, "1", TestUser{ID: "again"}, time.Minute); err != nil {
+		t.Errorf("Expected rewrites of a dirty key to fall back, got %v", err)
+	}
+	if err := c.Set(ctx, "2", TestUser{}, time.Minute); !errors.Is(err, ErrTooManyDirtyKeys) {
+		t.Errorf("Expected ErrTooManyDirtyKeys, got %v", err)
+	}
+	if _, dirty := c.dirty["2"]; dirty || len(c.dirty) != 1 {
+		t.Errorf("Expected only key 1 to be dirty, got %v", c.dirty)
+	}
+}
+
+func TestFallbackRecoveryDoesNotClear(t *testing.T) {
+	ctx := context.Background()
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	var changes atomic.Int32
+	c := NewFallback[TestUser](primary, NewMemory[TestUser](nil), &FallbackConfig{
+		ProbeInterval: 10 * time.Millisecond,
+		OnStateChange: func(bool, error) { changes.Add(1) },
+	})
+	defer c.Close()
+
+	_ = primary.Cache.Set(ctx, "kept", TestUser{ID: "kept"}, time.Minute)
+	primary.down.Store(true)
+	_ = c.Set(ctx, "1", TestUser{}, time.Minute)
+	if err := Clear(ctx, c); !errors.Is(err, errOutage) {
+		t.Error("Expected Clear to report the outage rather than fall back")
+	}
+
+	primary.down.Store(false)
+	waitFor(t, func() bool { return changes.Load() == 2 })
+	if _, found := primary.Cache.Get(ctx, "kept"); !found {
+		t.Error("Expected recovery to leave keys not written during the outage alone")
+	}
+}
+
+func TestFallbackConcurrentClose(t *testing.T) {
+	primary := &outageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	c := NewFallback[TestUser](primary, NewMemory[TestUser](nil), &FallbackConfig{ProbeInterval: time.Hour})
+	primary.down.Store(true)
+	_ = c.Set(context.Background(), "1", TestUser{}, time.Minute)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Close()
+		}()
+	}
+	wg.Wait()
+}
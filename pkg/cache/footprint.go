@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// HeapFootprint estimates the heap retained by an in-memory cache and how much
+// work it adds to the garbage collector, to help decide when a large cache
+// should move off-heap or to the distributed tier.
+type HeapFootprint struct {
+	// Entries is the number of live entries.
+	Entries int
+
+	// SampledEntries is the number of entries measured.
+	SampledEntries int
+
+	// AvgEntryBytes is the average estimated size of a sampled entry,
+	// including its key.
+	AvgEntryBytes int64
+
+	// EstimatedBytes is Entries × AvgEntryBytes.
+	EstimatedBytes int64
+
+	// AvgPointers is the average number of pointers per sampled entry that
+	// the garbage collector has to trace.
+	AvgPointers float64
+
+	// EstimatedPointers is Entries × AvgPointers.
+	EstimatedPointers int64
+}
+
+// entryOverheadBytes approximates ttlcache's per-entry bookkeeping
+// (item struct, map slot and priority queue slot).
+const entryOverheadBytes = 96
+
+// SampleHeapFootprint estimates the heap footprint of the in-memory cache
+// behind cache by measuring up to sampleSize entries (default: 100) with
+// reflection. Estimates ignore allocator rounding and shared data.
+// Returns ErrNotMemory if cache is not backed by an in-memory backend.
+func SampleHeapFootprint[T any](cache Cache[T], sampleSize int) (HeapFootprint, error) {
+	mc, err := memoryCacheOf(cache)
+	if err != nil {
+		return HeapFootprint{}, err
+	}
+	if sampleSize <= 0 {
+		sampleSize = 100
+	}
+
+	var footprint HeapFootprint
+	if mc.cache == nil {
+		return footprint, nil
+	}
+
+	keys := mc.cache.GetKeys()
+	footprint.Entries = len(keys)
+
+	var totalBytes, totalPointers int64
+	for _, key := range keys {
+		if footprint.SampledEntries == sampleSize {
+			break
+		}
+		item := mc.peek(key, time.Now())
+		if item == nil {
+			// Expired or removed since GetKeys
+			continue
+		}
+		var value any = item.value
+		m := &sizeMeter{seen: make(map[uintptr]struct{})}
+		m.measure(reflect.ValueOf(&value).Elem())
+		totalBytes += int64(len(key)) + entryOverheadBytes + m.bytes
+		totalPointers += m.pointers
+		footprint.SampledEntries++
+	}
+
+	if footprint.SampledEntries > 0 {
+		n := int64(footprint.SampledEntries)
+		footprint.AvgEntryBytes = totalBytes / n
+		footprint.EstimatedBytes = footprint.AvgEntryBytes * int64(footprint.Entries)
+		footprint.AvgPointers = float64(totalPointers) / float64(n)
+		footprint.EstimatedPointers = int64(footprint.AvgPointers * float64(footprint.Entries))
+	}
+	return footprint, nil
+}
+
+// sizeMeter walks a value and sums the sizes of everything it references.
+type sizeMeter struct {
+	bytes    int64
+	pointers int64
+	seen     map[uintptr]struct{}
+}
+
+// maxMeasureDepth stops runaway recursion on deeply nested values.
+const maxMeasureDepth = 32
+
+func (m *sizeMeter) measure(v reflect.Value) {
+	m.bytes += int64(v.Type().Size())
+	m.walk(v, 0)
+}
+
+// walk adds the sizes of the data v points to; v's own size is already counted.
+func (m *sizeMeter) walk(v reflect.Value, depth int) {
+	if depth > maxMeasureDepth {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() > 0 {
+			m.pointers++
+			m.bytes += int64(v.Len())
+		}
+
+	case reflect.Pointer:
+		if v.IsNil() || !m.visit(v.Pointer()) {
+			return
+		}
+		m.pointers++
+		elem := v.Elem()
+		m.bytes += int64(elem.Type().Size())
+		m.walk(elem, depth+1)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		m.pointers++
+		elem := v.Elem()
+		m.bytes += int64(elem.Type().Size())
+		m.walk(elem, depth+1)
+
+	case reflect.Slice:
+		if v.IsNil() || !m.visit(v.Pointer()) {
+			return
+		}
+		m.pointers++
+		m.bytes += int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			m.walk(v.Index(i), depth+1)
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			m.walk(v.Index(i), depth+1)
+		}
+
+	case reflect.Map:
+		if v.IsNil() || !m.visit(v.Pointer()) {
+			return
+		}
+		m.pointers++
+		entrySize := int64(v.Type().Key().Size() + v.Type().Elem().Size())
+		m.bytes += int64(v.Len()) * entrySize
+		iter := v.MapRange()
+		for iter.Next() {
+			m.walk(iter.Key(), depth+1)
+			m.walk(iter.Value(), depth+1)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			// Protobuf runtime state points at type metadata shared by all messages
+			if strings.HasPrefix(field.Type().PkgPath(), "google.golang.org/protobuf/internal/") {
+				continue
+			}
+			m.walk(field, depth+1)
+		}
+	}
+}
+
+// visit reports whether p is seen for the first time, so shared data is counted once.
+func (m *sizeMeter) visit(p uintptr) bool {
+	if p == 0 {
+		return false
+	}
+	if _, ok := m.seen[p]; ok {
+		return false
+	}
+	m.seen[p] = struct{}{}
+	return true
+}
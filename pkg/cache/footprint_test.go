@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestSampleHeapFootprint(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		_ = cache.Set(ctx, fmt.Sprintf("key%d", i), TestUser{ID: "123", Name: "John"}, time.Minute)
+	}
+
+	footprint, err := SampleHeapFootprint(cache, 5)
+	if err != nil {
+		t.Fatalf("SampleHeapFootprint failed: %v", err)
+	}
+	if footprint.Entries != 10 || footprint.SampledEntries != 5 {
+		t.Errorf("Unexpected counts: %+v", footprint)
+	}
+
+	// interface header + TestUser + string data + key + entry overhead
+	wantBytes := int64(16 + 32 + len("123") + len("John") + len("key0") + entryOverheadBytes)
+	if footprint.AvgEntryBytes != wantBytes {
+		t.Errorf("Expected %d bytes per entry, got %d", wantBytes, footprint.AvgEntryBytes)
+	}
+	if footprint.EstimatedBytes != 10*wantBytes {
+		t.Errorf("Expected %d estimated bytes, got %d", 10*wantBytes, footprint.EstimatedBytes)
+	}
+	// interface data pointer + two string data pointers
+	if footprint.AvgPointers != 3 || footprint.EstimatedPointers != 30 {
+		t.Errorf("Unexpected pointer estimates: %+v", footprint)
+	}
+}
+
+func TestSampleHeapFootprintNested(t *testing.T) {
+	ctx := context.Background()
+
+	type node struct {
+		Tags     []string
+		Children map[string]*node
+		Next     *node
+	}
+
+	cache := NewMemory[*node](&MemoryConfig{TrackHits: true})
+	defer cache.Close()
+
+	// Test cycles and shared pointers don't loop forever
+	root := &node{Tags: []string{"a", "b"}, Children: map[string]*node{}}
+	root.Next = root
+	root.Children["self"] = root
+	_ = cache.Set(ctx, "root", root, time.Minute)
+
+	footprint, err := SampleHeapFootprint(cache, 0)
+	if err != nil {
+		t.Fatalf("SampleHeapFootprint failed: %v", err)
+	}
+	if footprint.SampledEntries != 1 || footprint.AvgEntryBytes <= 0 {
+		t.Errorf("Unexpected footprint: %+v", footprint)
+	}
+
+	// Test proto messages are measured without their shared runtime metadata
+	protos := NewMemory[*wrapperspb.StringValue](nil)
+	defer protos.Close()
+	_ = protos.Set(ctx, "key1", wrapperspb.String("hello"), time.Minute)
+
+	footprint, _ = SampleHeapFootprint(protos, 0)
+	if footprint.AvgEntryBytes <= 0 || footprint.AvgEntryBytes > 512 {
+		t.Errorf("Expected a small proto footprint, got %d bytes", footprint.AvgEntryBytes)
+	}
+}
+
+func TestSampleHeapFootprintRequiresMemoryCache(t *testing.T) {
+	if _, err := SampleHeapFootprint(NewNoOp[TestUser](), 0); !errors.Is(err, ErrNotMemory) {
+		t.Errorf("Expected ErrNotMemory, got: %v", err)
+	}
+}
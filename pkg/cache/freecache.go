@@ -0,0 +1,342 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/coocood/freecache"
+)
+
+// freeCache is an in-memory cache that stores serialized bytes in FreeCache's
+// pre-allocated segments, so millions of entries add no pointers for the
+// garbage collector to trace.
+type freeCache[T any] struct {
+	cache     *freecache.Cache
+	codec     valueCodec[T]
+	closed    closeGuard
+	ctxPolicy contextPolicy
+	stats     statsCounters
+
+	// condMu serializes CompareAndSwap and GetDel, since FreeCache has no
+	// conditional replace or delete
+	condMu sync.Mutex
+}
+
+func newFreeCache[T any](config *MemoryConfig) *freeCache[T] {
+	size := 64 << 20
+	var serializer Serializer
+	if config != nil {
+		if config.SizeBytes > 0 {
+			size = config.SizeBytes
+		}
+		serializer = config.Serializer
+	}
+
+	return &freeCache[T]{
+		cache:     freecache.NewCache(size),
+		codec:     newValueCodec[T](serializer),
+		ctxPolicy: newContextPolicy(config),
+	}
+}
+
+func (c *freeCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *freeCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.ctxPolicy.cancelled(ctx) {
+		return zero, false, nil
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	data, err := c.cache.Get([]byte(key))
+	if errors.Is(err, freecache.ErrNotFound) {
+		c.stats.read(false, nil)
+		return zero, false, nil
+	}
+	if err != nil {
+		c.stats.read(false, err)
+		return zero, false, err
+	}
+
+	value, err := c.codec.decode(data)
+	c.stats.read(err == nil, err)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+func (c *freeCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	_, err := c.cache.TTL([]byte(key))
+	if errors.Is(err, freecache.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// TTL reports the lifetime left to key, in whole seconds like FreeCache
+// stores it.
+func (c *freeCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return 0, false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	seconds, err := c.cache.TTL([]byte(key))
+	if errors.Is(err, freecache.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return time.Duration(seconds) * time.Second, true, nil
+}
+
+// Expire changes the expiry of key in place, rounding ttl up to whole seconds
+// like Set.
+func (c *freeCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	err := c.cache.Touch([]byte(key), expireSecondsOf(ttl))
+	if errors.Is(err, freecache.ErrNotFound) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// Set stores the serialized value. TTLs are rounded up to whole seconds.
+// Entries larger than 1/1024 of SizeBytes are rejected by FreeCache.
+// GetDel removes key and returns its value; only the caller whose delete
+// removed the entry gets it.
+func (c *freeCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.ctxPolicy.cancelled(ctx) {
+		return zero, false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+
+	data, err := c.cache.Get([]byte(key))
+	if errors.Is(err, freecache.ErrNotFound) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+	if !c.cache.Del([]byte(key)) {
+		return zero, false, nil
+	}
+
+	value, err := c.codec.decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// scanKeys enumerates live keys with FreeCache's iterator, which skips
+// expired entries.
+func (c *freeCache[T]) scanKeys(ctx context.Context, cfg ScanConfig, fn func([]KeyInfo) error) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	batch := make([]KeyInfo, 0, cfg.BatchSize)
+	it := c.cache.NewIterator()
+	for entry := it.Next(); entry != nil; entry = it.Next() {
+		key := string(entry.Key)
+		if matched, err := path.Match(cfg.Pattern, key); err != nil {
+			return err
+		} else if !matched {
+			continue
+		}
+
+		info := KeyInfo{Key: key, Size: int64(len(entry.Value))}
+		if entry.ExpireAt != 0 {
+			info.TTL = time.Until(time.Unix(int64(entry.ExpireAt), 0))
+		}
+		batch = append(batch, info)
+		if len(batch) == cfg.BatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]KeyInfo, 0, cfg.BatchSize)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (c *freeCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return err
+	}
+
+	err = c.cache.Set([]byte(key), data, expireSecondsOf(ttl))
+	c.stats.wrote(err)
+	return err
+}
+
+// SetIfAbsent stores the serialized value unless key is stored, atomically
+// within FreeCache's segment lock.
+func (c *freeCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := c.cache.GetOrSet([]byte(key), data, expireSecondsOf(ttl))
+	if err != nil {
+		return false, err
+	}
+	return existing == nil, nil
+}
+
+// CompareAndSwap stores new if the serialized value of key equals that of old.
+// Concurrent swaps are serialized; a Set racing with it may be overwritten.
+func (c *freeCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	oldData, err := c.codec.encode(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.encode(new)
+	if err != nil {
+		return false, err
+	}
+
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+
+	current, err := c.cache.Get([]byte(key))
+	if errors.Is(err, freecache.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil || !bytes.Equal(current, oldData) {
+		return false, err
+	}
+	if err := c.cache.Set([]byte(key), newData, expireSecondsOf(ttl)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// expireSecondsOf rounds ttl up to the whole seconds FreeCache stores, 0 for
+// no expiry.
+func expireSecondsOf(ttl time.Duration) int {
+	if ttl <= 0 {
+		return 0
+	}
+	return int((ttl + time.Second - 1) / time.Second)
+}
+
+func (c *freeCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *freeCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		c.cache.Del([]byte(key))
+	}
+	c.stats.deleted(len(keys), nil)
+	return nil
+}
+
+func (c *freeCache[T]) Clear(ctx context.Context) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	c.cache.Clear()
+	return nil
+}
+
+// Stats returns the traffic counters; evictions include entries evacuated
+// to make room and expired entries FreeCache removed.
+func (c *freeCache[T]) Stats() CacheStats {
+	stats := c.stats.snapshot()
+	stats.Evictions = uint64(c.cache.EvacuateCount() + c.cache.ExpiredCount())
+	return stats
+}
+
+func (c *freeCache[T]) Close() error {
+	if c.closed.close() {
+		c.cache.Clear()
+	}
+	return nil
+}
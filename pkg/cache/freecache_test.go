@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestFreeCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[*TestUser](&MemoryConfig{Engine: MemoryEngineFreeCache, SizeBytes: 1 << 20})
+	defer cache.Close()
+
+	user := &TestUser{ID: "123", Name: "John"}
+	if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, found := cache.Get(ctx, "key1")
+	if !found || got.ID != "123" || got.Name != "John" {
+		t.Fatalf("Expected key1, got %v, %v", got, found)
+	}
+
+	// Test values are copies
+	got.Name = "Jane"
+	if again, _ := cache.Get(ctx, "key1"); again.Name != "John" {
+		t.Error("Expected mutating a read value not to change the cached entry")
+	}
+
+	// Test Delete
+	_ = cache.Delete(ctx, "key1")
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted")
+	}
+
+	// Test TTLs below a second are rounded up instead of meaning "no expiry"
+	_ = cache.Set(ctx, "short", user, 10*time.Millisecond)
+	if _, found := cache.Get(ctx, "short"); !found {
+		t.Error("Expected short-lived entry to be stored")
+	}
+
+	// Test entries above 1/1024 of the cache size are rejected
+	big := &TestUser{ID: string(make([]byte, 2<<10))}
+	if err := cache.Set(ctx, "big", big, time.Minute); err == nil {
+		t.Error("Expected oversized entry to be rejected")
+	}
+}
+
+func TestFreeCacheProto(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[*wrapperspb.StringValue](&MemoryConfig{Engine: MemoryEngineFreeCache})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", wrapperspb.String("hello"), time.Minute)
+	if got, found := cache.Get(ctx, "key1"); !found || got.GetValue() != "hello" {
+		t.Errorf("Expected proto round trip, got %v, %v", got, found)
+	}
+}
+
+func TestFreeCacheClose(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{Engine: MemoryEngineFreeCache})
+
+	_ = cache.Close()
+	if err := cache.Close(); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{}, time.Minute); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+}
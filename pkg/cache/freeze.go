@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// freezeCache stores snapshots of the values written to it.
+type freezeCache[T any] struct {
+	next   Cache[T]
+	freeze func(T) T
+}
+
+// WithFreeze wraps a cache so that every value written is passed once through
+// freeze, typically a deep copy, and the snapshot it returns is stored
+// instead. Memory caches store values as they are, so a caller mutating an
+// object after caching it races with every goroutine reading it; with a
+// snapshot, the cached value only changes through the cache. Set,
+// SetIfAbsent, CompareAndSwap (its new value) and the values loaded by
+// GetOrSet are frozen.
+//
+// Reads still share the stored snapshot between goroutines: treat values read
+// from the cache as read-only. Caches that serialize values, such as
+// distributed caches, don't need it. A nil freeze returns cache unchanged.
+func WithFreeze[T any](cache Cache[T], freeze func(T) T) Cache[T] {
+	if freeze == nil {
+		return cache
+	}
+	return &freezeCache[T]{next: cache, freeze: freeze}
+}
+
+// FreezeProto returns a freeze function for WithFreeze deep-copying proto
+// messages with proto.Clone.
+func FreezeProto[T proto.Message]() func(T) T {
+	return func(value T) T {
+		clone, _ := proto.Clone(value).(T)
+		return clone
+	}
+}
+
+func (c *freezeCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *freezeCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+// GetOrSet freezes the loaded value before it is cached; the caller receives
+// the snapshot too.
+func (c *freezeCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		return c.freeze(value), nil
+	})
+}
+
+func (c *freezeCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *freezeCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *freezeCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *freezeCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, c.freeze(value), ttl)
+}
+
+func (c *freezeCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, c.freeze(new), ttl)
+}
+
+func (c *freezeCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *freezeCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, c.freeze(value), ttl)
+}
+
+func (c *freezeCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *freezeCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *freezeCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *freezeCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *freezeCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *freezeCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
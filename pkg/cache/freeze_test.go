@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWithFreeze(t *testing.T) {
+	ctx := context.Background()
+	cache := WithFreeze(NewMemory[*TestUser](nil), func(user *TestUser) *TestUser {
+		clone := *user
+		return &clone
+	})
+	defer cache.Close()
+
+	user := &TestUser{ID: "1", Name: "John"}
+	_ = cache.Set(ctx, "1", user, time.Minute)
+	user.Name = "Jane"
+
+	if cached, _ := cache.Get(ctx, "1"); cached.Name != "John" {
+		t.Errorf("Expected the cached snapshot to be unaffected, got %q", cached.Name)
+	}
+
+	stored, _ := SetIfAbsent(ctx, cache, "2", user, time.Minute)
+	user.Name = "Joe"
+	if cached, _ := cache.Get(ctx, "2"); !stored || cached.Name != "Jane" {
+		t.Errorf("Expected SetIfAbsent to store a snapshot, got %q", cached.Name)
+	}
+
+	loaded := &TestUser{ID: "3", Name: "Ann"}
+	_, _ = GetOrSet(ctx, cache, "3", time.Minute, func(context.Context) (*TestUser, error) {
+		return loaded, nil
+	})
+	loaded.Name = "Bob"
+	if cached, _ := cache.Get(ctx, "3"); cached.Name != "Ann" {
+		t.Errorf("Expected GetOrSet to cache a snapshot, got %q", cached.Name)
+	}
+}
+
+func TestFreezeProto(t *testing.T) {
+	ctx := context.Background()
+	cache := WithFreeze(NewMemory[*wrapperspb.StringValue](nil), FreezeProto[*wrapperspb.StringValue]())
+	defer cache.Close()
+
+	msg := wrapperspb.String("John")
+	_ = cache.Set(ctx, "name", msg, time.Minute)
+	msg.Value = "Jane"
+
+	if cached, _ := cache.Get(ctx, "name"); cached.GetValue() != "John" {
+		t.Errorf("Expected the cached clone to be unaffected, got %q", cached.GetValue())
+	}
+}
+
+func TestWithFreezeNil(t *testing.T) {
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	if cache := WithFreeze(memory, nil); cache != memory {
+		t.Error("Expected a nil freeze to return the cache unchanged")
+	}
+}
@@ -0,0 +1,28 @@
+package cache
+
+import "context"
+
+// GetDel removes key and returns the value it held, e.g. with Redis GETDEL.
+// Use it for one-shot tokens such as password reset links or one-time
+// passwords, where only the first request presenting the token may consume it:
+//
+//	userID, found, err := cache.GetDel(ctx, resetTokens, token)
+//
+// Caches that don't implement GetDeleter are read with GetWithError and then
+// deleted, so two callers may both get the value; only rely on GetDel for
+// single use with caches that implement it.
+func GetDel[T any](ctx context.Context, cache Cache[T], key string) (T, bool, error) {
+	if d, ok := cache.(GetDeleter[T]); ok {
+		return d.GetDel(ctx, key)
+	}
+
+	value, found, err := GetWithError(ctx, cache, key)
+	if err != nil || !found {
+		return value, found, err
+	}
+	if err := cache.Delete(ctx, key); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return value, true, nil
+}
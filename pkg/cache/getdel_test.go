@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetDelMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			user := TestUser{ID: "1", Name: "Ann"}
+			_ = cache.Set(ctx, "token", user, time.Minute)
+			_ = flushAll(ctx, cache)
+
+			if got, found, err := GetDel(ctx, cache, "token"); err != nil || !found || got != user {
+				t.Errorf("Expected GetDel to return %+v, got %+v (found=%v, err=%v)", user, got, found, err)
+			}
+			_ = flushAll(ctx, cache)
+			if _, found := cache.Get(ctx, "token"); found {
+				t.Error("Expected the token to be removed")
+			}
+			if _, found, err := GetDel(ctx, cache, "token"); err != nil || found {
+				t.Errorf("Expected the token to be consumed, got found=%v, err=%v", found, err)
+			}
+		})
+	}
+}
+
+func TestGetDelConsumesOnce(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+			_ = cache.Set(ctx, "otp", TestUser{ID: "1"}, time.Minute)
+
+			var consumed atomic.Int32
+			var wg sync.WaitGroup
+			for range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, found, _ := GetDel(ctx, cache, "otp"); found {
+						consumed.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := consumed.Load(); n != 1 {
+				t.Errorf("Expected the token to be consumed once, got %d", n)
+			}
+		})
+	}
+}
+
+func TestGetDelFallsBackToDelete(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	backend := &unclearableCache[TestUser]{Cache: inner}
+	defer backend.Close()
+
+	_ = backend.Set(ctx, "token", TestUser{ID: "1"}, time.Minute)
+	if got, found, err := GetDel[TestUser](ctx, backend, "token"); err != nil || !found || got.ID != "1" {
+		t.Errorf("Expected GetDel to return the token, got %+v (found=%v, err=%v)", got, found, err)
+	}
+	if _, found := inner.Get(ctx, "token"); found {
+		t.Error("Expected the token to be removed")
+	}
+}
+
+func TestGetDelTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "token", TestUser{ID: "1"}, time.Hour)
+	if _, found, err := GetDel(ctx, cache, "token"); err != nil || !found {
+		t.Fatalf("Expected GetDel to find the token, got found=%v, err=%v", found, err)
+	}
+	if _, found := l1.Get(ctx, "token"); found {
+		t.Error("Expected the L1 copy to be removed")
+	}
+	if _, found := l2.Get(ctx, "token"); found {
+		t.Error("Expected the L2 entry to be removed")
+	}
+
+	// A copy left in L1 alone isn't handed out
+	_ = l1.Set(ctx, "token", TestUser{ID: "1"}, time.Minute)
+	if _, found, err := GetDel(ctx, cache, "token"); err != nil || found {
+		t.Errorf("Expected L2 to decide, got found=%v, err=%v", found, err)
+	}
+}
+
+func TestGetDelThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	cache := WithPrefix(backend, "p:")
+	_ = cache.Set(ctx, "token", TestUser{ID: "1"}, time.Minute)
+	if _, found, err := GetDel(ctx, cache, "token"); err != nil || !found {
+		t.Fatalf("Expected GetDel to find the token, got found=%v, err=%v", found, err)
+	}
+	if _, found := backend.Get(ctx, "p:token"); found {
+		t.Error("Expected the prefixed key to be removed")
+	}
+}
+
+func TestGetDelDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Set(ctx, "token", TestUser{ID: "1"}, time.Minute)
+	if got, found, err := GetDel(ctx, cache, "token"); err != nil || !found || got.ID != "1" {
+		t.Errorf("Expected GetDel to return the token, got %+v (found=%v, err=%v)", got, found, err)
+	}
+	if _, found, err := GetDel(ctx, cache, "token"); err != nil || found {
+		t.Errorf("Expected the token to be consumed, got found=%v, err=%v", found, err)
+	}
+
+	_ = cache.Set(ctx, "short", TestUser{ID: "1"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, found, err := GetDel(ctx, cache, "short"); err != nil || found {
+		t.Errorf("Expected an expired entry to be a miss, got found=%v, err=%v", found, err)
+	}
+}
+
+func TestGetDelDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	for _, rejectOlder := range []bool{false, true} {
+		cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr, RejectOlderWrites: rejectOlder})
+		if err != nil {
+			t.Fatalf("Failed to create cache: %v", err)
+		}
+
+		user := TestUser{ID: "1", Name: "Ann"}
+		_ = cache.Set(ctx, "token", user, time.Minute)
+		if got, found, err := GetDel(ctx, cache, "token"); err != nil || !found || got != user {
+			t.Errorf("Expected GetDel to return %+v (rejectOlder=%v), got %+v (found=%v, err=%v)", user, rejectOlder, got, found, err)
+		}
+		if _, found, err := GetDel(ctx, cache, "token"); err != nil || found {
+			t.Errorf("Expected the token to be consumed, got found=%v, err=%v", found, err)
+		}
+		_ = cache.Close()
+	}
+}
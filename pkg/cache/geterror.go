@@ -0,0 +1,18 @@
+package cache
+
+import "context"
+
+// GetWithError retrieves a value from cache like Get, but reports failed reads
+// (e.g. Redis connection errors or undecodable values) as errors instead of
+// misses, so callers can treat infrastructure errors differently. Caches that
+// don't implement ErrorGetter never fail a read and return a nil error.
+//
+// Distributed caches report failures according to DistributedConfig.Degraded:
+// set BackendDownError and SerializerErrorError to receive them here.
+func GetWithError[T any](ctx context.Context, cache Cache[T], key string) (T, bool, error) {
+	if eg, ok := cache.(ErrorGetter[T]); ok {
+		return eg.GetWithError(ctx, key)
+	}
+	value, found := cache.Get(ctx, key)
+	return value, found, nil
+}
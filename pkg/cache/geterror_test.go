@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetWithError(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Memory", func(t *testing.T) {
+		cache := NewMemory[TestUser](nil)
+		defer cache.Close()
+
+		_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+		if user, found, err := GetWithError(ctx, cache, "key1"); err != nil || !found || user.ID != "123" {
+			t.Errorf("Expected hit, got %v, %v, %v", user, found, err)
+		}
+		if _, found, err := GetWithError(ctx, cache, "missing"); err != nil || found {
+			t.Errorf("Expected clean miss, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("BackendDownThroughDecorators", func(t *testing.T) {
+		var cache Cache[TestUser] = newUnreachableDistributedCache(t, DegradedPolicy{OnBackendDown: BackendDownError})
+		cache = WithNilValuePolicy(cache, NilValueMiss)
+		cache = NewLoading(cache, nil)
+		cache = WithKeyFromContext(cache, localeFromContext)
+
+		_, found, err := GetWithError(ctx, cache, "key1")
+		if found || err == nil {
+			t.Errorf("Expected backend error, got %v, %v", found, err)
+		}
+
+		// Test plain Get still reports a miss
+		if _, found := cache.Get(ctx, "key1"); found {
+			t.Error("Expected miss from Get")
+		}
+	})
+
+	t.Run("KeyTooLong", func(t *testing.T) {
+		cache := WithMaxKeyLength(NewMemory[TestUser](nil), 4, KeyLengthReject)
+		defer cache.Close()
+
+		if _, _, err := GetWithError(ctx, cache, strings.Repeat("k", 5)); !errors.Is(err, ErrKeyTooLong) {
+			t.Errorf("Expected ErrKeyTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("Tiered", func(t *testing.T) {
+		l2 := newUnreachableDistributedCache(t, DegradedPolicy{OnBackendDown: BackendDownError})
+		cache := NewTiered[TestUser](NewMemory[TestUser](nil), l2, nil)
+
+		if _, _, err := GetWithError(ctx, cache, "key1"); err == nil {
+			t.Error("Expected L2 error to be reported")
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cachetest"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// goldenOrder is the canonical fixture for serializer golden files. Changing
+// it requires regenerating them with CACHETEST_UPDATE_GOLDEN=1.
+type goldenOrder struct {
+	ID       string    `json:"id"`
+	Quantity int       `json:"quantity"`
+	Price    float64   `json:"price"`
+	Tags     []string  `json:"tags"`
+	Created  time.Time `json:"created"`
+	Note     *string   `json:"note"`
+}
+
+func newGoldenOrder() goldenOrder {
+	note := "leave at the door"
+	return goldenOrder{
+		ID:       "order-1",
+		Quantity: 3,
+		Price:    19.99,
+		Tags:     []string{"gift", "express"},
+		Created:  time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC),
+		Note:     &note,
+	}
+}
+
+func goldenPath(name string) string {
+	return filepath.Join("testdata", "golden", name)
+}
+
+// TestSerializerGoldenFiles guards the stored format of every serializer:
+// a failure means entries written by the previous release won't match.
+func TestSerializerGoldenFiles(t *testing.T) {
+	order := newGoldenOrder()
+
+	t.Run("json", func(t *testing.T) {
+		cachetest.AssertGolden(t, NewJSONSerializer(), order, goldenPath("order.json"))
+	})
+	t.Run("gob", func(t *testing.T) {
+		cachetest.AssertGolden(t, NewGobSerializer(), order, goldenPath("order.gob"))
+	})
+	t.Run("protobuf", func(t *testing.T) {
+		created := timestamppb.New(order.Created)
+		cachetest.AssertGolden(t, protobufSerializer{}, created, goldenPath("timestamp.pb"))
+	})
+	t.Run("protojson", func(t *testing.T) {
+		// protojson output deliberately varies its whitespace
+		created := timestamppb.New(order.Created)
+		cachetest.AssertGoldenDecodes(t, NewProtoJSONSerializer(), created, goldenPath("timestamp.protojson"))
+	})
+	t.Run("type checking", func(t *testing.T) {
+		serializer, err := NewTypeCheckingSerializer(NewJSONSerializer(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cachetest.AssertGolden(t, serializer, order, goldenPath("order.typed"))
+	})
+
+	// Enough repetition for every algorithm to shrink it, so it isn't stored raw
+	compressible := order
+	for range 50 {
+		compressible.Tags = append(compressible.Tags, "gift")
+	}
+	for _, algo := range []CompressionType{CompressionGzip, CompressionSnappy, CompressionZstd} {
+		t.Run(string(algo), func(t *testing.T) {
+			// Compressed output may change with library versions; reading must not
+			serializer, err := NewCompressedSerializer(NewJSONSerializer(), algo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cachetest.AssertGoldenDecodes(t, serializer, compressible, goldenPath("order.json."+string(algo)))
+		})
+	}
+}
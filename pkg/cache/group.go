@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Group tracks the caches of an application so they can be shut down
+// together, e.g. from an fx/dig OnStop hook:
+//
+//	group := cache.NewGroup()
+//	users, err := cache.NewIn[*User](group, &cache.Config{Name: "users", ...})
+//	lc.Append(fx.Hook{OnStop: group.CloseAll})
+type Group struct {
+	mu      sync.Mutex
+	members []groupMember
+	clients []io.Closer
+	closed  bool
+}
+
+// groupMember is a tracked cache.
+type groupMember struct {
+	name    string
+	flush   func(ctx context.Context) error
+	close   func() error
+	checker HealthChecker
+}
+
+// NewGroup creates an empty Group.
+func NewGroup() *Group {
+	return &Group{}
+}
+
+// NewIn creates a cache like New and tracks it in group under config.Name.
+func NewIn[T any](group *Group, config *Config) (Cache[T], error) {
+	cache, err := New[T](config)
+	if err != nil {
+		return nil, err
+	}
+	name := ""
+	if config != nil {
+		name = config.Name
+	}
+	return Track(group, name, cache), nil
+}
+
+// Track adds a cache created without NewIn, e.g. an AsyncCache, to group under
+// name and returns it. Unnamed caches are named after their position.
+func Track[T any](group *Group, name string, cache Cache[T]) Cache[T] {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if name == "" {
+		name = fmt.Sprintf("cache-%d", len(group.members)+1)
+	}
+	group.members = append(group.members, groupMember{
+		name:    name,
+		flush:   func(ctx context.Context) error { return flushAll(ctx, cache) },
+		close:   cache.Close,
+		checker: HealthCheckerOf(cache),
+	})
+	return cache
+}
+
+// AddClient registers a resource shared by the group's caches, such as a
+// redis.UniversalClient passed as DistributedConfig.Client, to be closed after
+// every cache.
+func (g *Group) AddClient(client io.Closer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.clients = append(g.clients, client)
+}
+
+// Checkers returns a health checker per tracked cache by name, for CheckAll
+// and ReadinessHandler.
+func (g *Group) Checkers() map[string]HealthChecker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	checkers := make(map[string]HealthChecker, len(g.members))
+	for _, m := range g.members {
+		checkers[m.name] = m.checker
+	}
+	return checkers
+}
+
+// CloseAll shuts the group down in order: it flushes buffered writes (such as
+// asynchronous writers) of every cache, closes the caches in reverse order of
+// tracking, then closes the shared clients. Every step runs even when earlier
+// ones fail; the errors are returned together. When ctx is done first,
+// CloseAll returns ctx's error and closing finishes in the background. Calls
+// after the first return nil.
+func (g *Group) CloseAll(ctx context.Context) error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	members := g.members
+	clients := g.clients
+	g.mu.Unlock()
+
+	var mu sync.Mutex
+	var errs []error
+	addErr := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	// Flush concurrently so one slow backend doesn't eat the others' shutdown time
+	var wg sync.WaitGroup
+	for _, m := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.flush(ctx); err != nil {
+				addErr(fmt.Errorf("flush %s: %w", m.name, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Some caches drain on Close (e.g. AsyncCache), which can outlast ctx
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for i := len(members) - 1; i >= 0; i-- {
+			if err := members[i].close(); err != nil {
+				addErr(fmt.Errorf("close %s: %w", members[i].name, err))
+			}
+		}
+		for _, client := range clients {
+			if err := client.Close(); err != nil {
+				addErr(fmt.Errorf("close client: %w", err))
+			}
+		}
+	}()
+
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		addErr(fmt.Errorf("close: %w", ctx.Err()))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closeRecorder records Close calls in a shared log.
+type closeRecorder struct {
+	mu   *sync.Mutex
+	log  *[]string
+	name string
+	err  error
+}
+
+func (r *closeRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.log = append(*r.log, r.name)
+	return r.err
+}
+
+// recordedCache is a cache whose Close is recorded.
+type recordedCache[T any] struct {
+	Cache[T]
+	closer *closeRecorder
+}
+
+func (c *recordedCache[T]) Close() error {
+	_ = c.Cache.Close()
+	return c.closer.Close()
+}
+
+func (c *recordedCache[T]) unwrap() Cache[T] {
+	return c.Cache
+}
+
+func TestGroupCloseAll(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var log []string
+	recorder := func(name string, err error) *closeRecorder {
+		return &closeRecorder{mu: &mu, log: &log, name: name, err: err}
+	}
+
+	group := NewGroup()
+	backend := NewMemory[TestUser](nil)
+	async := NewAsync[TestUser](&recordedCache[TestUser]{Cache: backend, closer: recorder("async", nil)}, nil)
+	Track[TestUser](group, "users", async)
+	Track[TestUser](group, "", &recordedCache[TestUser]{Cache: NewMemory[TestUser](nil), closer: recorder("second", errors.New("boom"))})
+	group.AddClient(recorder("client", nil))
+
+	_ = async.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	err := group.CloseAll(ctx)
+	if err == nil || len(log) != 3 {
+		t.Fatalf("Expected every close to run and the failure to be reported, got %v (log %v)", err, log)
+	}
+
+	// Test caches close in reverse order, clients last
+	want := []string{"second", "async", "client"}
+	for i := range want {
+		if log[i] != want[i] {
+			t.Errorf("Expected close order %v, got %v", want, log)
+			break
+		}
+	}
+	if stats := async.QueueStats(); stats.Written != 1 {
+		t.Errorf("Expected the queued write to be flushed, got %+v", stats)
+	}
+
+	// Test CloseAll is idempotent
+	if err := group.CloseAll(ctx); err != nil {
+		t.Errorf("Expected a second CloseAll to return nil, got: %v", err)
+	}
+}
+
+func TestGroupCloseAllTimeout(t *testing.T) {
+	group := NewGroup()
+	slow := &gatedCache[TestUser]{Cache: NewMemory[TestUser](nil), gate: make(chan struct{})}
+	Track[TestUser](group, "slow", &blockingCloseCache[TestUser]{Cache: slow})
+	defer close(slow.gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := group.CloseAll(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected CloseAll to give up with ctx, got: %v", err)
+	}
+}
+
+// blockingCloseCache blocks Close until the gate of its gatedCache is closed.
+type blockingCloseCache[T any] struct {
+	Cache[T]
+}
+
+func (c *blockingCloseCache[T]) Close() error {
+	<-c.Cache.(*gatedCache[T]).gate
+	return nil
+}
+
+func TestGroupNewInAndCheckers(t *testing.T) {
+	group := NewGroup()
+	if _, err := NewIn[TestUser](group, &Config{Type: TypeMemory, Name: "users"}); err != nil {
+		t.Fatalf("NewIn failed: %v", err)
+	}
+	if _, err := NewIn[TestUser](group, &Config{Type: CacheType("unknown")}); err == nil {
+		t.Error("Expected an error for an invalid config")
+	}
+
+	results := CheckAll(context.Background(), group.Checkers())
+	if len(results) != 1 || results["users"] != nil {
+		t.Errorf("Expected the users cache to be healthy, got %v", results)
+	}
+	if err := group.CloseAll(context.Background()); err != nil {
+		t.Errorf("CloseAll failed: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheHottest(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{SkipTTLExtensionOnHit: true, TrackHits: true})
+	defer cache.Close()
+
+	for i := 0; i < 5; i++ {
+		_ = cache.Set(ctx, fmt.Sprintf("key%d", i), TestUser{ID: fmt.Sprint(i)}, time.Hour)
+	}
+	// Test hit counts order entries: key3 > key1 > rest
+	for i := 0; i < 3; i++ {
+		cache.Get(ctx, "key3")
+	}
+	cache.Get(ctx, "key1")
+
+	mc, err := memoryCacheOf(cache)
+	if err != nil {
+		t.Fatalf("memoryCacheOf failed: %v", err)
+	}
+	entries := mc.hottest(2)
+	if len(entries) != 2 || entries[0].key != "key3" || entries[1].key != "key1" {
+		t.Fatalf("Unexpected hottest entries: %+v", entries)
+	}
+	if entries[0].hits != 3 || entries[0].value.ID != "3" {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+	if entries[0].ttl <= 0 || entries[0].ttl > time.Hour {
+		t.Errorf("Expected remaining TTL within 1h, got %v", entries[0].ttl)
+	}
+
+	// Test values are still served normally when tracked
+	if user, found := cache.Get(ctx, "key0"); !found || user.ID != "0" {
+		t.Errorf("Expected key0 to be served, got %v, %v", user, found)
+	}
+}
+
+func TestMemoryCacheHottestDoesNotExtendTTLs(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{TrackHits: true})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "1"}, 200*time.Millisecond)
+	mc, err := memoryCacheOf(cache)
+	if err != nil {
+		t.Fatalf("memoryCacheOf failed: %v", err)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	entries := mc.hottest(1)
+	if len(entries) != 1 || entries[0].hits != 0 {
+		t.Fatalf("Expected the entry without a counted hit, got %+v", entries)
+	}
+	if entries[0].ttl > 80*time.Millisecond {
+		t.Errorf("Expected the scan not to extend the TTL, got %v left", entries[0].ttl)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected the entry to expire on its original schedule")
+	}
+}
+
+func TestHandOffRequiresMemoryAndDistributedCaches(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	defer l1.Close()
+
+	if _, err := HandOff(ctx, l1, l1, nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+	if _, err := HandOff(ctx, NewNoOp[TestUser](), l1, nil); !errors.Is(err, ErrNotMemory) {
+		t.Errorf("Expected ErrNotMemory, got: %v", err)
+	}
+	if _, err := TakeOver(ctx, l1, l1, nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestHandOffWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	l2, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer l2.Close()
+
+	old := NewMemory[TestUser](&MemoryConfig{SkipTTLExtensionOnHit: true, TrackHits: true})
+	defer old.Close()
+	for i := 0; i < 10; i++ {
+		_ = old.Set(ctx, fmt.Sprintf("user:%d", i), TestUser{ID: fmt.Sprint(i)}, time.Hour)
+	}
+	old.Get(ctx, "user:7")
+
+	config := &HandOffConfig{MaxEntries: 3}
+	sent, err := HandOff(ctx, old, l2, config)
+	if err != nil || sent != 3 {
+		t.Fatalf("HandOff: expected 3 entries, got %d, %v", sent, err)
+	}
+
+	successor := NewMemory[TestUser](nil)
+	defer successor.Close()
+	loaded, err := TakeOver(ctx, l2, successor, config)
+	if err != nil || loaded != 3 {
+		t.Fatalf("TakeOver: expected 3 entries, got %d, %v", loaded, err)
+	}
+	if user, found := successor.Get(ctx, "user:7"); !found || user.ID != "7" {
+		t.Errorf("Expected hottest entry user:7 to be handed off, got %v, %v", user, found)
+	}
+}
+
+func TestHandOffPrefixedWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	distributed, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer distributed.Close()
+	l2 := WithPrefix(distributed, "billing:")
+
+	old := NewMemory[TestUser](nil)
+	defer old.Close()
+	_ = old.Set(ctx, "user:1", TestUser{ID: "1"}, time.Hour)
+
+	if sent, err := HandOff(ctx, old, l2, nil); err != nil || sent != 1 {
+		t.Fatalf("HandOff: expected 1 entry, got %d, %v", sent, err)
+	}
+
+	// Test the snapshot stays out of other services' namespaces
+	client, _ := redisClientOf(l2)
+	if keys, err := client.Keys(ctx, "*").Result(); err != nil || len(keys) != 2 ||
+		!strings.HasPrefix(keys[0], "billing:") || !strings.HasPrefix(keys[1], "billing:") {
+		t.Errorf("Expected the snapshot under billing:, got %v (err=%v)", keys, err)
+	}
+	other := NewMemory[TestUser](nil)
+	defer other.Close()
+	if loaded, err := TakeOver(ctx, distributed, other, nil); err != nil || loaded != 0 {
+		t.Errorf("Expected no snapshot outside the prefix, got %d entries, %v", loaded, err)
+	}
+
+	// Test entries written under the prefix are found with their TTL
+	successor := NewMemory[TestUser](nil)
+	defer successor.Close()
+	if loaded, err := TakeOver(ctx, l2, successor, nil); err != nil || loaded != 1 {
+		t.Fatalf("TakeOver: expected 1 entry, got %d, %v", loaded, err)
+	}
+	if ttl, found, _ := TTL(ctx, successor, "user:1"); !found || ttl <= 9*time.Minute {
+		t.Errorf("Expected user:1 with the snapshot TTL, got %v, %v", ttl, found)
+	}
+}
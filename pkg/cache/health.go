@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HealthCheckerOf returns a HealthChecker for cache. Caches without a health
+// check of their own are considered healthy.
+func HealthCheckerOf[T any](cache Cache[T]) HealthChecker {
+	if hc, ok := cache.(HealthChecker); ok {
+		return hc
+	}
+	return healthyChecker{}
+}
+
+// healthyChecker is the health check of caches that can't be unhealthy.
+type healthyChecker struct{}
+
+func (healthyChecker) Ping(context.Context) error {
+	return nil
+}
+
+// CheckAll pings every checker concurrently and returns the outcome of each by
+// name, nil for healthy ones. Checks still running when ctx is done fail with
+// ctx's error, so bound ctx with a timeout.
+func CheckAll(ctx context.Context, checkers map[string]HealthChecker) map[string]error {
+	results := make(map[string]error, len(checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := ping(ctx, checker)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// ping runs a health check, giving up when ctx is done even if the checker
+// ignores it.
+func ping(ctx context.Context, checker HealthChecker) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.Ping(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HealthError aggregates the results of CheckAll into one error naming every
+// failed check, or nil if all are healthy. Checks named in optional are left
+// out, so caches the service can run without don't fail readiness.
+func HealthError(results map[string]error, optional ...string) error {
+	names := make([]string, 0, len(results))
+	for name, err := range results {
+		if err != nil && !slices.Contains(optional, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = fmt.Errorf("%s: %w", name, results[name])
+	}
+	return errors.Join(errs...)
+}
+
+// ReadinessConfig holds configuration for ReadinessHandler.
+type ReadinessConfig struct {
+	// Timeout bounds each readiness check (default: 2s)
+	Timeout time.Duration
+
+	// Optional names the caches whose failure is reported without failing
+	// readiness (optional)
+	Optional []string
+}
+
+// readinessResponse is the JSON body written by ReadinessHandler.
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Caches map[string]string `json:"caches"`
+}
+
+// ReadinessHandler returns an HTTP handler for readiness probes that checks
+// every cache with CheckAll. It responds 200 when all required caches are
+// healthy and 503 otherwise, with a JSON body reporting "ok", "degraded"
+// (only optional caches failed) or "unavailable", and each cache's outcome.
+func ReadinessHandler(checkers map[string]HealthChecker, config *ReadinessConfig) http.Handler {
+	var cfg ReadinessConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), cfg.Timeout)
+		defer cancel()
+
+		results := CheckAll(ctx, checkers)
+		response := readinessResponse{Status: "ok", Caches: make(map[string]string, len(results))}
+		for name, err := range results {
+			response.Caches[name] = "ok"
+			if err != nil {
+				response.Caches[name] = err.Error()
+				response.Status = "degraded"
+			}
+		}
+
+		status := http.StatusOK
+		if HealthError(results, cfg.Optional...) != nil {
+			response.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
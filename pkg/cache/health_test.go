@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkerFunc adapts a function to HealthChecker.
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestCheckAll(t *testing.T) {
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+	hang := make(chan struct{})
+	defer close(hang)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := CheckAll(ctx, map[string]HealthChecker{
+		"memory": HealthCheckerOf(memory),
+		"down":   checkerFunc(func(context.Context) error { return errors.New("connection refused") }),
+		"hung":   checkerFunc(func(context.Context) error { <-hang; return nil }),
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %v", results)
+	}
+	if results["memory"] != nil {
+		t.Errorf("Expected memory to be healthy, got: %v", results["memory"])
+	}
+	if results["down"] == nil {
+		t.Error("Expected down to fail")
+	}
+	if !errors.Is(results["hung"], context.DeadlineExceeded) {
+		t.Errorf("Expected hung to time out, got: %v", results["hung"])
+	}
+}
+
+func TestHealthError(t *testing.T) {
+	results := map[string]error{
+		"users":  nil,
+		"orders": errors.New("timeout"),
+		"search": errors.New("connection refused"),
+	}
+
+	err := HealthError(results)
+	if err == nil || !strings.Contains(err.Error(), "orders: timeout") || !strings.Contains(err.Error(), "search: ") {
+		t.Errorf("Expected both failures, got: %v", err)
+	}
+	if err := HealthError(results, "orders", "search"); err != nil {
+		t.Errorf("Expected optional failures to be ignored, got: %v", err)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	healthy := checkerFunc(func(context.Context) error { return nil })
+	down := checkerFunc(func(context.Context) error { return errors.New("connection refused") })
+
+	tests := []struct {
+		name       string
+		config     *ReadinessConfig
+		wantCode   int
+		wantStatus string
+	}{
+		{"required cache down", nil, http.StatusServiceUnavailable, "unavailable"},
+		{"optional cache down", &ReadinessConfig{Optional: []string{"search"}}, http.StatusOK, "degraded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ReadinessHandler(map[string]HealthChecker{"users": healthy, "search": down}, tt.config)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("Expected status code %d, got %d", tt.wantCode, recorder.Code)
+			}
+			var body readinessResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			if body.Status != tt.wantStatus || body.Caches["users"] != "ok" || body.Caches["search"] == "ok" {
+				t.Errorf("Unexpected body: %+v", body)
+			}
+		})
+	}
+}
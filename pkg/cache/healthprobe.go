@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthProbeConfig holds configuration for a HealthProbe.
+type HealthProbeConfig struct {
+	// Timeout bounds each health check (default: 1s)
+	Timeout time.Duration
+
+	// CacheFor is how long a result is reused, so frequent probes from
+	// several frameworks don't each reach the backend; negative disables
+	// reuse (default: 5s)
+	CacheFor time.Duration
+}
+
+// HealthProbe adapts a HealthChecker to the shapes health check frameworks
+// register, with a timeout and a briefly reused result:
+//
+//	probe := cache.HealthProbeOf(userCache, nil)
+//	health.AddReadinessCheck("users-cache", probe.CheckFunc()) // func() error
+//	checker.Register("users-cache", probe.Check)               // func(context.Context) error
+//	http.Handle("/readyz/users-cache", probe)                  // 200 or 503
+//
+// Failures are reused like successes, so a struggling backend isn't probed
+// harder. Liveness probes should usually leave caches out: restarting an
+// instance doesn't bring its cache backend back.
+type HealthProbe struct {
+	checker HealthChecker
+	config  HealthProbeConfig
+	now     func() time.Time
+
+	// mu is held while checking, so concurrent probes share one check
+	mu        sync.Mutex
+	lastErr   error
+	checkedAt time.Time
+}
+
+// NewHealthProbe creates a HealthProbe running checker.
+func NewHealthProbe(checker HealthChecker, config *HealthProbeConfig) *HealthProbe {
+	var cfg HealthProbeConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.CacheFor == 0 {
+		cfg.CacheFor = 5 * time.Second
+	}
+
+	return &HealthProbe{
+		checker: checker,
+		config:  cfg,
+		now:     time.Now,
+	}
+}
+
+// HealthProbeOf creates a HealthProbe for cache; see HealthCheckerOf.
+func HealthProbeOf[T any](cache Cache[T], config *HealthProbeConfig) *HealthProbe {
+	return NewHealthProbe(HealthCheckerOf(cache), config)
+}
+
+// Check returns the outcome of the last check when it is recent enough, and
+// checks again otherwise.
+func (p *HealthProbe) Check(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.checkedAt.IsZero() && p.now().Sub(p.checkedAt) < p.config.CacheFor {
+		return p.lastErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+	p.lastErr = ping(ctx, p.checker)
+	p.checkedAt = p.now()
+	return p.lastErr
+}
+
+// CheckFunc returns Check for frameworks registering checks without a
+// context.
+func (p *HealthProbe) CheckFunc() func() error {
+	return func() error {
+		return p.Check(context.Background())
+	}
+}
+
+// ServeHTTP responds 200 when the check passes and 503 with its error
+// otherwise.
+func (p *HealthProbe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := p.Check(r.Context()); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthProbeReusesResults(t *testing.T) {
+	var pings atomic.Int32
+	var down atomic.Bool
+	checker := checkerFunc(func(context.Context) error {
+		pings.Add(1)
+		if down.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	now := time.Now()
+	probe := NewHealthProbe(checker, &HealthProbeConfig{CacheFor: time.Second})
+	probe.now = func() time.Time { return now }
+
+	for range 3 {
+		if err := probe.Check(context.Background()); err != nil {
+			t.Fatalf("Expected healthy, got: %v", err)
+		}
+	}
+	if n := pings.Load(); n != 1 {
+		t.Errorf("Expected one ping while the result is fresh, got %d", n)
+	}
+
+	// Test a stale result is checked again, and failures are reused too
+	down.Store(true)
+	now = now.Add(time.Second)
+	if err := probe.CheckFunc()(); err == nil {
+		t.Error("Expected the new check to fail")
+	}
+	if err := probe.Check(context.Background()); err == nil || pings.Load() != 2 {
+		t.Errorf("Expected the failure to be reused, got %v after %d pings", err, pings.Load())
+	}
+}
+
+func TestHealthProbeTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hanging := checkerFunc(func(context.Context) error {
+		// Ignores its context, like a checker stuck on a dead connection
+		<-release
+		return nil
+	})
+	probe := NewHealthProbe(hanging, &HealthProbeConfig{Timeout: 10 * time.Millisecond})
+
+	if err := probe.Check(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestHealthProbeServeHTTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"healthy", nil, http.StatusOK},
+		{"down", errors.New("connection refused"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := NewHealthProbe(checkerFunc(func(context.Context) error { return tt.err }), nil)
+			recorder := httptest.NewRecorder()
+			probe.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("Expected status code %d, got %d", tt.wantCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestHealthProbeOfCacheWithoutHealthCheck(t *testing.T) {
+	probe := HealthProbeOf(NewNoOp[TestUser](), nil)
+	if err := probe.Check(context.Background()); err != nil {
+		t.Errorf("Expected caches without a health check to be healthy, got: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hedgeBudgetBurst caps the hedges that unused budget can save up for a burst.
+const hedgeBudgetBurst = 10
+
+// HedgingConfig holds configuration for hedged reads.
+type HedgingConfig struct {
+	// Delay is how long a read may run before a second, hedged read is
+	// issued, e.g. the backend's p95 latency (default: 10ms)
+	Delay time.Duration
+
+	// Budget is the fraction of reads that may be hedged, bounding the
+	// extra load on the backend (default: 0.05)
+	Budget float64
+}
+
+// hedgedCache issues a second read when the first one is slow.
+type hedgedCache[T any] struct {
+	next   Cache[T]
+	config HedgingConfig
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+type hedgeResult[T any] struct {
+	value T
+	found bool
+	err   error
+}
+
+// NewHedged wraps a cache so that a read still running after Delay is hedged:
+// a second read of the same key is issued and whichever answers first wins,
+// taming tail latency on flaky networks. Every read earns Budget hedges, so
+// at most that fraction of reads is hedged (plus a small saved-up burst).
+// A read that fails is not answered while the other one is still running.
+func NewHedged[T any](cache Cache[T], config *HedgingConfig) Cache[T] {
+	var cfg HedgingConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Delay == 0 {
+		cfg.Delay = 10 * time.Millisecond
+	}
+	if cfg.Budget == 0 {
+		cfg.Budget = 0.05
+	}
+
+	return &hedgedCache[T]{
+		next:   cache,
+		config: cfg,
+	}
+}
+
+func (c *hedgedCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *hedgedCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	c.earn()
+
+	// Cancels the losing read once the caller is answered
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult[T], 2)
+	read := func() {
+		value, found, err := GetWithError(readCtx, c.next, key)
+		results <- hedgeResult[T]{value: value, found: found, err: err}
+	}
+	go read()
+	pending := 1
+
+	timer := time.NewTimer(c.config.Delay)
+	defer timer.Stop()
+	hedge := timer.C
+
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil || pending == 0 {
+				return result.value, result.found, result.err
+			}
+		case <-hedge:
+			hedge = nil
+			if c.spend() {
+				go read()
+				pending++
+			}
+		case <-ctx.Done():
+			var zero T
+			return zero, false, ctx.Err()
+		}
+	}
+}
+
+// GetMulti reads the keys in one batch, which isn't hedged.
+func (c *hedgedCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+// earn adds one read's share of the hedging budget.
+func (c *hedgedCache[T]) earn() {
+	c.mu.Lock()
+	c.tokens = min(c.tokens+c.config.Budget, hedgeBudgetBurst)
+	c.mu.Unlock()
+}
+
+// spend takes one hedge from the budget, reporting whether one was available.
+func (c *hedgedCache[T]) spend() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokens < 1 {
+		return false
+	}
+	c.tokens--
+	return true
+}
+
+func (c *hedgedCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *hedgedCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *hedgedCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *hedgedCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *hedgedCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+// GetDel is never hedged: a second request could only find the key gone.
+func (c *hedgedCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *hedgedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *hedgedCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *hedgedCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *hedgedCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *hedgedCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *hedgedCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *hedgedCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// firstSlowCache delays only the first Get, like a read stuck on a bad connection.
+type firstSlowCache[T any] struct {
+	Cache[T]
+	delay time.Duration
+	reads atomic.Int32
+}
+
+func (c *firstSlowCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	if c.reads.Add(1) == 1 {
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			var zero T
+			return zero, false
+		}
+	}
+	return c.Cache.Get(ctx, key)
+}
+
+func TestHedgedCacheAnswersWithHedge(t *testing.T) {
+	ctx := context.Background()
+	backend := &firstSlowCache[TestUser]{Cache: NewMemory[TestUser](nil), delay: time.Second}
+	cache := NewHedged[TestUser](backend, &HedgingConfig{Delay: 10 * time.Millisecond, Budget: 1})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	start := time.Now()
+	user, found := cache.Get(ctx, "key1")
+	if !found || user.ID != "123" {
+		t.Fatalf("Expected hit, got %v, %v", user, found)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Expected the hedged read to answer, took %v", elapsed)
+	}
+	if reads := backend.reads.Load(); reads != 2 {
+		t.Errorf("Expected 2 reads, got %d", reads)
+	}
+}
+
+func TestHedgedCacheRespectsBudget(t *testing.T) {
+	ctx := context.Background()
+	backend := &firstSlowCache[TestUser]{Cache: NewMemory[TestUser](nil), delay: 50 * time.Millisecond}
+	cache := NewHedged[TestUser](backend, &HedgingConfig{Delay: time.Millisecond, Budget: 0.5})
+	defer cache.Close()
+
+	// Half a hedge earned - not enough to hedge
+	start := time.Now()
+	_, _ = cache.Get(ctx, "key1")
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected the read not to be hedged, took %v", elapsed)
+	}
+	if reads := backend.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 read, got %d", reads)
+	}
+}
+
+func TestHedgedCacheFastReadIsNotHedged(t *testing.T) {
+	ctx := context.Background()
+	backend := &firstSlowCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	cache := NewHedged[TestUser](backend, &HedgingConfig{Delay: time.Second, Budget: 1})
+	defer cache.Close()
+
+	_, _ = cache.Get(ctx, "key1")
+	if reads := backend.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 read, got %d", reads)
+	}
+}
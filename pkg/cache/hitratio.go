@@ -0,0 +1,297 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrCacheCold is returned by the warmth check of a HitRatioTracker until the
+// cache has warmed up.
+var ErrCacheCold = errors.New("cache is cold")
+
+// HitRatioConfig holds configuration for a HitRatioTracker.
+type HitRatioConfig struct {
+	// Windows are the rolling windows the hit ratio is computed over
+	// (default: 1m, 5m, 15m)
+	Windows []time.Duration
+
+	// Objective is the hit ratio SLO, between 0 and 1; each window reports
+	// whether it meets it (default: 0.9)
+	Objective float64
+
+	// WarmThreshold is the hit ratio over the shortest window at which the
+	// cache turns warm (default: Objective)
+	WarmThreshold float64
+
+	// MinReads is the number of reads the shortest window needs before the
+	// cache can turn warm, so a handful of early hits doesn't (default: 100)
+	MinReads int64
+}
+
+// HitRatioWindow is the hit ratio over one rolling window.
+type HitRatioWindow struct {
+	Window time.Duration
+	Reads  int64
+	Hits   int64
+
+	// HitRatio is Hits/Reads, 0 without reads
+	HitRatio float64
+
+	// MeetsObjective reports whether HitRatio reaches the objective; windows
+	// without reads don't
+	MeetsObjective bool
+}
+
+// HitRatioStats describes the warmth and rolling hit ratios of a cache.
+type HitRatioStats struct {
+	// Warm reports whether the cache has warmed up since it was created
+	Warm bool
+
+	// WarmedAt is when the cache turned warm, zero while cold
+	WarmedAt time.Time
+
+	// Objective is the configured hit ratio SLO
+	Objective float64
+
+	// Windows holds one entry per configured window, shortest first
+	Windows []HitRatioWindow
+}
+
+// hitRatioBucket counts the reads of one slice of time.
+type hitRatioBucket struct {
+	index int64
+	reads int64
+	hits  int64
+}
+
+// HitRatioTracker is a cache that tracks its rolling hit ratio against an
+// objective and whether it has warmed up since it was created. Services
+// that ramp traffic up gradually can gate the ramp on warmth:
+//
+//	users := cache.NewHitRatioTracker(userCache, &cache.HitRatioConfig{Objective: 0.8})
+//	checkers["users-cache-warm"] = users.WarmthCheck()
+//
+// A cache starts cold and turns warm the first time the hit ratio over the
+// shortest window reaches WarmThreshold with at least MinReads reads; it
+// then stays warm, since later dips are SLO misses, reported by Stats, rather
+// than a cold start. Reads that fail are not counted.
+type HitRatioTracker[T any] struct {
+	next   Cache[T]
+	config HitRatioConfig
+	now    func() time.Time
+
+	// width is the time covered by each bucket
+	width time.Duration
+
+	mu       sync.Mutex
+	buckets  []hitRatioBucket
+	warmedAt time.Time
+}
+
+// NewHitRatioTracker wraps cache to track its hit ratio.
+func NewHitRatioTracker[T any](cache Cache[T], config *HitRatioConfig) *HitRatioTracker[T] {
+	var cfg HitRatioConfig
+	if config != nil {
+		cfg = *config
+	}
+	if len(cfg.Windows) == 0 {
+		cfg.Windows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+	}
+	cfg.Windows = slices.Clone(cfg.Windows)
+	slices.Sort(cfg.Windows)
+	if cfg.Objective <= 0 || cfg.Objective > 1 {
+		cfg.Objective = 0.9
+	}
+	if cfg.WarmThreshold <= 0 || cfg.WarmThreshold > 1 {
+		cfg.WarmThreshold = cfg.Objective
+	}
+	if cfg.MinReads <= 0 {
+		cfg.MinReads = 100
+	}
+
+	// A dozen buckets per shortest window keeps its ratio smooth as buckets
+	// roll out of it
+	width := max(cfg.Windows[0]/12, time.Millisecond)
+	longest := cfg.Windows[len(cfg.Windows)-1]
+
+	return &HitRatioTracker[T]{
+		next:    cache,
+		config:  cfg,
+		now:     time.Now,
+		width:   width,
+		buckets: make([]hitRatioBucket, int((longest+width-1)/width)+1),
+	}
+}
+
+// record counts one read.
+func (c *HitRatioTracker[T]) record(hit bool) {
+	now := c.now()
+	index := now.UnixNano() / int64(c.width)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucket := &c.buckets[index%int64(len(c.buckets))]
+	if bucket.index != index {
+		*bucket = hitRatioBucket{index: index}
+	}
+	bucket.reads++
+	if hit {
+		bucket.hits++
+	}
+
+	if c.warmedAt.IsZero() {
+		window := c.windowLocked(c.config.Windows[0], index)
+		if window.Reads >= c.config.MinReads && window.HitRatio >= c.config.WarmThreshold {
+			c.warmedAt = now
+		}
+	}
+}
+
+// windowLocked sums the buckets within window of the bucket at index.
+func (c *HitRatioTracker[T]) windowLocked(window time.Duration, index int64) HitRatioWindow {
+	span := int64((window + c.width - 1) / c.width)
+	result := HitRatioWindow{Window: window}
+	for _, bucket := range c.buckets {
+		if bucket.index > index-span && bucket.index <= index {
+			result.Reads += bucket.reads
+			result.Hits += bucket.hits
+		}
+	}
+	if result.Reads > 0 {
+		result.HitRatio = float64(result.Hits) / float64(result.Reads)
+		result.MeetsObjective = result.HitRatio >= c.config.Objective
+	}
+	return result
+}
+
+// Stats returns the warmth and the hit ratio over every window.
+func (c *HitRatioTracker[T]) Stats() HitRatioStats {
+	index := c.now().UnixNano() / int64(c.width)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := HitRatioStats{
+		Warm:      !c.warmedAt.IsZero(),
+		WarmedAt:  c.warmedAt,
+		Objective: c.config.Objective,
+		Windows:   make([]HitRatioWindow, len(c.config.Windows)),
+	}
+	for i, window := range c.config.Windows {
+		stats.Windows[i] = c.windowLocked(window, index)
+	}
+	return stats
+}
+
+// Warm reports whether the cache has warmed up.
+func (c *HitRatioTracker[T]) Warm() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.warmedAt.IsZero()
+}
+
+// WarmthCheck returns a HealthChecker failing with ErrCacheCold until the
+// cache has warmed up, for readiness checks gating traffic on warmth. Ping
+// of the tracker itself still only checks the backend.
+func (c *HitRatioTracker[T]) WarmthCheck() HealthChecker {
+	return warmthChecker(func(context.Context) error {
+		if !c.Warm() {
+			return ErrCacheCold
+		}
+		return nil
+	})
+}
+
+// warmthChecker adapts a function to HealthChecker.
+type warmthChecker func(ctx context.Context) error
+
+func (f warmthChecker) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func (c *HitRatioTracker[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found := c.next.Get(ctx, key)
+	c.record(found)
+	return value, found
+}
+
+func (c *HitRatioTracker[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetWithError(ctx, c.next, key)
+	if err == nil {
+		c.record(found)
+	}
+	return value, found, err
+}
+
+func (c *HitRatioTracker[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	loaded := false
+	value, err := GetOrSet(ctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		loaded = true
+		return load(ctx)
+	})
+	// A failed load is still a miss; only a failed read isn't counted
+	if err == nil || loaded {
+		c.record(!loaded)
+	}
+	return value, err
+}
+
+func (c *HitRatioTracker[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *HitRatioTracker[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *HitRatioTracker[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *HitRatioTracker[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *HitRatioTracker[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *HitRatioTracker[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetDel(ctx, c.next, key)
+	if err == nil {
+		c.record(found)
+	}
+	return value, found, err
+}
+
+func (c *HitRatioTracker[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *HitRatioTracker[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *HitRatioTracker[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *HitRatioTracker[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *HitRatioTracker[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *HitRatioTracker[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *HitRatioTracker[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
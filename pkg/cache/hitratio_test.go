@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestHitRatioTracker returns a tracker over a memory cache with a clock
+// the test advances.
+func newTestHitRatioTracker(t *testing.T, config *HitRatioConfig) (*HitRatioTracker[TestUser], *time.Time) {
+	t.Helper()
+	inner := NewMemory[TestUser](nil)
+	tracker := NewHitRatioTracker(inner, config)
+	t.Cleanup(func() { _ = tracker.Close() })
+
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func TestHitRatioTrackerWindows(t *testing.T) {
+	ctx := context.Background()
+	tracker, now := newTestHitRatioTracker(t, &HitRatioConfig{
+		Windows:   []time.Duration{5 * time.Minute, time.Minute},
+		Objective: 0.5,
+	})
+
+	_ = tracker.Set(ctx, "a", TestUser{ID: "a"}, 0)
+	for range 3 {
+		tracker.Get(ctx, "missing")
+	}
+
+	// Four minutes later only the 5m window still sees the misses
+	*now = now.Add(4 * time.Minute)
+	tracker.Get(ctx, "a")
+
+	stats := tracker.Stats()
+	if len(stats.Windows) != 2 || stats.Windows[0].Window != time.Minute {
+		t.Fatalf("Expected windows shortest first, got %+v", stats.Windows)
+	}
+	if w := stats.Windows[0]; w.Reads != 1 || w.Hits != 1 || w.HitRatio != 1 || !w.MeetsObjective {
+		t.Errorf("Expected one hit in the 1m window, got %+v", w)
+	}
+	if w := stats.Windows[1]; w.Reads != 4 || w.Hits != 1 || w.HitRatio != 0.25 || w.MeetsObjective {
+		t.Errorf("Expected 1 hit out of 4 reads in the 5m window, got %+v", w)
+	}
+
+	// Once the window has passed, nothing is left
+	*now = now.Add(10 * time.Minute)
+	if w := tracker.Stats().Windows[1]; w.Reads != 0 || w.MeetsObjective {
+		t.Errorf("Expected an empty 5m window, got %+v", w)
+	}
+}
+
+func TestHitRatioTrackerWarmsUp(t *testing.T) {
+	ctx := context.Background()
+	tracker, now := newTestHitRatioTracker(t, &HitRatioConfig{
+		Windows:       []time.Duration{time.Minute},
+		WarmThreshold: 0.8,
+		MinReads:      10,
+	})
+	check := tracker.WarmthCheck()
+
+	if err := check.Ping(ctx); !errors.Is(err, ErrCacheCold) {
+		t.Errorf("Expected a new cache to be cold, got %v", err)
+	}
+
+	// Hits alone don't warm the cache before MinReads
+	_ = tracker.Set(ctx, "a", TestUser{ID: "a"}, 0)
+	for range 9 {
+		tracker.Get(ctx, "a")
+	}
+	if tracker.Warm() {
+		t.Fatal("Expected the cache to stay cold before MinReads")
+	}
+	tracker.Get(ctx, "a")
+	if !tracker.Warm() {
+		t.Fatal("Expected the cache to turn warm")
+	}
+	if err := check.Ping(ctx); err != nil {
+		t.Errorf("Expected the warmth check to pass, got %v", err)
+	}
+	warmedAt := tracker.Stats().WarmedAt
+	if !warmedAt.Equal(*now) {
+		t.Errorf("Expected WarmedAt %v, got %v", *now, warmedAt)
+	}
+
+	// A later dip is an SLO miss, not a cold start
+	*now = now.Add(time.Hour)
+	for range 20 {
+		tracker.Get(ctx, "missing")
+	}
+	if stats := tracker.Stats(); !stats.Warm || stats.Windows[0].MeetsObjective {
+		t.Errorf("Expected a warm cache missing its objective, got %+v", stats)
+	}
+}
+
+func TestHitRatioTrackerStaysColdBelowThreshold(t *testing.T) {
+	ctx := context.Background()
+	tracker, _ := newTestHitRatioTracker(t, &HitRatioConfig{MinReads: 10})
+
+	_ = tracker.Set(ctx, "a", TestUser{ID: "a"}, 0)
+	for i := range 100 {
+		if i%2 == 0 {
+			tracker.Get(ctx, "a")
+		} else {
+			tracker.Get(ctx, "missing")
+		}
+	}
+	if tracker.Warm() {
+		t.Error("Expected a 50% hit ratio to leave the cache cold")
+	}
+}
+
+func TestHitRatioTrackerGetOrSet(t *testing.T) {
+	ctx := context.Background()
+	tracker, _ := newTestHitRatioTracker(t, nil)
+
+	load := func(context.Context) (TestUser, error) { return TestUser{ID: "a"}, nil }
+	_, _ = tracker.GetOrSet(ctx, "a", time.Minute, load)
+	_, _ = tracker.GetOrSet(ctx, "a", time.Minute, load)
+
+	if w := tracker.Stats().Windows[0]; w.Reads != 2 || w.Hits != 1 {
+		t.Errorf("Expected a miss then a hit, got %+v", w)
+	}
+}
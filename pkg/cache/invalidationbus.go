@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationBusConfig holds configuration for an InvalidationBus.
+type InvalidationBusConfig struct {
+	// Channel is the pub/sub channel invalidations are published on; caches
+	// sharing it evict each other's keys (default: "cache:l1-invalidations")
+	Channel string
+
+	// Client publishes and subscribes (default for tiered caches: the L2
+	// client, required otherwise)
+	Client redis.UniversalClient
+
+	// PublishTimeout bounds publishing an invalidation (default: 1s)
+	PublishTimeout time.Duration
+}
+
+// invalidationMessage is the payload published on the bus.
+type invalidationMessage struct {
+	// Origin identifies the publishing bus, which ignores its own messages
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+	// All asks to evict every key, e.g. after a cache was cleared
+	All bool `json:"all,omitempty"`
+}
+
+// InvalidationBus broadcasts the keys an instance changed to every other
+// instance over Redis pub/sub, so they can evict their local copies. Delivery
+// is at-most-once: messages published while a subscriber is reconnecting are
+// lost, so local copies still need a TTL bounding their staleness.
+type InvalidationBus struct {
+	client  redis.UniversalClient
+	config  InvalidationBusConfig
+	origin  string
+	pubsub  *redis.PubSub
+	onEvict func(keys []string)
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewInvalidationBus subscribes to config.Channel and calls onEvict with the
+// keys published by other buses on it, or with nil when one published
+// PublishAll. Call Close to unsubscribe.
+func NewInvalidationBus(config *InvalidationBusConfig, onEvict func(keys []string)) (*InvalidationBus, error) {
+	if config == nil || config.Client == nil {
+		return nil, errors.New("invalidation bus requires a Client")
+	}
+
+	cfg := *config
+	if cfg.Channel == "" {
+		cfg.Channel = "cache:l1-invalidations"
+	}
+	if cfg.PublishTimeout <= 0 {
+		cfg.PublishTimeout = time.Second
+	}
+
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+
+	b := &InvalidationBus{
+		client:  cfg.Client,
+		config:  cfg,
+		origin:  hex.EncodeToString(origin),
+		pubsub:  cfg.Client.Subscribe(context.Background(), cfg.Channel),
+		onEvict: onEvict,
+		done:    make(chan struct{}),
+	}
+	go b.receive()
+	return b, nil
+}
+
+// Publish tells the other buses on the channel to evict keys. Failures are
+// logged rather than returned: the change itself succeeded, and other
+// instances' copies expire on their own.
+func (b *InvalidationBus) Publish(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	b.publish(ctx, invalidationMessage{Origin: b.origin, Keys: keys})
+}
+
+// PublishAll tells the other buses on the channel to evict every key. Failures
+// are logged like those of Publish.
+func (b *InvalidationBus) PublishAll(ctx context.Context) {
+	b.publish(ctx, invalidationMessage{Origin: b.origin, All: true})
+}
+
+func (b *InvalidationBus) publish(ctx context.Context, msg invalidationMessage) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b.config.PublishTimeout)
+	defer cancel()
+	if err := b.client.Publish(ctx, b.config.Channel, payload).Err(); err != nil {
+		slog.Default().WarnContext(ctx, "cache: publishing invalidation failed",
+			"channel", b.config.Channel, "keys", len(msg.Keys), "all", msg.All, "error", err)
+	}
+}
+
+// Close unsubscribes from the channel. The client is not closed.
+func (b *InvalidationBus) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		err = b.pubsub.Close()
+		<-b.done
+	})
+	return err
+}
+
+func (b *InvalidationBus) receive() {
+	defer close(b.done)
+	// The channel is closed by pubsub.Close; go-redis resubscribes on
+	// reconnects meanwhile
+	for msg := range b.pubsub.Channel() {
+		b.handle(msg.Payload)
+	}
+}
+
+// handle evicts the keys of a message published by another bus.
+func (b *InvalidationBus) handle(payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil || msg.Origin == b.origin {
+		return
+	}
+	switch {
+	case msg.All:
+		b.onEvict(nil)
+	case len(msg.Keys) > 0:
+		b.onEvict(msg.Keys)
+	}
+}
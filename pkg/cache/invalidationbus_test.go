@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewInvalidationBusRequiresClient(t *testing.T) {
+	if _, err := NewInvalidationBus(&InvalidationBusConfig{}, func([]string) {}); err == nil {
+		t.Error("Expected error without a Client")
+	}
+}
+
+func TestInvalidationBusIgnoresOwnMessages(t *testing.T) {
+	var evicted []string
+	bus := &InvalidationBus{origin: "self", onEvict: func(keys []string) { evicted = append(evicted, keys...) }}
+
+	own, _ := json.Marshal(invalidationMessage{Origin: "self", Keys: []string{"a"}})
+	other, _ := json.Marshal(invalidationMessage{Origin: "other", Keys: []string{"b", "c"}})
+	bus.handle(string(own))
+	bus.handle(string(other))
+	bus.handle("not json")
+
+	if len(evicted) != 2 || evicted[0] != "b" || evicted[1] != "c" {
+		t.Errorf("Expected only other instances' keys evicted, got %v", evicted)
+	}
+}
+
+func TestInvalidationBusEvictsAll(t *testing.T) {
+	var calls [][]string
+	bus := &InvalidationBus{origin: "self", onEvict: func(keys []string) { calls = append(calls, keys) }}
+
+	all, _ := json.Marshal(invalidationMessage{Origin: "other", All: true})
+	bus.handle(string(all))
+
+	if len(calls) != 1 || calls[0] != nil {
+		t.Errorf("Expected one eviction of every key, got %v", calls)
+	}
+}
+
+func TestTieredInvalidationWithoutRedis(t *testing.T) {
+	ctx := context.Background()
+
+	// Test the bus is disabled, not fatal, when L2 has no Redis client
+	cache := NewTiered(NewMemory[TestUser](nil), NewMemory[TestUser](nil), &TieredConfig{
+		Invalidation: &InvalidationBusConfig{},
+	})
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "k", TestUser{ID: "1"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+}
+
+func TestTieredInvalidationWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	newInstance := func() Cache[TestUser] {
+		c, err := New[TestUser](&Config{
+			Type:        TypeTiered,
+			Distributed: &DistributedConfig{Addr: addr},
+			Tiered: &TieredConfig{
+				L1TTL:        time.Hour,
+				Invalidation: &InvalidationBusConfig{Channel: "test:invalidations"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create cache: %v", err)
+		}
+		t.Cleanup(func() { _ = c.Close() })
+		return c
+	}
+	a, b := newInstance(), newInstance()
+
+	_ = a.Set(ctx, "user:1", TestUser{ID: "1", Name: "old"}, time.Hour)
+	if user, found := b.Get(ctx, "user:1"); !found || user.Name != "old" {
+		t.Fatalf("Expected b to read and promote the value, got %+v (found=%v)", user, found)
+	}
+
+	// Test an overwrite by a evicts b's L1 copy despite the long L1TTL
+	_ = a.Set(ctx, "user:1", TestUser{ID: "1", Name: "new"}, time.Hour)
+	waitFor(t, func() bool {
+		user, _ := b.Get(ctx, "user:1")
+		return user.Name == "new"
+	})
+
+	_ = a.Delete(ctx, "user:1")
+	waitFor(t, func() bool {
+		_, found := b.Get(ctx, "user:1")
+		return !found
+	})
+
+	if stats, _ := TieredStatsOf(b); stats.Invalidations < 2 {
+		t.Errorf("Expected b to count invalidations, got %+v", stats)
+	}
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// DeferredInvalidator collects cache invalidations made during a business
+// transaction and applies them only after the transaction commits, keeping
+// the cache consistent with the database:
+//
+//	inv := cache.NewDeferredInvalidator(userCache)
+//	tx, _ := db.BeginTx(ctx, nil)
+//	// ... update rows, calling inv.Delete(ctx, key) for affected entries ...
+//	if err := tx.Commit(); err != nil {
+//		inv.Rollback()
+//		return err
+//	}
+//	return inv.Commit(ctx)
+//
+// A DeferredInvalidator is safe for concurrent use but is meant to be scoped to
+// a single transaction.
+type DeferredInvalidator[T any] struct {
+	cache Cache[T]
+
+	mu   sync.Mutex
+	keys []string
+	seen map[string]struct{}
+}
+
+// NewDeferredInvalidator creates an invalidator that flushes into cache.
+func NewDeferredInvalidator[T any](cache Cache[T]) *DeferredInvalidator[T] {
+	return &DeferredInvalidator[T]{
+		cache: cache,
+		seen:  make(map[string]struct{}),
+	}
+}
+
+// Delete records key for invalidation on Commit. The cache is not touched.
+func (d *DeferredInvalidator[T]) Delete(_ context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seen[key]; !ok {
+		d.seen[key] = struct{}{}
+		d.keys = append(d.keys, key)
+	}
+	return nil
+}
+
+// Pending returns the keys that will be invalidated on Commit, in recording order.
+func (d *DeferredInvalidator[T]) Pending() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string(nil), d.keys...)
+}
+
+// Commit removes all recorded keys from the cache in a single batch (one
+// MULTI/EXEC pipeline on distributed caches) and resets the invalidator.
+func (d *DeferredInvalidator[T]) Commit(ctx context.Context) error {
+	keys := d.take()
+	if len(keys) == 0 {
+		return nil
+	}
+	return deleteMulti(ctx, d.cache, keys)
+}
+
+// Rollback discards all recorded keys without touching the cache.
+func (d *DeferredInvalidator[T]) Rollback() {
+	d.take()
+}
+
+func (d *DeferredInvalidator[T]) take() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := d.keys
+	d.keys = nil
+	d.seen = make(map[string]struct{})
+	return keys
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeferredInvalidator(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	ctx := context.Background()
+	user := TestUser{ID: "123", Name: "John"}
+	for _, key := range []string{"key1", "key2"} {
+		if err := cache.Set(ctx, key, user, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	inv := NewDeferredInvalidator(cache)
+
+	// Test Delete is deferred
+	_ = inv.Delete(ctx, "key1")
+	_ = inv.Delete(ctx, "key1")
+	_ = inv.Delete(ctx, "missing")
+	if _, found := cache.Get(ctx, "key1"); !found {
+		t.Error("Expected key1 to stay cached before Commit")
+	}
+	if pending := inv.Pending(); len(pending) != 2 {
+		t.Errorf("Expected 2 pending keys, got %v", pending)
+	}
+
+	// Test Commit flushes all recorded keys, ignoring missing ones
+	if err := inv.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be invalidated after Commit")
+	}
+	if pending := inv.Pending(); len(pending) != 0 {
+		t.Errorf("Expected no pending keys after Commit, got %v", pending)
+	}
+
+	// Test Rollback discards recorded keys
+	_ = inv.Delete(ctx, "key2")
+	inv.Rollback()
+	if err := inv.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key2"); !found {
+		t.Error("Expected key2 to stay cached after Rollback")
+	}
+}
+
+func TestDeferredInvalidatorWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"tx1", "tx2"} {
+		if err := cache.Set(ctx, key, TestUser{ID: key}, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	inv := NewDeferredInvalidator(cache)
+	_ = inv.Delete(ctx, "tx1")
+	_ = inv.Delete(ctx, "tx2")
+	if err := inv.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	for _, key := range []string{"tx1", "tx2"} {
+		if _, found := cache.Get(ctx, key); found {
+			t.Errorf("Expected %s to be invalidated", key)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WriteJournalConfig holds configuration for a WriteJournal.
+type WriteJournalConfig struct {
+	// Stream is the Redis Stream key holding pending writes (required)
+	Stream string
+
+	// Group is the consumer group shared by all instances (default: "cache-writers")
+	Group string
+
+	// Consumer identifies this instance within the group (default: hostname-pid)
+	Consumer string
+
+	// MaxLen caps the entries not acknowledged yet; Append fails with
+	// ErrQueueFull beyond it instead of trimming entries that were never
+	// applied (default: 0, unbounded)
+	MaxLen int64
+
+	// ClaimIdle is how long an entry may stay unacknowledged by its consumer
+	// before another instance claims and replays it (default: 1m)
+	ClaimIdle time.Duration
+}
+
+// JournalEntry is a single pending write recorded in a WriteJournal.
+type JournalEntry struct {
+	// ID is the stream entry ID, assigned by Append.
+	ID string
+
+	// Operation is OperationSet or OperationDelete.
+	Operation Operation
+
+	// Key is the cache key being written.
+	Key string
+
+	// Value is the serialized value (Set only).
+	Value []byte
+
+	// TTL is the TTL the value should be stored with (Set only).
+	TTL time.Duration
+}
+
+// WriteJournal is a durable log of pending writes backed by a Redis Stream.
+//
+// Write-behind caches (WriteBehindConfig.Journal) Append an entry before
+// acknowledging a write to their caller and Ack it once the write reached
+// their store. Appended entries are delivered to the appending consumer at
+// once, so other instances don't replay writes still buffered by a live one;
+// entries left unacknowledged by an instance that crashed are claimed by
+// another instance after ClaimIdle and returned by Read, so buffered writes
+// survive a pod crash. Delivery is at-least-once; applying an entry must be
+// idempotent.
+type WriteJournal struct {
+	client redis.UniversalClient
+	config WriteJournalConfig
+}
+
+// NewWriteJournal creates a journal on client, creating the stream and the
+// consumer group if they don't exist yet.
+func NewWriteJournal(ctx context.Context, client redis.UniversalClient, config *WriteJournalConfig) (*WriteJournal, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+	if config == nil || config.Stream == "" {
+		return nil, errors.New("journal stream cannot be empty")
+	}
+
+	cfg := *config
+	ensureWriteJournalDefaults(&cfg)
+
+	err := client.XGroupCreateMkStream(ctx, cfg.Stream, cfg.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, err
+	}
+
+	return &WriteJournal{
+		client: client,
+		config: cfg,
+	}, nil
+}
+
+func ensureWriteJournalDefaults(config *WriteJournalConfig) {
+	if config.Group == "" {
+		config.Group = "cache-writers"
+	}
+	if config.Consumer == "" {
+		hostname, _ := os.Hostname()
+		config.Consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if config.ClaimIdle == 0 {
+		config.ClaimIdle = time.Minute
+	}
+}
+
+// appendJournalScript adds an entry to the stream unless MaxLen entries are
+// pending, and delivers it to the appending consumer. XREADGROUP delivers the
+// oldest undelivered entries first, so entries other writers added with XADD
+// before it are delivered along with it; those are made idle for ClaimIdle at
+// once, so the next Read of any instance claims them.
+//
+// KEYS[1] stream
+// ARGV[1] max length (0: unbounded), ARGV[2] group, ARGV[3] consumer,
+// ARGV[4] ClaimIdle in ms, ARGV[5:] entry fields and values
+var appendJournalScript = redis.NewScript(`
+local maxlen = tonumber(ARGV[1])
+if maxlen > 0 and redis.call('XLEN', KEYS[1]) >= maxlen then
+	return false
+end
+local id = redis.call('XADD', KEYS[1], '*', unpack(ARGV, 5))
+while true do
+	local read = redis.call('XREADGROUP', 'GROUP', ARGV[2], ARGV[3], 'COUNT', 100, 'STREAMS', KEYS[1], '>')
+	if not read then
+		return id
+	end
+	local others, delivered = {}, false
+	for _, msg in ipairs(read[1][2]) do
+		if msg[1] == id then
+			delivered = true
+		else
+			table.insert(others, msg[1])
+		end
+	end
+	if #others > 0 then
+		redis.call('XCLAIM', KEYS[1], ARGV[2], ARGV[3], 0, unpack(others), 'IDLE', ARGV[4], 'JUSTID')
+	end
+	if delivered then
+		return id
+	end
+end
+`)
+
+// Append records a pending write, owned by this consumer until it is
+// acknowledged, and returns its stream entry ID. Returns ErrQueueFull when
+// MaxLen entries are pending.
+func (j *WriteJournal) Append(ctx context.Context, entry JournalEntry) (string, error) {
+	args := []any{j.config.MaxLen, j.config.Group, j.config.Consumer, j.config.ClaimIdle.Milliseconds()}
+	for field, value := range encodeJournalEntry(entry) {
+		args = append(args, field, value)
+	}
+
+	id, err := appendJournalScript.Run(ctx, j.client, []string{j.config.Stream}, args...).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrQueueFull
+	}
+	return id, err
+}
+
+// Read returns up to count entries for this instance to apply. Entries left
+// unacknowledged for longer than ClaimIdle, e.g. by a crashed instance, are
+// returned first, followed by entries not yet delivered to any consumer, e.g. appended
+// by other writers with XADD. When nothing is available, Read waits up to
+// block for new entries (block <= 0: don't wait).
+func (j *WriteJournal) Read(ctx context.Context, count int64, block time.Duration) ([]JournalEntry, error) {
+	claimed, _, err := j.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   j.config.Stream,
+		Group:    j.config.Group,
+		Consumer: j.config.Consumer,
+		MinIdle:  j.config.ClaimIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]JournalEntry, 0, len(claimed))
+	for _, msg := range claimed {
+		entries = append(entries, decodeJournalEntry(msg))
+	}
+	if int64(len(entries)) >= count {
+		return entries, nil
+	}
+
+	if block <= 0 {
+		block = -1
+	}
+	streams, err := j.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    j.config.Group,
+		Consumer: j.config.Consumer,
+		Streams:  []string{j.config.Stream, ">"},
+		Count:    count - int64(len(entries)),
+		Block:    block,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return entries, err
+	}
+
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			entries = append(entries, decodeJournalEntry(msg))
+		}
+	}
+	return entries, nil
+}
+
+// Ack marks entries as applied and removes them from the stream.
+func (j *WriteJournal) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := j.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, j.config.Stream, j.config.Group, ids...)
+		pipe.XDel(ctx, j.config.Stream, ids...)
+		return nil
+	})
+	return err
+}
+
+// Pending returns the number of entries not yet acknowledged, including
+// entries not yet read by any consumer.
+func (j *WriteJournal) Pending(ctx context.Context) (int64, error) {
+	return j.client.XLen(ctx, j.config.Stream).Result()
+}
+
+func encodeJournalEntry(entry JournalEntry) map[string]any {
+	return map[string]any{
+		"op":    string(entry.Operation),
+		"key":   entry.Key,
+		"value": entry.Value,
+		"ttl":   entry.TTL.Milliseconds(),
+	}
+}
+
+func decodeJournalEntry(msg redis.XMessage) JournalEntry {
+	entry := JournalEntry{ID: msg.ID}
+	if op, ok := msg.Values["op"].(string); ok {
+		entry.Operation = Operation(op)
+	}
+	if key, ok := msg.Values["key"].(string); ok {
+		entry.Key = key
+	}
+	if value, ok := msg.Values["value"].(string); ok {
+		entry.Value = []byte(value)
+	}
+	if ttl, ok := msg.Values["ttl"].(string); ok {
+		if ms, err := strconv.ParseInt(ttl, 10, 64); err == nil {
+			entry.TTL = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return entry
+}
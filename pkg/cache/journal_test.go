@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestJournalEntryEncoding(t *testing.T) {
+	entry := JournalEntry{
+		Operation: OperationSet,
+		Key:       "key1",
+		Value:     []byte(`{"id":"123"}`),
+		TTL:       1500 * time.Millisecond,
+	}
+
+	// Redis returns all stream field values as strings
+	values := make(map[string]any)
+	for field, value := range encodeJournalEntry(entry) {
+		switch v := value.(type) {
+		case []byte:
+			values[field] = string(v)
+		case int64:
+			values[field] = strconv.FormatInt(v, 10)
+		default:
+			values[field] = v
+		}
+	}
+
+	decoded := decodeJournalEntry(redis.XMessage{ID: "1-0", Values: values})
+	if decoded.ID != "1-0" || decoded.Operation != entry.Operation || decoded.Key != entry.Key {
+		t.Errorf("Expected %+v, got %+v", entry, decoded)
+	}
+	if !bytes.Equal(decoded.Value, entry.Value) {
+		t.Errorf("Expected value %s, got %s", entry.Value, decoded.Value)
+	}
+	if decoded.TTL != entry.TTL {
+		t.Errorf("Expected TTL %v, got %v", entry.TTL, decoded.TTL)
+	}
+}
+
+func TestWriteJournalWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	config := &WriteJournalConfig{Stream: "journal", Consumer: "crashed", ClaimIdle: 50 * time.Millisecond}
+	crashed, err := NewWriteJournal(ctx, client, config)
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+
+	for _, key := range []string{"key1", "key2"} {
+		if _, err := crashed.Append(ctx, JournalEntry{Operation: OperationSet, Key: key, Value: []byte("v")}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	// Appended entries belong to the first instance, which "crashes" without acking
+	entries, err := crashed.Read(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Expected appended entries not to be delivered again, got %d", len(entries))
+	}
+
+	// A successor claims the entries once they have been idle long enough
+	successor, err := NewWriteJournal(ctx, client, &WriteJournalConfig{
+		Stream:    "journal",
+		Consumer:  "successor",
+		ClaimIdle: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	entries, err = successor.Read(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 replayed entries, got %d", len(entries))
+	}
+	if entries[0].Key != "key1" || entries[1].Key != "key2" {
+		t.Errorf("Expected entries in append order, got %+v", entries)
+	}
+
+	if err := successor.Ack(ctx, entries[0].ID, entries[1].ID); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	pending, err := successor.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if pending != 0 {
+		t.Errorf("Expected no pending entries, got %d", pending)
+	}
+}
+
+func TestWriteJournalAppendClaimsOnlyItsEntryWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	appender, err := NewWriteJournal(ctx, client, &WriteJournalConfig{Stream: "journal", Consumer: "appender"})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+	reader, err := NewWriteJournal(ctx, client, &WriteJournalConfig{Stream: "journal", Consumer: "reader"})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+
+	// An entry added by another writer and not read by anyone yet
+	external := encodeJournalEntry(JournalEntry{Operation: OperationDelete, Key: "external"})
+	if err := client.XAdd(ctx, &redis.XAddArgs{Stream: "journal", Values: external}).Err(); err != nil {
+		t.Fatalf("XAdd failed: %v", err)
+	}
+	if _, err := appender.Append(ctx, JournalEntry{Operation: OperationDelete, Key: "buffered"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	// Test other instances get the external entry but not the appended one
+	entries, err := reader.Read(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Key != "external" {
+		t.Errorf("Expected only the external entry, got %+v", entries)
+	}
+}
+
+func TestWriteJournalMaxLenWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	journal, err := NewWriteJournal(ctx, client, &WriteJournalConfig{Stream: "journal", MaxLen: 2})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+
+	var ids []string
+	for _, key := range []string{"key1", "key2"} {
+		id, err := journal.Append(ctx, JournalEntry{Operation: OperationDelete, Key: key})
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Unacknowledged entries are never trimmed: the append is rejected instead
+	if _, err := journal.Append(ctx, JournalEntry{Operation: OperationDelete, Key: "key3"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+	if pending, _ := journal.Pending(ctx); pending != 2 {
+		t.Errorf("Expected the 2 unacknowledged entries to be kept, got %d", pending)
+	}
+
+	if err := journal.Ack(ctx, ids[0]); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	if _, err := journal.Append(ctx, JournalEntry{Operation: OperationDelete, Key: "key3"}); err != nil {
+		t.Errorf("Expected room after Ack, got %v", err)
+	}
+}
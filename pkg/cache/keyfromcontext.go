@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// KeyFromContextFunc derives the effective cache key from the request context,
+// e.g. to append an experiment bucket or locale taken from baggage.
+type KeyFromContextFunc func(ctx context.Context, key string) string
+
+// keyFromContextCache rewrites every key with a KeyFromContextFunc.
+type keyFromContextCache[T any] struct {
+	next  Cache[T]
+	keyFn KeyFromContextFunc
+}
+
+// WithKeyFromContext wraps a cache so that every key is passed through keyFn
+// together with the operation's context before reaching the wrapped cache.
+// This keeps variant-dependent data from being shared across variants.
+func WithKeyFromContext[T any](cache Cache[T], keyFn KeyFromContextFunc) Cache[T] {
+	return &keyFromContextCache[T]{
+		next:  cache,
+		keyFn: keyFn,
+	}
+}
+
+func (c *keyFromContextCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	mapped := make([]string, len(keys))
+	for i, key := range keys {
+		mapped[i] = c.keyFn(ctx, key)
+	}
+	results := GetMulti(ctx, c.next, mapped...)
+	for i := range results {
+		results[i].Key = keys[i]
+	}
+	return results
+}
+
+func (c *keyFromContextCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, c.keyFn(ctx, key), ttl)
+}
+
+func (c *keyFromContextCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, c.keyFn(ctx, key), value, ttl)
+}
+
+func (c *keyFromContextCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, c.keyFn(ctx, key), old, new, ttl)
+}
+
+func (c *keyFromContextCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, c.keyFn(ctx, key), value, ttl)
+}
+
+func (c *keyFromContextCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, c.keyFn(ctx, key), ttl, load)
+}
+
+func (c *keyFromContextCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, c.keyFn(ctx, key))
+}
+
+func (c *keyFromContextCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	mapped := make([]string, len(keys))
+	for i, key := range keys {
+		mapped[i] = c.keyFn(ctx, key)
+	}
+	return deleteMulti(ctx, c.next, mapped)
+}
+
+func (c *keyFromContextCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *keyFromContextCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *keyFromContextCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *keyFromContextCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type localeKey struct{}
+
+func localeFromContext(ctx context.Context, key string) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return key + ":" + locale
+	}
+	return key
+}
+
+func TestKeyFromContext(t *testing.T) {
+	cache, err := New[TestUser](&Config{Type: TypeMemory, KeyFromContext: localeFromContext})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cache.Close()
+
+	sv := context.WithValue(context.Background(), localeKey{}, "sv")
+	en := context.WithValue(context.Background(), localeKey{}, "en")
+
+	if err := cache.Set(sv, "greeting", TestUser{Name: "Hej"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Set(en, "greeting", TestUser{Name: "Hello"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Test variants are kept apart
+	retrieved, found := cache.Get(sv, "greeting")
+	if !found || retrieved.Name != "Hej" {
+		t.Errorf("Expected Hej for sv, got %+v (found=%v)", retrieved, found)
+	}
+	retrieved, found = cache.Get(en, "greeting")
+	if !found || retrieved.Name != "Hello" {
+		t.Errorf("Expected Hello for en, got %+v (found=%v)", retrieved, found)
+	}
+	if _, found := cache.Get(context.Background(), "greeting"); found {
+		t.Error("Expected no value without a locale")
+	}
+
+	// Test Delete only removes the context's variant
+	if err := cache.Delete(sv, "greeting"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, found := cache.Get(sv, "greeting"); found {
+		t.Error("Expected sv variant to be deleted")
+	}
+	if _, found := cache.Get(en, "greeting"); !found {
+		t.Error("Expected en variant to remain")
+	}
+}
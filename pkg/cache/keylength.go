@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrKeyTooLong is returned when a key exceeds the configured maximum length
+// under KeyLengthReject.
+var ErrKeyTooLong = errors.New("cache key too long")
+
+// KeyLengthPolicy decides what happens to keys longer than the maximum.
+type KeyLengthPolicy string
+
+const (
+	// KeyLengthReject fails writes and deletes with ErrKeyTooLong and
+	// reports reads as misses.
+	KeyLengthReject KeyLengthPolicy = "reject"
+	// KeyLengthHash replaces long keys with a truncated prefix followed by the
+	// SHA-256 of the full key, keeping keys scannable by prefix.
+	KeyLengthHash KeyLengthPolicy = "hash"
+)
+
+// hashedKeySuffixLen is the length of ":" plus a hex-encoded SHA-256.
+const hashedKeySuffixLen = 1 + sha256.Size*2
+
+// keyLengthCache enforces a maximum key length on the wrapped cache.
+type keyLengthCache[T any] struct {
+	next   Cache[T]
+	max    int
+	policy KeyLengthPolicy
+}
+
+// WithMaxKeyLength wraps a cache so that keys longer than maxLength bytes are
+// rejected or hashed according to policy (default: KeyLengthReject). This
+// guards the backend against runaway keys, such as megabyte-long keys built
+// by a bug, that Redis would accept and that can't be scanned sanely. Hashed
+// keys never exceed maxLength either: below 66 bytes they are the digest
+// alone, truncated to maxLength, so long keys are more likely to collide.
+func WithMaxKeyLength[T any](cache Cache[T], maxLength int, policy KeyLengthPolicy) Cache[T] {
+	if maxLength <= 0 {
+		return cache
+	}
+	if policy == "" {
+		policy = KeyLengthReject
+	}
+	return &keyLengthCache[T]{
+		next:   cache,
+		max:    maxLength,
+		policy: policy,
+	}
+}
+
+// key returns the key to use for the backend, or an error if it is rejected.
+func (c *keyLengthCache[T]) key(key string) (string, error) {
+	if len(key) <= c.max {
+		return key, nil
+	}
+
+	if c.policy == KeyLengthHash {
+		sum := sha256.Sum256([]byte(key))
+		digest := hex.EncodeToString(sum[:])
+		if c.max <= hashedKeySuffixLen {
+			// No room for a prefix; short limits keep part of the digest only
+			return digest[:min(c.max, len(digest))], nil
+		}
+		return key[:c.max-hashedKeySuffixLen] + ":" + digest, nil
+	}
+
+	prefix := key
+	if len(prefix) > 64 {
+		prefix = prefix[:64]
+	}
+	return "", fmt.Errorf("%w: %d bytes exceeds the maximum of %d (key starts with %q)", ErrKeyTooLong, len(key), c.max, prefix)
+}
+
+func (c *keyLengthCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	key, err := c.key(key)
+	if err != nil {
+		noteDecision(ctx, DecisionBypass)
+		var zero T
+		return zero, false
+	}
+	return c.next.Get(ctx, key)
+}
+
+func (c *keyLengthCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		noteDecision(ctx, DecisionBypass)
+		var zero T
+		return zero, false, err
+	}
+	return GetWithError(ctx, c.next, key)
+}
+
+// GetMulti reads the keys within the limit in one batch, failing the others
+// like GetWithError does.
+func (c *keyLengthCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := make([]KeyResult[T], len(keys))
+	var valid []string
+	var at []int
+	for i, key := range keys {
+		stored, err := c.key(key)
+		if err != nil {
+			results[i] = KeyResult[T]{Key: key, Err: err}
+			continue
+		}
+		valid = append(valid, stored)
+		at = append(at, i)
+	}
+	if len(valid) < len(keys) {
+		noteDecision(ctx, DecisionBypass)
+	}
+	if len(valid) == 0 {
+		return results
+	}
+
+	for j, r := range GetMulti(ctx, c.next, valid...) {
+		r.Key = keys[at[j]]
+		results[at[j]] = r
+	}
+	return results
+}
+
+func (c *keyLengthCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return false, err
+	}
+	return Exists(ctx, c.next, key)
+}
+
+func (c *keyLengthCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return 0, false, err
+	}
+	return TTL(ctx, c.next, key)
+}
+
+func (c *keyLengthCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *keyLengthCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return false, err
+	}
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *keyLengthCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return false, err
+	}
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *keyLengthCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	key, err := c.key(key)
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *keyLengthCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *keyLengthCache[T]) Delete(ctx context.Context, key string) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.next.Delete(ctx, key)
+}
+
+func (c *keyLengthCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	mapped := make([]string, len(keys))
+	for i, key := range keys {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		mapped[i] = k
+	}
+	return deleteMulti(ctx, c.next, mapped)
+}
+
+func (c *keyLengthCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *keyLengthCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *keyLengthCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *keyLengthCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
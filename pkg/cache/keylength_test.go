@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxKeyLengthReject(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New[TestUser](&Config{Type: TypeMemory, MaxKeyLength: 16})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	long := strings.Repeat("k", 17)
+
+	err = cache.Set(ctx, long, TestUser{ID: "123"}, time.Minute)
+	if !errors.Is(err, ErrKeyTooLong) {
+		t.Fatalf("Expected ErrKeyTooLong, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "17 bytes exceeds the maximum of 16") {
+		t.Errorf("Expected an informative error, got: %v", err)
+	}
+	if err := cache.Delete(ctx, long); !errors.Is(err, ErrKeyTooLong) {
+		t.Errorf("Expected ErrKeyTooLong on Delete, got: %v", err)
+	}
+	if _, found := cache.Get(ctx, long); found {
+		t.Error("Expected a miss for a too-long key")
+	}
+
+	// Test keys at the limit pass through
+	if err := cache.Set(ctx, long[:16], TestUser{ID: "123"}, time.Minute); err != nil {
+		t.Errorf("Set failed: %v", err)
+	}
+}
+
+func TestMaxKeyLengthHash(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := WithMaxKeyLength(inner, 100, KeyLengthHash)
+	defer cache.Close()
+
+	long := "users:" + strings.Repeat("x", 200)
+	if err := cache.Set(ctx, long, TestUser{ID: "123"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if user, found := cache.Get(ctx, long); !found || user.ID != "123" {
+		t.Errorf("Expected hashed key to round-trip, got %v, %v", user, found)
+	}
+
+	// Test the stored key is bounded and keeps the prefix
+	mc, _ := memoryCacheOf(inner)
+	entries := mc.hottest(1)
+	if len(entries) != 1 || len(entries[0].key) != 100 || !strings.HasPrefix(entries[0].key, "users:") {
+		t.Errorf("Unexpected stored key: %+v", entries)
+	}
+
+	// Test distinct long keys don't collide
+	if _, found := cache.Get(ctx, long+"y"); found {
+		t.Error("Expected a different long key to miss")
+	}
+}
+
+func TestMaxKeyLengthHashShortLimit(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := WithMaxKeyLength(inner, 16, KeyLengthHash)
+	defer cache.Close()
+
+	// Test limits too short for a prefix keep a truncated digest within them
+	long := "users:" + strings.Repeat("x", 200)
+	_ = cache.Set(ctx, long, TestUser{ID: "123"}, time.Minute)
+	if user, found := cache.Get(ctx, long); !found || user.ID != "123" {
+		t.Errorf("Expected hashed key to round-trip, got %v, %v", user, found)
+	}
+	mc, _ := memoryCacheOf(inner)
+	if entries := mc.hottest(1); len(entries) != 1 || len(entries[0].key) != 16 {
+		t.Errorf("Expected a 16-byte stored key, got %+v", entries)
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// errKeysStopped stops the scan behind Keys once the caller stops ranging.
+var errKeysStopped = errors.New("keys iteration stopped")
+
+// Keys iterates the keys stored in cache that match pattern (Redis glob
+// syntax, "" for all keys), so operational tooling can enumerate a cache
+// without talking to its backend:
+//
+//	for key, err := range cache.Keys(ctx, users, "user:*") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(key)
+//	}
+//
+// Keys are read in batches with ScanKeys, with the same backends and
+// guarantees; a failing scan yields its error once, with an empty key, and
+// ends the iteration. Breaking out of the loop stops the scan.
+func Keys[T any](ctx context.Context, cache Cache[T], pattern string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		err := ScanKeys(ctx, cache, &ScanConfig{Pattern: pattern}, func(batch []KeyInfo) error {
+			for _, info := range batch {
+				if !yield(info.Key, nil) {
+					return errKeysStopped
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errKeysStopped) {
+			yield("", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	for i := range 150 {
+		_ = cache.Set(ctx, "user:"+strconv.Itoa(i), TestUser{}, time.Minute)
+	}
+	_ = cache.Set(ctx, "order:1", TestUser{}, time.Minute)
+
+	var keys []string
+	for key, err := range Keys(ctx, cache, "user:*") {
+		if err != nil {
+			t.Fatalf("Keys failed: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) != 150 || slices.Contains(keys, "order:1") {
+		t.Errorf("Expected the 150 user keys across batches, got %d", len(keys))
+	}
+}
+
+func TestKeysStopsEarly(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	for i := range 10 {
+		_ = cache.Set(ctx, strconv.Itoa(i), TestUser{}, time.Minute)
+	}
+
+	seen := 0
+	for _, err := range Keys(ctx, cache, "") {
+		if err != nil {
+			t.Fatalf("Keys failed: %v", err)
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Errorf("Expected to stop after 3 keys, got %d", seen)
+	}
+}
+
+func TestKeysYieldsErrors(t *testing.T) {
+	var errs []error
+	for key, err := range Keys(context.Background(), NewNoOp[TestUser](), "") {
+		if key != "" {
+			t.Errorf("Expected no keys, got %q", key)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrNotScannable) {
+		t.Errorf("Expected ErrNotScannable once, got %v", errs)
+	}
+}
@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeyTraceDuration bounds how long TraceKey traces a key.
+const KeyTraceDuration = 10 * time.Minute
+
+// keyTraceDuration is KeyTraceDuration, shortened by tests.
+var keyTraceDuration = KeyTraceDuration
+
+// KeyEvent is one operation touching a traced key.
+type KeyEvent struct {
+	// Time is when the operation completed.
+	Time time.Time
+
+	// Key is the traced key.
+	Key string
+
+	// Operation is what happened to the key: a caller's read or write, or
+	// OperationEvict and OperationRefresh from inside the cache.
+	Operation Operation
+
+	// Source names the layer that reported the event: the cache name for
+	// callers' operations, or "memory", "tiered" or "refresh_ahead".
+	Source string
+
+	// Found reports whether a read found the key, or whether a conditional
+	// write (SetIfAbsent, CompareAndSwap), Expire or refresh took effect.
+	Found bool
+
+	// TTL is the TTL a write stored the key with.
+	TTL time.Duration
+
+	// Reason explains events from inside the cache, e.g. "expired" or
+	// "evicted" for evictions and "invalidation" for tiered deletes.
+	Reason string
+
+	// Err is the error the operation failed with.
+	Err error
+}
+
+// keyTrace is one call to TraceKey.
+type keyTrace struct {
+	sink func(KeyEvent)
+}
+
+// keyTraces holds the active traces by key. active is checked first, so
+// operations pay a single atomic load while nothing is traced.
+var keyTraces struct {
+	active atomic.Int64
+	mu     sync.RWMutex
+	byKey  map[string][]*keyTrace
+}
+
+// TraceKey calls sink with every operation touching key, across all caches
+// of the process, for KeyTraceDuration or until stop is called. It makes
+// "why did this key disappear?" investigations tractable:
+//
+//	stop := cache.TraceKey("user:42", func(e cache.KeyEvent) {
+//		slog.Info("cache key event", "op", e.Operation, "source", e.Source, "reason", e.Reason)
+//	})
+//	defer stop()
+//
+// Callers' operations are reported by caches created with New, or wrapped
+// with WithKeyTracing, under the key the caller passed. Evictions and
+// expiries of ttlcache memory caches, refresh-ahead reloads and tiered
+// invalidations are reported under the key the layer reporting them sees,
+// which includes any KeyPrefix. sink is called synchronously, possibly from
+// several goroutines at once, so it must be fast and safe for concurrent use.
+func TraceKey(key string, sink func(KeyEvent)) (stop func()) {
+	trace := &keyTrace{sink: sink}
+
+	keyTraces.mu.Lock()
+	if keyTraces.byKey == nil {
+		keyTraces.byKey = make(map[string][]*keyTrace)
+	}
+	keyTraces.byKey[key] = append(keyTraces.byKey[key], trace)
+	keyTraces.active.Add(1)
+	keyTraces.mu.Unlock()
+
+	var once sync.Once
+	var timer *time.Timer
+	stop = func() {
+		once.Do(func() {
+			timer.Stop()
+
+			keyTraces.mu.Lock()
+			defer keyTraces.mu.Unlock()
+			traces := keyTraces.byKey[key]
+			for i, t := range traces {
+				if t == trace {
+					traces = append(traces[:i:i], traces[i+1:]...)
+					break
+				}
+			}
+			if len(traces) == 0 {
+				delete(keyTraces.byKey, key)
+			} else {
+				keyTraces.byKey[key] = traces
+			}
+			keyTraces.active.Add(-1)
+		})
+	}
+	timer = time.AfterFunc(keyTraceDuration, stop)
+	return stop
+}
+
+// traceKey reports event to the traces of its key.
+func traceKey(event KeyEvent) {
+	if keyTraces.active.Load() == 0 {
+		return
+	}
+	keyTraces.mu.RLock()
+	traces := keyTraces.byKey[event.Key]
+	keyTraces.mu.RUnlock()
+
+	if len(traces) == 0 {
+		return
+	}
+	event.Time = time.Now()
+	for _, trace := range traces {
+		trace.sink(event)
+	}
+}
+
+// traceAllKeys reports an operation touching every key, such as Clear, to
+// every trace.
+func traceAllKeys(event KeyEvent) {
+	if keyTraces.active.Load() == 0 {
+		return
+	}
+	keyTraces.mu.RLock()
+	keys := make([]string, 0, len(keyTraces.byKey))
+	for key := range keyTraces.byKey {
+		keys = append(keys, key)
+	}
+	keyTraces.mu.RUnlock()
+
+	for _, key := range keys {
+		event.Key = key
+		traceKey(event)
+	}
+}
+
+// keyTracingCache reports the operations on traced keys.
+type keyTracingCache[T any] struct {
+	next   Cache[T]
+	source string
+}
+
+// WithKeyTracing wraps a cache so that its operations on keys traced with
+// TraceKey are reported with source as their Source. Caches created with New
+// are wrapped already, with their name.
+func WithKeyTracing[T any](cache Cache[T], source string) Cache[T] {
+	return &keyTracingCache[T]{next: cache, source: source}
+}
+
+// trace reports an operation on key; untraced keys cost one atomic load.
+func (c *keyTracingCache[T]) trace(key string, op Operation, found bool, ttl time.Duration, err error) {
+	traceKey(KeyEvent{Key: key, Operation: op, Source: c.source, Found: found, TTL: ttl, Err: err})
+}
+
+func (c *keyTracingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found := c.next.Get(ctx, key)
+	c.trace(key, OperationGet, found, 0, nil)
+	return value, found
+}
+
+func (c *keyTracingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetWithError(ctx, c.next, key)
+	c.trace(key, OperationGet, found, 0, err)
+	return value, found, err
+}
+
+func (c *keyTracingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := GetMulti(ctx, c.next, keys...)
+	for _, r := range results {
+		c.trace(r.Key, OperationGet, r.Found, 0, r.Err)
+	}
+	return results
+}
+
+func (c *keyTracingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	loaded := false
+	value, err := GetOrSet(ctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		loaded = true
+		return load(ctx)
+	})
+	if !loaded {
+		c.trace(key, OperationGet, err == nil, 0, err)
+		return value, err
+	}
+	// A load follows a miss and stores what it loaded
+	c.trace(key, OperationGet, false, 0, nil)
+	c.trace(key, OperationSet, false, ttl, err)
+	return value, err
+}
+
+func (c *keyTracingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *keyTracingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *keyTracingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	err := Expire(ctx, c.next, key, ttl)
+	c.trace(key, OperationExpire, err == nil, ttl, err)
+	return err
+}
+
+func (c *keyTracingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	c.trace(key, OperationSetIfAbsent, stored, ttl, err)
+	return stored, err
+}
+
+func (c *keyTracingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	c.trace(key, OperationCompareAndSwap, swapped, ttl, err)
+	return swapped, err
+}
+
+func (c *keyTracingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetDel(ctx, c.next, key)
+	c.trace(key, OperationGetDel, found, 0, err)
+	return value, found, err
+}
+
+func (c *keyTracingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	err := c.next.Set(ctx, key, value, ttl)
+	c.trace(key, OperationSet, false, ttl, err)
+	return err
+}
+
+func (c *keyTracingCache[T]) Delete(ctx context.Context, key string) error {
+	err := c.next.Delete(ctx, key)
+	c.trace(key, OperationDelete, false, 0, err)
+	return err
+}
+
+func (c *keyTracingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	err := deleteMulti(ctx, c.next, keys)
+	for _, key := range keys {
+		c.trace(key, OperationDelete, false, 0, err)
+	}
+	return err
+}
+
+func (c *keyTracingCache[T]) Clear(ctx context.Context) error {
+	err := Clear(ctx, c.next)
+	traceAllKeys(KeyEvent{Operation: OperationClear, Source: c.source, Err: err})
+	return err
+}
+
+func (c *keyTracingCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *keyTracingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *keyTracingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keyEventLog collects the events of a trace.
+type keyEventLog struct {
+	mu     sync.Mutex
+	events []KeyEvent
+}
+
+func (l *keyEventLog) sink(event KeyEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *keyEventLog) snapshot() []KeyEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]KeyEvent(nil), l.events...)
+}
+
+func TestTraceKeyRecordsOperations(t *testing.T) {
+	ctx := context.Background()
+	cache := WithKeyTracing(NewMemory[TestUser](nil), "users")
+	defer cache.Close()
+
+	var log keyEventLog
+	stop := TraceKey("traced", log.sink)
+	defer stop()
+
+	_ = cache.Set(ctx, "traced", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "other", TestUser{ID: "2"}, time.Minute)
+	cache.Get(ctx, "traced")
+	_ = Expire(ctx, cache, "traced", time.Hour)
+	_ = cache.Delete(ctx, "traced")
+	cache.Get(ctx, "traced")
+
+	events := log.snapshot()
+	want := []struct {
+		op    Operation
+		found bool
+		ttl   time.Duration
+	}{
+		{OperationSet, false, time.Minute},
+		{OperationGet, true, 0},
+		{OperationExpire, true, time.Hour},
+		{OperationDelete, false, 0},
+		{OperationGet, false, 0},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Key != "traced" || e.Source != "users" || e.Operation != w.op || e.Found != w.found || e.TTL != w.ttl {
+			t.Errorf("Event %d: expected %s (found=%v, ttl=%v), got %+v", i, w.op, w.found, w.ttl, e)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("Event %d has no timestamp", i)
+		}
+	}
+}
+
+func TestTraceKeyGetOrSet(t *testing.T) {
+	ctx := context.Background()
+	cache := WithKeyTracing(NewMemory[TestUser](nil), "users")
+	defer cache.Close()
+
+	var log keyEventLog
+	stop := TraceKey("a", log.sink)
+	defer stop()
+
+	load := func(context.Context) (TestUser, error) { return TestUser{ID: "a"}, nil }
+	_, _ = GetOrSet(ctx, cache, "a", time.Minute, load)
+	_, _ = GetOrSet(ctx, cache, "a", time.Minute, load)
+
+	events := log.snapshot()
+	if len(events) != 3 {
+		t.Fatalf("Expected a miss, a set and a hit, got %+v", events)
+	}
+	if events[0].Operation != OperationGet || events[0].Found ||
+		events[1].Operation != OperationSet || events[1].TTL != time.Minute ||
+		events[2].Operation != OperationGet || !events[2].Found {
+		t.Errorf("Expected a miss, a set and a hit, got %+v", events)
+	}
+}
+
+func TestTraceKeyStops(t *testing.T) {
+	ctx := context.Background()
+	cache := WithKeyTracing(NewMemory[TestUser](nil), "users")
+	defer cache.Close()
+
+	var stopped, running keyEventLog
+	stop := TraceKey("a", stopped.sink)
+	stopRunning := TraceKey("a", running.sink)
+	defer stopRunning()
+
+	stop()
+	stop()
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+
+	if events := stopped.snapshot(); len(events) != 0 {
+		t.Errorf("Expected no events after stop, got %+v", events)
+	}
+	if events := running.snapshot(); len(events) != 1 {
+		t.Errorf("Expected the other trace to keep running, got %+v", events)
+	}
+}
+
+func TestTraceKeyIsBounded(t *testing.T) {
+	defer func(d time.Duration) { keyTraceDuration = d }(keyTraceDuration)
+	keyTraceDuration = 10 * time.Millisecond
+
+	ctx := context.Background()
+	cache := WithKeyTracing(NewMemory[TestUser](nil), "users")
+	defer cache.Close()
+
+	var log keyEventLog
+	defer TraceKey("a", log.sink)()
+
+	waitFor(t, func() bool { return keyTraces.active.Load() == 0 })
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if events := log.snapshot(); len(events) != 0 {
+		t.Errorf("Expected no events once the trace ended, got %+v", events)
+	}
+}
+
+func TestTraceKeyMemoryExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	var log keyEventLog
+	defer TraceKey("short", log.sink)()
+
+	_ = cache.Set(ctx, "short", TestUser{ID: "1"}, 10*time.Millisecond)
+	waitFor(t, func() bool {
+		for _, e := range log.snapshot() {
+			if e.Operation == OperationEvict && e.Source == "memory" && e.Reason == string(MemoryEventExpired) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestTraceKeyClear(t *testing.T) {
+	ctx := context.Background()
+	cache := WithKeyTracing(NewMemory[TestUser](nil), "users")
+	defer cache.Close()
+
+	var log keyEventLog
+	defer TraceKey("a", log.sink)()
+
+	_ = Clear(ctx, cache)
+	if events := log.snapshot(); len(events) != 1 || events[0].Operation != OperationClear || events[0].Key != "a" {
+		t.Errorf("Expected a clear event, got %+v", events)
+	}
+}
+
+func TestTraceKeyFactoryCaches(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New[TestUser](&Config{Type: TypeMemory, Name: "sessions", KeyPrefix: "s:"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer cache.Close()
+
+	var log keyEventLog
+	defer TraceKey("a", log.sink)()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	if events := log.snapshot(); len(events) != 1 || events[0].Source != "sessions" {
+		t.Errorf("Expected a set reported by sessions, got %+v", events)
+	}
+}
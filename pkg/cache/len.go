@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyCounter is implemented by backends that count their live keys without
+// enumerating them.
+type keyCounter interface {
+	countKeys(ctx context.Context) (int64, error)
+}
+
+// keyCounterOf returns the backend behind cache that counts its own keys,
+// looking through decorators.
+func keyCounterOf[T any](cache Cache[T]) (keyCounter, bool) {
+	for cache != nil {
+		if kc, ok := cache.(keyCounter); ok {
+			return kc, true
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return nil, false
+}
+
+// Len returns the number of entries stored in cache, for dashboards and
+// sanity checks, looking through decorators to the backend. Distributed
+// caches report DBSIZE, summed over the masters of a cluster, which includes
+// keys written through other clients; ttlcache memory caches report their
+// map length and SQL caches count their live rows. Caches wrapped WithPrefix
+// and other backends count the keys ScanKeys enumerates, which reads every
+// key, and return ErrNotScannable when they can't be enumerated.
+func Len[T any](ctx context.Context, cache Cache[T]) (int64, error) {
+	if keyPrefixOf(cache) == "" {
+		if client, err := redisClientOf(cache); err == nil {
+			return redisDBSize(ctx, client)
+		}
+		if kc, ok := keyCounterOf(cache); ok {
+			return kc.countKeys(ctx)
+		}
+	}
+
+	var n int64
+	err := ScanKeys(ctx, cache, &ScanConfig{BatchSize: 1000}, func(batch []KeyInfo) error {
+		n += int64(len(batch))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// redisDBSize returns the number of keys of the database client is connected
+// to, on every master of a cluster.
+func redisDBSize(ctx context.Context, client redis.UniversalClient) (int64, error) {
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		var n atomic.Int64
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			size, err := node.DBSize(ctx).Result()
+			n.Add(size)
+			return err
+		})
+		if err != nil {
+			return 0, closedErr(err)
+		}
+		return n.Load(), nil
+	}
+
+	n, err := client.DBSize(ctx).Result()
+	if err != nil {
+		return 0, closedErr(err)
+	}
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLenMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			if n, err := Len(ctx, cache); err != nil || n != 0 {
+				t.Errorf("Expected an empty cache, got %d (err=%v)", n, err)
+			}
+			for i := range 5 {
+				_ = cache.Set(ctx, strconv.Itoa(i), TestUser{}, time.Minute)
+			}
+			_ = cache.Delete(ctx, "0")
+			if n, err := Len(ctx, cache); err != nil || n != 4 {
+				t.Errorf("Expected 4 entries, got %d (err=%v)", n, err)
+			}
+		})
+	}
+}
+
+func TestLenPrefix(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	users := WithPrefix(backend, "user:")
+	_ = users.Set(ctx, "1", TestUser{}, time.Minute)
+	_ = users.Set(ctx, "2", TestUser{}, time.Minute)
+	_ = backend.Set(ctx, "order:1", TestUser{}, time.Minute)
+
+	if n, err := Len(ctx, users); err != nil || n != 2 {
+		t.Errorf("Expected the namespace to hold 2 entries, got %d (err=%v)", n, err)
+	}
+	if n, err := Len(ctx, backend); err != nil || n != 3 {
+		t.Errorf("Expected the backend to hold 3 entries, got %d (err=%v)", n, err)
+	}
+}
+
+func TestLenTieredCountsL2(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{}, time.Hour)
+	_ = l2.Set(ctx, "2", TestUser{}, time.Hour)
+
+	if n, err := Len(ctx, cache); err != nil || n != 2 {
+		t.Errorf("Expected L2's 2 entries, got %d (err=%v)", n, err)
+	}
+}
+
+func TestLenDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Set(ctx, "1", TestUser{}, time.Minute)
+	_ = cache.Set(ctx, "2", TestUser{}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if n, err := Len(ctx, cache); err != nil || n != 1 {
+		t.Errorf("Expected 1 live entry, got %d (err=%v)", n, err)
+	}
+}
+
+func TestLenNotScannable(t *testing.T) {
+	if _, err := Len(context.Background(), NewNoOp[TestUser]()); !errors.Is(err, ErrNotScannable) {
+		t.Errorf("Expected ErrNotScannable, got %v", err)
+	}
+}
+
+func TestLenClosed(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	_ = cache.Close()
+
+	if _, err := Len(context.Background(), cache); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestLenDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "user:1", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "user:2", TestUser{ID: "2"}, 0)
+	_ = cache.Set(ctx, "order:1", TestUser{ID: "1"}, 0)
+
+	if n, err := Len(ctx, cache); err != nil || n != 3 {
+		t.Errorf("Expected DBSIZE to be 3, got %d (err=%v)", n, err)
+	}
+	if n, err := Len(ctx, WithPrefix(cache, "user:")); err != nil || n != 2 {
+		t.Errorf("Expected the namespace to hold 2 keys, got %d (err=%v)", n, err)
+	}
+}
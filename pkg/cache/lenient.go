@@ -0,0 +1,415 @@
+package cache
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes a field a lenient decode could not restore.
+type FieldError struct {
+	// Path locates the field, e.g. "address.zip" or "items[2].price".
+	Path string
+
+	// Err describes why the field was left at its zero value.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// DecodeReport lists the fields of one lenient decode that were left at their
+// zero value.
+type DecodeReport struct {
+	// Type is the Go type decoded into.
+	Type string
+
+	// Fields lists the fields that could not be restored.
+	Fields []FieldError
+}
+
+// LenientConfig holds configuration for a LenientJSONSerializer.
+type LenientConfig struct {
+	// OnFieldErrors is called for every decode that left fields at their zero
+	// value, e.g. to log or count schema drift (optional)
+	OnFieldErrors func(report DecodeReport)
+}
+
+// LenientJSONSerializer implements JSON serialization whose decoding survives
+// minor struct refactors: unknown fields are ignored and missing ones left at
+// their zero value (as with JSONSerializer), and values whose type changed are
+// converted where it is lossless (numbers and strings holding numbers or
+// booleans, integers and whole floats, a single value and a one-element
+// slice). Fields that still don't fit are left at their zero value and
+// reported to OnFieldErrors instead of failing the whole decode, so a
+// refactor doesn't turn every cached entry into a miss. Malformed JSON still
+// fails.
+type LenientJSONSerializer struct {
+	config LenientConfig
+}
+
+// NewLenientJSONSerializer creates a lenient JSON serializer.
+func NewLenientJSONSerializer(config *LenientConfig) *LenientJSONSerializer {
+	var cfg LenientConfig
+	if config != nil {
+		cfg = *config
+	}
+	return &LenientJSONSerializer{config: cfg}
+}
+
+// Serialize converts a value to JSON bytes.
+func (l *LenientJSONSerializer) Serialize(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Deserialize converts JSON bytes back to a value, leniently.
+func (l *LenientJSONSerializer) Deserialize(data []byte, v interface{}) error {
+	// Entries matching the current struct take the fast path
+	err := json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || !errors.As(err, &typeErr) {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return err
+	}
+
+	d := &lenientDecoder{}
+	target := rv.Elem()
+	target.SetZero()
+	d.assign(target, tree, "")
+
+	if len(d.errs) > 0 && l.config.OnFieldErrors != nil {
+		l.config.OnFieldErrors(DecodeReport{Type: target.Type().String(), Fields: d.errs})
+	}
+	return nil
+}
+
+// lenientDecoder assigns a decoded JSON tree to Go values, collecting the
+// fields it can't restore.
+type lenientDecoder struct {
+	errs []FieldError
+}
+
+func (d *lenientDecoder) fail(path string, err error) {
+	if path == "" {
+		path = "."
+	}
+	d.errs = append(d.errs, FieldError{Path: path, Err: err})
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// assign stores the JSON value src into dst, leaving dst zero on failure.
+func (d *lenientDecoder) assign(dst reflect.Value, src any, path string) {
+	if src == nil {
+		return
+	}
+
+	// Types with their own decoding (e.g. time.Time) get their JSON back
+	if reflect.PointerTo(dst.Type()).Implements(jsonUnmarshalerType) ||
+		reflect.PointerTo(dst.Type()).Implements(textUnmarshalerType) {
+		raw, _ := json.Marshal(src)
+		if err := json.Unmarshal(raw, dst.Addr().Interface()); err != nil {
+			dst.SetZero()
+			d.fail(path, err)
+		}
+		return
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		d.assign(elem.Elem(), src, path)
+		dst.Set(elem)
+
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			d.fail(path, fmt.Errorf("cannot decode into %s", dst.Type()))
+			return
+		}
+		dst.Set(reflect.ValueOf(plainJSON(src)))
+
+	case reflect.Struct:
+		obj, ok := src.(map[string]any)
+		if !ok {
+			d.fail(path, mismatch(src, dst.Type()))
+			return
+		}
+		d.assignStruct(dst, obj, path)
+
+	case reflect.Map:
+		obj, ok := src.(map[string]any)
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			d.fail(path, mismatch(src, dst.Type()))
+			return
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(obj))
+		for key, value := range obj {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			d.assign(elem, value, joinPath(path, key))
+			m.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(m)
+
+	case reflect.Slice, reflect.Array:
+		d.assignList(dst, src, path)
+
+	default:
+		if err := assignScalar(dst, src); err != nil {
+			dst.SetZero()
+			d.fail(path, err)
+		}
+	}
+}
+
+func (d *lenientDecoder) assignStruct(dst reflect.Value, obj map[string]any, path string) {
+	for key, value := range obj {
+		field, ok := fieldByJSONName(dst, key)
+		if !ok {
+			// Removed fields are expected after a refactor
+			continue
+		}
+		d.assign(field, value, joinPath(path, key))
+	}
+}
+
+func (d *lenientDecoder) assignList(dst reflect.Value, src any, path string) {
+	if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+		// []byte is base64 in JSON
+		if err := assignScalar(dst, src); err != nil {
+			d.fail(path, err)
+		}
+		return
+	}
+
+	items, ok := src.([]any)
+	if !ok {
+		// A single value widened to a list
+		items = []any{src}
+	}
+
+	if dst.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(dst.Type(), len(items), len(items)))
+	}
+	for i, item := range items {
+		if i >= dst.Len() {
+			d.fail(path, fmt.Errorf("%d elements do not fit %s", len(items), dst.Type()))
+			return
+		}
+		d.assign(dst.Index(i), item, path+"["+strconv.Itoa(i)+"]")
+	}
+}
+
+// fieldByJSONName finds the settable struct field encoding/json would decode
+// name into, looking into embedded structs.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	var fold reflect.Value
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && tagName == "" {
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.Type().Elem().Kind() != reflect.Struct || !sf.IsExported() {
+					continue
+				}
+				if embedded.IsNil() {
+					embedded.Set(reflect.New(embedded.Type().Elem()))
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if field, ok := fieldByJSONName(embedded, name); ok {
+					return field, true
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		fieldName := sf.Name
+		if tagName != "" {
+			fieldName = tagName
+		}
+		if fieldName == name {
+			return v.Field(i), true
+		}
+		if !fold.IsValid() && strings.EqualFold(fieldName, name) {
+			fold = v.Field(i)
+		}
+	}
+	// encoding/json matches names case-insensitively as a fallback
+	return fold, fold.IsValid()
+}
+
+// assignScalar stores a JSON scalar into a string, number or bool, converting
+// between them where it is lossless.
+func assignScalar(dst reflect.Value, src any) error {
+	switch dst.Kind() {
+	case reflect.String:
+		switch s := src.(type) {
+		case string:
+			dst.SetString(s)
+		case json.Number:
+			dst.SetString(s.String())
+		case bool:
+			dst.SetString(strconv.FormatBool(s))
+		default:
+			return mismatch(src, dst.Type())
+		}
+
+	case reflect.Bool:
+		switch s := src.(type) {
+		case bool:
+			dst.SetBool(s)
+		case string:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			dst.SetBool(b)
+		default:
+			return mismatch(src, dst.Type())
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		text, ok := numberText(src)
+		if !ok {
+			return mismatch(src, dst.Type())
+		}
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			f, ferr := strconv.ParseFloat(text, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return fmt.Errorf("%s is not an integer", text)
+			}
+			n = int64(f)
+		}
+		if dst.OverflowInt(n) {
+			return fmt.Errorf("%s overflows %s", text, dst.Type())
+		}
+		dst.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		text, ok := numberText(src)
+		if !ok {
+			return mismatch(src, dst.Type())
+		}
+		n, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			f, ferr := strconv.ParseFloat(text, 64)
+			if ferr != nil || f < 0 || f != float64(uint64(f)) {
+				return fmt.Errorf("%s is not an unsigned integer", text)
+			}
+			n = uint64(f)
+		}
+		if dst.OverflowUint(n) {
+			return fmt.Errorf("%s overflows %s", text, dst.Type())
+		}
+		dst.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		text, ok := numberText(src)
+		if !ok {
+			return mismatch(src, dst.Type())
+		}
+		f, err := strconv.ParseFloat(text, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+
+	default:
+		// Anything else (e.g. []byte) decodes the standard way or not at all
+		raw, _ := json.Marshal(src)
+		return json.Unmarshal(raw, dst.Addr().Interface())
+	}
+	return nil
+}
+
+// numberText returns the text of a JSON number, or of a string holding one.
+func numberText(src any) (string, bool) {
+	switch s := src.(type) {
+	case json.Number:
+		return s.String(), true
+	case string:
+		s = strings.TrimSpace(s)
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return "", false
+		}
+		return s, true
+	}
+	return "", false
+}
+
+// plainJSON converts the json.Numbers in a decoded tree to float64, as
+// encoding/json decodes numbers into interface values.
+func plainJSON(src any) any {
+	switch s := src.(type) {
+	case json.Number:
+		f, _ := s.Float64()
+		return f
+	case map[string]any:
+		for key, value := range s {
+			s[key] = plainJSON(value)
+		}
+	case []any:
+		for i, value := range s {
+			s[i] = plainJSON(value)
+		}
+	}
+	return src
+}
+
+func mismatch(src any, t reflect.Type) error {
+	var kind string
+	switch src.(type) {
+	case map[string]any:
+		kind = "object"
+	case []any:
+		kind = "array"
+	case string:
+		kind = "string"
+	case json.Number:
+		kind = "number"
+	case bool:
+		kind = "bool"
+	default:
+		kind = fmt.Sprintf("%T", src)
+	}
+	return fmt.Errorf("cannot convert %s to %s", kind, t)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+type orderV1 struct {
+	ID       string    `json:"id"`
+	Quantity float64   `json:"quantity"`
+	Price    string    `json:"price"`
+	Tags     string    `json:"tags"`
+	Status   string    `json:"status"`
+	Created  time.Time `json:"created"`
+	Removed  string    `json:"removed"`
+}
+
+type orderV2 struct {
+	ID       string    `json:"id"`
+	Quantity int       `json:"quantity"`
+	Price    float64   `json:"price"`
+	Tags     []string  `json:"tags"`
+	Status   int       `json:"status"`
+	Created  time.Time `json:"created"`
+	Added    string    `json:"added"`
+}
+
+func TestLenientJSONSerializer(t *testing.T) {
+	var reports []DecodeReport
+	serializer := NewLenientJSONSerializer(&LenientConfig{
+		OnFieldErrors: func(report DecodeReport) { reports = append(reports, report) },
+	})
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := serializer.Serialize(orderV1{
+		ID: "o1", Quantity: 3, Price: "9.5", Tags: "urgent", Status: "shipped", Created: created, Removed: "x",
+	})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	var order orderV2
+	if err := serializer.Deserialize(data, &order); err != nil {
+		t.Fatalf("Expected lenient decode to succeed, got: %v", err)
+	}
+	if order.ID != "o1" || order.Quantity != 3 || order.Price != 9.5 || !order.Created.Equal(created) {
+		t.Errorf("Expected widened fields to be restored, got %+v", order)
+	}
+	if len(order.Tags) != 1 || order.Tags[0] != "urgent" {
+		t.Errorf("Expected single value widened to a slice, got %v", order.Tags)
+	}
+	if order.Status != 0 || order.Added != "" {
+		t.Errorf("Expected unconvertible and missing fields to be zero, got %+v", order)
+	}
+
+	if len(reports) != 1 || reports[0].Type != "cache.orderV2" {
+		t.Fatalf("Expected one report for orderV2, got %+v", reports)
+	}
+	if fields := reports[0].Fields; len(fields) != 1 || fields[0].Path != "status" {
+		t.Errorf("Expected only status to be reported, got %+v", fields)
+	}
+}
+
+func TestLenientJSONSerializerNestedPaths(t *testing.T) {
+	var report DecodeReport
+	serializer := NewLenientJSONSerializer(&LenientConfig{
+		OnFieldErrors: func(r DecodeReport) { report = r },
+	})
+
+	type line struct {
+		Quantity int `json:"quantity"`
+	}
+	type order struct {
+		Lines []line         `json:"lines"`
+		Meta  map[string]int `json:"meta"`
+	}
+
+	var decoded order
+	data := []byte(`{"lines":[{"quantity":1},{"quantity":1.5}],"meta":{"a":"2","b":true}}`)
+	if err := serializer.Deserialize(data, &decoded); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if len(decoded.Lines) != 2 || decoded.Lines[0].Quantity != 1 || decoded.Meta["a"] != 2 {
+		t.Errorf("Unexpected decoded value: %+v", decoded)
+	}
+
+	paths := map[string]bool{}
+	for _, field := range report.Fields {
+		paths[field.Path] = true
+	}
+	if len(paths) != 2 || !paths["lines[1].quantity"] || !paths["meta.b"] {
+		t.Errorf("Expected lines[1].quantity and meta.b reported, got %+v", report.Fields)
+	}
+}
+
+func TestLenientJSONSerializerRejectsMalformed(t *testing.T) {
+	called := false
+	serializer := NewLenientJSONSerializer(&LenientConfig{
+		OnFieldErrors: func(DecodeReport) { called = true },
+	})
+
+	var order orderV2
+	if err := serializer.Deserialize([]byte(`{"id":`), &order); err == nil {
+		t.Error("Expected malformed JSON to fail")
+	}
+
+	// Entries matching the struct decode without a report
+	if err := serializer.Deserialize([]byte(`{"id":"o1","quantity":2}`), &order); err != nil || order.Quantity != 2 {
+		t.Errorf("Expected strict decode, got %+v (err=%v)", order, err)
+	}
+	if called {
+		t.Error("Expected no report for clean decodes")
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instrumentationName identifies this package to OpenTelemetry.
+const instrumentationName = "github.com/dentech-floss/cache"
+
+// lifecycleMetrics records cache construction, Close and health-probe outcomes
+// tagged by cache name and type, so fleet-wide dashboards can spot services
+// with broken cache configuration after a deploy.
+type lifecycleMetrics struct {
+	creations    metric.Int64Counter
+	closes       metric.Int64Counter
+	healthChecks metric.Int64Counter
+	attrs        []attribute.KeyValue
+}
+
+func newLifecycleMetrics(config *Config) *lifecycleMetrics {
+	provider := config.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	meter := provider.Meter(instrumentationName)
+
+	// Instrument creation only fails on invalid names; fall back to no-ops
+	creations, _ := meter.Int64Counter("cache.lifecycle.creations",
+		metric.WithDescription("Number of cache constructions by outcome"))
+	closes, _ := meter.Int64Counter("cache.lifecycle.closes",
+		metric.WithDescription("Number of cache Close calls by outcome"))
+	healthChecks, _ := meter.Int64Counter("cache.lifecycle.health_checks",
+		metric.WithDescription("Number of cache health probes by outcome"))
+
+	return &lifecycleMetrics{
+		creations:    creations,
+		closes:       closes,
+		healthChecks: healthChecks,
+		attrs: []attribute.KeyValue{
+			attribute.String("cache.name", config.Name),
+			attribute.String("cache.type", string(config.Type)),
+		},
+	}
+}
+
+func (m *lifecycleMetrics) record(ctx context.Context, counter metric.Int64Counter, outcome string) {
+	if counter == nil {
+		return
+	}
+	attrs := append([]attribute.KeyValue{attribute.String("outcome", outcome)}, m.attrs...)
+	counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+func (m *lifecycleMetrics) recordCreation(err error) {
+	m.record(context.Background(), m.creations, outcomeOf(err, "success", "failure"))
+}
+
+func outcomeOf(err error, ok, failed string) string {
+	if err != nil {
+		return failed
+	}
+	return ok
+}
+
+// lifecycleCache records Close and health-probe outcomes of the wrapped cache.
+type lifecycleCache[T any] struct {
+	next    Cache[T]
+	metrics *lifecycleMetrics
+}
+
+func (c *lifecycleCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *lifecycleCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *lifecycleCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *lifecycleCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *lifecycleCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *lifecycleCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *lifecycleCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *lifecycleCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *lifecycleCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *lifecycleCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *lifecycleCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, load)
+}
+
+func (c *lifecycleCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *lifecycleCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *lifecycleCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *lifecycleCache[T]) Close() error {
+	err := c.next.Close()
+	c.metrics.record(context.Background(), c.metrics.closes, outcomeOf(err, "success", "failure"))
+	return err
+}
+
+func (c *lifecycleCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *lifecycleCache[T]) Ping(ctx context.Context) error {
+	err := pingNext(ctx, c.next)
+	c.metrics.record(ctx, c.metrics.healthChecks, outcomeOf(err, "healthy", "unhealthy"))
+	return err
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectCounter returns the value of a counter data point matching outcome.
+func collectCounter(t *testing.T, reader *sdkmetric.ManualReader, name, outcome string) int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("Expected %s to be an int64 sum", name)
+			}
+			for _, dp := range sum.DataPoints {
+				if v, ok := dp.Attributes.Value(attribute.Key("outcome")); ok && v.AsString() == outcome {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestFactoryLifecycleMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// Test successful creation, health check and Close are recorded
+	cache, err := New[TestUser](&Config{Type: TypeMemory, Name: "users", MeterProvider: provider})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cache.(HealthChecker).Ping(context.Background()); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	if got := collectCounter(t, reader, "cache.lifecycle.creations", "success"); got != 1 {
+		t.Errorf("Expected 1 successful creation, got %d", got)
+	}
+	if got := collectCounter(t, reader, "cache.lifecycle.health_checks", "healthy"); got != 1 {
+		t.Errorf("Expected 1 healthy probe, got %d", got)
+	}
+	if got := collectCounter(t, reader, "cache.lifecycle.closes", "success"); got != 1 {
+		t.Errorf("Expected 1 successful close, got %d", got)
+	}
+
+	// Test failed creation is recorded
+	_, err = New[TestUser](&Config{Type: CacheType("unknown"), Name: "broken", MeterProvider: provider})
+	if err == nil {
+		t.Fatal("Expected error for unknown cache type")
+	}
+	if got := collectCounter(t, reader, "cache.lifecycle.creations", "failure"); got != 1 {
+		t.Errorf("Expected 1 failed creation, got %d", got)
+	}
+}
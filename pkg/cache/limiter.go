@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrLoadShed is returned by loading caches when a load is rejected because
+// MaxConcurrentLoads loads are running and the wait queue is full.
+var ErrLoadShed = errors.New("load shed: too many concurrent loads")
+
+// LoadLimitConfig bounds how many loader calls a loading cache runs at once,
+// so a cold start with thousands of distinct misses can't overwhelm the origin.
+type LoadLimitConfig struct {
+	// MaxConcurrentLoads is the maximum number of loads running at once (default: 0, unlimited)
+	MaxConcurrentLoads int
+
+	// MaxQueuedLoads is the maximum number of loads waiting for a free slot;
+	// loads beyond that fail with ErrLoadShed (default: 0, shed immediately)
+	MaxQueuedLoads int
+}
+
+// loadLimiter is a semaphore with a bounded wait queue. A nil limiter never blocks.
+type loadLimiter struct {
+	slots     chan struct{}
+	queued    atomic.Int64
+	maxQueued int64
+}
+
+func newLoadLimiter(config *LoadLimitConfig) *loadLimiter {
+	if config == nil || config.MaxConcurrentLoads <= 0 {
+		return nil
+	}
+	return &loadLimiter{
+		slots:     make(chan struct{}, config.MaxConcurrentLoads),
+		maxQueued: int64(config.MaxQueuedLoads),
+	}
+}
+
+// acquire takes a load slot, waiting in the queue if there is room.
+// The returned release function must be called when the load finishes.
+func (l *loadLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueued {
+		l.queued.Add(-1)
+		return nil, ErrLoadShed
+	}
+	defer l.queued.Add(-1)
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *loadLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := newLoadLimiter(&LoadLimitConfig{MaxConcurrentLoads: 1, MaxQueuedLoads: 1})
+
+	release, err := limiter.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	// A second load queues until the first is released
+	acquired := make(chan func())
+	go func() {
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			t.Errorf("queued acquire failed: %v", err)
+		}
+		acquired <- release
+	}()
+
+	// Wait for the second load to enter the queue
+	deadline := time.Now().Add(time.Second)
+	for limiter.queued.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for queued load")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A third load is shed because the queue is full
+	if _, err := limiter.acquire(ctx); !errors.Is(err, ErrLoadShed) {
+		t.Errorf("Expected ErrLoadShed, got: %v", err)
+	}
+
+	release()
+	(<-acquired)()
+
+	// Queued loads honor context cancellation
+	release, _ = limiter.acquire(ctx)
+	defer release()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := limiter.acquire(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestLoadLimiterUnlimited(t *testing.T) {
+	limiter := newLoadLimiter(nil)
+	if limiter != nil {
+		t.Fatal("Expected nil limiter without MaxConcurrentLoads")
+	}
+
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	release()
+}
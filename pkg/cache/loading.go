@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// LoadingConfig holds configuration for loading caches.
+type LoadingConfig struct {
+	// LoadLimit bounds concurrent loader calls (default: unlimited)
+	LoadLimit *LoadLimitConfig
+}
+
+// GetOrSet returns the cached value for key. On a miss it calls load, stores
+// the result with ttl and returns it, replacing the Get-miss-Set dance.
+//
+// Caches implementing GetOrSetter (see NewLoading) handle the call natively;
+// for others, concurrent misses of the same key each call load. Errors from
+// load are returned as-is and nothing is stored. WithNoStore and WithMaxAge
+// directives on ctx skip or cap the write.
+func GetOrSet[T any](ctx context.Context, cache Cache[T], key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	if gs, ok := cache.(GetOrSetter[T]); ok {
+		return gs.GetOrSet(ctx, key, ttl, load)
+	}
+
+	if value, found := cache.Get(ctx, key); found {
+		return value, nil
+	}
+	return loadAndStore(ctx, cache, key, ttl, load)
+}
+
+// loadAndStore calls load and stores its result in cache.
+func loadAndStore[T any](ctx context.Context, cache Cache[T], key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	value, err := load(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	if ttl, store := directiveTTL(ctx, ttl); store {
+		// The value was loaded either way; a failed write only costs a future reload
+		_ = cache.Set(ctx, key, value, ttl)
+	}
+	return value, nil
+}
+
+// loadingCache deduplicates concurrent loads of the same key and bounds
+// concurrent loads overall.
+type loadingCache[T any] struct {
+	next    Cache[T]
+	group   singleflight.Group
+	limiter *loadLimiter
+}
+
+// NewLoading wraps a cache with a native GetOrSet: concurrent misses of the
+// same key share a single load, and loads beyond config.LoadLimit fail with
+// ErrLoadShed. Callers sharing a load also share the context and directives
+// of the caller that started it.
+func NewLoading[T any](cache Cache[T], config *LoadingConfig) Cache[T] {
+	c := &loadingCache[T]{next: cache}
+	if config != nil {
+		c.limiter = newLoadLimiter(config.LoadLimit)
+	}
+	return c
+}
+
+func (c *loadingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	if value, found := c.next.Get(ctx, key); found {
+		return value, nil
+	}
+
+	result, err, _ := c.group.Do(key, func() (interface{}, error) {
+		release, err := c.limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return loadAndStore(ctx, c.next, key, ttl, load)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, _ := result.(T)
+	return value, nil
+}
+
+func (c *loadingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *loadingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *loadingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *loadingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *loadingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *loadingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *loadingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *loadingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+// GetDel never loads: there is nothing to consume for a missing key.
+func (c *loadingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *loadingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *loadingCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *loadingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *loadingCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *loadingCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *loadingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *loadingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
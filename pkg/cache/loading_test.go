@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrSet(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	loads := 0
+	load := func(context.Context) (TestUser, error) {
+		loads++
+		return TestUser{ID: "123"}, nil
+	}
+
+	// Test a miss loads and stores, a hit doesn't load
+	for i := 0; i < 2; i++ {
+		user, err := GetOrSet(ctx, cache, "key1", time.Minute, load)
+		if err != nil || user.ID != "123" {
+			t.Fatalf("Unexpected result: %v, %v", user, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected 1 load, got %d", loads)
+	}
+
+	// Test loader errors are returned and nothing is stored
+	loadErr := errors.New("origin unavailable")
+	_, err := GetOrSet(ctx, cache, "key2", time.Minute, func(context.Context) (TestUser, error) {
+		return TestUser{}, loadErr
+	})
+	if !errors.Is(err, loadErr) {
+		t.Errorf("Expected loader error, got: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key2"); found {
+		t.Error("Expected nothing stored after a failed load")
+	}
+
+	// Test no-store loads without storing
+	_, _ = GetOrSet(WithNoStore(ctx), cache, "key3", time.Minute, load)
+	if _, found := cache.Get(ctx, "key3"); found {
+		t.Error("Expected no-store load not to be stored")
+	}
+}
+
+func TestLoadingCacheDeduplicatesLoads(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLoading(NewMemory[TestUser](nil), nil)
+	defer cache.Close()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (TestUser, error) {
+		loads.Add(1)
+		<-release
+		return TestUser{ID: "123"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if user, err := GetOrSet(ctx, cache, "key1", time.Minute, load); err != nil || user.ID != "123" {
+				t.Errorf("Unexpected result: %v, %v", user, err)
+			}
+		}()
+	}
+
+	// Give all callers time to join the in-flight load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("Expected 1 load, got %d", loads.Load())
+	}
+}
+
+func TestLoadingCacheShedsLoads(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLoading(NewMemory[TestUser](nil), &LoadingConfig{
+		LoadLimit: &LoadLimitConfig{MaxConcurrentLoads: 1},
+	})
+	defer cache.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = GetOrSet(ctx, cache, "key1", time.Minute, func(context.Context) (TestUser, error) {
+			close(started)
+			<-release
+			return TestUser{ID: "123"}, nil
+		})
+	}()
+	<-started
+
+	_, err := GetOrSet(ctx, cache, "key2", time.Minute, func(context.Context) (TestUser, error) {
+		return TestUser{ID: "456"}, nil
+	})
+	if !errors.Is(err, ErrLoadShed) {
+		t.Errorf("Expected ErrLoadShed, got: %v", err)
+	}
+	close(release)
+}
+
+func TestFactoryLoadingWithKeyFromContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), localeKey{}, "sv")
+	cache, err := New[TestUser](&Config{
+		Type:           TypeMemory,
+		Loading:        &LoadingConfig{},
+		KeyFromContext: localeFromContext,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_, err = GetOrSet(ctx, cache, "key1", time.Minute, func(context.Context) (TestUser, error) {
+		return TestUser{ID: "123"}, nil
+	})
+	if err != nil {
+		t.Fatalf("GetOrSet failed: %v", err)
+	}
+
+	// Test the loaded value is stored under the context-derived key
+	if _, found := cache.Get(context.Background(), "key1"); found {
+		t.Error("Expected the value not to be visible without the locale")
+	}
+	if _, found := cache.Get(ctx, "key1"); !found {
+		t.Error("Expected the value under the derived key")
+	}
+}
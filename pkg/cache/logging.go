@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LoggingConfig configures WithLogging.
+type LoggingConfig struct {
+	// Logger receives the records (default: slog.Default())
+	Logger *slog.Logger
+
+	// Name identifies the cache in the "cache" attribute (optional)
+	Name string
+
+	// SlowThreshold is the duration above which an operation is logged as
+	// slow (default: 0, slow operations aren't logged)
+	SlowThreshold time.Duration
+}
+
+// loggingCache logs failed and slow operations.
+type loggingCache[T any] struct {
+	next   Cache[T]
+	config LoggingConfig
+}
+
+// WithLogging wraps a cache so that failed operations are logged as warnings,
+// including the failures plain Get reports as misses, such as connection or
+// deserialization errors, and operations slower than SlowThreshold are
+// logged too. Failures a backend hides itself, according to its
+// DegradedPolicy, are logged by the backend when it has a Logger.
+func WithLogging[T any](cache Cache[T], config *LoggingConfig) Cache[T] {
+	c := &loggingCache[T]{next: cache}
+	if config != nil {
+		c.config = *config
+	}
+	if c.config.Logger == nil {
+		c.config.Logger = slog.Default()
+	}
+	return c
+}
+
+// observe logs the operation on key that started at start, if it failed or
+// was slow.
+func (c *loggingCache[T]) observe(ctx context.Context, operation Operation, key string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	slow := c.config.SlowThreshold > 0 && elapsed > c.config.SlowThreshold
+	if err == nil && !slow {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("cache", c.config.Name),
+		slog.String("operation", string(operation)),
+		slog.Duration("duration", elapsed),
+	}
+	if key != "" {
+		attrs = append(attrs, slog.String("key", key))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		c.config.Logger.LogAttrs(ctx, slog.LevelWarn, "cache: operation failed", attrs...)
+		return
+	}
+	c.config.Logger.LogAttrs(ctx, slog.LevelWarn, "cache: slow operation", attrs...)
+}
+
+func (c *loggingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *loggingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	start := time.Now()
+	value, found, err := GetWithError(ctx, c.next, key)
+	c.observe(ctx, OperationGet, key, start, err)
+	return value, found, err
+}
+
+// GetMulti logs every key that failed, and every key when the batch was slow.
+func (c *loggingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	start := time.Now()
+	results := GetMulti(ctx, c.next, keys...)
+	for _, r := range results {
+		c.observe(ctx, OperationGet, r.Key, start, r.Err)
+	}
+	return results
+}
+
+func (c *loggingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	start := time.Now()
+	value, err := GetOrSet(ctx, c.next, key, ttl, load)
+	c.observe(ctx, OperationGet, key, start, err)
+	return value, err
+}
+
+func (c *loggingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	start := time.Now()
+	exists, err := Exists(ctx, c.next, key)
+	c.observe(ctx, OperationGet, key, start, err)
+	return exists, err
+}
+
+func (c *loggingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	start := time.Now()
+	ttl, found, err := TTL(ctx, c.next, key)
+	c.observe(ctx, OperationGet, key, start, err)
+	return ttl, found, err
+}
+
+func (c *loggingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	start := time.Now()
+	err := Expire(ctx, c.next, key, ttl)
+	c.observe(ctx, OperationExpire, key, start, err)
+	return err
+}
+
+func (c *loggingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	c.observe(ctx, OperationSetIfAbsent, key, start, err)
+	return stored, err
+}
+
+func (c *loggingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	c.observe(ctx, OperationCompareAndSwap, key, start, err)
+	return swapped, err
+}
+
+func (c *loggingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	start := time.Now()
+	value, found, err := GetDel(ctx, c.next, key)
+	c.observe(ctx, OperationGetDel, key, start, err)
+	return value, found, err
+}
+
+func (c *loggingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	start := time.Now()
+	err := c.next.Set(ctx, key, value, ttl)
+	c.observe(ctx, OperationSet, key, start, err)
+	return err
+}
+
+func (c *loggingCache[T]) Delete(ctx context.Context, key string) error {
+	start := time.Now()
+	err := c.next.Delete(ctx, key)
+	c.observe(ctx, OperationDelete, key, start, err)
+	return err
+}
+
+func (c *loggingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	start := time.Now()
+	err := deleteMulti(ctx, c.next, keys)
+	c.observe(ctx, OperationDelete, "", start, err)
+	return err
+}
+
+func (c *loggingCache[T]) Clear(ctx context.Context) error {
+	start := time.Now()
+	err := Clear(ctx, c.next)
+	c.observe(ctx, OperationClear, "", start, err)
+	return err
+}
+
+func (c *loggingCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *loggingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *loggingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newBufferLogger returns a logger writing text records to the returned buffer.
+func newBufferLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, nil)), &buf
+}
+
+func TestWithLoggingLogsHiddenFailures(t *testing.T) {
+	ctx := context.Background()
+	logger, buf := newBufferLogger()
+	backend := NewMemory[TestUser](nil)
+	cache := WithLogging(backend, &LoggingConfig{Logger: logger, Name: "users"})
+	_ = backend.Close()
+
+	// Plain Get reports the closed cache as a miss
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Fatal("Expected a miss")
+	}
+	out := buf.String()
+	for _, want := range []string{"cache: operation failed", "cache=users", "operation=get", "key=1", ErrClosed.Error()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the log, got %q", want, out)
+		}
+	}
+}
+
+func TestWithLoggingLogsSlowOperations(t *testing.T) {
+	ctx := context.Background()
+	logger, buf := newBufferLogger()
+	cache := WithLogging(NewMemory[TestUser](nil), &LoggingConfig{Logger: logger, SlowThreshold: time.Nanosecond})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	if out := buf.String(); !strings.Contains(out, "cache: slow operation") || !strings.Contains(out, "operation=set") {
+		t.Errorf("Expected the slow Set to be logged, got %q", out)
+	}
+
+	buf.Reset()
+	quiet := WithLogging(NewMemory[TestUser](nil), &LoggingConfig{Logger: logger})
+	defer quiet.Close()
+	_ = quiet.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	quiet.Get(ctx, "1")
+	if buf.Len() != 0 {
+		t.Errorf("Expected successful operations not to be logged, got %q", buf.String())
+	}
+}
+
+func TestDegradedPolicyLogsHiddenFailures(t *testing.T) {
+	ctx := context.Background()
+	logger, buf := newBufferLogger()
+	cache := newUnreachableDistributedCache(t, DegradedPolicy{})
+	cache.degraded.logger = logger
+
+	if _, found, err := cache.GetWithError(ctx, "key1"); found || err != nil {
+		t.Fatalf("Expected plain miss, got found=%v err=%v", found, err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "cache: backend read failed") || !strings.Contains(out, "policy=miss") {
+		t.Errorf("Expected the backend failure to be logged, got %q", out)
+	}
+
+	buf.Reset()
+	cache.degraded.policy.OnBackendDown = BackendDownError
+	if _, _, err := cache.GetWithError(ctx, "key1"); err == nil {
+		t.Fatal("Expected the backend error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected reported failures not to be logged, got %q", buf.String())
+	}
+}
+
+func TestNewPassesLoggerToDistributed(t *testing.T) {
+	logger, _ := newBufferLogger()
+	own, _ := newBufferLogger()
+
+	if got := distributedConfigOf(&Config{Logger: logger, Distributed: &DistributedConfig{}}); got.Logger != logger {
+		t.Error("Expected the distributed config to get the cache's logger")
+	}
+	if got := distributedConfigOf(&Config{Logger: logger, Distributed: &DistributedConfig{Logger: own}}); got.Logger != own {
+		t.Error("Expected the distributed config to keep its own logger")
+	}
+	if got := distributedConfigOf(&Config{Logger: logger}); got != nil {
+		t.Errorf("Expected no distributed config, got %+v", got)
+	}
+}
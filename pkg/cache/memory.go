@@ -2,93 +2,576 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jellydator/ttlcache/v2"
 )
 
+// ErrNotMemory is returned by helpers that require an in-memory cache.
+var ErrNotMemory = errors.New("cache is not backed by an in-memory backend")
+
 // memoryCache is an in-memory cache implementation.
 type memoryCache[T any] struct {
-	config *MemoryConfig
-	cache  *ttlcache.Cache
+	config    *MemoryConfig
+	cache     *ttlcache.Cache
+	closed    closeGuard
+	counters  memoryCounters
+	stats     statsCounters
+	ctxPolicy contextPolicy
+
+	// condMu serializes SetIfAbsent, CompareAndSwap and GetDel, since
+	// ttlcache has no conditional writes
+	condMu sync.Mutex
+}
+
+// contextPolicy decides whether in-memory operations give up on a done
+// context.
+type contextPolicy struct {
+	ignoreCancellation bool
+}
+
+func newContextPolicy(config *MemoryConfig) contextPolicy {
+	return contextPolicy{ignoreCancellation: config != nil && config.HonorContextCancellation != nil && !*config.HonorContextCancellation}
+}
+
+// cancelled reports whether an operation should return early because ctx is
+// done.
+func (p contextPolicy) cancelled(ctx context.Context) bool {
+	return !p.ignoreCancellation && ctx.Err() != nil
+}
+
+// memoryItem is stored for every entry. ttlcache only reports the TTL an
+// entry was set with, so the item keeps its expiry to report the remaining
+// lifetime.
+type memoryItem[T any] struct {
+	value T
+	ttl   time.Duration
+	// setTTL is the TTL given to Set, which MemoryConfig.AdaptiveTTL adapts
+	// ttl from
+	setTTL time.Duration
+	// expiresAt is in unix nanoseconds, 0 for entries without expiry
+	expiresAt atomic.Int64
+	// hits is only counted when MemoryConfig.TrackHits or AdaptiveTTL is set
+	hits atomic.Uint64
+}
+
+func newMemoryItem[T any](value T, ttl time.Duration, now time.Time) *memoryItem[T] {
+	item := &memoryItem[T]{value: value, ttl: ttl}
+	item.touch(now)
+	return item
+}
+
+// touch restarts the lifetime of the item, like ttlcache does on a hit when
+// TTLs are extended.
+func (i *memoryItem[T]) touch(now time.Time) {
+	if i.ttl > 0 {
+		i.expiresAt.Store(now.Add(i.ttl).UnixNano())
+	}
+}
+
+// expired reports whether the lifetime of the item ended at now. ttlcache
+// may still hold the item when a scan extended its own expiry.
+func (i *memoryItem[T]) expired(now time.Time) bool {
+	expiresAt := i.expiresAt.Load()
+	return expiresAt != 0 && now.UnixNano() >= expiresAt
+}
+
+// remaining returns the lifetime left at now, 0 for entries without expiry.
+func (i *memoryItem[T]) remaining(now time.Time) time.Duration {
+	expiresAt := i.expiresAt.Load()
+	if expiresAt == 0 {
+		return 0
+	}
+	// Never report an entry ttlcache hasn't expired yet as not expiring
+	return max(time.Unix(0, expiresAt).Sub(now), time.Nanosecond)
+}
+
+// memoryEntry is a live entry of a memory cache with its remaining TTL
+// (0 for entries without expiry).
+type memoryEntry[T any] struct {
+	key   string
+	value T
+	ttl   time.Duration
+	hits  uint64
+}
+
+// memoryCacheOf returns the memory cache behind cache, looking through decorators.
+func memoryCacheOf[T any](cache Cache[T]) (*memoryCache[T], error) {
+	for cache != nil {
+		if mc, ok := cache.(*memoryCache[T]); ok {
+			return mc, nil
+		}
+		if tc, ok := cache.(*tieredCache[T]); ok {
+			cache = tc.l1
+			continue
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return nil, ErrNotMemory
 }
 
 // NewMemory creates a new in-memory cache with optional configuration.
 // This is a convenience function for creating memory caches directly.
 func NewMemory[T any](config *MemoryConfig) Cache[T] {
+	if config != nil {
+		switch config.Engine {
+		case MemoryEngineRistretto:
+			// Only fails on invalid settings, which newRistrettoCache never produces
+			if cache, err := newRistrettoCache[T](config); err == nil {
+				return cache
+			}
+		case MemoryEngineFreeCache:
+			return newFreeCache[T](config)
+		}
+	}
+
 	cache := ttlcache.NewCache()
 
 	if config != nil {
-		cache.SkipTTLExtensionOnHit(config.SkipTTLExtensionOnHit)
+		// Adaptive TTLs replace ttlcache's extension on hit
+		cache.SkipTTLExtensionOnHit(config.SkipTTLExtensionOnHit || config.AdaptiveTTL != nil)
+		if config.MaxEntries > 0 {
+			cache.SetCacheSizeLimit(config.MaxEntries)
+		}
 	} else {
 		// Default behavior: don't extend TTL on hit
 		cache.SkipTTLExtensionOnHit(true)
 	}
 
-	return &memoryCache[T]{
-		config: config,
-		cache:  cache,
+	c := &memoryCache[T]{
+		config:    config,
+		cache:     cache,
+		ctxPolicy: newContextPolicy(config),
 	}
+	if config != nil {
+		c.counters.onEvent = config.OnEvent
+	}
+	c.counters.watch(cache)
+	return c
 }
 
 func (c *memoryCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *memoryCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
 	var zero T
 
-	// Check if context is cancelled
-	select {
-	case <-ctx.Done():
-		return zero, false
-	default:
+	if c.ctxPolicy.cancelled(ctx) {
+		return zero, false, nil
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
 	}
 
 	if c.cache == nil {
-		return zero, false
+		return zero, false, nil
+	}
+
+	item, err := c.lookup(key)
+	c.stats.read(item != nil, err)
+	if err != nil || item == nil {
+		return zero, false, err
+	}
+
+	if c.config != nil && (c.config.TrackHits || c.config.AdaptiveTTL != nil) {
+		hits := item.hits.Add(1)
+		if c.config.AdaptiveTTL != nil {
+			c.adaptTTL(key, item, hits)
+		}
+	}
+	return item.value, true, nil
+}
+
+// lookup returns the item stored under key, or nil when it is missing.
+// Like ttlcache, it extends the lifetime of the item unless
+// MemoryConfig.SkipTTLExtensionOnHit is set.
+func (c *memoryCache[T]) lookup(key string) (*memoryItem[T], error) {
+	value, err := c.cache.Get(key)
+	if err != nil {
+		if errors.Is(err, ttlcache.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, closedErr(err)
+	}
+
+	item, ok := value.(*memoryItem[T])
+	now := time.Now()
+	if !ok || item.expired(now) {
+		return nil, nil
 	}
+	if c.extendsTTLOnHit() {
+		item.touch(now)
+	}
+	return item, nil
+}
 
+// peek returns the live item stored under key, or nil, without extending its
+// lifetime, for scans that must not keep every entry alive. ttlcache extends
+// its own expiry on a read when TTLs are extended, but lookup goes by the
+// item's.
+func (c *memoryCache[T]) peek(key string, now time.Time) *memoryItem[T] {
 	value, err := c.cache.Get(key)
 	if err != nil {
-		return zero, false
+		return nil
+	}
+	item, ok := value.(*memoryItem[T])
+	if !ok || item.expired(now) {
+		return nil
+	}
+	return item
+}
+
+func (c *memoryCache[T]) extendsTTLOnHit() bool {
+	return c.config != nil && !c.config.SkipTTLExtensionOnHit && c.config.AdaptiveTTL == nil
+}
+
+func (c *memoryCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
 	}
 
-	typedValue, ok := value.(T)
-	if !ok {
-		return zero, false
+	if c.closed.isClosed() {
+		return false, ErrClosed
 	}
 
-	return typedValue, true
+	if c.cache == nil {
+		return false, nil
+	}
+
+	item, err := c.lookup(key)
+	return item != nil, err
+}
+
+// TTL reports the lifetime left to key. Like Get, it extends the TTL of the
+// entry unless MemoryConfig.SkipTTLExtensionOnHit is set, reporting the
+// extended lifetime.
+func (c *memoryCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return 0, false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	if c.cache == nil {
+		return 0, false, nil
+	}
+
+	item, err := c.lookup(key)
+	if err != nil || item == nil {
+		return 0, false, err
+	}
+	return item.remaining(time.Now()), true, nil
+}
+
+// Expire stores the value of key again with ttl, since ttlcache can't change
+// the TTL of an entry in place; a Set racing with it may be overwritten.
+func (c *memoryCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.cache == nil {
+		return ErrKeyNotFound
+	}
+
+	item, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return ErrKeyNotFound
+	}
+
+	renewed := newMemoryItem(item.value, ttl, time.Now())
+	renewed.setTTL = ttl
+	renewed.hits.Store(item.hits.Load())
+	return closedErr(c.cache.SetWithTTL(key, renewed, ttl))
+}
+
+// SetIfAbsent stores value unless key is stored. Concurrent conditional
+// writes are serialized; a Set racing with it may be overwritten.
+func (c *memoryCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.cache == nil {
+		return false, nil
+	}
+
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+
+	item, err := c.lookup(key)
+	if err != nil || item != nil {
+		return false, err
+	}
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwap stores new if key holds a value equal to old, compared with
+// proto.Equal for proto messages and reflect.DeepEqual otherwise. Concurrent
+// conditional writes are serialized; a Set racing with it may be overwritten.
+func (c *memoryCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	if c.cache == nil {
+		return false, nil
+	}
+
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+
+	item, err := c.lookup(key)
+	if err != nil || item == nil || !valuesEqual(item.value, old) {
+		return false, err
+	}
+	if err := c.Set(ctx, key, new, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDel removes key and returns its value. Concurrent GetDel calls are
+// serialized, so only one of them gets the value.
+func (c *memoryCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.ctxPolicy.cancelled(ctx) {
+		return zero, false, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	if c.cache == nil {
+		return zero, false, nil
+	}
+
+	c.condMu.Lock()
+	defer c.condMu.Unlock()
+
+	item, err := c.lookup(key)
+	if err != nil || item == nil {
+		return zero, false, err
+	}
+	// The entry may have expired or been deleted since the lookup
+	if err := c.cache.Remove(key); err != nil {
+		if errors.Is(err, ttlcache.ErrNotFound) {
+			return zero, false, nil
+		}
+		return zero, false, closedErr(err)
+	}
+	return item.value, true, nil
 }
 
 func (c *memoryCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
-	// Check if context is cancelled
-	select {
-	case <-ctx.Done():
+	if c.ctxPolicy.cancelled(ctx) {
 		return ctx.Err()
-	default:
+	}
+
+	if c.closed.isClosed() {
+		c.counters.closedWrite(key)
+		return ErrClosed
 	}
 
 	if c.cache == nil {
 		return nil
 	}
 
-	return c.cache.SetWithTTL(key, value, ttl)
+	// Only looked up for the hook: concurrent first writes of a key may both
+	// miss it, which MemoryStats counts exactly
+	var replaced bool
+	if c.counters.onEvent != nil {
+		_, err := c.cache.Get(key)
+		replaced = err == nil
+	}
+
+	lifetime := ttl
+	if c.config != nil && c.config.AdaptiveTTL != nil && ttl > 0 {
+		lifetime = c.config.AdaptiveTTL.lifetime(ttl, 0)
+	}
+	item := newMemoryItem(value, lifetime, time.Now())
+	item.setTTL = ttl
+	err := closedErr(c.cache.SetWithTTL(key, item, lifetime))
+	c.stats.wrote(err)
+	if err != nil {
+		if errors.Is(err, ErrClosed) {
+			c.counters.closedWrite(key)
+		}
+		return err
+	}
+	c.counters.written(key, replaced)
+	return nil
 }
 
 func (c *memoryCache[T]) Delete(ctx context.Context, key string) error {
-	// Check if context is cancelled
-	select {
-	case <-ctx.Done():
+	if c.ctxPolicy.cancelled(ctx) {
 		return ctx.Err()
-	default:
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
 	}
 
 	if c.cache == nil {
 		return nil
 	}
 
-	return c.cache.Remove(key)
+	err := c.cache.Remove(key)
+	c.stats.deleted(1, ignoreNotFound(err))
+	return closedErr(err)
 }
 
+func (c *memoryCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.cache == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := ignoreNotFound(c.cache.Remove(key)); err != nil {
+			c.stats.deleted(len(keys), err)
+			return closedErr(err)
+		}
+	}
+	c.stats.deleted(len(keys), nil)
+	return nil
+}
+
+// ignoreNotFound hides the error ttlcache returns for removing a missing key.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, ttlcache.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (c *memoryCache[T]) Clear(ctx context.Context) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if c.cache == nil {
+		return nil
+	}
+
+	return closedErr(c.cache.Purge())
+}
+
+// Stats returns the traffic counters; evictions include expired entries.
+func (c *memoryCache[T]) Stats() CacheStats {
+	stats := c.stats.snapshot()
+	stats.Evictions = c.counters.evicted.Load() + c.counters.expired.Load()
+	return stats
+}
+
+// countKeys returns the number of entries ttlcache holds; expired entries
+// are removed as they expire.
+func (c *memoryCache[T]) countKeys(ctx context.Context) (int64, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return 0, ctx.Err()
+	}
+
+	if c.closed.isClosed() {
+		return 0, ErrClosed
+	}
+
+	if c.cache == nil {
+		return 0, nil
+	}
+	return int64(c.cache.Count()), nil
+}
+
+// hottest returns up to n live entries, most hit first when hits are tracked
+// and longest-lived first otherwise. Scanning neither counts hits nor
+// extends TTLs.
+func (c *memoryCache[T]) hottest(n int) []memoryEntry[T] {
+	if c.cache == nil {
+		return nil
+	}
+
+	keys := c.cache.GetKeys()
+	entries := make([]memoryEntry[T], 0, len(keys))
+	now := time.Now()
+	for _, key := range keys {
+		item := c.peek(key, now)
+		if item == nil {
+			// Expired or removed since GetKeys
+			continue
+		}
+		entries = append(entries, memoryEntry[T]{
+			key:   key,
+			value: item.value,
+			ttl:   item.remaining(now),
+			hits:  item.hits.Load(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].hits != entries[j].hits {
+			return entries[i].hits > entries[j].hits
+		}
+		// Entries without expiry (ttl 0) outlive everything else
+		if (entries[i].ttl == 0) != (entries[j].ttl == 0) {
+			return entries[i].ttl == 0
+		}
+		return entries[i].ttl > entries[j].ttl
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// Close releases the cache. It is idempotent and safe to call concurrently
+// with other operations, which fail with ErrClosed afterwards.
 func (c *memoryCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
 	if c.cache != nil {
 		return c.cache.Close()
 	}
@@ -110,3 +110,28 @@ func TestMemoryCacheContextCancellation(t *testing.T) {
 		t.Errorf("Expected context.Canceled, got: %v", err)
 	}
 }
+
+func TestMemoryCacheIgnoreContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	honor := false
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine, HonorContextCancellation: &honor})
+			defer cache.Close()
+
+			user := TestUser{ID: "123", Name: "John"}
+			if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+				t.Fatalf("Expected Set to ignore the cancelled context, got: %v", err)
+			}
+			_ = flushAll(ctx, cache)
+			if got, found := cache.Get(ctx, "key1"); !found || got != user {
+				t.Errorf("Expected Get to ignore the cancelled context, got %+v (found=%v)", got, found)
+			}
+			if err := cache.Delete(ctx, "key1"); err != nil {
+				t.Errorf("Expected Delete to ignore the cancelled context, got: %v", err)
+			}
+		})
+	}
+}
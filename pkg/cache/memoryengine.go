@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MemoryWorkload describes the traffic an in-memory cache is expected to
+// serve, for MemoryEngineRecommendation.
+type MemoryWorkload struct {
+	// ReadRatio is the fraction of operations that are reads, between 0 and
+	// 1; the rest are writes (default: 0.9)
+	ReadRatio float64
+
+	// Keys is the number of distinct keys, read and written uniformly
+	// (default: 10000)
+	Keys int
+
+	// ValueBytes is the size of the payload of each value (default: 128)
+	ValueBytes int
+
+	// Concurrency is the number of goroutines issuing operations
+	// (default: GOMAXPROCS)
+	Concurrency int
+
+	// Duration is how long each engine is measured (default: 200ms)
+	Duration time.Duration
+}
+
+// MemoryEngineResult reports how one engine performed under a workload.
+type MemoryEngineResult struct {
+	Engine MemoryEngine
+
+	// Ops is the number of operations completed
+	Ops int64
+
+	// OpsPerSecond is the throughput across all goroutines
+	OpsPerSecond float64
+
+	// AllocsPerOp and BytesPerOp are the heap allocations per operation,
+	// measured process-wide
+	AllocsPerOp float64
+	BytesPerOp  float64
+
+	// HitRatio is the fraction of reads that found their key; Ristretto's
+	// admission policy may reject writes, lowering it
+	HitRatio float64
+}
+
+// MemoryEngineReport holds the measurements of every engine and the one
+// recommended for the workload.
+type MemoryEngineReport struct {
+	// Workload is the measured workload, with defaults applied
+	Workload MemoryWorkload
+
+	// Results holds one result per engine, highest throughput first
+	Results []MemoryEngineResult
+
+	// Recommended is the engine with the highest throughput among those
+	// whose hit ratio is within 5 percentage points of the best one
+	Recommended MemoryEngine
+}
+
+// memoryEngineHitRatioTolerance is how much lower than the best hit ratio a
+// recommended engine's may be.
+const memoryEngineHitRatioTolerance = 0.05
+
+// engineBenchValue is the value measured engines store, a typical small
+// struct that FreeCache serializes as JSON.
+type engineBenchValue struct {
+	ID      string
+	Payload string
+}
+
+// MemoryEngineRecommendation measures every memory engine under workload
+// (defaults when nil) in this process and recommends one. Run it on the
+// hardware and GOMAXPROCS the service runs with, e.g. from a one-off command,
+// since results depend on both:
+//
+//	report, err := cache.MemoryEngineRecommendation(ctx, &cache.MemoryWorkload{
+//		ReadRatio: 0.95,
+//		Keys:      500_000,
+//	})
+//	config := &cache.MemoryConfig{Engine: report.Recommended}
+//
+// Measuring takes about Duration per engine and keeps the CPUs busy. Only
+// throughput and hit ratio decide; weigh AllocsPerOp too for services
+// sensitive to GC pauses, and the engines' semantics (see MemoryEngine).
+func MemoryEngineRecommendation(ctx context.Context, workload *MemoryWorkload) (MemoryEngineReport, error) {
+	var w MemoryWorkload
+	if workload != nil {
+		w = *workload
+	}
+	if w.ReadRatio <= 0 || w.ReadRatio > 1 {
+		w.ReadRatio = 0.9
+	}
+	if w.Keys <= 0 {
+		w.Keys = 10000
+	}
+	if w.ValueBytes <= 0 {
+		w.ValueBytes = 128
+	}
+	if w.Concurrency <= 0 {
+		w.Concurrency = runtime.GOMAXPROCS(0)
+	}
+	if w.Duration <= 0 {
+		w.Duration = 200 * time.Millisecond
+	}
+
+	report := MemoryEngineReport{Workload: w}
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		result, err := measureMemoryEngine(ctx, engine, w)
+		if err != nil {
+			return MemoryEngineReport{}, err
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	slices.SortFunc(report.Results, func(a, b MemoryEngineResult) int {
+		switch {
+		case a.OpsPerSecond > b.OpsPerSecond:
+			return -1
+		case a.OpsPerSecond < b.OpsPerSecond:
+			return 1
+		}
+		return 0
+	})
+	var bestHitRatio float64
+	for _, result := range report.Results {
+		bestHitRatio = max(bestHitRatio, result.HitRatio)
+	}
+	for _, result := range report.Results {
+		if result.HitRatio >= bestHitRatio-memoryEngineHitRatioTolerance {
+			report.Recommended = result.Engine
+			break
+		}
+	}
+	return report, nil
+}
+
+// measureMemoryEngine runs w against a fresh cache of engine, preloaded with
+// every key.
+func measureMemoryEngine(ctx context.Context, engine MemoryEngine, w MemoryWorkload) (MemoryEngineResult, error) {
+	config := &MemoryConfig{
+		Engine:     engine,
+		MaxEntries: w.Keys,
+		// Leave FreeCache room for every entry and its JSON overhead
+		SizeBytes: max(64<<20, 4*w.Keys*(w.ValueBytes+64)),
+	}
+	cache := NewMemory[engineBenchValue](config)
+	defer cache.Close()
+
+	keys := make([]string, w.Keys)
+	payload := strings.Repeat("x", w.ValueBytes)
+	for i := range keys {
+		keys[i] = "bench:" + strconv.Itoa(i)
+		if err := cache.Set(ctx, keys[i], engineBenchValue{ID: keys[i], Payload: payload}, 0); err != nil {
+			return MemoryEngineResult{}, err
+		}
+	}
+	if err := flushAll(ctx, cache); err != nil {
+		return MemoryEngineResult{}, err
+	}
+
+	var ops, reads, hits atomic.Int64
+	deadline := time.Now().Add(w.Duration)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for worker := range w.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every engine sees the same sequence of operations
+			rng := rand.New(rand.NewPCG(uint64(worker), 1))
+			var n, workerReads, workerHits int64
+			for ctx.Err() == nil {
+				// Checking the clock on every operation would dominate it
+				if n%256 == 0 && time.Now().After(deadline) {
+					break
+				}
+				key := keys[rng.IntN(len(keys))]
+				if rng.Float64() < w.ReadRatio {
+					workerReads++
+					if _, found := cache.Get(ctx, key); found {
+						workerHits++
+					}
+				} else {
+					_ = cache.Set(ctx, key, engineBenchValue{ID: key, Payload: payload}, 0)
+				}
+				n++
+			}
+			ops.Add(n)
+			reads.Add(workerReads)
+			hits.Add(workerHits)
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if err := ctx.Err(); err != nil {
+		return MemoryEngineResult{}, err
+	}
+
+	result := MemoryEngineResult{Engine: engine, Ops: ops.Load()}
+	if result.Ops > 0 {
+		n := float64(result.Ops)
+		result.OpsPerSecond = n / elapsed.Seconds()
+		result.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / n
+		result.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / n
+	}
+	if r := reads.Load(); r > 0 {
+		result.HitRatio = float64(hits.Load()) / float64(r)
+	}
+	return result, nil
+}
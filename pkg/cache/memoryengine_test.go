@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryEngineRecommendation(t *testing.T) {
+	ctx := context.Background()
+
+	report, err := MemoryEngineRecommendation(ctx, &MemoryWorkload{
+		Keys:        1000,
+		Concurrency: 2,
+		Duration:    20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("MemoryEngineRecommendation failed: %v", err)
+	}
+
+	if report.Workload.ReadRatio != 0.9 || report.Workload.ValueBytes != 128 {
+		t.Errorf("Expected defaults to be applied, got %+v", report.Workload)
+	}
+	if len(report.Results) != 3 {
+		t.Fatalf("Expected a result per engine, got %+v", report.Results)
+	}
+	for i, result := range report.Results {
+		if result.Ops == 0 || result.OpsPerSecond <= 0 {
+			t.Errorf("Expected %s to complete operations, got %+v", result.Engine, result)
+		}
+		if i > 0 && result.OpsPerSecond > report.Results[i-1].OpsPerSecond {
+			t.Errorf("Expected results ordered by throughput, got %+v", report.Results)
+		}
+	}
+	if report.Recommended == "" {
+		t.Error("Expected an engine to be recommended")
+	}
+}
+
+func TestMemoryEngineRecommendationCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := MemoryEngineRecommendation(ctx, &MemoryWorkload{Keys: 10}); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
+
+// BenchmarkMemoryEngines compares the engines on read-heavy, mixed and
+// write-heavy workloads over 10k keys:
+//
+//	go test ./pkg/cache -run '^$' -bench MemoryEngines -benchmem
+func BenchmarkMemoryEngines(b *testing.B) {
+	ctx := context.Background()
+	workloads := []struct {
+		name      string
+		readRatio float64
+	}{
+		{"read-heavy", 0.95},
+		{"mixed", 0.5},
+		{"write-heavy", 0.1},
+	}
+
+	const keyCount = 10000
+	keys := make([]string, keyCount)
+	for i := range keys {
+		keys[i] = "bench:" + strconv.Itoa(i)
+	}
+	value := engineBenchValue{ID: "bench", Payload: strings.Repeat("x", 128)}
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		for _, workload := range workloads {
+			b.Run(string(engine)+"/"+workload.name, func(b *testing.B) {
+				cache := NewMemory[engineBenchValue](&MemoryConfig{Engine: engine, MaxEntries: keyCount})
+				defer cache.Close()
+				for _, key := range keys {
+					_ = cache.Set(ctx, key, value, 0)
+				}
+				_ = flushAll(ctx, cache)
+
+				// Every 100 operations read readRatio*100 times
+				reads := int(workload.readRatio * 100)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						key := keys[i%keyCount]
+						if i%100 < reads {
+							_, _ = cache.Get(ctx, key)
+						} else {
+							_ = cache.Set(ctx, key, value, 0)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"sync/atomic"
+
+	"github.com/jellydator/ttlcache/v2"
+)
+
+// MemoryEventType identifies a condition inside a ttlcache-backed memory cache.
+type MemoryEventType string
+
+const (
+	// MemoryEventReplaced means a Set overwrote a live entry.
+	MemoryEventReplaced MemoryEventType = "replaced"
+	// MemoryEventEvicted means an entry was evicted to stay within MaxEntries.
+	MemoryEventEvicted MemoryEventType = "evicted"
+	// MemoryEventExpired means an expired entry was removed.
+	MemoryEventExpired MemoryEventType = "expired"
+	// MemoryEventClosedWrite means a Set failed because the cache was closed.
+	MemoryEventClosedWrite MemoryEventType = "closed_write"
+)
+
+// MemoryEvent reports a condition of one key of a memory cache.
+type MemoryEvent struct {
+	Type MemoryEventType
+	Key  string
+}
+
+// MemoryStats reports what happened inside a ttlcache-backed memory cache,
+// which is otherwise invisible: entries silently replaced, evicted or
+// expired, and writes lost to a closed cache.
+type MemoryStats struct {
+	// Entries is the number of entries currently stored, including expired
+	// ones not removed yet.
+	Entries int
+	// Replaced counts Sets that overwrote a live entry.
+	Replaced uint64
+	// Evicted counts entries evicted to stay within MaxEntries.
+	Evicted uint64
+	// Expired counts expired entries removed.
+	Expired uint64
+	// ClosedWrites counts Sets that failed because the cache was closed.
+	ClosedWrites uint64
+}
+
+// memoryCounters accumulates MemoryStats and forwards events to the hook.
+type memoryCounters struct {
+	onEvent  func(MemoryEvent)
+	expiries expiryListeners
+
+	inserted     atomic.Uint64
+	created      atomic.Uint64
+	evicted      atomic.Uint64
+	expired      atomic.Uint64
+	closedWrites atomic.Uint64
+}
+
+// watch subscribes the counters to the events of cache.
+func (m *memoryCounters) watch(cache *ttlcache.Cache) {
+	cache.SetNewItemCallback(func(string, interface{}) {
+		m.created.Add(1)
+	})
+	// ttlcache calls it on its own goroutine
+	cache.SetExpirationReasonCallback(func(key string, reason ttlcache.EvictionReason, _ interface{}) {
+		var eventType MemoryEventType
+		switch reason {
+		case ttlcache.EvictedSize:
+			m.evicted.Add(1)
+			eventType = MemoryEventEvicted
+		case ttlcache.Expired:
+			m.expired.Add(1)
+			eventType = MemoryEventExpired
+			m.expiries.notify(key)
+		default:
+			return
+		}
+		m.emit(eventType, key)
+		traceKey(KeyEvent{Key: key, Operation: OperationEvict, Source: "memory", Reason: string(eventType)})
+	})
+}
+
+// written counts a stored Set; replaced is only known when events are hooked.
+func (m *memoryCounters) written(key string, replaced bool) {
+	m.inserted.Add(1)
+	if replaced {
+		m.emit(MemoryEventReplaced, key)
+	}
+}
+
+func (m *memoryCounters) closedWrite(key string) {
+	m.closedWrites.Add(1)
+	m.emit(MemoryEventClosedWrite, key)
+}
+
+func (m *memoryCounters) emit(eventType MemoryEventType, key string) {
+	if m.onEvent != nil {
+		m.onEvent(MemoryEvent{Type: eventType, Key: key})
+	}
+}
+
+// MemoryStatsOf returns the statistics of the ttlcache-backed memory cache
+// behind cache, looking through decorators (the L1 of tiered caches).
+// Returns ErrNotMemory for other caches, including the Ristretto and
+// FreeCache engines.
+func MemoryStatsOf[T any](cache Cache[T]) (MemoryStats, error) {
+	mc, err := memoryCacheOf(cache)
+	if err != nil {
+		return MemoryStats{}, err
+	}
+
+	stats := MemoryStats{
+		Evicted:      mc.counters.evicted.Load(),
+		Expired:      mc.counters.expired.Load(),
+		ClosedWrites: mc.counters.closedWrites.Load(),
+	}
+	// ttlcache counts a new entry before Set counts the write, so a
+	// concurrent snapshot may briefly see more new entries than writes
+	if inserted, created := mc.counters.inserted.Load(), mc.counters.created.Load(); inserted > created {
+		stats.Replaced = inserted - created
+	}
+	if mc.cache != nil && !mc.closed.isClosed() {
+		stats.Entries = mc.cache.Count()
+	}
+	return stats, nil
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryEventLog collects MemoryEvents reported from any goroutine.
+type memoryEventLog struct {
+	mu     sync.Mutex
+	events []MemoryEvent
+}
+
+func (l *memoryEventLog) record(event MemoryEvent) {
+	l.mu.Lock()
+	l.events = append(l.events, event)
+	l.mu.Unlock()
+}
+
+func (l *memoryEventLog) has(want MemoryEvent) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, event := range l.events {
+		if event == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMemoryEvents(t *testing.T) {
+	ctx := context.Background()
+	var log memoryEventLog
+	cache := NewMemory[TestUser](&MemoryConfig{SkipTTLExtensionOnHit: true, MaxEntries: 2, OnEvent: log.record})
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "a", TestUser{ID: "a2"}, time.Minute)
+	if !log.has(MemoryEvent{Type: MemoryEventReplaced, Key: "a"}) {
+		t.Error("Expected a replaced event for a")
+	}
+
+	// Test the entry closest to expiring is evicted beyond MaxEntries
+	_ = cache.Set(ctx, "short", TestUser{ID: "s"}, 10*time.Second)
+	_ = cache.Set(ctx, "b", TestUser{ID: "b"}, time.Minute)
+	waitFor(t, func() bool { return log.has(MemoryEvent{Type: MemoryEventEvicted, Key: "short"}) })
+
+	// Test expiry
+	_ = cache.Set(ctx, "b", TestUser{ID: "b"}, 10*time.Millisecond)
+	waitFor(t, func() bool { return log.has(MemoryEvent{Type: MemoryEventExpired, Key: "b"}) })
+
+	stats, err := MemoryStatsOf(cache)
+	if err != nil {
+		t.Fatalf("MemoryStatsOf failed: %v", err)
+	}
+	if stats.Replaced != 2 || stats.Evicted != 1 || stats.Expired != 1 || stats.Entries != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Test writes to a closed cache
+	_ = cache.Close()
+	if err := cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Expected ErrClosed, got: %v", err)
+	}
+	if !log.has(MemoryEvent{Type: MemoryEventClosedWrite, Key: "a"}) {
+		t.Error("Expected a closed write event")
+	}
+	if stats, _ := MemoryStatsOf(cache); stats.ClosedWrites != 1 {
+		t.Errorf("Expected one closed write, got %+v", stats)
+	}
+}
+
+func TestMemoryStatsWithoutHook(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTiered(NewMemory[TestUser](nil), NewMemory[TestUser](nil), nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "b", TestUser{ID: "b"}, time.Minute)
+
+	stats, err := MemoryStatsOf(cache)
+	if err != nil {
+		t.Fatalf("MemoryStatsOf failed: %v", err)
+	}
+	if stats.Replaced != 1 || stats.Entries != 2 {
+		t.Errorf("Expected the L1 stats, got %+v", stats)
+	}
+}
+
+func TestMemoryStatsOfOtherCaches(t *testing.T) {
+	cache := NewMemory[TestUser](&MemoryConfig{Engine: MemoryEngineFreeCache})
+	defer cache.Close()
+
+	if _, err := MemoryStatsOf(cache); !errors.Is(err, ErrNotMemory) {
+		t.Errorf("Expected ErrNotMemory, got: %v", err)
+	}
+}
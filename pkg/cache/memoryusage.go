@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errEnoughKeys stops the SCAN of SampleMemoryUsage once MaxKeys were measured.
+var errEnoughKeys = errors.New("enough keys sampled")
+
+// MemoryUsageConfig holds configuration for SampleMemoryUsage.
+type MemoryUsageConfig struct {
+	// Pattern restricts sampling to keys matching a SCAN MATCH pattern
+	// (default: every key under the cache's key prefix)
+	Pattern string
+
+	// MaxKeys bounds the number of keys inspected (default: 100)
+	MaxKeys int
+
+	// Samples is passed to MEMORY USAGE SAMPLES for nested values
+	// (default: 0, the server default)
+	Samples int
+
+	// TopN is the number of largest keys reported (default: 10)
+	TopN int
+}
+
+// KeyMemoryUsage is the server-side memory used by a single key.
+type KeyMemoryUsage struct {
+	Key   string
+	Bytes int64
+}
+
+// MemoryUsageStats aggregates MEMORY USAGE over a sample of keys.
+type MemoryUsageStats struct {
+	// Keys is the number of keys measured.
+	Keys int
+
+	// TotalBytes is the sum of the measured sizes.
+	TotalBytes int64
+
+	// MinBytes, MaxBytes and MeanBytes describe the size distribution.
+	MinBytes  int64
+	MaxBytes  int64
+	MeanBytes int64
+
+	// P50, P90 and P99 are size percentiles in bytes (nearest rank).
+	P50 int64
+	P90 int64
+	P99 int64
+
+	// Largest lists the biggest keys, largest first.
+	Largest []KeyMemoryUsage
+}
+
+// SampleMemoryUsage measures MEMORY USAGE for a bounded set of keys in the
+// Redis/Valkey namespace behind cache, showing which caches consume server memory.
+// Returns an error if cache is not backed by a distributed backend.
+func SampleMemoryUsage[T any](ctx context.Context, cache Cache[T], config *MemoryUsageConfig) (MemoryUsageStats, error) {
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return MemoryUsageStats{}, err
+	}
+
+	var cfg MemoryUsageConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Pattern == "" {
+		cfg.Pattern = globReplacer.Replace(keyPrefixOf(cache)) + "*"
+	}
+	ensureMemoryUsageDefaults(&cfg)
+
+	usages := make([]KeyMemoryUsage, 0, cfg.MaxKeys)
+	scanned := 0
+	err = scanRedis(ctx, client, cfg.Pattern, int64(cfg.MaxKeys), func(keys []string) error {
+		keys = keys[:min(len(keys), cfg.MaxKeys-scanned)]
+		scanned += len(keys)
+
+		pipe := client.Pipeline()
+		cmds := make([]*redis.IntCmd, len(keys))
+		for i, key := range keys {
+			if cfg.Samples > 0 {
+				cmds[i] = pipe.MemoryUsage(ctx, key, cfg.Samples)
+			} else {
+				cmds[i] = pipe.MemoryUsage(ctx, key)
+			}
+		}
+		// Keys expiring since SCAN returned them fail alone and are skipped
+		_, _ = pipe.Exec(ctx)
+		for i, key := range keys {
+			if size, err := cmds[i].Result(); err == nil {
+				usages = append(usages, KeyMemoryUsage{Key: key, Bytes: size})
+			}
+		}
+
+		if scanned >= cfg.MaxKeys {
+			return errEnoughKeys
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughKeys) {
+		return MemoryUsageStats{}, err
+	}
+
+	return newMemoryUsageStats(usages, cfg.TopN), nil
+}
+
+func ensureMemoryUsageDefaults(config *MemoryUsageConfig) {
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = 100
+	}
+	if config.TopN == 0 {
+		config.TopN = 10
+	}
+}
+
+func newMemoryUsageStats(usages []KeyMemoryUsage, topN int) MemoryUsageStats {
+	stats := MemoryUsageStats{Keys: len(usages)}
+	if len(usages) == 0 {
+		return stats
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Bytes > usages[j].Bytes
+	})
+	for _, u := range usages {
+		stats.TotalBytes += u.Bytes
+	}
+
+	n := len(usages)
+	stats.MaxBytes = usages[0].Bytes
+	stats.MinBytes = usages[n-1].Bytes
+	stats.MeanBytes = stats.TotalBytes / int64(n)
+
+	// usages is sorted descending, so the p-th percentile sits counting from the end
+	percentile := func(p int) int64 {
+		rank := (p*n + 99) / 100
+		return usages[n-rank].Bytes
+	}
+	stats.P50 = percentile(50)
+	stats.P90 = percentile(90)
+	stats.P99 = percentile(99)
+
+	if topN > n {
+		topN = n
+	}
+	stats.Largest = append([]KeyMemoryUsage(nil), usages[:topN]...)
+	return stats
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryUsageStats(t *testing.T) {
+	usages := make([]KeyMemoryUsage, 0, 100)
+	for i := 1; i <= 100; i++ {
+		usages = append(usages, KeyMemoryUsage{Key: fmt.Sprintf("key:%d", i), Bytes: int64(i * 10)})
+	}
+
+	stats := newMemoryUsageStats(usages, 3)
+
+	if stats.Keys != 100 || stats.TotalBytes != 50500 {
+		t.Errorf("Unexpected totals: %d keys, %d bytes", stats.Keys, stats.TotalBytes)
+	}
+	if stats.MinBytes != 10 || stats.MaxBytes != 1000 || stats.MeanBytes != 505 {
+		t.Errorf("Unexpected min/max/mean: %d/%d/%d", stats.MinBytes, stats.MaxBytes, stats.MeanBytes)
+	}
+	if stats.P50 != 500 || stats.P90 != 900 || stats.P99 != 990 {
+		t.Errorf("Unexpected percentiles: p50=%d p90=%d p99=%d", stats.P50, stats.P90, stats.P99)
+	}
+	if len(stats.Largest) != 3 || stats.Largest[0].Key != "key:100" {
+		t.Errorf("Unexpected largest keys: %v", stats.Largest)
+	}
+
+	if empty := newMemoryUsageStats(nil, 3); empty.Keys != 0 || empty.Largest != nil {
+		t.Errorf("Expected empty stats, got: %+v", empty)
+	}
+}
+
+func TestSampleMemoryUsageRequiresDistributedCache(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_, err := SampleMemoryUsage(context.Background(), cache, nil)
+	if !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestSampleMemoryUsageWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		_ = cache.Set(ctx, fmt.Sprintf("users:%d", i), TestUser{ID: "123"}, time.Hour)
+	}
+	_ = cache.Set(ctx, "other", TestUser{ID: "123"}, time.Hour)
+
+	stats, err := SampleMemoryUsage(ctx, cache, &MemoryUsageConfig{Pattern: "users:*"})
+	if err != nil {
+		t.Fatalf("SampleMemoryUsage failed: %v", err)
+	}
+	if stats.Keys != 20 {
+		t.Errorf("Expected 20 keys, got %d", stats.Keys)
+	}
+	if stats.TotalBytes <= 0 || stats.P50 <= 0 {
+		t.Errorf("Expected positive sizes, got: %+v", stats)
+	}
+}
+
+func TestSampleMemoryUsagePrefixedWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	distributed, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer distributed.Close()
+	cache := WithPrefix(distributed, "users:")
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		_ = cache.Set(ctx, fmt.Sprint(i), TestUser{ID: "123"}, time.Hour)
+	}
+	_ = distributed.Set(ctx, "other", TestUser{ID: "123"}, time.Hour)
+
+	// Test the default pattern covers the cache's namespace only
+	stats, err := SampleMemoryUsage(ctx, cache, nil)
+	if err != nil {
+		t.Fatalf("SampleMemoryUsage failed: %v", err)
+	}
+	if stats.Keys != 20 {
+		t.Errorf("Expected 20 keys, got %d", stats.Keys)
+	}
+
+	// Test MaxKeys bounds the keys measured
+	if stats, err := SampleMemoryUsage(ctx, cache, &MemoryUsageConfig{MaxKeys: 5}); err != nil || stats.Keys != 5 {
+		t.Errorf("Expected 5 keys, got %d (err=%v)", stats.Keys, err)
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+// Middleware adds cross-cutting behavior to a cache by wrapping it, like the
+// With... decorators of this package.
+type Middleware[T any] func(Cache[T]) Cache[T]
+
+// Wrap applies middlewares to cache, the first one outermost:
+//
+//	cache.Wrap(c, logging, metrics, prefixing)
+//
+// is logging(metrics(prefixing(c))), so logging sees operations as the
+// caller issues them. Nil middlewares are skipped. Optional interfaces such
+// as GetOrSetter and HealthChecker keep working through the built-in
+// middlewares.
+func Wrap[T any](cache Cache[T], middlewares ...Middleware[T]) Cache[T] {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			cache = middlewares[i](cache)
+		}
+	}
+	return cache
+}
+
+// Chain combines middlewares into one, applied in the same order as by Wrap,
+// e.g. to share an organization's standard stack.
+func Chain[T any](middlewares ...Middleware[T]) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return Wrap(cache, middlewares...)
+	}
+}
+
+// PrefixMiddleware is WithPrefix as a Middleware.
+func PrefixMiddleware[T any](prefix string) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return WithPrefix(cache, prefix)
+	}
+}
+
+// LoggingMiddleware is WithLogging as a Middleware.
+func LoggingMiddleware[T any](config *LoggingConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return WithLogging(cache, config)
+	}
+}
+
+// SpansMiddleware is WithSpans as a Middleware.
+func SpansMiddleware[T any](config *SpanConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return WithSpans(cache, config)
+	}
+}
+
+// FreezeMiddleware is WithFreeze as a Middleware.
+func FreezeMiddleware[T any](freeze func(T) T) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return WithFreeze(cache, freeze)
+	}
+}
+
+// SingleflightMiddleware is NewLoading as a Middleware: concurrent GetOrSet
+// misses of the same key share one load.
+func SingleflightMiddleware[T any](config *LoadingConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return NewLoading(cache, config)
+	}
+}
+
+// RetryMiddleware is NewRetrying as a Middleware.
+func RetryMiddleware[T any](config *RetryConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return NewRetrying(cache, config)
+	}
+}
+
+// SheddingMiddleware is NewShedding as a Middleware.
+func SheddingMiddleware[T any](config *SheddingConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return NewShedding(cache, config)
+	}
+}
+
+// ReadThroughMiddleware is NewReadThrough as a Middleware.
+func ReadThroughMiddleware[T any](loader Loader[T], config *ReadThroughConfig) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return NewReadThrough(cache, loader, config)
+	}
+}
+
+// WriteThroughMiddleware is NewWriteThrough as a Middleware.
+func WriteThroughMiddleware[T any](store Store[T]) Middleware[T] {
+	return func(cache Cache[T]) Cache[T] {
+		return NewWriteThrough(cache, store)
+	}
+}
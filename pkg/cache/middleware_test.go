@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namedMiddleware records the order in which its layer sees Get calls.
+func namedMiddleware(name string, calls *[]string) Middleware[TestUser] {
+	return func(next Cache[TestUser]) Cache[TestUser] {
+		return &funcCache[TestUser]{Cache: next, get: func(ctx context.Context, key string) (TestUser, bool) {
+			*calls = append(*calls, name)
+			return next.Get(ctx, key)
+		}}
+	}
+}
+
+// funcCache overrides Get of the wrapped cache.
+type funcCache[T any] struct {
+	Cache[T]
+	get func(ctx context.Context, key string) (T, bool)
+}
+
+func (c *funcCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.get(ctx, key)
+}
+
+func TestWrapOrder(t *testing.T) {
+	var calls []string
+	cache := Wrap(NewMemory[TestUser](nil),
+		namedMiddleware("outer", &calls),
+		nil,
+		Chain(namedMiddleware("middle", &calls), namedMiddleware("inner", &calls)),
+	)
+	defer cache.Close()
+
+	cache.Get(context.Background(), "1")
+	if len(calls) != 3 || calls[0] != "outer" || calls[1] != "middle" || calls[2] != "inner" {
+		t.Errorf("Expected outer, middle, inner, got %v", calls)
+	}
+}
+
+func TestBuiltInMiddlewares(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	metrics, err := PrometheusMiddleware[TestUser](registry, "users")
+	if err != nil {
+		t.Fatalf("PrometheusMiddleware failed: %v", err)
+	}
+	logger, _ := newBufferLogger()
+
+	backend := NewMemory[TestUser](nil)
+	cache := Wrap(backend,
+		LoggingMiddleware[TestUser](&LoggingConfig{Logger: logger}),
+		metrics,
+		SpansMiddleware[TestUser](nil),
+		SingleflightMiddleware[TestUser](nil),
+		PrefixMiddleware[TestUser]("users:"),
+	)
+	defer cache.Close()
+
+	var loads atomic.Int32
+	for range 2 {
+		_, err := GetOrSet(ctx, cache, "1", time.Minute, func(context.Context) (TestUser, error) {
+			loads.Add(1)
+			return TestUser{ID: "1"}, nil
+		})
+		if err != nil {
+			t.Fatalf("GetOrSet failed: %v", err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Errorf("Expected one load, got %d", loads.Load())
+	}
+	if _, found := backend.Get(ctx, "users:1"); !found {
+		t.Error("Expected the value under the prefixed key")
+	}
+	if got := gatheredValue(t, registry, "cache_operations_total", map[string]string{"operation": "get", "result": "hit"}); got != 1 {
+		t.Errorf("Expected 1 recorded hit, got %v", got)
+	}
+	if _, err := PrometheusMiddleware[TestUser](registry, "users"); err == nil {
+		t.Error("Expected registering the name twice to fail")
+	}
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyResult is the outcome of a batch read for one key: a value (Found), a
+// miss, or a failure with its cause in Err.
+type KeyResult[T any] struct {
+	Key   string
+	Value T
+	Found bool
+	Err   error
+}
+
+// KeyError is the failure of a batch write for one key.
+type KeyError struct {
+	Key string
+	Err error
+}
+
+// BatchError reports the keys a batch operation failed for; the other keys
+// succeeded. Use errors.As to retry or log the failures.
+type BatchError struct {
+	Failed []KeyError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Failed) == 1 {
+		return fmt.Sprintf("batch failed for key %q: %v", e.Failed[0].Key, e.Failed[0].Err)
+	}
+	return fmt.Sprintf("batch failed for %d keys, first %q: %v", len(e.Failed), e.Failed[0].Key, e.Failed[0].Err)
+}
+
+// Unwrap returns the errors of every failed key, so errors.Is matches
+// causes such as ErrClosed.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, failed := range e.Failed {
+		errs[i] = failed.Err
+	}
+	return errs
+}
+
+// GetMulti reads keys from cache, reporting a value, a miss or an error for
+// each key in the order given, so callers can use the keys that were read
+// while retrying or logging the ones that failed:
+//
+//	for _, r := range cache.GetMulti(ctx, users, ids...) {
+//		switch {
+//		case r.Err != nil:
+//			log.Printf("reading %s: %v", r.Key, r.Err)
+//		case r.Found:
+//			found[r.Key] = r.Value
+//		}
+//	}
+//
+// Distributed caches read every key in one pipelined round trip, decoding
+// each reply on its own; caches that don't implement MultiGetter are read
+// with one GetWithError per key.
+func GetMulti[T any](ctx context.Context, cache Cache[T], keys ...string) []KeyResult[T] {
+	if mg, ok := cache.(MultiGetter[T]); ok {
+		return mg.GetMulti(ctx, keys...)
+	}
+
+	results := make([]KeyResult[T], len(keys))
+	for i, key := range keys {
+		value, found, err := GetWithError(ctx, cache, key)
+		results[i] = KeyResult[T]{Key: key, Value: value, Found: found, Err: err}
+	}
+	return results
+}
+
+// keyResults returns results for keys that all ended with err, or all
+// missed for a nil err.
+func keyResults[T any](keys []string, err error) []KeyResult[T] {
+	results := make([]KeyResult[T], len(keys))
+	for i, key := range keys {
+		results[i] = KeyResult[T]{Key: key, Err: err}
+	}
+	return results
+}
+
+// getPipelined reads keys with one pipelined GET each, so keys of different
+// cluster slots can be read together and fail on their own, and turns every
+// reply into a result with decode.
+func getPipelined[T any](ctx context.Context, client redis.UniversalClient, keys []string,
+	decode func(ctx context.Context, key string, data []byte, err error) (T, bool, error)) []KeyResult[T] {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipe := client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+	// Errors are reported per key below
+	_, _ = pipe.Exec(ctx)
+
+	results := make([]KeyResult[T], len(keys))
+	for i, key := range keys {
+		data, err := cmds[i].Bytes()
+		value, found, err := decode(ctx, key, data, err)
+		results[i] = KeyResult[T]{Key: key, Value: value, Found: found, Err: err}
+	}
+	return results
+}
+
+// batchError returns a *BatchError for the keys whose errs entry is
+// non-nil, or nil if there are none.
+func batchError(keys []string, errs []error) error {
+	var failed []KeyError
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, KeyError{Key: keys[i], Err: err})
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BatchError{Failed: failed}
+}
@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var errKeyBroken = errors.New("key broken")
+
+// brokenKeysCache fails reads and deletes of the keys in broken, hiding the
+// optional interfaces of the cache it embeds.
+type brokenKeysCache[T any] struct {
+	Cache[T]
+	broken map[string]bool
+}
+
+func (c *brokenKeysCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if c.broken[key] {
+		var zero T
+		return zero, false, errKeyBroken
+	}
+	value, found := c.Cache.Get(ctx, key)
+	return value, found, nil
+}
+
+func (c *brokenKeysCache[T]) Delete(ctx context.Context, key string) error {
+	if c.broken[key] {
+		return errKeyBroken
+	}
+	return c.Cache.Delete(ctx, key)
+}
+
+// batchCountingCache records the batches read through GetMulti, hiding the
+// optional interfaces of the cache it embeds.
+type batchCountingCache[T any] struct {
+	Cache[T]
+	batches [][]string
+}
+
+func (c *batchCountingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	c.batches = append(c.batches, keys)
+	results := make([]KeyResult[T], len(keys))
+	for i, key := range keys {
+		value, found := c.Cache.Get(ctx, key)
+		results[i] = KeyResult[T]{Key: key, Value: value, Found: found}
+	}
+	return results
+}
+
+func TestGetMultiReportsEachKey(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := &brokenKeysCache[TestUser]{Cache: inner, broken: map[string]bool{"b": true}}
+	defer cache.Close()
+
+	_ = inner.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = inner.Set(ctx, "b", TestUser{ID: "b"}, time.Minute)
+
+	results := GetMulti[TestUser](ctx, cache, "a", "b", "c")
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %+v", results)
+	}
+	if r := results[0]; r.Key != "a" || !r.Found || r.Value.ID != "a" || r.Err != nil {
+		t.Errorf("Expected a hit for a, got %+v", r)
+	}
+	if r := results[1]; r.Key != "b" || r.Found || !errors.Is(r.Err, errKeyBroken) {
+		t.Errorf("Expected a failure for b, got %+v", r)
+	}
+	if r := results[2]; r.Key != "c" || r.Found || r.Err != nil {
+		t.Errorf("Expected a miss for c, got %+v", r)
+	}
+}
+
+func TestGetMultiPrefix(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	cache := WithPrefix(backend, "p:")
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+
+	results := GetMulti(ctx, cache, "a", "b")
+	if results[0].Key != "a" || !results[0].Found || results[1].Key != "b" || results[1].Found {
+		t.Errorf("Expected a hit for a and a miss for b without the prefix, got %+v", results)
+	}
+}
+
+func TestGetMultiThroughDecoratorsAndTiers(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := &batchCountingCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	cache := NewRetrying(WithMaxKeyLength(NewTiered(l1, l2, nil), 8, KeyLengthReject), nil)
+	defer cache.Close()
+
+	_ = l1.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = l2.Set(ctx, "b", TestUser{ID: "b"}, time.Minute)
+
+	results := GetMulti(ctx, cache, "a", "b", "c", "too-long-key")
+	if len(l2.batches) != 1 || len(l2.batches[0]) != 2 || l2.batches[0][0] != "b" || l2.batches[0][1] != "c" {
+		t.Fatalf("Expected one L2 batch of the L1 misses, got %v", l2.batches)
+	}
+	if r := results[0]; r.Key != "a" || !r.Found || r.Value.ID != "a" {
+		t.Errorf("Expected an L1 hit for a, got %+v", r)
+	}
+	if r := results[1]; r.Key != "b" || !r.Found || r.Value.ID != "b" {
+		t.Errorf("Expected an L2 hit for b, got %+v", r)
+	}
+	if r := results[2]; r.Key != "c" || r.Found || r.Err != nil {
+		t.Errorf("Expected a miss for c, got %+v", r)
+	}
+	if r := results[3]; r.Key != "too-long-key" || !errors.Is(r.Err, ErrKeyTooLong) {
+		t.Errorf("Expected ErrKeyTooLong for the long key, got %+v", r)
+	}
+	if _, found := l1.Get(ctx, "b"); !found {
+		t.Error("Expected the L2 hit to be promoted into L1")
+	}
+}
+
+func TestDeleteMultiReportsFailedKeys(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := &brokenKeysCache[TestUser]{Cache: inner, broken: map[string]bool{"b": true}}
+	defer cache.Close()
+
+	_ = inner.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = inner.Set(ctx, "b", TestUser{ID: "b"}, time.Minute)
+
+	err := deleteMulti[TestUser](ctx, cache, []string{"a", "b"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 1 || batchErr.Failed[0].Key != "b" {
+		t.Fatalf("Expected a BatchError for b, got %v", err)
+	}
+	if !errors.Is(err, errKeyBroken) {
+		t.Errorf("Expected the cause to be matched, got %v", err)
+	}
+	if _, found := inner.Get(ctx, "a"); found {
+		t.Error("Expected a to be deleted")
+	}
+}
+
+func TestGetMultiDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{
+		Addr:     addr,
+		Degraded: DegradedPolicy{OnSerializerError: SerializerErrorError},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	_ = client.Set(ctx, "corrupt", "not json", time.Minute).Err()
+
+	results := GetMulti(ctx, cache, "a", "corrupt", "missing")
+	if r := results[0]; !r.Found || r.Value.ID != "a" || r.Err != nil {
+		t.Errorf("Expected a hit for a, got %+v", r)
+	}
+	if r := results[1]; r.Found || r.Err == nil {
+		t.Errorf("Expected a decode failure for corrupt, got %+v", r)
+	}
+	if r := results[2]; r.Found || r.Err != nil {
+		t.Errorf("Expected a miss for missing, got %+v", r)
+	}
+
+	_ = cache.Close()
+	for _, r := range GetMulti(ctx, cache, "a", "b") {
+		if !errors.Is(r.Err, ErrClosed) {
+			t.Errorf("Expected ErrClosed for %s, got %v", r.Key, r.Err)
+		}
+	}
+}
+
+func TestGetMultiNewDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := New[TestUser](&Config{
+		Type:      TypeDistributed,
+		KeyPrefix: "p:",
+		Retry:     &RetryConfig{},
+		Distributed: &DistributedConfig{
+			Addr:     addr,
+			Degraded: DegradedPolicy{OnSerializerError: SerializerErrorError},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	_ = client.Set(ctx, "p:corrupt", "not json", time.Minute).Err()
+
+	results := GetMulti(ctx, cache, "a", "corrupt", "missing")
+	if r := results[0]; r.Key != "a" || !r.Found || r.Value.ID != "a" || r.Err != nil {
+		t.Errorf("Expected a hit for a, got %+v", r)
+	}
+	if r := results[1]; r.Key != "corrupt" || r.Found || r.Err == nil {
+		t.Errorf("Expected a decode failure for corrupt, got %+v", r)
+	}
+	if r := results[2]; r.Key != "missing" || r.Found || r.Err != nil {
+		t.Errorf("Expected a miss for missing, got %+v", r)
+	}
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrNilValue is returned by Set when a nil value is rejected by NilValueReject.
+var ErrNilValue = errors.New("nil values cannot be cached")
+
+// NilValuePolicy decides how a cache treats nil values (nil pointers, maps,
+// slices and interfaces).
+type NilValuePolicy string
+
+const (
+	// NilValueStore caches nil values like any other value, so Get returns (nil, true).
+	NilValueStore NilValuePolicy = "store"
+	// NilValueReject makes Set return ErrNilValue for nil values.
+	NilValueReject NilValuePolicy = "reject"
+	// NilValueMiss treats nil values as absent: Set of nil removes the key and
+	// a nil value read from the backend is reported as a miss.
+	NilValueMiss NilValuePolicy = "miss"
+)
+
+// nilValueCache applies a NilValuePolicy to the wrapped cache.
+type nilValueCache[T any] struct {
+	next   Cache[T]
+	policy NilValuePolicy
+}
+
+// WithNilValuePolicy wraps a cache to apply policy to nil values. Without it,
+// storing a nil pointer makes Get return (nil, true), which surprises callers.
+func WithNilValuePolicy[T any](cache Cache[T], policy NilValuePolicy) Cache[T] {
+	if policy == "" || policy == NilValueStore {
+		return cache
+	}
+	return &nilValueCache[T]{
+		next:   cache,
+		policy: policy,
+	}
+}
+
+func (c *nilValueCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found := c.next.Get(ctx, key)
+	if found && c.policy == NilValueMiss && isNil(value) {
+		var zero T
+		return zero, false
+	}
+	return value, found
+}
+
+func (c *nilValueCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetWithError(ctx, c.next, key)
+	if found && c.policy == NilValueMiss && isNil(value) {
+		var zero T
+		return zero, false, err
+	}
+	return value, found, err
+}
+
+func (c *nilValueCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := GetMulti(ctx, c.next, keys...)
+	if c.policy != NilValueMiss {
+		return results
+	}
+	for i, r := range results {
+		if r.Found && isNil(r.Value) {
+			results[i] = KeyResult[T]{Key: r.Key, Err: r.Err}
+		}
+	}
+	return results
+}
+
+func (c *nilValueCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.policy == NilValueMiss {
+		// A stored nil is reported as absent, which takes reading it
+		_, found, err := c.GetWithError(ctx, key)
+		return found, err
+	}
+	return Exists(ctx, c.next, key)
+}
+
+func (c *nilValueCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.policy == NilValueMiss {
+		if found, err := c.Exists(ctx, key); err != nil || !found {
+			return 0, false, err
+		}
+	}
+	return TTL(ctx, c.next, key)
+}
+
+func (c *nilValueCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *nilValueCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if !isNil(value) {
+		return SetIfAbsent(ctx, c.next, key, value, ttl)
+	}
+
+	if c.policy == NilValueReject {
+		return false, ErrNilValue
+	}
+	// NilValueMiss: nil values are never stored
+	return false, nil
+}
+
+func (c *nilValueCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if !isNil(new) {
+		return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	}
+
+	if c.policy == NilValueReject {
+		return false, ErrNilValue
+	}
+	// NilValueMiss: nil values are never stored
+	return false, nil
+}
+
+func (c *nilValueCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetDel(ctx, c.next, key)
+	if found && c.policy == NilValueMiss && isNil(value) {
+		var zero T
+		return zero, false, err
+	}
+	return value, found, err
+}
+
+func (c *nilValueCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if !isNil(value) {
+		return c.next.Set(ctx, key, value, ttl)
+	}
+
+	if c.policy == NilValueReject {
+		return ErrNilValue
+	}
+
+	// NilValueMiss: make sure no previous value is served instead
+	return deleteMulti(ctx, c.next, []string{key})
+}
+
+func (c *nilValueCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *nilValueCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *nilValueCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *nilValueCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *nilValueCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *nilValueCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice, channel, function or interface.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
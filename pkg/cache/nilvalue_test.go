@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNilValuePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Store", func(t *testing.T) {
+		cache, _ := New[*TestUser](&Config{Type: TypeMemory})
+		defer cache.Close()
+
+		if err := cache.Set(ctx, "key1", nil, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		value, found := cache.Get(ctx, "key1")
+		if !found || value != nil {
+			t.Errorf("Expected (nil, true), got (%v, %v)", value, found)
+		}
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		cache, _ := New[*TestUser](&Config{Type: TypeMemory, NilValues: NilValueReject})
+		defer cache.Close()
+
+		if err := cache.Set(ctx, "key1", nil, time.Minute); !errors.Is(err, ErrNilValue) {
+			t.Errorf("Expected ErrNilValue, got: %v", err)
+		}
+		if err := cache.Set(ctx, "key1", &TestUser{ID: "123"}, time.Minute); err != nil {
+			t.Errorf("Set of non-nil value failed: %v", err)
+		}
+	})
+
+	t.Run("Miss", func(t *testing.T) {
+		inner := NewMemory[*TestUser](nil)
+		cache := WithNilValuePolicy(inner, NilValueMiss)
+		defer cache.Close()
+
+		// Test Set of nil removes the previous value
+		_ = cache.Set(ctx, "key1", &TestUser{ID: "123"}, time.Minute)
+		if err := cache.Set(ctx, "key1", nil, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, found := cache.Get(ctx, "key1"); found {
+			t.Error("Expected nil Set to remove key1")
+		}
+
+		// Test nil values already in the backend read as misses
+		_ = inner.Set(ctx, "key2", nil, time.Minute)
+		if _, found := cache.Get(ctx, "key2"); found {
+			t.Error("Expected nil value to be reported as a miss")
+		}
+	})
+}
+
+func TestIsNil(t *testing.T) {
+	var nilUser *TestUser
+	var nilMap map[string]int
+	var nilErr error
+
+	tests := []struct {
+		value any
+		want  bool
+	}{
+		{nil, true},
+		{nilUser, true},
+		{nilMap, true},
+		{nilErr, true},
+		{&TestUser{}, false},
+		{TestUser{}, false},
+		{0, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNil(tt.value); got != tt.want {
+			t.Errorf("isNil(%#v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
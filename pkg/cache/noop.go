@@ -7,7 +7,9 @@ import (
 
 // noOpCache is a cache implementation that does nothing.
 // Useful for testing or when caching is disabled.
-type noOpCache[T any] struct{}
+type noOpCache[T any] struct {
+	closed closeGuard
+}
 
 // NewNoOp creates a new no-op cache.
 // This is a convenience function for creating no-op caches directly.
@@ -23,12 +25,81 @@ func (c *noOpCache[T]) Get(
 	return zero, false
 }
 
+func (c *noOpCache[T]) Exists(
+	_ context.Context,
+	_ string,
+) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	return false, nil
+}
+
+func (c *noOpCache[T]) TTL(
+	_ context.Context,
+	_ string,
+) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+	return 0, false, nil
+}
+
+func (c *noOpCache[T]) Expire(
+	_ context.Context,
+	_ string,
+	_ time.Duration,
+) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return ErrKeyNotFound
+}
+
+func (c *noOpCache[T]) SetIfAbsent(
+	_ context.Context,
+	_ string,
+	_ T,
+	_ time.Duration,
+) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	return false, nil
+}
+
+func (c *noOpCache[T]) CompareAndSwap(
+	_ context.Context,
+	_ string,
+	_, _ T,
+	_ time.Duration,
+) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+	return false, nil
+}
+
+func (c *noOpCache[T]) GetDel(
+	_ context.Context,
+	_ string,
+) (T, bool, error) {
+	var zero T
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+	return zero, false, nil
+}
+
 func (c *noOpCache[T]) Set(
 	_ context.Context,
 	_ string,
 	_ T,
 	_ time.Duration,
 ) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
 	return nil
 }
 
@@ -36,9 +107,20 @@ func (c *noOpCache[T]) Delete(
 	_ context.Context,
 	_ string,
 ) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (c *noOpCache[T]) Clear(_ context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
 	return nil
 }
 
 func (c *noOpCache[T]) Close() error {
+	c.closed.close()
 	return nil
 }
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// pollingKey is the key a PollingValue stores its value under.
+const pollingKey = "value"
+
+// PollingConfig holds configuration for a PollingValue.
+type PollingConfig struct {
+	// Interval is how often the value is refreshed in the background (default: 1m)
+	Interval time.Duration
+
+	// JitterPercent spreads refreshes by up to this percentage of Interval
+	// either way, so instances don't poll in lockstep (default: 10)
+	JitterPercent float64
+
+	// MaxStale is how long the last value keeps being served once refreshes
+	// start failing, after which Get loads synchronously and reports the
+	// error (default: 0, serve the last value until a refresh succeeds)
+	MaxStale time.Duration
+
+	// Timeout bounds each call of the refresher (default: 10s)
+	Timeout time.Duration
+}
+
+// PollingValue caches one value, such as a configuration document or a set
+// of feature flags, refreshing it in the background with a refresher
+// function. Reads are served from memory; failed refreshes keep serving the
+// last value. This is the "cache one global value" pattern otherwise built
+// with sync.Once and timers.
+type PollingValue[T any] struct {
+	refresher LoadFunc[T]
+	config    PollingConfig
+	values    Cache[T]
+	flights   singleflight.Group
+	counters  refreshCounters
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewPollingValue creates a PollingValue and starts refreshing it in the
+// background right away. Call Close to stop refreshing.
+func NewPollingValue[T any](refresher LoadFunc[T], config *PollingConfig) *PollingValue[T] {
+	var cfg PollingConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.JitterPercent == 0 {
+		cfg.JitterPercent = 10
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	p := &PollingValue[T]{
+		refresher: refresher,
+		config:    cfg,
+		values:    NewMemory[T](nil),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go p.poll()
+	return p
+}
+
+// Get returns the current value. Before the first successful refresh, or once
+// refreshes failed for longer than MaxStale, it calls the refresher itself
+// (sharing the call with concurrent Gets and the background refresh) and
+// returns its error.
+func (p *PollingValue[T]) Get(ctx context.Context) (T, error) {
+	if value, found := p.values.Get(ctx, pollingKey); found {
+		return value, nil
+	}
+	return p.refresh(ctx)
+}
+
+// RefreshStats returns a snapshot of the refresh counters.
+func (p *PollingValue[T]) RefreshStats() RefreshStats {
+	return p.counters.snapshot()
+}
+
+// Close stops the background refreshes and releases the value.
+func (p *PollingValue[T]) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+	})
+	return p.values.Close()
+}
+
+// poll refreshes the value every Interval, with jitter, until Close.
+func (p *PollingValue[T]) poll() {
+	defer close(p.done)
+
+	for delay := time.Duration(0); ; delay = JitteredTTL(p.config.Interval, p.config.JitterPercent) {
+		timer := time.NewTimer(delay)
+		select {
+		case <-p.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		// Failures are counted and keep the last value in place
+		_, _ = p.refresh(context.Background())
+	}
+}
+
+// refresh calls the refresher once for all concurrent callers and stores its value.
+func (p *PollingValue[T]) refresh(ctx context.Context) (T, error) {
+	result, err, _ := p.flights.Do(pollingKey, func() (any, error) {
+		// Shared with other callers, so the caller's cancellation must not abort it
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.config.Timeout)
+		defer cancel()
+
+		value, err := p.refresher(loadCtx)
+		p.counters.record(err)
+		if err != nil {
+			return value, err
+		}
+
+		// The value expires MaxStale after the latest the next refresh can
+		// run; without MaxStale it never expires and is only replaced
+		var ttl time.Duration
+		if p.config.MaxStale > 0 {
+			longest := time.Duration(float64(p.config.Interval) * (1 + p.config.JitterPercent/100))
+			ttl = longest + p.config.MaxStale
+		}
+		// Only fails after Close, when the value is no longer needed
+		_ = p.values.Set(context.Background(), pollingKey, value, ttl)
+		return value, nil
+	})
+
+	value, _ := result.(T)
+	return value, err
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPollingValue(t *testing.T) {
+	ctx := context.Background()
+	var version atomic.Int32
+	var failing atomic.Bool
+	value := NewPollingValue(func(context.Context) (int32, error) {
+		if failing.Load() {
+			return 0, errors.New("config service down")
+		}
+		return version.Add(1), nil
+	}, &PollingConfig{Interval: 10 * time.Millisecond})
+	defer value.Close()
+
+	if v, err := value.Get(ctx); err != nil || v < 1 {
+		t.Fatalf("Expected a loaded value, got %v, %v", v, err)
+	}
+
+	// Test the value is refreshed in the background
+	waitFor(t, func() bool {
+		v, _ := value.Get(ctx)
+		return v >= 3
+	})
+
+	// Test the last value is served while refreshes fail
+	failing.Store(true)
+	waitFor(t, func() bool {
+		return value.RefreshStats().Failures >= 2
+	})
+	if v, err := value.Get(ctx); err != nil || v < 3 {
+		t.Errorf("Expected the last value, got %v, %v", v, err)
+	}
+}
+
+func TestPollingValueMaxStale(t *testing.T) {
+	ctx := context.Background()
+	var failing atomic.Bool
+	value := NewPollingValue(func(context.Context) (string, error) {
+		if failing.Load() {
+			return "", errors.New("config service down")
+		}
+		return "v1", nil
+	}, &PollingConfig{Interval: 10 * time.Millisecond, MaxStale: 20 * time.Millisecond})
+	defer value.Close()
+
+	if v, err := value.Get(ctx); err != nil || v != "v1" {
+		t.Fatalf("Expected v1, got %q, %v", v, err)
+	}
+
+	// Test Get reports the error once the value is too stale
+	failing.Store(true)
+	waitFor(t, func() bool {
+		_, err := value.Get(ctx)
+		return err != nil
+	})
+}
+
+func TestPollingValueInitialError(t *testing.T) {
+	value := NewPollingValue(func(context.Context) (string, error) {
+		return "", errors.New("config service down")
+	}, &PollingConfig{Interval: time.Hour})
+
+	if _, err := value.Get(context.Background()); err == nil {
+		t.Error("Expected the refresher's error without a value")
+	}
+	if err := value.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if stats := value.RefreshStats(); stats.Attempts == 0 || stats.Successes != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// prefixCache prepends a namespace to every key.
+type prefixCache[T any] struct {
+	next   Cache[T]
+	prefix string
+}
+
+// WithPrefix wraps a cache so that prefix is prepended to every key before it
+// reaches the wrapped cache, e.g. "billing:", so services sharing one
+// Redis/Valkey instance can't collide. ScanKeys on the wrapped cache only
+// reports the namespace's keys, without the prefix. An empty prefix returns
+// cache unchanged.
+func WithPrefix[T any](cache Cache[T], prefix string) Cache[T] {
+	if prefix == "" {
+		return cache
+	}
+	return &prefixCache[T]{
+		next:   cache,
+		prefix: prefix,
+	}
+}
+
+func (c *prefixCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, c.prefix+key)
+}
+
+func (c *prefixCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, c.prefix+key)
+}
+
+func (c *prefixCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	results := GetMulti(ctx, c.next, prefixed...)
+	for i := range results {
+		results[i].Key = keys[i]
+	}
+	return results
+}
+
+func (c *prefixCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, c.prefix+key)
+}
+
+func (c *prefixCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, c.prefix+key)
+}
+
+func (c *prefixCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, c.prefix+key, ttl)
+}
+
+func (c *prefixCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, c.prefix+key, value, ttl)
+}
+
+func (c *prefixCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, c.prefix+key, old, new, ttl)
+}
+
+func (c *prefixCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, c.prefix+key)
+}
+
+func (c *prefixCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, c.prefix+key, value, ttl)
+}
+
+func (c *prefixCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, c.prefix+key, ttl, load)
+}
+
+func (c *prefixCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, c.prefix+key)
+}
+
+func (c *prefixCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return deleteMulti(ctx, c.next, prefixed)
+}
+
+// Clear deletes the namespace's keys as ScanKeys finds them, leaving other
+// keys of the wrapped cache alone.
+func (c *prefixCache[T]) Clear(ctx context.Context) error {
+	if pc, ok := c.next.(prefixClearer); ok {
+		return pc.clearPrefix(ctx, keyPrefixOf[T](c))
+	}
+
+	err := ScanKeys(ctx, c, nil, func(batch []KeyInfo) error {
+		keys := make([]string, len(batch))
+		for i, info := range batch {
+			keys[i] = info.Key
+		}
+		return c.DeleteMulti(ctx, keys...)
+	})
+	if errors.Is(err, ErrNotScannable) {
+		return ErrNotClearable
+	}
+	return err
+}
+
+func (c *prefixCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *prefixCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *prefixCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// keyPrefixOf returns the combined prefix WithPrefix decorators add to the
+// keys of cache before they reach the backend.
+func keyPrefixOf[T any](cache Cache[T]) string {
+	var prefix string
+	for cache != nil {
+		if pc, ok := cache.(*prefixCache[T]); ok {
+			// Inner prefixes are prepended to outer ones
+			prefix = pc.prefix + prefix
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return prefix
+}
+
+// globReplacer escapes the glob metacharacters shared by Redis SCAN patterns
+// and path.Match.
+var globReplacer = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
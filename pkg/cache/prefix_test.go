@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithPrefix(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	billing := WithPrefix(backend, "billing:")
+	search := WithPrefix(backend, "search:")
+	defer backend.Close()
+
+	// Test namespaces sharing a backend don't collide
+	_ = billing.Set(ctx, "user:1", TestUser{ID: "billing"}, time.Minute)
+	_ = search.Set(ctx, "user:1", TestUser{ID: "search"}, time.Minute)
+	if user, _ := billing.Get(ctx, "user:1"); user.ID != "billing" {
+		t.Errorf("Expected the billing entry, got %+v", user)
+	}
+	if user, found := backend.Get(ctx, "search:user:1"); !found || user.ID != "search" {
+		t.Errorf("Expected the search entry under its prefix, got %+v", user)
+	}
+
+	if err := deleteMulti(ctx, billing, []string{"user:1"}); err != nil {
+		t.Fatalf("DeleteMulti failed: %v", err)
+	}
+	if _, found := billing.Get(ctx, "user:1"); found {
+		t.Error("Expected the billing entry to be deleted")
+	}
+	if _, found := search.Get(ctx, "user:1"); !found {
+		t.Error("Expected the search entry to be kept")
+	}
+
+	if WithPrefix(backend, "") != backend {
+		t.Error("Expected an empty prefix to return the cache unchanged")
+	}
+}
+
+func TestScanKeysWithPrefix(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := New[TestUser](&Config{Type: TypeMemory, KeyPrefix: "team[1]:"})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "user:1", TestUser{}, time.Minute)
+	_ = cache.Set(ctx, "order:1", TestUser{}, time.Minute)
+	mc, _ := memoryCacheOf(cache)
+	_ = mc.Set(ctx, "other:user:1", TestUser{}, time.Minute)
+
+	// Test only the namespace is enumerated, without the prefix
+	keys := collectKeys(t, cache, &ScanConfig{Pattern: "user:*"})
+	if len(keys) != 1 || keys[0].Key != "user:1" {
+		t.Errorf("Expected user:1 only, got %+v", keys)
+	}
+	if all := collectKeys(t, cache, nil); len(all) != 2 {
+		t.Errorf("Expected the 2 keys of the namespace, got %+v", all)
+	}
+}
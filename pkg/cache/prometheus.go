@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// prometheusMetrics holds the collectors registered for one cache name.
+type prometheusMetrics struct {
+	operations *prometheus.CounterVec
+	durations  *prometheus.HistogramVec
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// prometheusCache records Prometheus metrics for every operation.
+type prometheusCache[T any] struct {
+	next    Cache[T]
+	metrics *prometheusMetrics
+}
+
+// WithPrometheus wraps a cache so that every operation is recorded in
+// Prometheus metrics registered with registerer, labelled cache=name, for
+// teams that don't use OpenTelemetry:
+//
+//   - cache_operations_total{operation, result}: operations by result, "hit"
+//     or "miss" for reads, "ok" otherwise, or "error"
+//   - cache_operation_duration_seconds{operation}: operation latencies
+//   - cache_hit_ratio: hits over reads since the cache was wrapped
+//
+// Several caches can share a registerer under different names; registering
+// a name twice returns the registerer's AlreadyRegisteredError.
+func WithPrometheus[T any](cache Cache[T], registerer prometheus.Registerer, name string) (Cache[T], error) {
+	metrics, err := newPrometheusMetrics(registerer, name)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusCache[T]{next: cache, metrics: metrics}, nil
+}
+
+// PrometheusMiddleware is WithPrometheus as a Middleware. The metrics are
+// registered once, by PrometheusMiddleware: every cache it wraps is recorded
+// under name.
+func PrometheusMiddleware[T any](registerer prometheus.Registerer, name string) (Middleware[T], error) {
+	metrics, err := newPrometheusMetrics(registerer, name)
+	if err != nil {
+		return nil, err
+	}
+	return func(cache Cache[T]) Cache[T] {
+		return &prometheusCache[T]{next: cache, metrics: metrics}
+	}, nil
+}
+
+// newPrometheusMetrics registers the collectors of the cache name with
+// registerer, unregistering them all if one fails.
+func newPrometheusMetrics(registerer prometheus.Registerer, name string) (*prometheusMetrics, error) {
+	labels := prometheus.Labels{"cache": name}
+	m := &prometheusMetrics{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "cache_operations_total",
+			Help:        "Cache operations by operation and result.",
+			ConstLabels: labels,
+		}, []string{"operation", "result"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "cache_operation_duration_seconds",
+			Help:        "Latency of cache operations.",
+			ConstLabels: labels,
+			Buckets:     []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"operation"}),
+	}
+	hitRatio := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "cache_hit_ratio",
+		Help:        "Fraction of cache reads that found their key.",
+		ConstLabels: labels,
+	}, m.hitRatio)
+
+	var registered []prometheus.Collector
+	for _, collector := range []prometheus.Collector{m.operations, m.durations, hitRatio} {
+		if err := registerer.Register(collector); err != nil {
+			for _, r := range registered {
+				registerer.Unregister(r)
+			}
+			return nil, err
+		}
+		registered = append(registered, collector)
+	}
+	return m, nil
+}
+
+func (m *prometheusMetrics) hitRatio() float64 {
+	hits, misses := m.hits.Load(), m.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// observe records an operation that started at start.
+func (c *prometheusCache[T]) observe(operation Operation, start time.Time, result string) {
+	c.metrics.durations.WithLabelValues(string(operation)).Observe(time.Since(start).Seconds())
+	c.metrics.operations.WithLabelValues(string(operation), result).Inc()
+}
+
+// observeRead records a read that started at start, counting it in the hit
+// ratio unless it failed.
+func (c *prometheusCache[T]) observeRead(operation Operation, start time.Time, found bool, err error) {
+	decision := decisionOf(found, err)
+	switch decision {
+	case DecisionHit:
+		c.metrics.hits.Add(1)
+	case DecisionMiss:
+		c.metrics.misses.Add(1)
+	}
+	c.observe(operation, start, string(decision))
+}
+
+// observeWrite records a write that started at start.
+func (c *prometheusCache[T]) observeWrite(operation Operation, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	c.observe(operation, start, result)
+}
+
+func (c *prometheusCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	start := time.Now()
+	value, found := c.next.Get(ctx, key)
+	c.observeRead(OperationGet, start, found, nil)
+	return value, found
+}
+
+func (c *prometheusCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	start := time.Now()
+	value, found, err := GetWithError(ctx, c.next, key)
+	c.observeRead(OperationGet, start, found, err)
+	return value, found, err
+}
+
+func (c *prometheusCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	start := time.Now()
+	loaded := false
+	value, err := GetOrSet(ctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		loaded = true
+		return load(ctx)
+	})
+	c.observeRead(OperationGet, start, !loaded && err == nil, err)
+	return value, err
+}
+
+func (c *prometheusCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *prometheusCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *prometheusCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	start := time.Now()
+	err := Expire(ctx, c.next, key, ttl)
+	c.observeWrite(OperationExpire, start, err)
+	return err
+}
+
+func (c *prometheusCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	c.observeWrite(OperationSetIfAbsent, start, err)
+	return stored, err
+}
+
+func (c *prometheusCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	c.observeWrite(OperationCompareAndSwap, start, err)
+	return swapped, err
+}
+
+func (c *prometheusCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	start := time.Now()
+	value, found, err := GetDel(ctx, c.next, key)
+	c.observeRead(OperationGetDel, start, found, err)
+	return value, found, err
+}
+
+func (c *prometheusCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	start := time.Now()
+	err := c.next.Set(ctx, key, value, ttl)
+	c.observeWrite(OperationSet, start, err)
+	return err
+}
+
+func (c *prometheusCache[T]) Delete(ctx context.Context, key string) error {
+	start := time.Now()
+	err := c.next.Delete(ctx, key)
+	c.observeWrite(OperationDelete, start, err)
+	return err
+}
+
+func (c *prometheusCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	start := time.Now()
+	err := deleteMulti(ctx, c.next, keys)
+	c.observeWrite(OperationDelete, start, err)
+	return err
+}
+
+func (c *prometheusCache[T]) Clear(ctx context.Context) error {
+	start := time.Now()
+	err := Clear(ctx, c.next)
+	c.observeWrite(OperationClear, start, err)
+	return err
+}
+
+func (c *prometheusCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *prometheusCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *prometheusCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatheredValue returns the value of the counter, gauge or histogram sample
+// count named name whose labels include labels.
+func gatheredValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for label, value := range labels {
+				matched := false
+				for _, pair := range metric.GetLabel() {
+					if pair.GetName() == label && pair.GetValue() == value {
+						matched = true
+					}
+				}
+				if !matched {
+					continue metrics
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return 0
+}
+
+func TestWithPrometheus(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	cache, err := WithPrometheus(NewMemory[TestUser](nil), registry, "users")
+	if err != nil {
+		t.Fatalf("WithPrometheus failed: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	cache.Get(ctx, "1")
+	cache.Get(ctx, "1")
+	cache.Get(ctx, "2")
+	_ = cache.Delete(ctx, "1")
+
+	for _, tc := range []struct {
+		operation, result string
+		want              float64
+	}{
+		{"get", "hit", 2},
+		{"get", "miss", 1},
+		{"set", "ok", 1},
+		{"delete", "ok", 1},
+	} {
+		labels := map[string]string{"cache": "users", "operation": tc.operation, "result": tc.result}
+		if got := gatheredValue(t, registry, "cache_operations_total", labels); got != tc.want {
+			t.Errorf("Expected %v %s/%s operations, got %v", tc.want, tc.operation, tc.result, got)
+		}
+	}
+	if got := gatheredValue(t, registry, "cache_operation_duration_seconds", map[string]string{"operation": "get"}); got != 3 {
+		t.Errorf("Expected 3 get latencies, got %v", got)
+	}
+	if got := gatheredValue(t, registry, "cache_hit_ratio", map[string]string{"cache": "users"}); got < 0.66 || got > 0.67 {
+		t.Errorf("Expected a hit ratio of 2/3, got %v", got)
+	}
+}
+
+func TestWithPrometheusSharedRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := WithPrometheus(NewMemory[TestUser](nil), registry, "users"); err != nil {
+		t.Fatalf("WithPrometheus failed: %v", err)
+	}
+	if _, err := WithPrometheus(NewMemory[TestUser](nil), registry, "orders"); err != nil {
+		t.Fatalf("Expected a second cache to share the registry, got %v", err)
+	}
+
+	_, err := WithPrometheus(NewMemory[TestUser](nil), registry, "users")
+	var already prometheus.AlreadyRegisteredError
+	if !errors.As(err, &already) {
+		t.Errorf("Expected AlreadyRegisteredError, got %v", err)
+	}
+}
+
+func TestWithPrometheusCountsErrors(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	backend := NewMemory[TestUser](nil)
+	cache, err := WithPrometheus(backend, registry, "users")
+	if err != nil {
+		t.Fatalf("WithPrometheus failed: %v", err)
+	}
+	_ = cache.Close()
+
+	_, _, _ = GetWithError(ctx, cache, "1")
+	_ = cache.Set(ctx, "1", TestUser{}, time.Minute)
+
+	if got := gatheredValue(t, registry, "cache_operations_total", map[string]string{"operation": "get", "result": "error"}); got != 1 {
+		t.Errorf("Expected 1 failed read, got %v", got)
+	}
+	if got := gatheredValue(t, registry, "cache_operations_total", map[string]string{"operation": "set", "result": "error"}); got != 1 {
+		t.Errorf("Expected 1 failed write, got %v", got)
+	}
+	if got := gatheredValue(t, registry, "cache_hit_ratio", nil); got != 0 {
+		t.Errorf("Expected failed reads to be left out of the hit ratio, got %v", got)
+	}
+}
@@ -0,0 +1,313 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrQuotaExceeded is returned by Set when the namespace has written more than
+// its budget within the quota window and the quota rejects excess writes.
+var ErrQuotaExceeded = errors.New("namespace write quota exceeded")
+
+// QuotaAction selects what happens to writes beyond the budget.
+type QuotaAction string
+
+const (
+	// QuotaFlag lets excess writes through, recording a span event, a warning
+	// and the "cache.quota.exceeded" metric.
+	QuotaFlag QuotaAction = "flag"
+	// QuotaReject fails excess writes with ErrQuotaExceeded.
+	QuotaReject QuotaAction = "reject"
+)
+
+const quotaExceededEventName = "cache.quota_exceeded"
+
+// QuotaConfig holds configuration for per-namespace write quotas.
+type QuotaConfig struct {
+	// Namespace identifies whose writes are accounted, e.g. a team or service (required)
+	Namespace string
+
+	// BytesPerWindow is the budget of serialized bytes the namespace may
+	// write within Window (required)
+	BytesPerWindow int64
+
+	// Window is the length of the sliding accounting window (default: 1m)
+	Window time.Duration
+
+	// Action selects what happens to writes beyond the budget (default: QuotaFlag)
+	Action QuotaAction
+
+	// Serializer measures values; use the serializer of the backend for
+	// accurate accounting (default: proto.Size for proto messages, JSON otherwise)
+	Serializer Serializer
+
+	// KeyPrefix prefixes the accounting counters in Redis/Valkey (default: "cache:quota:")
+	KeyPrefix string
+
+	// MeterProvider receives the "cache.quota.exceeded" counter
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+}
+
+// quotaScript adds a write to the current window's counter unless it is
+// rejected, and returns whether the budget is exceeded and the usage. Usage
+// decays the previous window's bytes linearly over the current window.
+//
+// KEYS[1] current window counter, KEYS[2] previous window counter
+// ARGV[1] size, ARGV[2] budget, ARGV[3] weight of the previous window,
+// ARGV[4] counter TTL in ms, ARGV[5] "1" to reject excess writes
+var quotaScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+local size = tonumber(ARGV[1])
+local usage = current + math.floor(previous * tonumber(ARGV[3])) + size
+local exceeded = usage > tonumber(ARGV[2])
+if exceeded and ARGV[5] == '1' then
+	return {1, usage - size}
+end
+redis.call('INCRBY', KEYS[1], size)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+if exceeded then
+	return {1, usage}
+end
+return {0, usage}
+`)
+
+// quotaCache accounts the bytes written through it against a namespace budget.
+type quotaCache[T any] struct {
+	next     Cache[T]
+	client   redis.UniversalClient
+	config   QuotaConfig
+	codec    valueCodec[T]
+	now      func() time.Time
+	exceeded metric.Int64Counter
+}
+
+// NewQuota wraps a Redis/Valkey-backed cache so the bytes it writes are
+// accounted against a per-namespace budget in Redis, shared by every instance
+// writing to the namespace. Usage is approximate: each Set adds the serialized
+// size of its value to a counter for the current window, and the previous
+// window's bytes count with a weight decaying to zero, approximating a sliding
+// window. Writes beyond BytesPerWindow are flagged or rejected depending on
+// Action. Accounting failures let the write through.
+func NewQuota[T any](cache Cache[T], config *QuotaConfig) (Cache[T], error) {
+	if config == nil || config.Namespace == "" {
+		return nil, errors.New("quota namespace is required")
+	}
+	if config.BytesPerWindow <= 0 {
+		return nil, errors.New("quota BytesPerWindow must be positive")
+	}
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := *config
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Action == "" {
+		cfg.Action = QuotaFlag
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "cache:quota:"
+	}
+	provider := cfg.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	exceeded, _ := provider.Meter(instrumentationName).Int64Counter("cache.quota.exceeded",
+		metric.WithDescription("Number of writes beyond the namespace quota"))
+
+	return &quotaCache[T]{
+		next:     cache,
+		client:   client,
+		config:   cfg,
+		codec:    newValueCodec[T](cfg.Serializer),
+		now:      time.Now,
+		exceeded: exceeded,
+	}, nil
+}
+
+func (c *quotaCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *quotaCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *quotaCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *quotaCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *quotaCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *quotaCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *quotaCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.admit(ctx, key, value); err != nil {
+		return err
+	}
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+// SetIfAbsent accounts value against the quota like Set, whether or not it
+// ends up stored.
+func (c *quotaCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if err := c.admit(ctx, key, value); err != nil {
+		return false, err
+	}
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+// CompareAndSwap accounts new against the quota like Set, whether or not it
+// ends up stored.
+func (c *quotaCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if err := c.admit(ctx, key, new); err != nil {
+		return false, err
+	}
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *quotaCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+// admit accounts the size of value written under key, returning
+// ErrQuotaExceeded when the write must be rejected.
+func (c *quotaCache[T]) admit(ctx context.Context, key string, value T) error {
+	size, ok := c.size(value)
+	if !ok {
+		// Can't measure - the backend reports the encoding error
+		return nil
+	}
+
+	exceeded, usage, err := c.account(ctx, size)
+	if err != nil {
+		// Fail open - a quota outage must not take the cache down with it
+		return nil
+	}
+	if exceeded {
+		c.recordExceeded(ctx, key, usage)
+		if c.config.Action == QuotaReject {
+			return fmt.Errorf("%w: namespace %q", ErrQuotaExceeded, c.config.Namespace)
+		}
+	}
+	return nil
+}
+
+func (c *quotaCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *quotaCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *quotaCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *quotaCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *quotaCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *quotaCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// size returns the approximate number of bytes value occupies in the backend.
+func (c *quotaCache[T]) size(value T) (int64, bool) {
+	if msg, ok := any(value).(proto.Message); ok && c.config.Serializer == nil {
+		return int64(proto.Size(msg)), true
+	}
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return 0, false
+	}
+	return int64(len(data)), true
+}
+
+// account adds size to the namespace's usage and reports whether the budget is
+// exceeded. Rejected writes are not added.
+func (c *quotaCache[T]) account(ctx context.Context, size int64) (bool, int64, error) {
+	current, previous, weight := quotaWindows(c.now(), c.config.Window)
+	reject := "0"
+	if c.config.Action == QuotaReject {
+		reject = "1"
+	}
+
+	result, err := quotaScript.Run(ctx, c.client,
+		[]string{c.counterKey(current), c.counterKey(previous)},
+		size, c.config.BytesPerWindow, strconv.FormatFloat(weight, 'f', 6, 64),
+		(2 * c.config.Window).Milliseconds(), reject,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected quota script result: %v", result)
+	}
+	return result[0] == 1, result[1], nil
+}
+
+// counterKey returns the counter of a window. The namespace is a hash tag so
+// both windows' counters live in the same Redis Cluster slot.
+func (c *quotaCache[T]) counterKey(window int64) string {
+	return c.config.KeyPrefix + "{" + c.config.Namespace + "}:" + strconv.FormatInt(window, 10)
+}
+
+func (c *quotaCache[T]) recordExceeded(ctx context.Context, key string, usage int64) {
+	attrs := []attribute.KeyValue{
+		attribute.String("cache.namespace", c.config.Namespace),
+		attribute.String("action", string(c.config.Action)),
+	}
+	if c.exceeded != nil {
+		c.exceeded.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	trace.SpanFromContext(ctx).AddEvent(quotaExceededEventName, trace.WithAttributes(
+		append(attrs, attribute.String("cache.key", key), attribute.Int64("usage_bytes", usage))...,
+	))
+	slog.Default().WarnContext(ctx, "cache: namespace write quota exceeded",
+		slog.String("namespace", c.config.Namespace),
+		slog.String("key", key),
+		slog.Int64("usage_bytes", usage),
+		slog.Int64("budget_bytes", c.config.BytesPerWindow),
+	)
+}
+
+// quotaWindows returns the index of the window containing now, the index of
+// the one before it, and the weight of the previous window's bytes: 1 at the
+// start of the current window, decaying linearly to 0 at its end.
+func quotaWindows(now time.Time, window time.Duration) (current, previous int64, weight float64) {
+	nanos := now.UnixNano()
+	current = nanos / int64(window)
+	elapsed := float64(nanos%int64(window)) / float64(window)
+	return current, current - 1, 1 - elapsed
+}
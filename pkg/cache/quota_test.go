@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuotaWindows(t *testing.T) {
+	window := time.Minute
+	start := time.Unix(0, 0).Add(100 * window)
+
+	current, previous, weight := quotaWindows(start, window)
+	if current != 100 || previous != 99 || weight != 1 {
+		t.Errorf("Expected window 100 after 99 with weight 1, got %d, %d, %v", current, previous, weight)
+	}
+
+	// Test the previous window decays over the current one
+	_, _, weight = quotaWindows(start.Add(45*time.Second), window)
+	if weight != 0.25 {
+		t.Errorf("Expected weight 0.25 three quarters into the window, got %v", weight)
+	}
+}
+
+func TestNewQuotaValidation(t *testing.T) {
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	if _, err := NewQuota(memory, nil); err == nil {
+		t.Error("Expected error without config")
+	}
+	if _, err := NewQuota(memory, &QuotaConfig{Namespace: "team-a"}); err == nil {
+		t.Error("Expected error without a budget")
+	}
+	if _, err := NewQuota(memory, &QuotaConfig{Namespace: "team-a", BytesPerWindow: 1 << 20}); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed for a memory cache, got: %v", err)
+	}
+}
+
+func TestQuotaWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	newCache := func(action QuotaAction) Cache[TestUser] {
+		c, err := New[TestUser](&Config{
+			Type:        TypeDistributed,
+			Distributed: &DistributedConfig{Addr: addr},
+			Quota: &QuotaConfig{
+				Namespace:      "team-" + string(action),
+				BytesPerWindow: 1000,
+				Window:         time.Hour,
+				Action:         action,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create cache: %v", err)
+		}
+		t.Cleanup(func() { _ = c.Close() })
+		return c
+	}
+	user := TestUser{ID: "1", Name: strings.Repeat("x", 400)}
+
+	// Test writes beyond the budget are rejected and not stored
+	rejecting := newCache(QuotaReject)
+	for i, key := range []string{"r1", "r2"} {
+		if err := rejecting.Set(ctx, key, user, time.Minute); err != nil {
+			t.Fatalf("Set %d within budget failed: %v", i, err)
+		}
+	}
+	if err := rejecting.Set(ctx, "r3", user, time.Minute); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got: %v", err)
+	}
+	if _, found := rejecting.Get(ctx, "r3"); found {
+		t.Error("Expected the rejected write not to be stored")
+	}
+
+	// Test flagged writes are stored
+	flagging := newCache(QuotaFlag)
+	for _, key := range []string{"f1", "f2", "f3"} {
+		if err := flagging.Set(ctx, key, user, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if _, found := flagging.Get(ctx, "f3"); !found {
+		t.Error("Expected the flagged write to be stored")
+	}
+}
@@ -0,0 +1,310 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// RacingConfig holds configuration for racing caches.
+type RacingConfig struct {
+	// RepairTTL is the TTL of values copied to the secondary to repair an
+	// inconsistent or missing entry, capped at the primary entry's remaining
+	// TTL (default: 1m)
+	RepairTTL time.Duration
+
+	// ReadTimeout bounds both reads, including the slower one that keeps
+	// running after the caller has been answered (default: 1s)
+	ReadTimeout time.Duration
+}
+
+// racingGenerations is the number of write generation counters keys are
+// spread over; keys sharing a counter only skip more repairs.
+const racingGenerations = 256
+
+// racingCache reads from two backends at once and answers with the first hit.
+type racingCache[T any] struct {
+	primary   Cache[T]
+	secondary Cache[T]
+	config    RacingConfig
+
+	// generations count the writes of the keys hashing to each counter, so a
+	// repair can tell whether its key was written since the read started
+	seed        maphash.Seed
+	generations [racingGenerations]atomic.Uint64
+}
+
+type raceResult[T any] struct {
+	value   T
+	found   bool
+	err     error
+	primary bool
+}
+
+// NewRacing creates a cache that issues Get to primary and secondary (e.g. the
+// primary Redis and a near-cache replica) concurrently and returns the first
+// hit, for ultra-latency-sensitive paths. Once both reads are in, the slower
+// result is used to repair the secondary in the background, as the primary is
+// authoritative: a primary hit is copied to a secondary that missed it or
+// holds a different value, for RepairTTL at most and never longer than the
+// primary entry lives, and a key the primary misses is deleted from the
+// secondary. A repair is skipped when the key was written or deleted through
+// the racing cache since the read started, so it can't bring back an older
+// value. Writes and deletes go to both backends, primary first.
+//
+// Close closes both backends.
+func NewRacing[T any](primary, secondary Cache[T], config *RacingConfig) Cache[T] {
+	var cfg RacingConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.RepairTTL == 0 {
+		cfg.RepairTTL = time.Minute
+	}
+	if cfg.ReadTimeout == 0 {
+		cfg.ReadTimeout = time.Second
+	}
+
+	return &racingCache[T]{
+		primary:   primary,
+		secondary: secondary,
+		config:    cfg,
+		seed:      maphash.MakeSeed(),
+	}
+}
+
+func (c *racingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+// GetWithError answers with the first hit; when both backends miss or fail,
+// it returns the primary's error, or else the secondary's.
+func (c *racingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	generation := c.generation(key).Load()
+
+	// The reads outlive the caller so the slower one can still repair
+	readCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.ReadTimeout)
+
+	results := make(chan raceResult[T], 2)
+	read := func(cache Cache[T], primary bool) {
+		value, found, err := GetWithError(readCtx, cache, key)
+		results <- raceResult[T]{value: value, found: found, err: err, primary: primary}
+	}
+	go read(c.primary, true)
+	go read(c.secondary, false)
+
+	answer := make(chan raceResult[T], 1)
+	go func() {
+		defer cancel()
+
+		var got [2]raceResult[T]
+		answered := false
+		for i := range got {
+			got[i] = <-results
+			if got[i].found && !answered {
+				answer <- got[i]
+				answered = true
+			}
+		}
+		if !answered {
+			answer <- raceResult[T]{err: raceError(got)}
+		}
+		c.repair(readCtx, key, generation, got)
+	}()
+
+	select {
+	case result := <-answer:
+		if result.found {
+			noteTier(ctx, racingTier(result.primary))
+		}
+		return result.value, result.found, result.err
+	case <-ctx.Done():
+		var zero T
+		return zero, false, ctx.Err()
+	}
+}
+
+// GetOrSet answers with the first hit and loads on a miss, storing the
+// loaded value in both backends. Read errors are treated as misses, like
+// the package GetOrSet does.
+func (c *racingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	if value, found, _ := c.GetWithError(ctx, key); found {
+		return value, nil
+	}
+	return loadAndStore(ctx, c, key, ttl, load)
+}
+
+// repair reconciles the two backends once both reads are in, unless a read
+// failed or the key was written since generation was read.
+func (c *racingCache[T]) repair(ctx context.Context, key string, generation uint64, got [2]raceResult[T]) {
+	primary, secondary := got[0], got[1]
+	if secondary.primary {
+		primary, secondary = secondary, primary
+	}
+	if primary.err != nil || secondary.err != nil || c.generation(key).Load() != generation {
+		return
+	}
+
+	// Repairs are best effort - the next read tries again
+	switch {
+	case primary.found && (!secondary.found || !valuesEqual(primary.value, secondary.value)):
+		ttl := c.config.RepairTTL
+		// Don't let the copy outlive the primary entry
+		if remaining, ok, err := TTL(ctx, c.primary, key); err == nil && ok && remaining > 0 && remaining < ttl {
+			ttl = remaining
+		}
+		_ = c.secondary.Set(ctx, key, primary.value, ttl)
+	case !primary.found && secondary.found:
+		// The primary expired or deleted the key: drop the copy
+		_ = deleteMulti(ctx, c.secondary, []string{key})
+	}
+}
+
+func (c *racingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	found, err := Exists(ctx, c.primary, key)
+	if err != nil {
+		return Exists(ctx, c.secondary, key)
+	}
+	return found, err
+}
+
+func (c *racingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ttl, found, err := TTL(ctx, c.primary, key)
+	if err != nil {
+		return TTL(ctx, c.secondary, key)
+	}
+	return ttl, found, err
+}
+
+func (c *racingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	c.written(key)
+	if err := Expire(ctx, c.primary, key, ttl); err != nil {
+		return err
+	}
+	return Expire(ctx, c.secondary, key, ttl)
+}
+
+// SetIfAbsent claims key in the primary, which decides, and copies the value
+// to the secondary once stored.
+func (c *racingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	c.written(key)
+	stored, err := SetIfAbsent(ctx, c.primary, key, value, ttl)
+	if err != nil || !stored {
+		return false, err
+	}
+	return true, c.secondary.Set(ctx, key, value, ttl)
+}
+
+// CompareAndSwap swaps key in the primary, which decides, and copies the new
+// value to the secondary once stored.
+func (c *racingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	c.written(key)
+	swapped, err := CompareAndSwap(ctx, c.primary, key, old, new, ttl)
+	if err != nil || !swapped {
+		return false, err
+	}
+	return true, c.secondary.Set(ctx, key, new, ttl)
+}
+
+// GetDel consumes the value from the primary, which decides, and removes
+// the copy from the secondary.
+func (c *racingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	c.written(key)
+	value, found, err := GetDel(ctx, c.primary, key)
+	if err != nil {
+		return value, false, err
+	}
+	return value, found, deleteMulti(ctx, c.secondary, []string{key})
+}
+
+func (c *racingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	c.written(key)
+	if err := c.primary.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return c.secondary.Set(ctx, key, value, ttl)
+}
+
+func (c *racingCache[T]) Delete(ctx context.Context, key string) error {
+	c.written(key)
+	return errors.Join(
+		deleteMulti(ctx, c.primary, []string{key}),
+		deleteMulti(ctx, c.secondary, []string{key}),
+	)
+}
+
+func (c *racingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.written(key)
+	}
+	return errors.Join(
+		deleteMulti(ctx, c.primary, keys),
+		deleteMulti(ctx, c.secondary, keys),
+	)
+}
+
+func (c *racingCache[T]) Clear(ctx context.Context) error {
+	for i := range c.generations {
+		c.generations[i].Add(1)
+	}
+	return errors.Join(Clear(ctx, c.primary), Clear(ctx, c.secondary))
+}
+
+func (c *racingCache[T]) Close() error {
+	return errors.Join(c.primary.Close(), c.secondary.Close())
+}
+
+// unwrap exposes the primary so helpers needing the Redis client keep working.
+func (c *racingCache[T]) unwrap() Cache[T] {
+	return c.primary
+}
+
+func (c *racingCache[T]) Ping(ctx context.Context) error {
+	return errors.Join(pingNext(ctx, c.primary), pingNext(ctx, c.secondary))
+}
+
+// generation returns the write generation counter of key.
+func (c *racingCache[T]) generation(key string) *atomic.Uint64 {
+	return &c.generations[maphash.String(c.seed, key)%racingGenerations]
+}
+
+// written records a write of key before it is made, so repairs of reads
+// started earlier are skipped.
+func (c *racingCache[T]) written(key string) {
+	c.generation(key).Add(1)
+}
+
+// raceError returns the primary's read error, or else the secondary's.
+func raceError[T any](got [2]raceResult[T]) error {
+	primary, secondary := got[0], got[1]
+	if secondary.primary {
+		primary, secondary = secondary, primary
+	}
+	if primary.err != nil {
+		return primary.err
+	}
+	return secondary.err
+}
+
+func racingTier(primary bool) string {
+	if primary {
+		return "primary"
+	}
+	return "secondary"
+}
+
+// valuesEqual compares two cached values, using proto.Equal for proto messages.
+func valuesEqual[T any](a, b T) bool {
+	if am, ok := any(a).(proto.Message); ok {
+		if bm, ok := any(b).(proto.Message); ok {
+			return proto.Equal(am, bm)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
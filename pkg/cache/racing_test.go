@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowCache delays every Get by delay.
+type slowCache[T any] struct {
+	Cache[T]
+	delay time.Duration
+}
+
+func (c *slowCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	time.Sleep(c.delay)
+	return c.Cache.Get(ctx, key)
+}
+
+// delayedCache reads at once but returns every Get result after delay.
+type delayedCache[T any] struct {
+	Cache[T]
+	delay time.Duration
+}
+
+func (c *delayedCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found := c.Cache.Get(ctx, key)
+	time.Sleep(c.delay)
+	return value, found
+}
+
+func TestRacingCacheReturnsFirstHit(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&slowCache[TestUser]{Cache: primary, delay: 200 * time.Millisecond}, secondary, nil)
+	defer cache.Close()
+
+	_ = primary.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+	_ = secondary.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	start := time.Now()
+	user, found := cache.Get(ctx, "key1")
+	if !found || user.ID != "123" {
+		t.Fatalf("Expected hit, got %v, %v", user, found)
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("Expected the faster backend to answer, took %v", elapsed)
+	}
+}
+
+func TestRacingCacheFallsBackToSlowerHit(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&slowCache[TestUser]{Cache: primary, delay: 20 * time.Millisecond}, secondary, nil)
+	defer cache.Close()
+
+	_ = primary.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	// Test a fast miss doesn't hide a slower hit
+	if user, found := cache.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Fatalf("Expected hit from primary, got %v, %v", user, found)
+	}
+
+	// Test the backend that missed is repaired
+	waitFor(t, func() bool {
+		_, found := secondary.Get(ctx, "key1")
+		return found
+	})
+}
+
+func TestRacingCacheRepairsInconsistentSecondary(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&slowCache[TestUser]{Cache: primary, delay: 20 * time.Millisecond}, secondary, nil)
+	defer cache.Close()
+
+	_ = primary.Set(ctx, "key1", TestUser{ID: "123", Name: "new"}, time.Minute)
+	_ = secondary.Set(ctx, "key1", TestUser{ID: "123", Name: "old"}, time.Minute)
+
+	// The faster, stale secondary answers first...
+	if user, _ := cache.Get(ctx, "key1"); user.Name != "old" {
+		t.Errorf("Expected the secondary's value, got %v", user)
+	}
+
+	// ...and is then repaired from the primary
+	waitFor(t, func() bool {
+		user, _ := secondary.Get(ctx, "key1")
+		return user.Name == "new"
+	})
+}
+
+func TestRacingCacheDropsCopiesThePrimaryMisses(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&slowCache[TestUser]{Cache: primary, delay: 20 * time.Millisecond}, secondary, nil)
+	defer cache.Close()
+
+	_ = secondary.Set(ctx, "key1", TestUser{ID: "stale"}, time.Minute)
+	_, _ = cache.Get(ctx, "key1")
+
+	waitFor(t, func() bool {
+		_, found := secondary.Get(ctx, "key1")
+		return !found
+	})
+	if _, found := primary.Get(ctx, "key1"); found {
+		t.Error("Expected the secondary's copy not to be written back to the primary")
+	}
+}
+
+func TestRacingCacheRepairKeepsPrimaryTTL(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](primary, &slowCache[TestUser]{Cache: secondary, delay: 20 * time.Millisecond}, &RacingConfig{RepairTTL: time.Hour})
+	defer cache.Close()
+
+	_ = primary.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+	_, _ = cache.Get(ctx, "key1")
+
+	waitFor(t, func() bool {
+		_, found := secondary.Get(ctx, "key1")
+		return found
+	})
+	if ttl, _, _ := TTL(ctx, secondary, "key1"); ttl > time.Minute {
+		t.Errorf("Expected the copy to expire with the primary entry, got TTL %v", ttl)
+	}
+}
+
+func TestRacingCacheReportsCancellation(t *testing.T) {
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&slowCache[TestUser]{Cache: primary, delay: 200 * time.Millisecond},
+		&slowCache[TestUser]{Cache: secondary, delay: 200 * time.Millisecond}, nil)
+	defer cache.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := GetWithError(ctx, cache, "key1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's context error, got %v", err)
+	}
+}
+
+func TestRacingCacheRepairSkipsKeysWrittenDuringRead(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing[TestUser](&delayedCache[TestUser]{Cache: primary, delay: 50 * time.Millisecond}, secondary, nil)
+	defer cache.Close()
+
+	_ = primary.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	// The primary has read the value when the key is deleted
+	read := make(chan bool)
+	go func() {
+		_, found := cache.Get(ctx, "key1")
+		read <- found
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	<-read
+
+	time.Sleep(50 * time.Millisecond)
+	if _, found := secondary.Get(ctx, "key1"); found {
+		t.Error("Expected the repair not to bring back the deleted key")
+	}
+}
+
+func TestRacingCacheGetOrSet(t *testing.T) {
+	ctx := context.Background()
+	primary := NewMemory[TestUser](nil)
+	secondary := NewMemory[TestUser](nil)
+	cache := NewRacing(primary, secondary, nil)
+	defer cache.Close()
+
+	loads := 0
+	load := func(context.Context) (TestUser, error) {
+		loads++
+		return TestUser{ID: "123"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		if user, err := GetOrSet(ctx, cache, "key1", time.Minute, load); err != nil || user.ID != "123" {
+			t.Fatalf("Expected loaded value, got %v, %v", user, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected a single load, got %d", loads)
+	}
+	for _, backend := range []Cache[TestUser]{primary, secondary} {
+		if _, found := backend.Get(ctx, "key1"); !found {
+			t.Error("Expected the loaded value in both backends")
+		}
+	}
+}
+
+func TestRacingCacheMiss(t *testing.T) {
+	cache := NewRacing(NewMemory[TestUser](nil), NewMemory[TestUser](nil), nil)
+	defer cache.Close()
+
+	if _, found := cache.Get(context.Background(), "missing"); found {
+		t.Error("Expected miss")
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met within 1s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Loader loads the value of a key from the source of truth, with the TTL to
+// cache it for. Returning ErrNotFound (or an error wrapping it) reports that
+// the key has no value.
+type Loader[T any] interface {
+	Load(ctx context.Context, key string) (T, time.Duration, error)
+}
+
+// LoaderFunc adapts a function to the Loader interface.
+type LoaderFunc[T any] func(ctx context.Context, key string) (T, time.Duration, error)
+
+// Load calls f.
+func (f LoaderFunc[T]) Load(ctx context.Context, key string) (T, time.Duration, error) {
+	return f(ctx, key)
+}
+
+// ReadThroughConfig holds configuration for read-through caches.
+type ReadThroughConfig struct {
+	// LoadLimit bounds concurrent Load calls (default: unlimited)
+	LoadLimit *LoadLimitConfig
+}
+
+// readThroughCache populates misses from a Loader.
+type readThroughCache[T any] struct {
+	next    Cache[T]
+	loader  Loader[T]
+	group   singleflight.Group
+	limiter *loadLimiter
+}
+
+// NewReadThrough wraps a cache so that reads missing a key load it with
+// loader and cache it with the TTL the loader returns, turning any cache into
+// a read-through cache. Concurrent misses of the same key share a single
+// load, and loads beyond config.LoadLimit fail with ErrLoadShed. Callers
+// sharing a load also share the context and directives of the caller that
+// started it.
+//
+// Get reports failed loads, and keys the loader doesn't find, as misses;
+// GetWithError reports the loader's errors, except ErrNotFound. Exists, TTL
+// and GetDel never load, and GetOrSet uses the loader it is given.
+func NewReadThrough[T any](cache Cache[T], loader Loader[T], config *ReadThroughConfig) Cache[T] {
+	c := &readThroughCache[T]{next: cache, loader: loader}
+	if config != nil {
+		c.limiter = newLoadLimiter(config.LoadLimit)
+	}
+	return c
+}
+
+func (c *readThroughCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *readThroughCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetWithError(ctx, c.next, key)
+	if found || err != nil {
+		return value, found, err
+	}
+
+	result, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.load(ctx, key)
+	})
+	if err != nil {
+		var zero T
+		if errors.Is(err, ErrNotFound) {
+			err = nil
+		}
+		return zero, false, err
+	}
+	value, _ = result.(T)
+	return value, true, nil
+}
+
+// load calls the loader within the load limit and stores its value.
+func (c *readThroughCache[T]) load(ctx context.Context, key string) (interface{}, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	value, ttl, err := c.loader.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if ttl, store := directiveTTL(ctx, ttl); store {
+		// The value was loaded either way; a failed write only costs a future reload
+		_ = c.next.Set(ctx, key, value, ttl)
+	}
+	return value, nil
+}
+
+func (c *readThroughCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, load)
+}
+
+func (c *readThroughCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *readThroughCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *readThroughCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *readThroughCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *readThroughCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+// GetDel never loads: there is nothing to consume for a missing key.
+func (c *readThroughCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *readThroughCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *readThroughCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *readThroughCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *readThroughCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *readThroughCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *readThroughCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *readThroughCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
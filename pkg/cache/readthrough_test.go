@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadThroughLoadsMisses(t *testing.T) {
+	ctx := context.Background()
+	var loads atomic.Int32
+	loader := LoaderFunc[TestUser](func(ctx context.Context, key string) (TestUser, time.Duration, error) {
+		loads.Add(1)
+		if key == "missing" {
+			return TestUser{}, 0, fmt.Errorf("user %s: %w", key, ErrNotFound)
+		}
+		return TestUser{ID: key}, time.Minute, nil
+	})
+	backend := NewMemory[TestUser](nil)
+	cache := NewReadThrough[TestUser](backend, loader, nil)
+	defer cache.Close()
+
+	if value, found := cache.Get(ctx, "1"); !found || value.ID != "1" {
+		t.Fatalf("Expected the loaded value, got %+v found=%v", value, found)
+	}
+	if ttl, found, _ := TTL(ctx, backend, "1"); !found || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected the value cached with the loader's TTL, got %v", ttl)
+	}
+	cache.Get(ctx, "1")
+	if loads.Load() != 1 {
+		t.Errorf("Expected the second read to hit, got %d loads", loads.Load())
+	}
+
+	if _, found, err := GetWithError(ctx, cache, "missing"); found || err != nil {
+		t.Errorf("Expected a plain miss for ErrNotFound, got found=%v err=%v", found, err)
+	}
+	if exists, _ := Exists(ctx, cache, "2"); exists {
+		t.Error("Expected Exists not to load")
+	}
+}
+
+func TestReadThroughReportsLoadErrors(t *testing.T) {
+	ctx := context.Background()
+	loadErr := errors.New("origin down")
+	cache := NewReadThrough[TestUser](NewMemory[TestUser](nil), LoaderFunc[TestUser](func(context.Context, string) (TestUser, time.Duration, error) {
+		return TestUser{}, 0, loadErr
+	}), nil)
+	defer cache.Close()
+
+	if _, found, err := GetWithError(ctx, cache, "1"); found || !errors.Is(err, loadErr) {
+		t.Errorf("Expected the loader's error, got found=%v err=%v", found, err)
+	}
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Error("Expected Get to report a miss")
+	}
+}
+
+func TestReadThroughSharesConcurrentLoads(t *testing.T) {
+	ctx := context.Background()
+	var loads atomic.Int32
+	release := make(chan struct{})
+	cache := NewReadThrough[TestUser](NewMemory[TestUser](nil), LoaderFunc[TestUser](func(_ context.Context, key string) (TestUser, time.Duration, error) {
+		loads.Add(1)
+		<-release
+		return TestUser{ID: key}, time.Minute, nil
+	}), nil)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, found := cache.Get(ctx, "1"); !found || value.ID != "1" {
+				t.Errorf("Expected the shared load's value, got %+v", value)
+			}
+		}()
+	}
+	waitFor(t, func() bool { return loads.Load() == 1 })
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("Expected a single load, got %d", loads.Load())
+	}
+}
@@ -0,0 +1,56 @@
+package cache
+
+import "sync/atomic"
+
+// RefreshStats reports background refresh work done by refresh-ahead and
+// stale-while-revalidate layers, so operators can see whether the feature is
+// working or silently failing.
+type RefreshStats struct {
+	// Attempts counts background refreshes started.
+	Attempts uint64
+	// Successes counts refreshes that stored a new value.
+	Successes uint64
+	// Failures counts refreshes whose loader or write failed.
+	Failures uint64
+	// SavedMisses counts reads served from an entry that a background refresh
+	// kept alive past its original expiry.
+	SavedMisses uint64
+}
+
+// RefreshStatsProvider is an optional interface for caches that refresh
+// entries in the background.
+type RefreshStatsProvider interface {
+	// RefreshStats returns a snapshot of the background refresh counters.
+	RefreshStats() RefreshStats
+}
+
+// refreshCounters accumulates RefreshStats; safe for concurrent use.
+type refreshCounters struct {
+	attempts    atomic.Uint64
+	successes   atomic.Uint64
+	failures    atomic.Uint64
+	savedMisses atomic.Uint64
+}
+
+// record counts one finished refresh attempt.
+func (c *refreshCounters) record(err error) {
+	c.attempts.Add(1)
+	if err != nil {
+		c.failures.Add(1)
+		return
+	}
+	c.successes.Add(1)
+}
+
+func (c *refreshCounters) savedMiss() {
+	c.savedMisses.Add(1)
+}
+
+func (c *refreshCounters) snapshot() RefreshStats {
+	return RefreshStats{
+		Attempts:    c.attempts.Load(),
+		Successes:   c.successes.Load(),
+		Failures:    c.failures.Load(),
+		SavedMisses: c.savedMisses.Load(),
+	}
+}
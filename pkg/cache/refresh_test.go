@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRefreshCounters(t *testing.T) {
+	var counters refreshCounters
+
+	counters.record(nil)
+	counters.record(nil)
+	counters.record(errors.New("loader failed"))
+	counters.savedMiss()
+
+	want := RefreshStats{Attempts: 3, Successes: 2, Failures: 1, SavedMisses: 1}
+	if got := counters.snapshot(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,452 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RefreshAheadConfig holds configuration for refresh-ahead caches.
+type RefreshAheadConfig struct {
+	// Workers is the number of background refreshes run at once (default: 4)
+	Workers int
+
+	// Threshold is the fraction of an entry's TTL after which a read queues a
+	// refresh, e.g. 0.8 refreshes entries in the last fifth of their life
+	// (default: 0.8)
+	Threshold float64
+
+	// MinHits is how many reads an entry needs since it was stored to count as
+	// hot; colder entries are left to expire (default: 2)
+	MinHits int
+
+	// MaxTracked bounds the number of keys tracked for refreshing; keys loaded
+	// beyond it are cached but not refreshed (default: 10000)
+	MaxTracked int
+
+	// QueueSize bounds the refreshes waiting for a worker; refreshes queued
+	// beyond it are dropped until the next read (default: 1000)
+	QueueSize int
+
+	// Timeout bounds each background load (default: 10s)
+	Timeout time.Duration
+
+	// FailureBackoff is how long reads wait before queuing another refresh of
+	// a key whose refresh failed, doubling with each consecutive failure, so
+	// a broken upstream for one key isn't called on every read (default: 1s)
+	FailureBackoff time.Duration
+
+	// MaxFailureBackoff caps FailureBackoff (default: 1m)
+	MaxFailureBackoff time.Duration
+}
+
+// refreshEntry tracks a key loaded through GetOrSet, with what it takes to
+// load it again.
+type refreshEntry[T any] struct {
+	load     LoadFunc[T]
+	ttl      time.Duration
+	storedAt time.Time
+	hits     int
+	queued   bool
+
+	// version advances on every write of the key, so a refresh loading
+	// meanwhile doesn't overwrite it
+	version uint64
+
+	// expiredAt is when the entry would have expired had it not been
+	// refreshed, zero if it never was
+	expiredAt time.Time
+
+	// failures counts consecutive failed refreshes; none is queued before
+	// retryAt
+	failures int
+	retryAt  time.Time
+}
+
+// refreshAheadCache reloads hot entries loaded through GetOrSet shortly
+// before they expire.
+type refreshAheadCache[T any] struct {
+	next     Cache[T]
+	config   RefreshAheadConfig
+	counters refreshCounters
+
+	mu      sync.Mutex
+	entries map[string]*refreshEntry[T]
+
+	queue     chan string
+	stop      chan struct{}
+	workers   sync.WaitGroup
+	closeOnce sync.Once
+	now       func() time.Time
+}
+
+// NewRefreshAhead wraps a cache so that entries loaded through GetOrSet are
+// reloaded in the background once read at least config.MinHits times and
+// past config.Threshold of their TTL, so hot keys almost never miss. Keys
+// whose refresh fails are retried with exponential backoff. Entries
+// stored without a TTL, or only with Set, are never refreshed. The cache
+// implements RefreshStatsProvider; Close stops the workers.
+func NewRefreshAhead[T any](cache Cache[T], config *RefreshAheadConfig) Cache[T] {
+	var cfg RefreshAheadConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold >= 1 {
+		cfg.Threshold = 0.8
+	}
+	if cfg.MinHits <= 0 {
+		cfg.MinHits = 2
+	}
+	if cfg.MaxTracked <= 0 {
+		cfg.MaxTracked = 10000
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.FailureBackoff <= 0 {
+		cfg.FailureBackoff = time.Second
+	}
+	if cfg.MaxFailureBackoff <= 0 {
+		cfg.MaxFailureBackoff = time.Minute
+	}
+
+	c := &refreshAheadCache[T]{
+		next:    cache,
+		config:  cfg,
+		entries: make(map[string]*refreshEntry[T]),
+		queue:   make(chan string, cfg.QueueSize),
+		stop:    make(chan struct{}),
+		now:     time.Now,
+	}
+	for range cfg.Workers {
+		c.workers.Add(1)
+		go c.work()
+	}
+	return c
+}
+
+func (c *refreshAheadCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	if value, found := c.next.Get(ctx, key); found {
+		c.hit(key)
+		return value, nil
+	}
+
+	value, err := GetOrSet(ctx, c.next, key, ttl, load)
+	if err == nil && ttl > 0 {
+		c.track(key, ttl, load)
+	}
+	return value, err
+}
+
+func (c *refreshAheadCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found := c.next.Get(ctx, key)
+	if found {
+		c.hit(key)
+	}
+	return value, found
+}
+
+func (c *refreshAheadCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetWithError(ctx, c.next, key)
+	if found {
+		c.hit(key)
+	}
+	return value, found, err
+}
+
+func (c *refreshAheadCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := GetMulti(ctx, c.next, keys...)
+	for _, r := range results {
+		if r.Found {
+			c.hit(r.Key)
+		}
+	}
+	return results
+}
+
+func (c *refreshAheadCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *refreshAheadCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *refreshAheadCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := Expire(ctx, c.next, key, ttl); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if ttl > 0 {
+			entry.ttl, entry.storedAt = ttl, c.now()
+			entry.version++
+		} else {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *refreshAheadCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	if err != nil || !stored {
+		return false, err
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if ttl > 0 {
+			entry.ttl, entry.storedAt, entry.hits = ttl, c.now(), 0
+			entry.version++
+		} else {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	return true, nil
+}
+
+func (c *refreshAheadCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	if err != nil || !swapped {
+		return false, err
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if ttl > 0 {
+			entry.ttl, entry.storedAt, entry.hits = ttl, c.now(), 0
+			entry.version++
+		} else {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	return true, nil
+}
+
+func (c *refreshAheadCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	c.untrack(key)
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *refreshAheadCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if ttl > 0 {
+			entry.ttl, entry.storedAt, entry.hits = ttl, c.now(), 0
+			entry.version++
+		} else {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *refreshAheadCache[T]) Delete(ctx context.Context, key string) error {
+	c.untrack(key)
+	return c.next.Delete(ctx, key)
+}
+
+func (c *refreshAheadCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.untrack(key)
+	}
+	return deleteMulti(ctx, c.next, keys)
+}
+
+// Clear stops refreshing every key, so none is reloaded into the cleared
+// cache, and clears the underlying cache.
+func (c *refreshAheadCache[T]) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+
+	return Clear(ctx, c.next)
+}
+
+// Close stops the workers, abandoning queued refreshes, and closes the
+// underlying cache.
+func (c *refreshAheadCache[T]) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.workers.Wait()
+	})
+	return c.next.Close()
+}
+
+func (c *refreshAheadCache[T]) RefreshStats() RefreshStats {
+	return c.counters.snapshot()
+}
+
+func (c *refreshAheadCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *refreshAheadCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// track records a freshly loaded key, making room by forgetting expired
+// entries when MaxTracked is reached.
+func (c *refreshAheadCache[T]) track(key string, ttl time.Duration, load LoadFunc[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.config.MaxTracked {
+		for k, entry := range c.entries {
+			if !entry.queued && now.Sub(entry.storedAt) >= entry.ttl {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.config.MaxTracked {
+			return
+		}
+	}
+	c.entries[key] = &refreshEntry[T]{load: load, ttl: ttl, storedAt: now}
+}
+
+func (c *refreshAheadCache[T]) untrack(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// hit counts a read of key and queues its refresh once it is hot and close
+// to expiring.
+func (c *refreshAheadCache[T]) hit(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	entry.hits++
+
+	now := c.now()
+	if !entry.expiredAt.IsZero() && now.After(entry.expiredAt) {
+		// Without the refresh this read would have missed
+		c.counters.savedMiss()
+		entry.expiredAt = time.Time{}
+	}
+
+	age := now.Sub(entry.storedAt)
+	if entry.queued || entry.hits < c.config.MinHits || age < time.Duration(float64(entry.ttl)*c.config.Threshold) {
+		return
+	}
+	if now.Before(entry.retryAt) {
+		// Backing off after failed refreshes
+		return
+	}
+
+	select {
+	case c.queue <- key:
+		entry.queued = true
+	default:
+		// Workers are saturated; a later read queues it again
+	}
+}
+
+func (c *refreshAheadCache[T]) work() {
+	defer c.workers.Done()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case key := <-c.queue:
+			c.refresh(key)
+		}
+	}
+}
+
+// refresh reloads key and stores the result, keeping the previous value on
+// failure.
+func (c *refreshAheadCache[T]) refresh(key string) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		// Deleted while queued
+		c.mu.Unlock()
+		return
+	}
+	load, ttl, version := entry.load, entry.ttl, entry.version
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	value, err := load(ctx)
+	if err == nil {
+		if !c.unchanged(key, entry, version) {
+			// Deleted, reloaded or written while loading; don't overwrite it
+			c.settle(key, entry)
+			return
+		}
+		err = c.next.Set(ctx, key, value, ttl)
+		if err == nil && !c.unchanged(key, entry, version) {
+			// A write raced the Set and may have landed first; a miss is
+			// better than keeping the refreshed value over it
+			_ = c.next.Delete(ctx, key)
+			c.settle(key, entry)
+			return
+		}
+	}
+	c.counters.record(err)
+	traceKey(KeyEvent{Key: key, Operation: OperationRefresh, Source: "refresh_ahead", Found: err == nil, TTL: ttl, Err: err})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == entry {
+		entry.queued = false
+		if err != nil {
+			entry.failures++
+			entry.retryAt = c.now().Add(c.failureBackoff(entry.failures))
+			return
+		}
+		entry.expiredAt = entry.storedAt.Add(entry.ttl)
+		entry.storedAt, entry.hits = c.now(), 0
+		entry.failures, entry.retryAt = 0, time.Time{}
+	}
+}
+
+// settle lets reads queue entry again after a refresh that stored nothing.
+func (c *refreshAheadCache[T]) settle(key string, entry *refreshEntry[T]) {
+	c.mu.Lock()
+	if c.entries[key] == entry {
+		entry.queued = false
+	}
+	c.mu.Unlock()
+}
+
+// unchanged reports whether entry still tracks key at version.
+func (c *refreshAheadCache[T]) unchanged(key string, entry *refreshEntry[T], version uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key] == entry && entry.version == version
+}
+
+// failureBackoff returns the wait after the given number of consecutive
+// failed refreshes.
+func (c *refreshAheadCache[T]) failureBackoff(failures int) time.Duration {
+	backoff := c.config.FailureBackoff
+	for i := 1; i < failures && backoff < c.config.MaxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, c.config.MaxFailureBackoff)
+}
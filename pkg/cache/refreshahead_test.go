@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for refresh-ahead tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestRefreshAhead(t *testing.T, config *RefreshAheadConfig) (Cache[int64], *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Now()}
+	cache := NewRefreshAhead(NewMemory[int64](nil), config)
+	cache.(*refreshAheadCache[int64]).now = clock.Now
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache, clock
+}
+
+func TestRefreshAhead(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newTestRefreshAhead(t, &RefreshAheadConfig{MinHits: 2})
+
+	var loads atomic.Int64
+	load := func(context.Context) (int64, error) { return loads.Add(1), nil }
+
+	if v, err := GetOrSet(ctx, cache, "k", time.Minute, load); err != nil || v != 1 {
+		t.Fatalf("Expected initial load, got %v, %v", v, err)
+	}
+
+	// Test hot entries aren't refreshed before the threshold
+	cache.Get(ctx, "k")
+	cache.Get(ctx, "k")
+	time.Sleep(20 * time.Millisecond)
+	if loads.Load() != 1 {
+		t.Fatalf("Expected no refresh before the threshold, got %d loads", loads.Load())
+	}
+
+	// Test reads past the threshold queue a background refresh
+	clock.Advance(50 * time.Second)
+	if v, _ := cache.Get(ctx, "k"); v != 1 {
+		t.Errorf("Expected the current value while refreshing, got %v", v)
+	}
+	waitFor(t, func() bool {
+		v, _ := cache.Get(ctx, "k")
+		return v == 2
+	})
+
+	// Test a read after the original expiry counts as a saved miss
+	clock.Advance(20 * time.Second)
+	cache.Get(ctx, "k")
+	stats := cache.(RefreshStatsProvider).RefreshStats()
+	if stats.Successes != 1 || stats.SavedMisses != 1 {
+		t.Errorf("Unexpected refresh stats: %+v", stats)
+	}
+}
+
+func TestRefreshAheadSkipsColdAndDeletedKeys(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newTestRefreshAhead(t, &RefreshAheadConfig{MinHits: 3})
+
+	var loads atomic.Int64
+	load := func(context.Context) (int64, error) { return loads.Add(1), nil }
+
+	_, _ = GetOrSet(ctx, cache, "cold", time.Minute, load)
+	_, _ = GetOrSet(ctx, cache, "deleted", time.Minute, load)
+	_, _ = GetOrSet(ctx, cache, "forever", 0, load)
+	_ = cache.Delete(ctx, "deleted")
+
+	clock.Advance(55 * time.Second)
+	cache.Get(ctx, "cold")
+	for range 3 {
+		cache.Get(ctx, "forever")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if loads.Load() != 3 {
+		t.Errorf("Expected no refreshes, got %d loads", loads.Load())
+	}
+	if stats := cache.(RefreshStatsProvider).RefreshStats(); stats.Attempts != 0 {
+		t.Errorf("Expected no refresh attempts, got %+v", stats)
+	}
+}
+
+func TestRefreshAheadKeepsWritesMadeWhileLoading(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newTestRefreshAhead(t, &RefreshAheadConfig{MinHits: 1})
+
+	loading := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var loads atomic.Int64
+	load := func(context.Context) (int64, error) {
+		if loads.Add(1) > 1 {
+			loading <- struct{}{}
+			<-release
+		}
+		return 1, nil
+	}
+	_, _ = GetOrSet(ctx, cache, "k", time.Minute, load)
+
+	clock.Advance(50 * time.Second)
+	cache.Get(ctx, "k")
+	<-loading
+
+	// Test a write during the refresh isn't overwritten by its stale load
+	_ = cache.Set(ctx, "k", 100, time.Minute)
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+
+	if v, found := cache.Get(ctx, "k"); !found || v != 100 {
+		t.Errorf("Expected the write made while loading, got %v (found=%v)", v, found)
+	}
+}
+
+func TestRefreshAheadKeepsValueOnFailure(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newTestRefreshAhead(t, &RefreshAheadConfig{MinHits: 1})
+
+	var failing atomic.Bool
+	load := func(context.Context) (int64, error) {
+		if failing.Load() {
+			return 0, context.DeadlineExceeded
+		}
+		return 7, nil
+	}
+	_, _ = GetOrSet(ctx, cache, "k", time.Minute, load)
+
+	failing.Store(true)
+	clock.Advance(50 * time.Second)
+	cache.Get(ctx, "k")
+	waitFor(t, func() bool {
+		return cache.(RefreshStatsProvider).RefreshStats().Failures == 1
+	})
+	if v, found := cache.Get(ctx, "k"); !found || v != 7 {
+		t.Errorf("Expected the previous value, got %v (found=%v)", v, found)
+	}
+}
+
+func TestRefreshAheadBacksOffFailingKeys(t *testing.T) {
+	ctx := context.Background()
+	cache, clock := newTestRefreshAhead(t, &RefreshAheadConfig{
+		MinHits:           1,
+		FailureBackoff:    time.Second,
+		MaxFailureBackoff: 3 * time.Second,
+	})
+
+	var failing atomic.Bool
+	load := func(context.Context) (int64, error) {
+		if failing.Load() {
+			return 0, context.DeadlineExceeded
+		}
+		return 1, nil
+	}
+	_, _ = GetOrSet(ctx, cache, "k", time.Minute, load)
+
+	failing.Store(true)
+	clock.Advance(50 * time.Second)
+	attempts := func() uint64 { return cache.(RefreshStatsProvider).RefreshStats().Attempts }
+	readUntil := func(want uint64) {
+		t.Helper()
+		waitFor(t, func() bool {
+			cache.Get(ctx, "k")
+			return attempts() == want
+		})
+	}
+
+	readUntil(1)
+
+	// Test reads within the backoff don't queue refreshes
+	for range 10 {
+		cache.Get(ctx, "k")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if attempts() != 1 {
+		t.Fatalf("Expected no refresh within the backoff, got %d attempts", attempts())
+	}
+
+	// Test the backoff doubles after consecutive failures, up to the cap
+	clock.Advance(time.Second)
+	readUntil(2)
+	clock.Advance(time.Second)
+	cache.Get(ctx, "k")
+	time.Sleep(20 * time.Millisecond)
+	if attempts() != 2 {
+		t.Fatalf("Expected a 2s backoff after the second failure, got %d attempts", attempts())
+	}
+
+	refresher := cache.(*refreshAheadCache[int64])
+	if got := refresher.failureBackoff(5); got != 3*time.Second {
+		t.Errorf("Expected backoff capped at 3s, got %v", got)
+	}
+
+	// Test a successful refresh clears the backoff
+	failing.Store(false)
+	clock.Advance(time.Second)
+	readUntil(3)
+	if stats := refresher.RefreshStats(); stats.Successes != 1 {
+		t.Errorf("Expected the retry to succeed, got %+v", stats)
+	}
+	refresher.mu.Lock()
+	failures, retryAt := refresher.entries["k"].failures, refresher.entries["k"].retryAt
+	refresher.mu.Unlock()
+	if failures != 0 || !retryAt.IsZero() {
+		t.Errorf("Expected the backoff cleared, got %d failures until %v", failures, retryAt)
+	}
+}
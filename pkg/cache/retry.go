@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// RetryConfig holds configuration for cache-level retries.
+type RetryConfig struct {
+	// MaxAttempts is the number of attempts of an operation, the first one
+	// included (default: 3)
+	MaxAttempts int
+
+	// Budgets overrides MaxAttempts per operation, e.g.
+	// {OperationGet: 2}; Exists and TTL count as OperationGet and
+	// DeleteMulti as OperationDelete (optional)
+	Budgets map[Operation]int
+
+	// Operations are the operations retried (default: OperationGet and
+	// OperationDelete, which are idempotent). Retrying others, e.g.
+	// OperationSet, may apply them twice when a failed attempt reached the
+	// backend
+	Operations []Operation
+
+	// InitialBackoff is the wait before the first retry (default: 10ms)
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts (default: 1s)
+	MaxBackoff time.Duration
+
+	// Multiplier grows the wait after each retry (default: 2)
+	Multiplier float64
+
+	// Jitter is the fraction of each wait that is randomized, spreading the
+	// retries of concurrent callers (default: 0.2)
+	Jitter float64
+
+	// Retryable decides whether an error is worth retrying (default: every
+	// error but ErrClosed and context cancellation)
+	Retryable func(error) bool
+
+	// Logger logs retried failures (optional)
+	Logger *slog.Logger
+}
+
+// retryCache retries failed operations with exponential backoff.
+type retryCache[T any] struct {
+	next    Cache[T]
+	config  RetryConfig
+	retried map[Operation]bool
+}
+
+// NewRetrying wraps a cache so that failed operations are retried with
+// exponential backoff and jitter, on top of the retries of the Redis client,
+// which only cover network errors of single commands. Only Operations are
+// retried, Get and Delete by default, up to their budget of attempts; the
+// error of the last attempt is returned. Waits end early when ctx is done.
+//
+// Reads are retried on the errors they report to error-aware callers: backends
+// answering failures as misses, such as distributed caches with
+// BackendDownMiss, leave nothing to retry. GetOrSet is not retried, as it
+// would call its loader again.
+func NewRetrying[T any](cache Cache[T], config *RetryConfig) Cache[T] {
+	var cfg RetryConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 3
+	}
+	if len(cfg.Operations) == 0 {
+		cfg.Operations = []Operation{OperationGet, OperationDelete}
+	}
+	if cfg.InitialBackoff == 0 {
+		cfg.InitialBackoff = 10 * time.Millisecond
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = time.Second
+	}
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = 2
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = 0.2
+	}
+	if cfg.Retryable == nil {
+		cfg.Retryable = isRetryable
+	}
+
+	retried := make(map[Operation]bool, len(cfg.Operations))
+	for _, op := range cfg.Operations {
+		retried[op] = true
+	}
+	return &retryCache[T]{next: cache, config: cfg, retried: retried}
+}
+
+// isRetryable reports whether err may go away on another attempt.
+func isRetryable(err error) bool {
+	return !errors.Is(err, ErrClosed) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// attempts returns the number of attempts of op.
+func (c *retryCache[T]) attempts(op Operation) int {
+	if !c.retried[op] {
+		return 1
+	}
+	if n, ok := c.config.Budgets[op]; ok {
+		return max(n, 1)
+	}
+	return c.config.MaxAttempts
+}
+
+// backoff returns the wait before retry number n, counting from 1.
+func (c *retryCache[T]) backoff(n int) time.Duration {
+	wait := float64(c.config.InitialBackoff) * math.Pow(c.config.Multiplier, float64(n-1))
+	wait = min(wait, float64(c.config.MaxBackoff))
+	wait -= wait * c.config.Jitter * rand.Float64()
+	return time.Duration(wait)
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't
+// retryable or exhausts the attempts of op.
+func retry[T, R any](ctx context.Context, c *retryCache[T], op Operation, key string, fn func() (R, error)) (R, error) {
+	result, err := fn()
+	for n := 1; n < c.attempts(op) && err != nil && c.config.Retryable(err); n++ {
+		if c.config.Logger != nil {
+			c.config.Logger.WarnContext(ctx, "cache: retrying failed operation",
+				slog.String("operation", string(op)),
+				slog.String("key", key),
+				slog.Int("attempt", n+1),
+				slog.Any("error", err),
+			)
+		}
+
+		timer := time.NewTimer(c.backoff(n))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		result, err = fn()
+	}
+	return result, err
+}
+
+// readResult pairs a value read with whether it was found, for helpers
+// running reads that return both.
+type readResult[T any] struct {
+	value T
+	found bool
+}
+
+func (c *retryCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *retryCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	r, err := retry(ctx, c, OperationGet, key, func() (readResult[T], error) {
+		value, ok, err := GetWithError(ctx, c.next, key)
+		return readResult[T]{value, ok}, err
+	})
+	return r.value, r.found, err
+}
+
+// GetMulti reads the keys in one batch and retries the keys that failed
+// with a retryable error, in one batch per attempt.
+func (c *retryCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := make([]KeyResult[T], len(keys))
+	pending := make([]int, len(keys))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	_, _ = retry(ctx, c, OperationGet, strings.Join(keys, ","), func() (struct{}, error) {
+		batch := make([]string, len(pending))
+		for j, i := range pending {
+			batch[j] = keys[i]
+		}
+
+		var failed []int
+		var err error
+		for j, r := range GetMulti(ctx, c.next, batch...) {
+			results[pending[j]] = r
+			if r.Err != nil && c.config.Retryable(r.Err) {
+				failed = append(failed, pending[j])
+				if err == nil {
+					err = r.Err
+				}
+			}
+		}
+		pending = failed
+		return struct{}{}, err
+	})
+	return results
+}
+
+func (c *retryCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, load)
+}
+
+func (c *retryCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return retry(ctx, c, OperationGet, key, func() (bool, error) {
+		return Exists(ctx, c.next, key)
+	})
+}
+
+func (c *retryCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	r, err := retry(ctx, c, OperationGet, key, func() (readResult[time.Duration], error) {
+		ttl, ok, err := TTL(ctx, c.next, key)
+		return readResult[time.Duration]{ttl, ok}, err
+	})
+	return r.value, r.found, err
+}
+
+func (c *retryCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	_, err := retry(ctx, c, OperationExpire, key, func() (struct{}, error) {
+		return struct{}{}, Expire(ctx, c.next, key, ttl)
+	})
+	return err
+}
+
+func (c *retryCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return retry(ctx, c, OperationSetIfAbsent, key, func() (bool, error) {
+		return SetIfAbsent(ctx, c.next, key, value, ttl)
+	})
+}
+
+func (c *retryCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return retry(ctx, c, OperationCompareAndSwap, key, func() (bool, error) {
+		return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	})
+}
+
+func (c *retryCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	r, err := retry(ctx, c, OperationGetDel, key, func() (readResult[T], error) {
+		value, ok, err := GetDel(ctx, c.next, key)
+		return readResult[T]{value, ok}, err
+	})
+	return r.value, r.found, err
+}
+
+func (c *retryCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	_, err := retry(ctx, c, OperationSet, key, func() (struct{}, error) {
+		return struct{}{}, c.next.Set(ctx, key, value, ttl)
+	})
+	return err
+}
+
+func (c *retryCache[T]) Delete(ctx context.Context, key string) error {
+	_, err := retry(ctx, c, OperationDelete, key, func() (struct{}, error) {
+		return struct{}{}, c.next.Delete(ctx, key)
+	})
+	return err
+}
+
+func (c *retryCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	_, err := retry(ctx, c, OperationDelete, "", func() (struct{}, error) {
+		return struct{}{}, deleteMulti(ctx, c.next, keys)
+	})
+	return err
+}
+
+func (c *retryCache[T]) Clear(ctx context.Context) error {
+	_, err := retry(ctx, c, OperationClear, "", func() (struct{}, error) {
+		return struct{}{}, Clear(ctx, c.next)
+	})
+	return err
+}
+
+func (c *retryCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *retryCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *retryCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
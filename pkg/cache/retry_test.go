@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errFlaky = errors.New("flaky backend")
+
+// flakyCache fails the first failures calls of every operation it counts.
+type flakyCache[T any] struct {
+	Cache[T]
+	failures int32
+	calls    atomic.Int32
+}
+
+func (c *flakyCache[T]) fail() error {
+	if c.calls.Add(1) <= c.failures {
+		return errFlaky
+	}
+	return nil
+}
+
+func (c *flakyCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if err := c.fail(); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return GetWithError(ctx, c.Cache, key)
+}
+
+func (c *flakyCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.fail(); err != nil {
+		return err
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func (c *flakyCache[T]) Delete(ctx context.Context, key string) error {
+	if err := c.fail(); err != nil {
+		return err
+	}
+	return c.Cache.Delete(ctx, key)
+}
+
+func newFlakyCache(failures int32) *flakyCache[TestUser] {
+	return &flakyCache[TestUser]{Cache: NewMemory[TestUser](nil), failures: failures}
+}
+
+func TestRetryingRetriesIdempotentOperations(t *testing.T) {
+	ctx := context.Background()
+	flaky := newFlakyCache(2)
+	_ = flaky.Cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	cache := NewRetrying[TestUser](flaky, &RetryConfig{InitialBackoff: time.Millisecond})
+
+	value, found, err := GetWithError(ctx, cache, "1")
+	if err != nil || !found || value.ID != "1" {
+		t.Fatalf("Expected the third attempt to succeed, got found=%v err=%v", found, err)
+	}
+	if got := flaky.calls.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+
+	flaky.calls.Store(0)
+	if err := cache.Set(ctx, "1", TestUser{}, time.Minute); !errors.Is(err, errFlaky) {
+		t.Errorf("Expected Set not to be retried by default, got %v", err)
+	}
+	if got := flaky.calls.Load(); got != 1 {
+		t.Errorf("Expected 1 Set attempt, got %d", got)
+	}
+
+	flaky.calls.Store(0)
+	if err := cache.Delete(ctx, "1"); err != nil {
+		t.Errorf("Expected Delete to be retried, got %v", err)
+	}
+}
+
+func TestRetryingBudgets(t *testing.T) {
+	ctx := context.Background()
+	flaky := newFlakyCache(5)
+	cache := NewRetrying[TestUser](flaky, &RetryConfig{
+		Operations:     []Operation{OperationGet, OperationSet},
+		Budgets:        map[Operation]int{OperationSet: 2},
+		InitialBackoff: time.Millisecond,
+	})
+
+	if err := cache.Set(ctx, "1", TestUser{}, time.Minute); !errors.Is(err, errFlaky) {
+		t.Errorf("Expected the last error, got %v", err)
+	}
+	if got := flaky.calls.Load(); got != 2 {
+		t.Errorf("Expected the Set budget of 2 attempts, got %d", got)
+	}
+
+	flaky.calls.Store(0)
+	_, _, _ = GetWithError(ctx, cache, "1")
+	if got := flaky.calls.Load(); got != 3 {
+		t.Errorf("Expected MaxAttempts for Get, got %d", got)
+	}
+}
+
+func TestRetryingStopsOnDoneContext(t *testing.T) {
+	flaky := newFlakyCache(5)
+	cache := NewRetrying[TestUser](flaky, &RetryConfig{InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := GetWithError(ctx, cache, "1"); !errors.Is(err, errFlaky) {
+		t.Errorf("Expected the backend error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected the wait to end with the context")
+	}
+}
+
+func TestRetryingBackoff(t *testing.T) {
+	c := NewRetrying[TestUser](NewMemory[TestUser](nil), &RetryConfig{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     30 * time.Millisecond,
+		Jitter:         0.5,
+	}).(*retryCache[TestUser])
+
+	for n, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 5: 30 * time.Millisecond} {
+		if got := c.backoff(n); got > want || got < want/2 {
+			t.Errorf("Retry %d: expected a wait in [%v, %v], got %v", n, want/2, want, got)
+		}
+	}
+}
+
+func TestRetryingSkipsClosedCache(t *testing.T) {
+	backend := NewMemory[TestUser](nil)
+	_ = backend.Close()
+	cache := NewRetrying(backend, &RetryConfig{InitialBackoff: time.Hour})
+
+	if _, _, err := GetWithError(context.Background(), cache, "1"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed without retries, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+)
+
+// ristrettoCache is an in-memory cache backed by Ristretto.
+type ristrettoCache[T any] struct {
+	cache     *ristretto.Cache[string, T]
+	ctxPolicy contextPolicy
+	stats     statsCounters
+
+	// Ristretto panics on writes racing with its Close, so operations hold
+	// the read lock and Close the write lock
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newRistrettoCache[T any](config *MemoryConfig) (*ristrettoCache[T], error) {
+	maxEntries := int64(100000)
+	if config != nil && config.MaxEntries > 0 {
+		maxEntries = int64(config.MaxEntries)
+	}
+
+	c := &ristrettoCache[T]{ctxPolicy: newContextPolicy(config)}
+	cache, err := ristretto.NewCache(&ristretto.Config[string, T]{
+		// Ristretto recommends tracking 10x as many keys as the cache holds
+		NumCounters: maxEntries * 10,
+		// Every entry costs 1, so MaxCost is the maximum number of entries
+		MaxCost:            maxEntries,
+		BufferItems:        64,
+		IgnoreInternalCost: true,
+		OnEvict: func(*ristretto.Item[T]) {
+			c.stats.evictions.Add(1)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cache = cache
+	return c, nil
+}
+
+func (c *ristrettoCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *ristrettoCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.ctxPolicy.cancelled(ctx) {
+		return zero, false, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return zero, false, ErrClosed
+	}
+
+	value, found := c.cache.Get(key)
+	c.stats.read(found, nil)
+	return value, found, nil
+}
+
+func (c *ristrettoCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return false, ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return false, ErrClosed
+	}
+
+	_, found := c.cache.Get(key)
+	return found, nil
+}
+
+func (c *ristrettoCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.ctxPolicy.cancelled(ctx) {
+		return 0, false, ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return 0, false, ErrClosed
+	}
+
+	ttl, found := c.cache.GetTTL(key)
+	return ttl, found, nil
+}
+
+// Expire stores the value of key again with ttl. Like Set, the write is
+// applied asynchronously and may be rejected by the admission policy.
+func (c *ristrettoCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	value, found := c.cache.Get(key)
+	if !found {
+		return ErrKeyNotFound
+	}
+	c.cache.SetWithTTL(key, value, 1, ttl)
+	return nil
+}
+
+// Set stores value. Writes are applied asynchronously and may be rejected by
+// Ristretto's admission policy, so a value is only guaranteed to be readable
+// after Flush, and only if it was admitted.
+func (c *ristrettoCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	// Rejections by the admission policy are not errors - the entry simply isn't cached
+	c.cache.SetWithTTL(key, value, 1, ttl)
+	c.stats.wrote(nil)
+	return nil
+}
+
+func (c *ristrettoCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *ristrettoCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		c.cache.Del(key)
+	}
+	c.stats.deleted(len(keys), nil)
+	return nil
+}
+
+// Clear removes every entry, dropping buffered writes.
+func (c *ristrettoCache[T]) Clear(ctx context.Context) error {
+	if c.ctxPolicy.cancelled(ctx) {
+		return ctx.Err()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	c.cache.Clear()
+	return nil
+}
+
+// Flush blocks until all buffered writes have been applied.
+func (c *ristrettoCache[T]) Flush(_ context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+	c.cache.Wait()
+	return nil
+}
+
+// Stats returns the traffic counters; evictions include expired entries.
+func (c *ristrettoCache[T]) Stats() CacheStats {
+	return c.stats.snapshot()
+}
+
+func (c *ristrettoCache[T]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.cache.Close()
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRistrettoCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{Engine: MemoryEngineRistretto, MaxEntries: 1000})
+	defer cache.Close()
+
+	rc, ok := cache.(*ristrettoCache[TestUser])
+	if !ok {
+		t.Fatalf("Expected a Ristretto cache, got %T", cache)
+	}
+
+	// Test Set and Get once the write buffer is applied
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	_ = rc.Flush(ctx)
+	if user, found := cache.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Errorf("Expected key1, got %v, %v", user, found)
+	}
+
+	// Test TTL expiry
+	_ = cache.Set(ctx, "short", TestUser{ID: "456"}, 50*time.Millisecond)
+	_ = rc.Flush(ctx)
+	time.Sleep(100 * time.Millisecond)
+	if _, found := cache.Get(ctx, "short"); found {
+		t.Error("Expected short-lived entry to expire")
+	}
+
+	// Test Delete
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted")
+	}
+}
+
+func TestFactoryRistrettoEngine(t *testing.T) {
+	// Test the factory selects the engine and buffered writes are flushed
+	result := SelfTest(context.Background(), &Config{
+		Type:   TypeMemory,
+		Memory: &MemoryConfig{Engine: MemoryEngineRistretto},
+	}, TestUser{ID: "canary"})
+	if !result.Passed() {
+		t.Errorf("Expected self-test to pass, got: %v", result.Err())
+	}
+}
+
+func TestRistrettoCacheClose(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{Engine: MemoryEngineRistretto})
+
+	// Test Close racing with writes neither panics nor returns other errors
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+				if err != nil && !errors.Is(err, ErrClosed) {
+					t.Errorf("Expected nil or ErrClosed, got: %v", err)
+					return
+				}
+				_ = cache.Delete(ctx, "key1")
+			}
+		}()
+	}
+	_ = cache.Close()
+	wg.Wait()
+
+	if err := cache.Close(); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{}, time.Minute); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+	if _, _, err := GetWithError(ctx, cache, "key1"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeySamplerConfig holds configuration for a KeySampler.
+type KeySamplerConfig struct {
+	// Pattern restricts sampling to keys matching a SCAN MATCH pattern (default: "*")
+	Pattern string
+
+	// SampleSize is the maximum number of keys inspected per round (default: 100)
+	SampleSize int
+
+	// Interval is the time between sampling rounds when started (default: 1m)
+	Interval time.Duration
+
+	// TTLBuckets are the upper bounds of the TTL histogram buckets, in ascending order
+	// (default: 1s, 10s, 1m, 10m, 1h, 24h)
+	TTLBuckets []time.Duration
+
+	// SizeBuckets are the upper bounds in bytes of the value size histogram buckets,
+	// in ascending order (default: 128, 1KiB, 16KiB, 128KiB, 1MiB)
+	SizeBuckets []int64
+}
+
+// DurationBucket is a histogram bucket counting TTLs up to UpperBound.
+type DurationBucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+// SizeBucket is a histogram bucket counting value sizes up to UpperBound bytes.
+type SizeBucket struct {
+	UpperBound int64
+	Count      int
+}
+
+// KeySampleStats describes the TTL and size distribution of a sample of keys.
+type KeySampleStats struct {
+	// SampledAt is when the sample was taken.
+	SampledAt time.Time
+
+	// Keys is the number of keys inspected.
+	Keys int
+
+	// Persistent is the number of sampled keys without a TTL.
+	Persistent int
+
+	// TTL is the histogram of remaining TTLs for keys that have one.
+	// The final bucket has an UpperBound of -1 and counts everything above the last bound.
+	TTL []DurationBucket
+
+	// Size is the histogram of value sizes in bytes.
+	// The final bucket has an UpperBound of -1 and counts everything above the last bound.
+	Size []SizeBucket
+
+	// TotalBytes is the sum of the sampled value sizes.
+	TotalBytes int64
+}
+
+// KeySampler periodically samples keys from a distributed cache and records
+// their TTL and size distributions, giving visibility into server-side state
+// the client otherwise can't see.
+type KeySampler struct {
+	client redis.UniversalClient
+	config KeySamplerConfig
+
+	mu     sync.Mutex
+	cursor uint64
+	stats  KeySampleStats
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewKeySampler creates a sampler for the Redis/Valkey namespace behind cache.
+// Returns an error if cache is not backed by a distributed backend.
+func NewKeySampler[T any](cache Cache[T], config *KeySamplerConfig) (*KeySampler, error) {
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg KeySamplerConfig
+	if config != nil {
+		cfg = *config
+	}
+	ensureKeySamplerDefaults(&cfg)
+
+	return &KeySampler{
+		client: client,
+		config: cfg,
+	}, nil
+}
+
+func ensureKeySamplerDefaults(config *KeySamplerConfig) {
+	if config.Pattern == "" {
+		config.Pattern = "*"
+	}
+	if config.SampleSize == 0 {
+		config.SampleSize = 100
+	}
+	if config.Interval == 0 {
+		config.Interval = time.Minute
+	}
+	if len(config.TTLBuckets) == 0 {
+		config.TTLBuckets = []time.Duration{
+			time.Second, 10 * time.Second, time.Minute, 10 * time.Minute, time.Hour, 24 * time.Hour,
+		}
+	}
+	if len(config.SizeBuckets) == 0 {
+		config.SizeBuckets = []int64{128, 1 << 10, 16 << 10, 128 << 10, 1 << 20}
+	}
+}
+
+// Sample takes a single sample and stores it as the latest stats.
+// Successive calls continue the SCAN where the previous one stopped,
+// so repeated samples cover different parts of the keyspace.
+func (s *KeySampler) Sample(ctx context.Context) (KeySampleStats, error) {
+	s.mu.Lock()
+	cursor := s.cursor
+	s.mu.Unlock()
+
+	keys := make([]string, 0, s.config.SampleSize)
+	for len(keys) < s.config.SampleSize {
+		batch, next, err := s.client.Scan(ctx, cursor, s.config.Pattern, int64(s.config.SampleSize)).Result()
+		if err != nil {
+			return KeySampleStats{}, err
+		}
+		keys = append(keys, batch...)
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(keys) > s.config.SampleSize {
+		keys = keys[:s.config.SampleSize]
+	}
+
+	pipe := s.client.Pipeline()
+	ttls := make([]*redis.DurationCmd, len(keys))
+	sizes := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		ttls[i] = pipe.PTTL(ctx, key)
+		sizes[i] = pipe.StrLen(ctx, key)
+	}
+	if len(keys) > 0 {
+		// Individual command errors (e.g. a key expiring mid-sample) are checked below
+		_, _ = pipe.Exec(ctx)
+	}
+
+	stats := newKeySampleStats(s.config)
+	for i := range keys {
+		ttl, err := ttls[i].Result()
+		if err != nil || ttl == -2 {
+			// Key disappeared between SCAN and PTTL
+			continue
+		}
+		size, err := sizes[i].Result()
+		if err != nil {
+			// Not a string value, e.g. an index maintained by another feature
+			continue
+		}
+		stats.add(ttl, size)
+	}
+
+	s.mu.Lock()
+	s.cursor = cursor
+	s.stats = stats
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+// Stats returns the most recent sample.
+func (s *KeySampler) Stats() KeySampleStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Start begins sampling every Interval in the background until Stop is called
+// or ctx is cancelled. Sampling errors are ignored; the previous stats are kept.
+func (s *KeySampler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return errors.New("key sampler already started")
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(ctx, s.stop, s.done)
+	return nil
+}
+
+// Stop stops background sampling and waits for the current round to finish.
+func (s *KeySampler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (s *KeySampler) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	_, _ = s.Sample(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+			_, _ = s.Sample(ctx)
+		}
+	}
+}
+
+func newKeySampleStats(config KeySamplerConfig) KeySampleStats {
+	ttlBuckets := make([]DurationBucket, 0, len(config.TTLBuckets)+1)
+	for _, bound := range config.TTLBuckets {
+		ttlBuckets = append(ttlBuckets, DurationBucket{UpperBound: bound})
+	}
+	ttlBuckets = append(ttlBuckets, DurationBucket{UpperBound: -1})
+
+	sizeBuckets := make([]SizeBucket, 0, len(config.SizeBuckets)+1)
+	for _, bound := range config.SizeBuckets {
+		sizeBuckets = append(sizeBuckets, SizeBucket{UpperBound: bound})
+	}
+	sizeBuckets = append(sizeBuckets, SizeBucket{UpperBound: -1})
+
+	return KeySampleStats{
+		SampledAt: time.Now(),
+		TTL:       ttlBuckets,
+		Size:      sizeBuckets,
+	}
+}
+
+// add records one key. A negative ttl means the key has no expiry.
+func (s *KeySampleStats) add(ttl time.Duration, size int64) {
+	s.Keys++
+	s.TotalBytes += size
+
+	if ttl < 0 {
+		s.Persistent++
+	} else {
+		i := sort.Search(len(s.TTL)-1, func(i int) bool { return ttl <= s.TTL[i].UpperBound })
+		s.TTL[i].Count++
+	}
+
+	i := sort.Search(len(s.Size)-1, func(i int) bool { return size <= s.Size[i].UpperBound })
+	s.Size[i].Count++
+}
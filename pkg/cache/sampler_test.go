@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKeySampleStatsHistogram(t *testing.T) {
+	config := KeySamplerConfig{
+		TTLBuckets:  []time.Duration{time.Second, time.Minute},
+		SizeBuckets: []int64{10, 100},
+	}
+	stats := newKeySampleStats(config)
+
+	stats.add(500*time.Millisecond, 5)
+	stats.add(time.Second, 10)
+	stats.add(30*time.Second, 50)
+	stats.add(time.Hour, 1000)
+	stats.add(-1, 100)
+
+	if stats.Keys != 5 {
+		t.Errorf("Expected 5 keys, got %d", stats.Keys)
+	}
+	if stats.Persistent != 1 {
+		t.Errorf("Expected 1 persistent key, got %d", stats.Persistent)
+	}
+	if stats.TotalBytes != 1165 {
+		t.Errorf("Expected 1165 total bytes, got %d", stats.TotalBytes)
+	}
+
+	wantTTL := []int{2, 1, 1}
+	for i, want := range wantTTL {
+		if stats.TTL[i].Count != want {
+			t.Errorf("TTL bucket %d: expected %d, got %d", i, want, stats.TTL[i].Count)
+		}
+	}
+	wantSize := []int{2, 2, 1}
+	for i, want := range wantSize {
+		if stats.Size[i].Count != want {
+			t.Errorf("Size bucket %d: expected %d, got %d", i, want, stats.Size[i].Count)
+		}
+	}
+}
+
+func TestKeySamplerRequiresDistributedCache(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_, err := NewKeySampler(cache, nil)
+	if !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestKeySamplerWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		err := cache.Set(ctx, fmt.Sprintf("sampled:%d", i), TestUser{ID: "123"}, time.Hour)
+		if err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := cache.Set(ctx, "other", TestUser{ID: "123"}, time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	sampler, err := NewKeySampler(cache, &KeySamplerConfig{Pattern: "sampled:*", SampleSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to create sampler: %v", err)
+	}
+
+	stats, err := sampler.Sample(ctx)
+	if err != nil {
+		t.Fatalf("Sample failed: %v", err)
+	}
+	if stats.Keys == 0 || stats.Keys > 10 {
+		t.Errorf("Expected between 1 and 10 sampled keys, got %d", stats.Keys)
+	}
+	if stats.Persistent != 0 {
+		t.Errorf("Expected no persistent keys, got %d", stats.Persistent)
+	}
+	if sampler.Stats().Keys != stats.Keys {
+		t.Error("Expected Stats to return the latest sample")
+	}
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotScannable is returned by ScanKeys for caches whose keys can't be enumerated.
+var ErrNotScannable = errors.New("cache does not support key enumeration")
+
+// keyScanner is implemented by backends that enumerate their own keys.
+// Patterns are matched with path.Match.
+type keyScanner interface {
+	scanKeys(ctx context.Context, cfg ScanConfig, fn func([]KeyInfo) error) error
+}
+
+// keyScannerOf returns the backend behind cache that enumerates its own
+// keys, looking through decorators.
+func keyScannerOf[T any](cache Cache[T]) (keyScanner, bool) {
+	for cache != nil {
+		if ks, ok := cache.(keyScanner); ok {
+			return ks, true
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return nil, false
+}
+
+// ScanConfig holds configuration for ScanKeys.
+type ScanConfig struct {
+	// Pattern selects keys with Redis glob syntax (default: "*")
+	Pattern string
+
+	// BatchSize is the number of keys passed to each callback (default: 100)
+	BatchSize int
+}
+
+// KeyInfo describes a stored key.
+type KeyInfo struct {
+	// Key is the cache key.
+	Key string
+
+	// Size is the stored value size in bytes, or -1 when the backend keeps
+	// values unserialized (in-memory caches).
+	Size int64
+
+	// TTL is the remaining time to live (0 for keys without expiry).
+	TTL time.Duration
+}
+
+// ScanKeys enumerates the keys stored in cache in batches, looking through
+// decorators to the Redis/Valkey, SQL, disk or in-memory backend. Caches
+// wrapped WithPrefix only enumerate their namespace and report keys without
+// the prefix. Enumeration is not a snapshot: keys written or expiring during
+// the scan may be skipped or, on Redis, reported more than once. fn errors
+// stop the scan and are returned. Returns ErrNotScannable for other
+// backends: DynamoDB, where enumerating would scan the whole table, and the
+// Ristretto memory engine.
+func ScanKeys[T any](ctx context.Context, cache Cache[T], config *ScanConfig, fn func(batch []KeyInfo) error) error {
+	var cfg ScanConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Pattern == "" {
+		cfg.Pattern = "*"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+
+	if prefix := keyPrefixOf(cache); prefix != "" {
+		// Only enumerate the namespace, reporting keys as callers see them
+		cfg.Pattern = globReplacer.Replace(prefix) + cfg.Pattern
+		report := fn
+		fn = func(batch []KeyInfo) error {
+			for i := range batch {
+				batch[i].Key = strings.TrimPrefix(batch[i].Key, prefix)
+			}
+			return report(batch)
+		}
+	}
+
+	if client, err := redisClientOf(cache); err == nil {
+		return scanRedisKeys(ctx, client, cfg, fn)
+	}
+	if ks, ok := keyScannerOf(cache); ok {
+		return ks.scanKeys(ctx, cfg, fn)
+	}
+	if mc, err := memoryCacheOf(cache); err == nil {
+		return scanMemoryKeys(ctx, mc, cfg, fn)
+	}
+	return ErrNotScannable
+}
+
+// scanRedis calls fn with the keys matching pattern, one SCAN page of about
+// count keys at a time. Cluster masters are scanned one after another.
+func scanRedis(ctx context.Context, client redis.UniversalClient, pattern string, count int64, fn func(keys []string) error) error {
+	nodes := []redis.Cmdable{client}
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		nodes = nil
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			nodes = append(nodes, node)
+			return nil
+		})
+		if err != nil {
+			return closedErr(err)
+		}
+	}
+
+	for _, node := range nodes {
+		var cursor uint64
+		for {
+			keys, next, err := node.Scan(ctx, cursor, pattern, count).Result()
+			if err != nil {
+				return closedErr(err)
+			}
+			if len(keys) > 0 {
+				if err := fn(keys); err != nil {
+					return err
+				}
+			}
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func scanRedisKeys(ctx context.Context, client redis.UniversalClient, cfg ScanConfig, fn func([]KeyInfo) error) error {
+	return scanRedis(ctx, client, cfg.Pattern, int64(cfg.BatchSize), func(keys []string) error {
+		pipe := client.Pipeline()
+		sizes := make([]*redis.IntCmd, len(keys))
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, key := range keys {
+			sizes[i] = pipe.StrLen(ctx, key)
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		// Individual command errors (e.g. a key expiring mid-scan) are checked below
+		_, _ = pipe.Exec(ctx)
+
+		batch := make([]KeyInfo, 0, len(keys))
+		for i, key := range keys {
+			ttl, err := ttls[i].Result()
+			if err != nil || ttl == -2 {
+				// Removed since SCAN returned it
+				continue
+			}
+			if ttl < 0 {
+				ttl = 0
+			}
+			batch = append(batch, KeyInfo{Key: key, Size: sizes[i].Val(), TTL: ttl})
+		}
+		return fn(batch)
+	})
+}
+
+func scanMemoryKeys[T any](ctx context.Context, mc *memoryCache[T], cfg ScanConfig, fn func([]KeyInfo) error) error {
+	if mc.closed.isClosed() {
+		return ErrClosed
+	}
+
+	batch := make([]KeyInfo, 0, cfg.BatchSize)
+	for _, key := range mc.cache.GetKeys() {
+		if matched, err := path.Match(cfg.Pattern, key); err != nil {
+			return err
+		} else if !matched {
+			continue
+		}
+		now := time.Now()
+		item := mc.peek(key, now)
+		if item == nil {
+			// Expired or removed since GetKeys
+			continue
+		}
+
+		batch = append(batch, KeyInfo{Key: key, Size: -1, TTL: item.remaining(now)})
+		if len(batch) == cfg.BatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]KeyInfo, 0, cfg.BatchSize)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func collectKeys(t *testing.T, cache Cache[TestUser], config *ScanConfig) []KeyInfo {
+	t.Helper()
+
+	var keys []KeyInfo
+	err := ScanKeys(context.Background(), cache, config, func(batch []KeyInfo) error {
+		keys = append(keys, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanKeys failed: %v", err)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	return keys
+}
+
+func TestScanKeysMemory(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := New[TestUser](&Config{Type: TypeMemory})
+	defer cache.Close()
+
+	for i := range 5 {
+		_ = cache.Set(ctx, "user:"+strconv.Itoa(i), TestUser{ID: strconv.Itoa(i)}, time.Minute)
+	}
+	_ = cache.Set(ctx, "order:1", TestUser{}, 0)
+
+	keys := collectKeys(t, cache, &ScanConfig{Pattern: "user:*", BatchSize: 2})
+	if len(keys) != 5 {
+		t.Fatalf("Expected 5 user keys, got %d", len(keys))
+	}
+	if keys[0].Key != "user:0" || keys[0].Size != -1 || keys[0].TTL <= 0 || keys[0].TTL > time.Minute {
+		t.Errorf("Unexpected key info: %+v", keys[0])
+	}
+
+	all := collectKeys(t, cache, nil)
+	if len(all) != 6 || all[0].Key != "order:1" || all[0].TTL != 0 {
+		t.Errorf("Expected all keys with order:1 first and without expiry, got %+v", all)
+	}
+}
+
+func TestScanKeysStopsOnCallbackError(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	for i := range 5 {
+		_ = cache.Set(ctx, strconv.Itoa(i), TestUser{}, time.Minute)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := ScanKeys(ctx, cache, &ScanConfig{BatchSize: 1}, func([]KeyInfo) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected scan to stop after 1 call, got %d calls and %v", calls, err)
+	}
+}
+
+func TestScanKeysDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	for i := range 5 {
+		_ = cache.Set(ctx, "user:"+strconv.Itoa(i), TestUser{ID: strconv.Itoa(i)}, time.Minute)
+	}
+	_ = cache.Set(ctx, "order:1", TestUser{}, 0)
+	_ = cache.Set(ctx, "user:expired", TestUser{}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	// Batches smaller than the keyspace page through it
+	keys := collectKeys(t, cache, &ScanConfig{Pattern: "user:*", BatchSize: 2})
+	if len(keys) != 5 {
+		t.Fatalf("Expected 5 live user keys, got %+v", keys)
+	}
+	if keys[0].Key != "user:0" || keys[0].Size <= 0 || keys[0].TTL <= 0 || keys[0].TTL > time.Minute {
+		t.Errorf("Unexpected key info: %+v", keys[0])
+	}
+
+	all := collectKeys(t, cache, &ScanConfig{BatchSize: 1})
+	if len(all) != 6 || all[0].Key != "order:1" || all[0].TTL != 0 {
+		t.Errorf("Expected all live keys with order:1 first and without expiry, got %+v", all)
+	}
+}
+
+func TestScanKeysFreeCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{Engine: MemoryEngineFreeCache})
+	defer cache.Close()
+
+	for i := range 3 {
+		_ = cache.Set(ctx, "user:"+strconv.Itoa(i), TestUser{ID: strconv.Itoa(i)}, time.Minute)
+	}
+	_ = cache.Set(ctx, "order:1", TestUser{}, 0)
+
+	keys := collectKeys(t, cache, &ScanConfig{Pattern: "user:*", BatchSize: 2})
+	if len(keys) != 3 {
+		t.Fatalf("Expected 3 user keys, got %+v", keys)
+	}
+	if keys[0].Size <= 0 || keys[0].TTL <= 0 || keys[0].TTL > time.Minute {
+		t.Errorf("Unexpected key info: %+v", keys[0])
+	}
+}
+
+func TestScanKeysPrefixedDisk(t *testing.T) {
+	ctx := context.Background()
+	backend := newTestDiskCache(t, &DiskConfig{})
+	cache := WithPrefix(backend, "tenant:")
+
+	_ = cache.Set(ctx, "a", TestUser{}, 0)
+	_ = backend.Set(ctx, "a", TestUser{}, 0)
+
+	keys := collectKeys(t, cache, nil)
+	if len(keys) != 1 || keys[0].Key != "a" {
+		t.Errorf("Expected only the namespace, without its prefix, got %+v", keys)
+	}
+}
+
+func TestScanKeysNotScannable(t *testing.T) {
+	err := ScanKeys(context.Background(), NewNoOp[TestUser](), nil, func([]KeyInfo) error { return nil })
+	if !errors.Is(err, ErrNotScannable) {
+		t.Errorf("Expected ErrNotScannable, got: %v", err)
+	}
+}
+
+func TestScanKeysWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "user:1", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "user:2", TestUser{ID: "2"}, 0)
+
+	keys := collectKeys(t, cache, &ScanConfig{Pattern: "user:*"})
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %+v", keys)
+	}
+	if keys[0].Size <= 0 || keys[0].TTL <= 0 || keys[1].TTL != 0 {
+		t.Errorf("Unexpected key infos: %+v", keys)
+	}
+}
@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationSchedulerConfig holds configuration for an InvalidationScheduler.
+type InvalidationSchedulerConfig struct {
+	// Key is the sorted set holding scheduled invalidations; use one per
+	// cache sharing a Redis/Valkey (default: "cache:invalidations:" followed
+	// by the KeyPrefix of the cache)
+	Key string
+
+	// Client stores the schedule (default: the client of the scheduled cache,
+	// required for caches not backed by Redis/Valkey)
+	Client redis.UniversalClient
+
+	// PollInterval is how often due invalidations are looked for, bounding
+	// how late they run (default: 1s)
+	PollInterval time.Duration
+
+	// BatchSize bounds the invalidations claimed per poll (default: 100)
+	BatchSize int
+
+	// ClaimTimeout is how long claimed invalidations are hidden from other
+	// instances; those still claimed after it, because the claiming instance
+	// crashed, are claimed again (default: 30s)
+	ClaimTimeout time.Duration
+}
+
+// claimDueScript returns the invalidations due by ARGV[1] and reschedules them
+// to ARGV[3], so each one is executed by a single instance while it stays in
+// the schedule until settleClaimedScript removes it.
+//
+// KEYS[1] schedule, ARGV[1] now in unix ms, ARGV[2] batch size, ARGV[3] end
+// of the claim in unix ms
+var claimDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(due) do
+	redis.call('ZADD', KEYS[1], 'XX', ARGV[3], member)
+end
+return due
+`)
+
+// settleClaimedScript removes claimed invalidations, or reschedules them to
+// ARGV[2] when it isn't empty. Members rescheduled since the claim no longer
+// have its score and are left alone.
+//
+// KEYS[1] schedule, ARGV[1] end of the claim in unix ms, ARGV[2] new score or
+// "", ARGV[3..] claimed members
+var settleClaimedScript = redis.NewScript(`
+local claimed = tonumber(ARGV[1])
+for i = 3, #ARGV do
+	if tonumber(redis.call('ZSCORE', KEYS[1], ARGV[i])) == claimed then
+		if ARGV[2] == '' then
+			redis.call('ZREM', KEYS[1], ARGV[i])
+		else
+			redis.call('ZADD', KEYS[1], 'XX', ARGV[2], ARGV[i])
+		end
+	end
+end
+return 0
+`)
+
+// InvalidationScheduler deletes cache keys at given times, for expiry tied to
+// business events (a subscription ending, an embargo lifting) rather than a
+// TTL known when the value is written:
+//
+//	scheduler, err := cache.NewInvalidationScheduler(prices, nil)
+//	err = scheduler.Schedule(ctx, "price:sku-1", promotion.EndsAt)
+//
+// The schedule is a Redis sorted set scored by due time, so it survives
+// restarts and is shared by every instance: each due invalidation is claimed
+// atomically and executed by one of them. An invalidation is only removed from
+// the schedule once its delete succeeded: failed deletes are retried on the
+// next poll, and those of an instance crashing in between after ClaimTimeout.
+type InvalidationScheduler[T any] struct {
+	cache  Cache[T]
+	client redis.UniversalClient
+	config InvalidationSchedulerConfig
+	now    func() time.Time
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewInvalidationScheduler creates a scheduler deleting keys from cache and
+// starts executing due invalidations in the background. Call Close to stop;
+// it doesn't close cache.
+func NewInvalidationScheduler[T any](cache Cache[T], config *InvalidationSchedulerConfig) (*InvalidationScheduler[T], error) {
+	var cfg InvalidationSchedulerConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Key == "" {
+		cfg.Key = "cache:invalidations:" + keyPrefixOf(cache)
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = 30 * time.Second
+	}
+
+	client := cfg.Client
+	if client == nil {
+		var err error
+		if client, err = redisClientOf(cache); err != nil {
+			return nil, fmt.Errorf("invalidation scheduler requires a Client: %w", err)
+		}
+	}
+
+	s := &InvalidationScheduler[T]{
+		cache:  cache,
+		client: client,
+		config: cfg,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Schedule invalidates key at the given time, replacing any earlier schedule
+// of the key. Times in the past invalidate it on the next poll.
+func (s *InvalidationScheduler[T]) Schedule(ctx context.Context, key string, at time.Time) error {
+	return s.client.ZAdd(ctx, s.config.Key, redis.Z{Score: float64(at.UnixMilli()), Member: key}).Err()
+}
+
+// Cancel removes the scheduled invalidation of key, if any.
+func (s *InvalidationScheduler[T]) Cancel(ctx context.Context, key string) error {
+	return s.client.ZRem(ctx, s.config.Key, key).Err()
+}
+
+// Scheduled returns when key is due to be invalidated, and false if it isn't
+// scheduled.
+func (s *InvalidationScheduler[T]) Scheduled(ctx context.Context, key string) (time.Time, bool, error) {
+	score, err := s.client.ZScore(ctx, s.config.Key, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.UnixMilli(int64(score)), true, nil
+}
+
+// Pending returns the number of scheduled invalidations, due or not.
+func (s *InvalidationScheduler[T]) Pending(ctx context.Context) (int64, error) {
+	return s.client.ZCard(ctx, s.config.Key).Result()
+}
+
+// Close stops executing invalidations; they stay scheduled for other
+// instances or the next start.
+func (s *InvalidationScheduler[T]) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	return nil
+}
+
+func (s *InvalidationScheduler[T]) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			// Drain full batches before waiting for the next tick
+			for s.runDue() == s.config.BatchSize {
+				select {
+				case <-s.stop:
+					return
+				default:
+				}
+			}
+		}
+	}
+}
+
+// runDue executes one batch of due invalidations and returns its size.
+func (s *InvalidationScheduler[T]) runDue() int {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.PollInterval+5*time.Second)
+	defer cancel()
+
+	now := s.now()
+	claim := strconv.FormatInt(now.Add(s.config.ClaimTimeout).UnixMilli(), 10)
+	keys, err := claimDueScript.Run(ctx, s.client, []string{s.config.Key},
+		strconv.FormatInt(now.UnixMilli(), 10), s.config.BatchSize, claim).StringSlice()
+	if err != nil {
+		slog.Default().WarnContext(ctx, "cache: claiming scheduled invalidations failed", "error", err)
+		return 0
+	}
+	if len(keys) == 0 {
+		return 0
+	}
+
+	// Scores of claimed members tell a schedule made meanwhile apart
+	args := make([]any, 0, len(keys)+2)
+	args = append(args, claim, "")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	if err := deleteMulti(ctx, s.cache, keys); err != nil {
+		slog.Default().WarnContext(ctx, "cache: scheduled invalidation failed, retrying",
+			"keys", len(keys), "error", err)
+		args[1] = strconv.FormatInt(now.UnixMilli(), 10)
+		if err := settleClaimedScript.Run(ctx, s.client, []string{s.config.Key}, args...).Err(); err != nil {
+			slog.Default().WarnContext(ctx, "cache: releasing scheduled invalidations failed", "error", err)
+		}
+		return 0
+	}
+	if err := settleClaimedScript.Run(ctx, s.client, []string{s.config.Key}, args...).Err(); err != nil {
+		// They run again once the claim times out, deleting the keys twice
+		slog.Default().WarnContext(ctx, "cache: removing scheduled invalidations failed", "error", err)
+	}
+	return len(keys)
+}
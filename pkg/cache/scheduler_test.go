@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// deleteOutageCache fails deletes while down.
+type deleteOutageCache[T any] struct {
+	Cache[T]
+	down atomic.Bool
+}
+
+func (c *deleteOutageCache[T]) Delete(ctx context.Context, key string) error {
+	if c.down.Load() {
+		return errOutage
+	}
+	return c.Cache.Delete(ctx, key)
+}
+
+func TestNewInvalidationSchedulerRequiresClient(t *testing.T) {
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	if _, err := NewInvalidationScheduler(memory, nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed for a memory cache without Client, got: %v", err)
+	}
+}
+
+func TestInvalidationSchedulerWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	// Test a memory cache invalidated through a schedule in Valkey
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+	scheduler, err := NewInvalidationScheduler(memory, &InvalidationSchedulerConfig{
+		Client:       client,
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	_ = memory.Set(ctx, "due", TestUser{ID: "1"}, 0)
+	_ = memory.Set(ctx, "later", TestUser{ID: "2"}, 0)
+	_ = memory.Set(ctx, "cancelled", TestUser{ID: "3"}, 0)
+
+	if err := scheduler.Schedule(ctx, "later", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	_ = scheduler.Schedule(ctx, "cancelled", time.Now().Add(50*time.Millisecond))
+	_ = scheduler.Cancel(ctx, "cancelled")
+	_ = scheduler.Schedule(ctx, "due", time.Now().Add(50*time.Millisecond))
+
+	waitFor(t, func() bool {
+		_, found := memory.Get(ctx, "due")
+		return !found
+	})
+	if _, found := memory.Get(ctx, "later"); !found {
+		t.Error("Expected later to stay until it is due")
+	}
+	if _, found := memory.Get(ctx, "cancelled"); !found {
+		t.Error("Expected cancelled to stay")
+	}
+
+	if at, scheduled, err := scheduler.Scheduled(ctx, "later"); err != nil || !scheduled || time.Until(at) < 59*time.Minute {
+		t.Errorf("Expected later scheduled in an hour, got %v, %v, %v", at, scheduled, err)
+	}
+	if pending, err := scheduler.Pending(ctx); err != nil || pending != 1 {
+		t.Errorf("Expected 1 pending invalidation, got %d (err=%v)", pending, err)
+	}
+}
+
+func TestInvalidationSchedulerKeepsFailedInvalidations(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	memory := &deleteOutageCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	defer memory.Close()
+	memory.down.Store(true)
+
+	cache := WithPrefix[TestUser](memory, "billing:")
+	scheduler, err := NewInvalidationScheduler(cache, &InvalidationSchedulerConfig{
+		Client:       client,
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	if scheduler.config.Key != "cache:invalidations:billing:" {
+		t.Errorf("Expected the default key to follow the cache prefix, got %q", scheduler.config.Key)
+	}
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, 0)
+	_ = scheduler.Schedule(ctx, "1", time.Now())
+
+	// Failed deletes stay scheduled and are retried on later polls
+	time.Sleep(100 * time.Millisecond)
+	if _, found := cache.Get(ctx, "1"); !found {
+		t.Fatal("Expected the key to stay while deletes fail")
+	}
+	if pending, err := scheduler.Pending(ctx); err != nil || pending != 1 {
+		t.Fatalf("Expected the failed invalidation to stay scheduled, got %d (err=%v)", pending, err)
+	}
+
+	memory.down.Store(false)
+	waitFor(t, func() bool {
+		_, found := cache.Get(ctx, "1")
+		return !found
+	})
+	waitFor(t, func() bool {
+		pending, err := scheduler.Pending(ctx)
+		return err == nil && pending == 0
+	})
+}
+
+func TestInvalidationSchedulerReclaimsAbandonedClaims(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+	_ = memory.Set(ctx, "1", TestUser{ID: "1"}, 0)
+
+	// Simulate an instance that claimed the invalidation and crashed
+	key := "cache:invalidations:"
+	now := time.Now()
+	_ = client.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: "1"}).Err()
+	claimed, err := claimDueScript.Run(ctx, client, []string{key},
+		now.UnixMilli(), 1, now.Add(100*time.Millisecond).UnixMilli()).StringSlice()
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Expected to claim the invalidation, got %v (err=%v)", claimed, err)
+	}
+
+	scheduler, err := NewInvalidationScheduler(memory, &InvalidationSchedulerConfig{
+		Client:       client,
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	time.Sleep(30 * time.Millisecond)
+	if _, found := memory.Get(ctx, "1"); !found {
+		t.Fatal("Expected a claimed invalidation to be hidden until its claim times out")
+	}
+	waitFor(t, func() bool {
+		_, found := memory.Get(ctx, "1")
+		return !found
+	})
+	waitFor(t, func() bool {
+		pending, err := scheduler.Pending(ctx)
+		return err == nil && pending == 0
+	})
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrScriptNotRegistered is returned by Scripts when running a script that
+// wasn't registered.
+var ErrScriptNotRegistered = errors.New("script not registered")
+
+// ScriptsConfig holds configuration for a Scripts registry.
+type ScriptsConfig struct {
+	// Serializer encodes arguments and decodes results; use the serializer
+	// of the backend so scripts see values as the cache stores them
+	// (default: proto.Marshal for proto messages, JSON otherwise)
+	Serializer Serializer
+}
+
+// Scripts is a registry of Lua scripts run on the Redis/Valkey server behind
+// a distributed cache, for custom atomic operations the Cache interface
+// doesn't cover:
+//
+//	scripts, err := cache.NewScripts(carts, nil)
+//	err = scripts.Register(ctx, "add_item", `
+//	local cart = redis.call('GET', KEYS[1])
+//	...
+//	return cart`)
+//	cart, found, err := scripts.Run(ctx, "add_item", []string{"cart:1"}, item)
+//
+// Scripts are loaded with SCRIPT LOAD and run with EVALSHA; a server that
+// lost them, e.g. after a restart or failover, gets them loaded again. The
+// cache's key prefix applies to keys.
+type Scripts[T any] struct {
+	client redis.UniversalClient
+	prefix string
+	codec  valueCodec[T]
+
+	mu      sync.RWMutex
+	scripts map[string]*redis.Script
+}
+
+// NewScripts creates a Scripts registry on the Redis/Valkey client behind
+// cache. Returns ErrNotDistributed for other caches.
+func NewScripts[T any](cache Cache[T], config *ScriptsConfig) (*Scripts[T], error) {
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg ScriptsConfig
+	if config != nil {
+		cfg = *config
+	}
+	return &Scripts[T]{
+		client:  client,
+		prefix:  keyPrefixOf(cache),
+		codec:   newValueCodec[T](cfg.Serializer),
+		scripts: make(map[string]*redis.Script),
+	}, nil
+}
+
+// Register loads src on the server under name, replacing a script
+// registered under the same name.
+func (s *Scripts[T]) Register(ctx context.Context, name, src string) error {
+	script := redis.NewScript(src)
+	if err := script.Load(ctx, s.client).Err(); err != nil {
+		return closedErr(err)
+	}
+
+	s.mu.Lock()
+	s.scripts[name] = script
+	s.mu.Unlock()
+	return nil
+}
+
+// Run runs the script registered under name and decodes the string it
+// returns as a value. A nil reply is reported as not found. Arguments of type
+// T are encoded with the serializer; other arguments are sent as they are,
+// so with T a string or []byte every such argument is encoded.
+func (s *Scripts[T]) Run(ctx context.Context, name string, keys []string, args ...any) (T, bool, error) {
+	var zero T
+
+	data, err := s.RunCmd(ctx, name, keys, args...).Text()
+	if errors.Is(err, redis.Nil) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, err := s.codec.decode([]byte(data))
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// RunCmd runs the script registered under name and returns its raw reply,
+// for scripts returning numbers, tables or statuses. Arguments are encoded
+// as for Run.
+func (s *Scripts[T]) RunCmd(ctx context.Context, name string, keys []string, args ...any) *redis.Cmd {
+	s.mu.RLock()
+	script, ok := s.scripts[name]
+	s.mu.RUnlock()
+	if !ok {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(fmt.Errorf("%w: %s", ErrScriptNotRegistered, name))
+		return cmd
+	}
+
+	prefixed := keys
+	if s.prefix != "" {
+		prefixed = make([]string, len(keys))
+		for i, key := range keys {
+			prefixed[i] = s.prefix + key
+		}
+	}
+
+	encoded := make([]any, len(args))
+	for i, arg := range args {
+		value, ok := arg.(T)
+		if !ok {
+			encoded[i] = arg
+			continue
+		}
+		data, err := s.codec.encode(value)
+		if err != nil {
+			cmd := redis.NewCmd(ctx)
+			cmd.SetErr(err)
+			return cmd
+		}
+		encoded[i] = data
+	}
+
+	cmd := script.EvalSha(ctx, s.client, prefixed, encoded...)
+	if redis.HasErrorPrefix(cmd.Err(), "NOSCRIPT") {
+		// The server lost the script; load it again and retry once
+		if err := script.Load(ctx, s.client).Err(); err != nil {
+			cmd.SetErr(closedErr(err))
+			return cmd
+		}
+		cmd = script.EvalSha(ctx, s.client, prefixed, encoded...)
+	}
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		cmd.SetErr(closedErr(err))
+	}
+	return cmd
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewScriptsRequiresDistributed(t *testing.T) {
+	if _, err := NewScripts(NewMemory[TestUser](nil), nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got %v", err)
+	}
+}
+
+func TestScriptsWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	backend, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer backend.Close()
+	cache := WithPrefix(backend, "app:")
+
+	scripts, err := NewScripts(cache, nil)
+	if err != nil {
+		t.Fatalf("NewScripts failed: %v", err)
+	}
+
+	// Swaps in a value and returns the previous one
+	err = scripts.Register(ctx, "swap", `
+local old = redis.call('GET', KEYS[1])
+redis.call('SET', KEYS[1], ARGV[1])
+return old`)
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ann := TestUser{ID: "1", Name: "Ann"}
+	if _, found, err := scripts.Run(ctx, "swap", []string{"user"}, ann); err != nil || found {
+		t.Errorf("Expected no previous value, got found=%v, err=%v", found, err)
+	}
+	if got, found := cache.Get(ctx, "user"); !found || got != ann {
+		t.Errorf("Expected the script to store %+v under the prefix, got %+v (found=%v)", ann, got, found)
+	}
+	bob := TestUser{ID: "1", Name: "Bob"}
+	if got, found, err := scripts.Run(ctx, "swap", []string{"user"}, bob); err != nil || !found || got != ann {
+		t.Errorf("Expected the previous value %+v, got %+v (found=%v, err=%v)", ann, got, found, err)
+	}
+
+	// Scripts are loaded again once the server lost them
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	if err := client.ScriptFlush(ctx).Err(); err != nil {
+		t.Fatalf("SCRIPT FLUSH failed: %v", err)
+	}
+	if got, found, err := scripts.Run(ctx, "swap", []string{"user"}, ann); err != nil || !found || got != bob {
+		t.Errorf("Expected the script to be reloaded, got %+v (found=%v, err=%v)", got, found, err)
+	}
+
+	// Raw replies
+	_ = scripts.Register(ctx, "ttl", `return redis.call('PTTL', KEYS[1])`)
+	_ = cache.Set(ctx, "short", ann, time.Minute)
+	if ms, err := scripts.RunCmd(ctx, "ttl", []string{"short"}).Int64(); err != nil || ms <= 0 {
+		t.Errorf("Expected a positive PTTL, got %d (err=%v)", ms, err)
+	}
+
+	if _, _, err := scripts.Run(ctx, "missing", nil); !errors.Is(err, ErrScriptNotRegistered) {
+		t.Errorf("Expected ErrScriptNotRegistered, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SelfTestNamespace prefixes the canary keys written by SelfTest.
+const SelfTestNamespace = "__cache_selftest__:"
+
+// SelfTestStep is the outcome of a single SelfTest step.
+type SelfTestStep struct {
+	// Name identifies the step: "create", "ping", "set", "get", "round-trip",
+	// "delete" or "verify-delete".
+	Name string
+
+	// Duration is how long the step took.
+	Duration time.Duration
+
+	// Err is nil if the step passed.
+	Err error
+}
+
+// SelfTestResult is the structured outcome of SelfTest.
+type SelfTestResult struct {
+	// Key is the canary key used.
+	Key string
+
+	// Steps lists the steps run, in order. Steps after a failed one are skipped.
+	Steps []SelfTestStep
+}
+
+// Passed reports whether every step passed.
+func (r SelfTestResult) Passed() bool {
+	return r.Err() == nil
+}
+
+// Err returns the failed steps' errors, or nil if every step passed.
+func (r SelfTestResult) Err() error {
+	var errs []error
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, step.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SelfTest validates config at boot time: it creates the cache, writes sample
+// under a canary key in SelfTestNamespace, reads it back and checks that it
+// survived the serializer round trip, then deletes it again. The cache is
+// closed before returning.
+//
+//	result := cache.SelfTest(ctx, config, &User{ID: "canary"})
+//	if !result.Passed() {
+//		log.Fatalf("cache misconfigured: %v", result.Err())
+//	}
+func SelfTest[T any](ctx context.Context, config *Config, sample T) SelfTestResult {
+	result := SelfTestResult{Key: SelfTestNamespace + randomSuffix()}
+
+	run := func(name string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		result.Steps = append(result.Steps, SelfTestStep{Name: name, Duration: time.Since(start), Err: err})
+		return err == nil
+	}
+
+	var cache Cache[T]
+	if !run("create", func() (err error) {
+		cache, err = New[T](config)
+		return err
+	}) {
+		return result
+	}
+	defer cache.Close()
+
+	if hc, ok := cache.(HealthChecker); ok {
+		if !run("ping", func() error { return hc.Ping(ctx) }) {
+			return result
+		}
+	}
+
+	if !run("set", func() error {
+		if err := cache.Set(ctx, result.Key, sample, time.Minute); err != nil {
+			return err
+		}
+		// Make buffered writes (e.g. Ristretto) visible to the read below
+		return flushAll(ctx, cache)
+	}) {
+		return result
+	}
+
+	var got T
+	if !run("get", func() error {
+		value, found, err := GetWithError(ctx, cache, result.Key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.New("canary key not found after set")
+		}
+		got = value
+		return nil
+	}) {
+		// Best effort - the canary expires on its own
+		_ = cache.Delete(ctx, result.Key)
+		return result
+	}
+
+	run("round-trip", func() error {
+		if !valuesEqual(sample, got) {
+			return fmt.Errorf("value changed in the round trip: wrote %v, read %v", sample, got)
+		}
+		return nil
+	})
+
+	if !run("delete", func() error { return cache.Delete(ctx, result.Key) }) {
+		return result
+	}
+
+	run("verify-delete", func() error {
+		if _, found := cache.Get(ctx, result.Key); found {
+			return errors.New("canary key still present after delete")
+		}
+		return nil
+	})
+
+	return result
+}
+
+func randomSuffix() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	ctx := context.Background()
+
+	result := SelfTest(ctx, &Config{Type: TypeMemory}, TestUser{ID: "canary", Name: "Canary"})
+	if !result.Passed() {
+		t.Fatalf("Expected self-test to pass, got: %v", result.Err())
+	}
+	if !strings.HasPrefix(result.Key, SelfTestNamespace) {
+		t.Errorf("Expected canary key in %s, got %s", SelfTestNamespace, result.Key)
+	}
+
+	var names []string
+	for _, step := range result.Steps {
+		names = append(names, step.Name)
+	}
+	if got := strings.Join(names, ","); got != "create,ping,set,get,round-trip,delete,verify-delete" {
+		t.Errorf("Unexpected steps: %s", got)
+	}
+}
+
+func TestSelfTestFailures(t *testing.T) {
+	ctx := context.Background()
+
+	// Test a failed create stops the self-test
+	result := SelfTest(ctx, &Config{Type: "unknown"}, TestUser{})
+	if result.Passed() || len(result.Steps) != 1 || result.Steps[0].Name != "create" {
+		t.Errorf("Expected failed create step, got: %+v", result.Steps)
+	}
+
+	// Test a cache that doesn't store values fails the get step
+	result = SelfTest(ctx, &Config{Type: TypeNoOp}, TestUser{ID: "canary"})
+	if result.Passed() {
+		t.Fatal("Expected no-op cache to fail the self-test")
+	}
+	if err := result.Err(); !strings.Contains(err.Error(), "get: canary key not found") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // Serializer defines the interface for serializing and deserializing data.
@@ -54,6 +59,76 @@ func (g *GobSerializer) Deserialize(data []byte, v interface{}) error {
 	return gob.NewDecoder(&gobBuffer{&data}).Decode(v)
 }
 
+// ProtoJSONSerializer implements protojson serialization for proto messages,
+// so cached entries are human-readable (e.g. in redis-cli).
+type ProtoJSONSerializer struct{}
+
+// NewProtoJSONSerializer creates a new protojson serializer.
+func NewProtoJSONSerializer() *ProtoJSONSerializer {
+	return &ProtoJSONSerializer{}
+}
+
+// Serialize converts a proto message to protojson bytes.
+func (p *ProtoJSONSerializer) Serialize(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protojson serialization requires a proto.Message, got %T", v)
+	}
+	return protojson.Marshal(msg)
+}
+
+// Deserialize converts protojson bytes back to a proto message. v is either a
+// message or a pointer to a message pointer, which is allocated as needed.
+// Unknown fields are ignored so older readers accept newer writers' entries.
+func (p *ProtoJSONSerializer) Deserialize(data []byte, v interface{}) error {
+	msg, err := protoTarget(v)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+}
+
+// protobufSerializer implements binary protobuf serialization behind the
+// Serializer interface, for wrapping serializers such as CompressedSerializer.
+type protobufSerializer struct{}
+
+func (protobufSerializer) Serialize(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf serialization requires a proto.Message, got %T", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (protobufSerializer) Deserialize(data []byte, v interface{}) error {
+	msg, err := protoTarget(v)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// protoTarget returns the message to decode into: v itself, or the message v
+// points to, allocating it when nil.
+func protoTarget(v interface{}) (proto.Message, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return msg, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("proto deserialization requires a proto.Message, got %T", v)
+	}
+	elem := rv.Elem()
+	if elem.IsNil() {
+		elem.Set(reflect.New(elem.Type().Elem()))
+	}
+	msg, ok := elem.Interface().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("proto deserialization requires a proto.Message, got %T", v)
+	}
+	return msg, nil
+}
+
 // gobBuffer is a simple buffer implementation for gob encoding/decoding.
 type gobBuffer struct {
 	data *[]byte
@@ -80,9 +155,79 @@ func NewSerializer(serializationType SerializationType) (Serializer, error) {
 		return &JSONSerializer{}, nil
 	case SerializationGob:
 		return &GobSerializer{}, nil
+	case SerializationProtoJSON:
+		return &ProtoJSONSerializer{}, nil
 	case SerializationProtobuf:
 		return nil, errors.New("protobuf serialization requires special handling - use NewDistributed")
 	default:
 		return nil, errors.New("unknown serialization type")
 	}
 }
+
+// ErrUnsupportedValueType is returned when creating a serializing cache for a
+// type parameter its serializer can't decode values into.
+var ErrUnsupportedValueType = errors.New("unsupported cache value type")
+
+// isProtoType reports whether T is a proto message pointer such as *pb.User,
+// which is serialized with proto.Marshal by default. It looks at the type
+// rather than its zero value, which is a nil interface for interface types.
+func isProtoType[T any]() bool {
+	t := reflect.TypeFor[T]()
+	return t.Kind() == reflect.Pointer && t.Implements(reflect.TypeFor[proto.Message]())
+}
+
+// checkValueType rejects type parameters that serializing caches can't
+// decode into without a custom Serializer: proto message structs, which
+// must not be copied and are cached by pointer, and interfaces with methods
+// such as proto.Message, whose concrete type is unknown when reading.
+func checkValueType[T any](serializer Serializer) error {
+	if serializer != nil {
+		return nil
+	}
+	t := reflect.TypeFor[T]()
+	switch {
+	case t.Kind() == reflect.Interface && t.NumMethod() > 0:
+		return fmt.Errorf("%w: can't decode into interface %v; use a concrete type, set a Serializer, or use AnyCache for proto messages",
+			ErrUnsupportedValueType, t)
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(reflect.TypeFor[proto.Message]()):
+		return fmt.Errorf("%w: proto message %v must be cached by pointer (*%v)", ErrUnsupportedValueType, t, t)
+	}
+	return nil
+}
+
+// valueCodec encodes values of T for byte-oriented stores, using proto.Marshal
+// for proto messages unless a Serializer is given, and JSON otherwise.
+type valueCodec[T any] struct {
+	serializer Serializer // nil for proto messages
+}
+
+func newValueCodec[T any](serializer Serializer) valueCodec[T] {
+	if serializer == nil && !isProtoType[T]() {
+		serializer = NewJSONSerializer()
+	}
+	return valueCodec[T]{serializer: serializer}
+}
+
+func (c valueCodec[T]) encode(value T) ([]byte, error) {
+	if c.serializer != nil {
+		return c.serializer.Serialize(value)
+	}
+	msg, ok := any(value).(proto.Message)
+	if !ok {
+		return nil, errors.New("a Serializer is required for non-proto types")
+	}
+	return proto.Marshal(msg)
+}
+
+func (c valueCodec[T]) decode(data []byte) (T, error) {
+	var result T
+	if c.serializer != nil {
+		err := c.serializer.Deserialize(data, &result)
+		return result, err
+	}
+
+	// Create a new instance of T using reflection
+	result = reflect.New(reflect.TypeOf(result).Elem()).Interface().(T)
+	err := proto.Unmarshal(data, any(result).(proto.Message))
+	return result, err
+}
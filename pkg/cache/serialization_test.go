@@ -1,7 +1,13 @@
 package cache
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestSerializers(t *testing.T) {
@@ -60,6 +66,11 @@ func TestNewSerializer(t *testing.T) {
 			serializationType: SerializationGob,
 			wantErr:           false,
 		},
+		{
+			name:              "ProtoJSON serialization",
+			serializationType: SerializationProtoJSON,
+			wantErr:           false,
+		},
 		{
 			name:              "Protobuf serialization",
 			serializationType: SerializationProtobuf,
@@ -97,3 +108,63 @@ func TestNewSerializer(t *testing.T) {
 		})
 	}
 }
+
+func TestProtoJSONSerializer(t *testing.T) {
+	serializer := NewProtoJSONSerializer()
+
+	data, err := serializer.Serialize(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf("ProtoJSON Serialize failed: %v", err)
+	}
+	if string(data) != `"hello"` {
+		t.Errorf("Expected human-readable JSON, got %s", data)
+	}
+
+	// Test decoding into a nil message pointer, as caches of *Msg do
+	var retrieved *wrapperspb.StringValue
+	if err := serializer.Deserialize(data, &retrieved); err != nil || retrieved.GetValue() != "hello" {
+		t.Errorf("Expected hello, got %v (err=%v)", retrieved, err)
+	}
+
+	// Test decoding into a message directly
+	msg := &wrapperspb.StringValue{}
+	if err := serializer.Deserialize(data, msg); err != nil || msg.GetValue() != "hello" {
+		t.Errorf("Expected hello, got %v (err=%v)", msg, err)
+	}
+
+	// Test non-proto values are rejected
+	if _, err := serializer.Serialize(TestUser{}); err == nil {
+		t.Error("Expected error for non-proto value")
+	}
+	var user TestUser
+	if err := serializer.Deserialize(data, &user); err == nil {
+		t.Error("Expected error for non-proto target")
+	}
+}
+
+func TestProtoJSONDistributedCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := New[*wrapperspb.StringValue](&Config{
+		Type:        TypeDistributed,
+		Distributed: &DistributedConfig{Addr: addr, SerializationType: SerializationProtoJSON},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "key1", wrapperspb.String("hello"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, found := cache.Get(ctx, "key1"); !found || value.GetValue() != "hello" {
+		t.Errorf("Expected hello, got %v (found=%v)", value, found)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	if raw, _ := client.Get(ctx, "key1").Result(); !strings.Contains(raw, "hello") {
+		t.Errorf("Expected stored entry to be readable, got %q", raw)
+	}
+}
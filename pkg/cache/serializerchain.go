@@ -0,0 +1,54 @@
+package cache
+
+import "errors"
+
+// SerializerWrapper wraps a serializer with another layer, e.g. compression,
+// encryption or checksums. Custom layers are plain functions:
+//
+//	encrypt := func(inner cache.Serializer) (cache.Serializer, error) {
+//		return newEncryptingSerializer(inner, key), nil
+//	}
+type SerializerWrapper func(inner Serializer) (Serializer, error)
+
+// ChainSerializer wraps base with each wrapper in order, so the first wrapper
+// sees base's output and the last one produces the stored bytes. For example
+// compression followed by encryption encrypts the compressed payload.
+func ChainSerializer(base Serializer, wrappers ...SerializerWrapper) (Serializer, error) {
+	if base == nil {
+		return nil, errors.New("base serializer cannot be nil")
+	}
+	serializer := base
+	for _, wrap := range wrappers {
+		if wrap == nil {
+			continue
+		}
+		wrapped, err := wrap(serializer)
+		if err != nil {
+			return nil, err
+		}
+		serializer = wrapped
+	}
+	return serializer, nil
+}
+
+// WithCompression returns a wrapper compressing with algo, see NewCompressedSerializer.
+func WithCompression(algo CompressionType) SerializerWrapper {
+	return func(inner Serializer) (Serializer, error) {
+		return NewCompressedSerializer(inner, algo)
+	}
+}
+
+// WithZstd returns a wrapper compressing with zstd, see NewZstdSerializer.
+func WithZstd(config *ZstdConfig) SerializerWrapper {
+	return func(inner Serializer) (Serializer, error) {
+		return NewZstdSerializer(inner, config)
+	}
+}
+
+// WithTypeChecking returns a wrapper tagging payloads with their type, see
+// NewTypeCheckingSerializer.
+func WithTypeChecking(config *TypeCheckConfig) SerializerWrapper {
+	return func(inner Serializer) (Serializer, error) {
+		return NewTypeCheckingSerializer(inner, config)
+	}
+}
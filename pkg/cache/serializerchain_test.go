@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// prefixSerializer marks payloads with a prefix to make wrapping order visible.
+type prefixSerializer struct {
+	inner  Serializer
+	prefix string
+}
+
+func (p prefixSerializer) Serialize(v interface{}) ([]byte, error) {
+	data, err := p.inner.Serialize(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(p.prefix), data...), nil
+}
+
+func (p prefixSerializer) Deserialize(data []byte, v interface{}) error {
+	if !strings.HasPrefix(string(data), p.prefix) {
+		return errors.New("missing prefix " + p.prefix)
+	}
+	return p.inner.Deserialize(data[len(p.prefix):], v)
+}
+
+func withPrefix(prefix string) SerializerWrapper {
+	return func(inner Serializer) (Serializer, error) {
+		return prefixSerializer{inner: inner, prefix: prefix}, nil
+	}
+}
+
+func TestChainSerializer(t *testing.T) {
+	serializer, err := ChainSerializer(&JSONSerializer{}, withPrefix("a:"), nil, withPrefix("b:"))
+	if err != nil {
+		t.Fatalf("ChainSerializer failed: %v", err)
+	}
+
+	// Test the last wrapper produces the stored bytes
+	data, err := serializer.Serialize(TestUser{ID: "123"})
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "b:a:{") {
+		t.Errorf("Expected wrappers applied in order, got %q", data)
+	}
+
+	var retrieved TestUser
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved.ID != "123" {
+		t.Errorf("Expected ID 123, got %+v", retrieved)
+	}
+}
+
+func TestChainSerializerBuiltins(t *testing.T) {
+	serializer, err := ChainSerializer(&JSONSerializer{},
+		WithTypeChecking(nil),
+		WithCompression(CompressionSnappy),
+	)
+	if err != nil {
+		t.Fatalf("ChainSerializer failed: %v", err)
+	}
+
+	user := TestUser{ID: "123", Name: strings.Repeat("John ", 100)}
+	data, err := serializer.Serialize(user)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if data[0] != compressedHeaderSnappy {
+		t.Errorf("Expected the compression layer outermost, got header %q", data[0])
+	}
+
+	var retrieved TestUser
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved != user {
+		t.Errorf("Expected %+v, got %+v", user, retrieved)
+	}
+}
+
+func TestChainSerializerErrors(t *testing.T) {
+	if _, err := ChainSerializer(nil); err == nil {
+		t.Error("Expected error for nil base serializer")
+	}
+	if _, err := ChainSerializer(&JSONSerializer{}, WithCompression(CompressionType("lz4"))); err == nil {
+		t.Error("Expected error from a failing wrapper")
+	}
+}
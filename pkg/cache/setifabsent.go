@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// SetIfAbsent stores value under key with ttl unless the key is already
+// stored and not expired, reporting whether it was stored, e.g. with Redis
+// SET NX. Use it for idempotency tokens, where only the first request to
+// claim a token proceeds, or simple leader election, where the instance that
+// stores the leader key holds the lease until its TTL runs out:
+//
+//	claimed, err := cache.SetIfAbsent(ctx, leases, "leader", instanceID, 10*time.Second)
+//
+// Caches that don't implement AbsentSetter are checked with Exists and then
+// written with Set, so two callers may both store the key; only rely on
+// SetIfAbsent for mutual exclusion with caches that implement it.
+func SetIfAbsent[T any](ctx context.Context, cache Cache[T], key string, value T, ttl time.Duration) (bool, error) {
+	if s, ok := cache.(AbsentSetter[T]); ok {
+		return s.SetIfAbsent(ctx, key, value, ttl)
+	}
+
+	found, err := Exists(ctx, cache, key)
+	if err != nil || found {
+		return false, err
+	}
+	if err := cache.Set(ctx, key, value, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetIfAbsentMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			first := TestUser{ID: "1", Name: "Ann"}
+			if stored, err := SetIfAbsent(ctx, cache, "token", first, time.Minute); err != nil || !stored {
+				t.Fatalf("Expected SetIfAbsent to store token, got %v, %v", stored, err)
+			}
+			_ = flushAll(ctx, cache)
+
+			if stored, err := SetIfAbsent(ctx, cache, "token", TestUser{ID: "2"}, time.Minute); err != nil || stored {
+				t.Errorf("Expected SetIfAbsent to keep token, got %v, %v", stored, err)
+			}
+			_ = flushAll(ctx, cache)
+			if got, found := cache.Get(ctx, "token"); !found || got != first {
+				t.Errorf("Expected %+v, got %+v (found=%v)", first, got, found)
+			}
+		})
+	}
+}
+
+func TestSetIfAbsentAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_, _ = SetIfAbsent(ctx, cache, "lease", TestUser{ID: "1"}, 20*time.Millisecond)
+	waitFor(t, func() bool {
+		stored, _ := SetIfAbsent(ctx, cache, "lease", TestUser{ID: "2"}, time.Minute)
+		return stored
+	})
+	if got, _ := cache.Get(ctx, "lease"); got.ID != "2" {
+		t.Errorf("Expected the lease to be taken over, got %+v", got)
+	}
+}
+
+func TestSetIfAbsentConcurrentClaims(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			var wg sync.WaitGroup
+			var winners atomic.Int32
+			for i := range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					stored, err := SetIfAbsent(ctx, cache, "leader", TestUser{ID: string(rune('a' + i))}, time.Minute)
+					if err != nil {
+						t.Errorf("SetIfAbsent failed: %v", err)
+					}
+					if stored {
+						winners.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := winners.Load(); n != 1 {
+				t.Errorf("Expected exactly one claim to win, got %d", n)
+			}
+		})
+	}
+}
+
+func TestSetIfAbsentFallsBackToExists(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	backend := &unclearableCache[TestUser]{Cache: inner}
+	defer backend.Close()
+
+	if stored, err := SetIfAbsent[TestUser](ctx, backend, "a", TestUser{ID: "1"}, time.Minute); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store a, got %v, %v", stored, err)
+	}
+	if stored, err := SetIfAbsent[TestUser](ctx, backend, "a", TestUser{ID: "2"}, time.Minute); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep a, got %v, %v", stored, err)
+	}
+	if got, _ := inner.Get(ctx, "a"); got.ID != "1" {
+		t.Errorf("Expected the first value, got %+v", got)
+	}
+}
+
+func TestSetIfAbsentThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	defer backend.Close()
+
+	cache := WithDefaultTTL(WithPrefix(backend, "p:"), time.Hour)
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "a"}, 0); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store a, got %v, %v", stored, err)
+	}
+	if ttl, _, _ := TTL(ctx, backend, "p:a"); ttl <= 59*time.Minute {
+		t.Errorf("Expected the default TTL on the prefixed key, got %v", ttl)
+	}
+	if stored, _ := SetIfAbsent(ctx, cache, "a", TestUser{ID: "b"}, 0); stored {
+		t.Error("Expected SetIfAbsent to keep the prefixed key")
+	}
+}
+
+func TestSetIfAbsentTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "1"}, time.Hour); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store a, got %v, %v", stored, err)
+	}
+	if ttl, _, _ := TTL(ctx, l1, "a"); ttl > time.Minute {
+		t.Errorf("Expected the L1 copy to be capped, got %v", ttl)
+	}
+
+	// L2 decides, even when this instance's L1 doesn't hold the key
+	_ = l1.Delete(ctx, "a")
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "2"}, time.Hour); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep a, got %v, %v", stored, err)
+	}
+	if _, found := l1.Get(ctx, "a"); found {
+		t.Error("Expected a lost claim not to populate L1")
+	}
+}
+
+func TestSetIfAbsentAsyncWaitsForQueuedWrites(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	cache := NewAsync(inner, nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "1"}, time.Minute)
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "2"}, time.Minute); err != nil || stored {
+		t.Errorf("Expected the queued Set to be seen, got %v, %v", stored, err)
+	}
+}
+
+func TestSetIfAbsentDryRun(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemory[TestUser](nil)
+	defer inner.Close()
+
+	var ops []DryRunOperation
+	cache := NewDryRun(inner, func(_ context.Context, op DryRunOperation) {
+		ops = append(ops, op)
+	})
+
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "1"}, time.Minute); err != nil || !stored {
+		t.Errorf("Expected SetIfAbsent to report it would store a, got %v, %v", stored, err)
+	}
+	if _, found := inner.Get(ctx, "a"); found {
+		t.Error("Expected dry run not to store a")
+	}
+	if len(ops) != 1 || ops[0].Operation != OperationSetIfAbsent || ops[0].TTL != time.Minute {
+		t.Errorf("Expected a recorded SetIfAbsent, got %+v", ops)
+	}
+}
+
+func TestSetIfAbsentDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "1"}, 10*time.Millisecond); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store a, got %v, %v", stored, err)
+	}
+	if stored, _ := SetIfAbsent(ctx, cache, "a", TestUser{ID: "2"}, time.Minute); stored {
+		t.Error("Expected SetIfAbsent to keep a")
+	}
+
+	// Expired entries not yet cleaned up are replaced
+	time.Sleep(20 * time.Millisecond)
+	if stored, err := SetIfAbsent(ctx, cache, "a", TestUser{ID: "2"}, time.Minute); err != nil || !stored {
+		t.Errorf("Expected SetIfAbsent to replace the expired entry, got %v, %v", stored, err)
+	}
+	if got, _ := cache.Get(ctx, "a"); got.ID != "2" {
+		t.Errorf("Expected the new value, got %+v", got)
+	}
+}
+
+func TestSetIfAbsentDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	if stored, err := SetIfAbsent(ctx, cache, "token", TestUser{ID: "1"}, time.Minute); err != nil || !stored {
+		t.Fatalf("Expected SetIfAbsent to store token, got %v, %v", stored, err)
+	}
+	if stored, err := SetIfAbsent(ctx, cache, "token", TestUser{ID: "2"}, time.Minute); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep token, got %v, %v", stored, err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "token"); ttl <= 50*time.Second {
+		t.Errorf("Expected about a minute left, got %v", ttl)
+	}
+	if got, _ := cache.Get(ctx, "token"); got.ID != "1" {
+		t.Errorf("Expected the first value, got %+v", got)
+	}
+}
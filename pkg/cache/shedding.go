@@ -0,0 +1,299 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ErrShed is returned by writes rejected by a shedding cache because the
+// backend's limit was reached.
+var ErrShed = errors.New("cache operation shed: backend limit reached")
+
+// SheddingConfig holds configuration for shedding caches.
+type SheddingConfig struct {
+	// MaxInFlight is the maximum number of operations running against the
+	// backend at once (default: 0, unlimited)
+	MaxInFlight int
+
+	// MaxQPS is the maximum rate of operations per second
+	// (default: 0, unlimited)
+	MaxQPS float64
+
+	// Burst is the number of operations that may run at once beyond MaxQPS
+	// after a quiet period (default: MaxQPS, at least 1)
+	Burst int
+
+	// MeterProvider receives the "cache.shed.operations" counter
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+}
+
+// sheddingCache rejects operations beyond a concurrency or rate limit.
+type sheddingCache[T any] struct {
+	next   Cache[T]
+	config SheddingConfig
+	slots  chan struct{} // nil without MaxInFlight
+	shed   metric.Int64Counter
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewShedding wraps a cache so that operations beyond MaxInFlight concurrent
+// operations or MaxQPS operations per second are shed instead of reaching the
+// backend, protecting a shared Valkey cluster from a misbehaving service.
+// Shed operations return at once: reads as misses, writes with ErrShed.
+// GetOrSet admits its read and its write on their own, so no slot is held
+// while the loader runs; a shed read calls the loader and a shed write leaves
+// the loaded value uncached. Deletes and Clear are never shed, since a
+// dropped invalidation would leave stale values behind, but count against
+// the limits, waiting for a free slot until their context is done. Shed
+// operations are counted in the "cache.shed.operations" metric by operation.
+func NewShedding[T any](cache Cache[T], config *SheddingConfig) Cache[T] {
+	var cfg SheddingConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = max(int(cfg.MaxQPS), 1)
+	}
+	provider := cfg.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	shed, _ := provider.Meter(instrumentationName).Int64Counter("cache.shed.operations",
+		metric.WithDescription("Number of cache operations shed by the backend limit"))
+
+	c := &sheddingCache[T]{
+		next:   cache,
+		config: cfg,
+		shed:   shed,
+		tokens: float64(cfg.Burst),
+		now:    time.Now,
+	}
+	c.last = c.now()
+	if cfg.MaxInFlight > 0 {
+		c.slots = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return c
+}
+
+// admit takes a slot and a token for an operation, reporting whether it may
+// run. The returned release function must be called when it finishes.
+func (c *sheddingCache[T]) admit(ctx context.Context, op Operation) (func(), bool) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+		default:
+			c.record(ctx, op)
+			return nil, false
+		}
+	}
+	if !c.take(false) {
+		c.release()
+		c.record(ctx, op)
+		return nil, false
+	}
+	return c.release, true
+}
+
+// admitAlways takes a slot and a token for an operation that is never shed,
+// waiting for a free slot until ctx is done.
+func (c *sheddingCache[T]) admitAlways(ctx context.Context) (func(), error) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c.take(true)
+	return c.release, nil
+}
+
+func (c *sheddingCache[T]) release() {
+	if c.slots != nil {
+		<-c.slots
+	}
+}
+
+// take spends a token of the rate limit, reporting whether one was available.
+// Forced operations always get one, running the bucket into debt.
+func (c *sheddingCache[T]) take(force bool) bool {
+	if c.config.MaxQPS <= 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.tokens = min(c.tokens+now.Sub(c.last).Seconds()*c.config.MaxQPS, float64(c.config.Burst))
+	c.last = now
+	switch {
+	case c.tokens >= 1:
+		c.tokens--
+		return true
+	case force:
+		c.tokens = max(c.tokens-1, -float64(c.config.Burst))
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *sheddingCache[T]) record(ctx context.Context, op Operation) {
+	if c.shed != nil {
+		c.shed.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", string(op))))
+	}
+}
+
+func (c *sheddingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *sheddingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	release, ok := c.admit(ctx, OperationGet)
+	if !ok {
+		noteDecision(ctx, DecisionBypass)
+		var zero T
+		return zero, false, nil
+	}
+	defer release()
+	return GetWithError(ctx, c.next, key)
+}
+
+// GetMulti admits the batch as a single read; a shed batch misses every key.
+func (c *sheddingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	release, ok := c.admit(ctx, OperationGet)
+	if !ok {
+		noteDecision(ctx, DecisionBypass)
+		return keyResults[T](keys, nil)
+	}
+	defer release()
+	return GetMulti(ctx, c.next, keys...)
+}
+
+// GetOrSet reads key and stores the loaded value of a miss as two admitted
+// operations, so the loader runs without holding a slot.
+func (c *sheddingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	// Read errors are treated as misses, like the package GetOrSet does
+	if value, found, err := c.GetWithError(ctx, key); err == nil && found {
+		return value, nil
+	}
+	return loadAndStore(ctx, c, key, ttl, load)
+}
+
+func (c *sheddingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	release, ok := c.admit(ctx, OperationGet)
+	if !ok {
+		return false, nil
+	}
+	defer release()
+	return Exists(ctx, c.next, key)
+}
+
+func (c *sheddingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	release, ok := c.admit(ctx, OperationGet)
+	if !ok {
+		return 0, false, nil
+	}
+	defer release()
+	return TTL(ctx, c.next, key)
+}
+
+func (c *sheddingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	release, ok := c.admit(ctx, OperationExpire)
+	if !ok {
+		return ErrShed
+	}
+	defer release()
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *sheddingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	release, ok := c.admit(ctx, OperationSetIfAbsent)
+	if !ok {
+		return false, ErrShed
+	}
+	defer release()
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *sheddingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	release, ok := c.admit(ctx, OperationCompareAndSwap)
+	if !ok {
+		return false, ErrShed
+	}
+	defer release()
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *sheddingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	release, ok := c.admit(ctx, OperationGetDel)
+	if !ok {
+		var zero T
+		return zero, false, ErrShed
+	}
+	defer release()
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *sheddingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	release, ok := c.admit(ctx, OperationSet)
+	if !ok {
+		return ErrShed
+	}
+	defer release()
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *sheddingCache[T]) Delete(ctx context.Context, key string) error {
+	release, err := c.admitAlways(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.next.Delete(ctx, key)
+}
+
+func (c *sheddingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	release, err := c.admitAlways(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *sheddingCache[T]) Clear(ctx context.Context) error {
+	release, err := c.admitAlways(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return Clear(ctx, c.next)
+}
+
+func (c *sheddingCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *sheddingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *sheddingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
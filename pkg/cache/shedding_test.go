@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSheddingMaxInFlight(t *testing.T) {
+	ctx := context.Background()
+	backend := &blockingCache[TestUser]{Cache: NewMemory[TestUser](nil), release: make(chan struct{})}
+	_ = backend.Cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	c := NewShedding[TestUser](backend, &SheddingConfig{MaxInFlight: 1}).(*sheddingCache[TestUser])
+	defer c.Close()
+
+	done := make(chan error)
+	go func() { done <- c.Set(ctx, "2", TestUser{ID: "2"}, time.Minute) }()
+	waitFor(t, func() bool { return len(c.slots) == 1 })
+
+	if _, found, err := GetWithError(ctx, c, "1"); found || err != nil {
+		t.Errorf("Expected a fast miss while the slot is taken, got found=%v err=%v", found, err)
+	}
+	if err := c.Set(ctx, "3", TestUser{}, time.Minute); !errors.Is(err, ErrShed) {
+		t.Errorf("Expected ErrShed, got %v", err)
+	}
+	loaded, err := GetOrSet(ctx, c, "1", time.Minute, func(context.Context) (TestUser, error) {
+		return TestUser{ID: "loaded"}, nil
+	})
+	if err != nil || loaded.ID != "loaded" {
+		t.Errorf("Expected the loader to answer, got %+v, %v", loaded, err)
+	}
+
+	close(backend.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, found := c.Get(ctx, "1"); !found || value.ID != "1" {
+		t.Errorf("Expected a hit once the slot is free, got %+v", value)
+	}
+}
+
+func TestSheddingGetOrSetReleasesSlotWhileLoading(t *testing.T) {
+	ctx := context.Background()
+	c := NewShedding[TestUser](NewMemory[TestUser](nil), &SheddingConfig{MaxInFlight: 1}).(*sheddingCache[TestUser])
+	defer c.Close()
+
+	loaded, err := GetOrSet(ctx, c, "1", time.Minute, func(context.Context) (TestUser, error) {
+		if err := c.Set(ctx, "2", TestUser{ID: "2"}, time.Minute); err != nil {
+			t.Errorf("Expected writes to be admitted while loading, got %v", err)
+		}
+		return TestUser{ID: "1"}, nil
+	})
+	if err != nil || loaded.ID != "1" {
+		t.Fatalf("Expected the loaded value, got %+v, %v", loaded, err)
+	}
+	if value, found := c.Get(ctx, "1"); !found || value.ID != "1" {
+		t.Errorf("Expected the loaded value to be stored, got %+v", value)
+	}
+}
+
+func TestSheddingDeleteHonorsContext(t *testing.T) {
+	ctx := context.Background()
+	backend := &blockingCache[TestUser]{Cache: NewMemory[TestUser](nil), release: make(chan struct{})}
+	c := NewShedding[TestUser](backend, &SheddingConfig{MaxInFlight: 1}).(*sheddingCache[TestUser])
+	defer c.Close()
+
+	done := make(chan error)
+	go func() { done <- c.Set(ctx, "1", TestUser{ID: "1"}, time.Minute) }()
+	waitFor(t, func() bool { return len(c.slots) == 1 })
+
+	deleteCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := c.Delete(deleteCtx, "1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the delete to give up with its context, got %v", err)
+	}
+
+	close(backend.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := c.Delete(ctx, "1"); err != nil {
+		t.Errorf("Expected the delete to run once the slot is free, got %v", err)
+	}
+}
+
+func TestSheddingMaxQPS(t *testing.T) {
+	ctx := context.Background()
+	c := NewShedding[TestUser](NewMemory[TestUser](nil), &SheddingConfig{MaxQPS: 10, Burst: 2}).(*sheddingCache[TestUser])
+	defer c.Close()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.last = now
+
+	_ = c.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	if _, found := c.Get(ctx, "1"); !found {
+		t.Error("Expected the burst to admit the read")
+	}
+	if _, found := c.Get(ctx, "1"); found {
+		t.Error("Expected the read beyond the burst to be shed")
+	}
+
+	// Deletes are never shed, and run the bucket into debt
+	if err := c.Delete(ctx, "2"); err != nil {
+		t.Errorf("Expected Delete to run, got %v", err)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if _, found := c.Get(ctx, "1"); found {
+		t.Error("Expected the token earned to repay the delete")
+	}
+	now = now.Add(100 * time.Millisecond)
+	if _, found := c.Get(ctx, "1"); !found {
+		t.Error("Expected a read once a token is earned")
+	}
+}
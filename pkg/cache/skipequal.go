@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// writeRecord is the hash of the last value written to a key.
+type writeRecord struct {
+	sum       [sha256.Size]byte
+	expiresAt time.Time // zero when the entry never expires
+}
+
+// skipEqualCache skips Sets whose value equals the last value written locally.
+type skipEqualCache[T any] struct {
+	next       Cache[T]
+	serializer Serializer
+	now        func() time.Time
+
+	mu        sync.Mutex
+	written   map[string]writeRecord
+	lastPrune time.Time
+}
+
+// NewSkipEqualWrites wraps a cache so that a Set is skipped when the value is
+// byte-for-byte equal to the last value this process wrote to the same key and
+// that write has not expired yet. Values are compared by a SHA-256 hash of their
+// serialized form: proto messages use deterministic protobuf encoding, other types
+// use serializer (JSON when nil).
+//
+// The comparison only knows about local writes. Writes from other instances,
+// backend evictions and expirations shorter than the TTL passed to Set are not
+// observed, so use it for mostly-static data where skipping a write is harmless.
+func NewSkipEqualWrites[T any](cache Cache[T], serializer Serializer) Cache[T] {
+	if serializer == nil {
+		serializer = NewJSONSerializer()
+	}
+	return &skipEqualCache[T]{
+		next:       cache,
+		serializer: serializer,
+		now:        time.Now,
+		written:    make(map[string]writeRecord),
+		lastPrune:  time.Now(),
+	}
+}
+
+func (c *skipEqualCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *skipEqualCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+func (c *skipEqualCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	return GetMulti(ctx, c.next, keys...)
+}
+
+func (c *skipEqualCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *skipEqualCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *skipEqualCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := Expire(ctx, c.next, key, ttl); err != nil {
+		c.forget(key)
+		return err
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	if record, ok := c.written[key]; ok {
+		record.expiresAt = time.Time{}
+		if ttl > 0 {
+			record.expiresAt = now.Add(ttl)
+		}
+		c.written[key] = record
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// SetIfAbsent is never skipped, since whether it stores depends on the
+// backend, but remembers what it stored so that equal Sets can be.
+func (c *skipEqualCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	now := c.now()
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	sum, ok := c.hash(value)
+	if err != nil || !stored || !ok {
+		c.forget(key)
+		return stored, err
+	}
+
+	record := writeRecord{sum: sum}
+	if ttl > 0 {
+		record.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.written[key] = record
+	c.pruneLocked(now)
+	c.mu.Unlock()
+
+	return true, nil
+}
+
+// CompareAndSwap is never skipped, like SetIfAbsent, but remembers what it
+// stored so that equal Sets can be.
+func (c *skipEqualCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	now := c.now()
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	sum, ok := c.hash(new)
+	if err != nil || !swapped || !ok {
+		c.forget(key)
+		return swapped, err
+	}
+
+	record := writeRecord{sum: sum}
+	if ttl > 0 {
+		record.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.written[key] = record
+	c.pruneLocked(now)
+	c.mu.Unlock()
+
+	return true, nil
+}
+
+func (c *skipEqualCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	c.forget(key)
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *skipEqualCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	sum, ok := c.hash(value)
+	if !ok {
+		// Can't compare - always write
+		c.forget(key)
+		return c.next.Set(ctx, key, value, ttl)
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	record, found := c.written[key]
+	c.mu.Unlock()
+
+	if found && record.sum == sum && (record.expiresAt.IsZero() || now.Before(record.expiresAt)) {
+		return nil
+	}
+
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		c.forget(key)
+		return err
+	}
+
+	record = writeRecord{sum: sum}
+	if ttl > 0 {
+		record.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.written[key] = record
+	c.pruneLocked(now)
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *skipEqualCache[T]) Delete(ctx context.Context, key string) error {
+	c.forget(key)
+	return c.next.Delete(ctx, key)
+}
+
+func (c *skipEqualCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		c.forget(key)
+	}
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *skipEqualCache[T]) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	clear(c.written)
+	c.mu.Unlock()
+
+	return Clear(ctx, c.next)
+}
+
+func (c *skipEqualCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *skipEqualCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *skipEqualCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+func (c *skipEqualCache[T]) hash(value T) ([sha256.Size]byte, bool) {
+	return valueSum(c.serializer, value)
+}
+
+// valueSum hashes the serialized form of value, with deterministic protobuf
+// encoding for proto messages and serializer for other types, reporting
+// whether it could be serialized.
+func valueSum[T any](serializer Serializer, value T) ([sha256.Size]byte, bool) {
+	var data []byte
+	var err error
+	if msg, ok := any(value).(proto.Message); ok {
+		data, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	} else {
+		data, err = serializer.Serialize(value)
+	}
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(data), true
+}
+
+func (c *skipEqualCache[T]) forget(key string) {
+	c.mu.Lock()
+	delete(c.written, key)
+	c.mu.Unlock()
+}
+
+// pruneLocked drops expired write records, at most once per minute.
+func (c *skipEqualCache[T]) pruneLocked(now time.Time) {
+	if now.Sub(c.lastPrune) < time.Minute {
+		return
+	}
+	for key, record := range c.written {
+		if !record.expiresAt.IsZero() && !now.Before(record.expiresAt) {
+			delete(c.written, key)
+		}
+	}
+	c.lastPrune = now
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingCache counts the writes that reach it.
+type countingCache[T any] struct {
+	Cache[T]
+	sets    int
+	deletes int
+}
+
+func (c *countingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	c.sets++
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func (c *countingCache[T]) Delete(ctx context.Context, key string) error {
+	c.deletes++
+	return c.Cache.Delete(ctx, key)
+}
+
+func TestSkipEqualWrites(t *testing.T) {
+	inner := &countingCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	defer inner.Close()
+
+	now := time.Now()
+	cache := NewSkipEqualWrites[TestUser](inner, nil).(*skipEqualCache[TestUser])
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	user := TestUser{ID: "123", Name: "John"}
+
+	// Test repeated equal writes are skipped
+	for i := 0; i < 3; i++ {
+		if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if inner.sets != 1 {
+		t.Errorf("Expected 1 backend write, got %d", inner.sets)
+	}
+
+	// Test changed value is written
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123", Name: "Jane"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if inner.sets != 2 {
+		t.Errorf("Expected 2 backend writes, got %d", inner.sets)
+	}
+
+	// Test equal value is written again once the previous write expired
+	now = now.Add(2 * time.Minute)
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123", Name: "Jane"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if inner.sets != 3 {
+		t.Errorf("Expected 3 backend writes, got %d", inner.sets)
+	}
+
+	// Test Delete forgets the last write
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123", Name: "Jane"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if inner.sets != 4 {
+		t.Errorf("Expected 4 backend writes, got %d", inner.sets)
+	}
+	if _, found := cache.Get(ctx, "key1"); !found {
+		t.Error("Expected key1 to be present after Set following Delete")
+	}
+}
@@ -0,0 +1,274 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanConfig describes the caches whose operations WithSpans traces.
+type SpanConfig struct {
+	// Name identifies the cache in the "cache.name" attribute (optional)
+	Name string
+
+	// Backend is reported in the "cache.backend" attribute, e.g. "memory"
+	// (optional)
+	Backend string
+
+	// Serializer is reported in the "cache.serializer" attribute, e.g.
+	// "json", or "none" for caches holding values as is (optional)
+	Serializer string
+
+	// TracerProvider creates the spans
+	// (default: the global OpenTelemetry tracer provider)
+	TracerProvider trace.TracerProvider
+}
+
+// spansCache starts a span for every operation.
+type spansCache[T any] struct {
+	next   Cache[T]
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+// WithSpans wraps a cache so that every operation runs in its own span,
+// named after the method (e.g. "cache.Get", "cache.Set") and started from
+// the caller's context. Unlike the command spans of an instrumented Redis
+// client, they cover the whole operation, serialization included, for every
+// backend, the memory cache too. Spans carry the cache name, backend and
+// serializer, a hash of the key ("cache.key_hash", so keys holding personal
+// data don't reach the tracing backend) and, for reads, whether the key was
+// found ("cache.hit"). Failed operations record the error.
+func WithSpans[T any](cache Cache[T], config *SpanConfig) Cache[T] {
+	if config == nil {
+		config = &SpanConfig{}
+	}
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &spansCache[T]{
+		next:   cache,
+		tracer: provider.Tracer(instrumentationName),
+		attrs: []attribute.KeyValue{
+			attribute.String("cache.name", config.Name),
+			attribute.String("cache.backend", config.Backend),
+			attribute.String("cache.serializer", config.Serializer),
+		},
+	}
+}
+
+// keyHash returns the attribute identifying key without revealing it.
+func keyHash(key string) attribute.KeyValue {
+	sum := sha256.Sum256([]byte(key))
+	return attribute.String("cache.key_hash", hex.EncodeToString(sum[:8]))
+}
+
+// start starts the span of the operation name on key, if any.
+func (c *spansCache[T]) start(ctx context.Context, name string, keys ...string) (context.Context, trace.Span) {
+	attrs := c.attrs
+	switch len(keys) {
+	case 0:
+	case 1:
+		attrs = append(attrs[:len(attrs):len(attrs)], keyHash(keys[0]))
+	default:
+		attrs = append(attrs[:len(attrs):len(attrs)], attribute.Int("cache.keys", len(keys)))
+	}
+	return c.tracer.Start(ctx, "cache."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// end ends span, recording err if the operation failed.
+func (c *spansCache[T]) end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endRead ends the span of a read, recording whether the key was found.
+func (c *spansCache[T]) endRead(span trace.Span, found bool, err error) {
+	if err == nil {
+		span.SetAttributes(attribute.Bool("cache.hit", found))
+	}
+	c.end(span, err)
+}
+
+func (c *spansCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	ctx, span := c.start(ctx, "Get", key)
+	value, found := c.next.Get(ctx, key)
+	c.endRead(span, found, nil)
+	return value, found
+}
+
+func (c *spansCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	ctx, span := c.start(ctx, "Get", key)
+	value, found, err := GetWithError(ctx, c.next, key)
+	c.endRead(span, found, err)
+	return value, found, err
+}
+
+func (c *spansCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	ctx, span := c.start(ctx, "GetMulti", keys...)
+	results := GetMulti(ctx, c.next, keys...)
+	hits := 0
+	var errs []error
+	for _, r := range results {
+		if r.Found {
+			hits++
+		}
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	span.SetAttributes(attribute.Int("cache.hits", hits))
+	c.end(span, errors.Join(errs...))
+	return results
+}
+
+func (c *spansCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	ctx, span := c.start(ctx, "GetOrSet", key)
+	loaded := false
+	value, err := GetOrSet(ctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		loaded = true
+		return load(ctx)
+	})
+	c.endRead(span, !loaded && err == nil, err)
+	return value, err
+}
+
+func (c *spansCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := c.start(ctx, "Exists", key)
+	exists, err := Exists(ctx, c.next, key)
+	c.endRead(span, exists, err)
+	return exists, err
+}
+
+func (c *spansCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ctx, span := c.start(ctx, "TTL", key)
+	ttl, found, err := TTL(ctx, c.next, key)
+	c.endRead(span, found, err)
+	return ttl, found, err
+}
+
+func (c *spansCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	ctx, span := c.start(ctx, "Expire", key)
+	err := Expire(ctx, c.next, key, ttl)
+	c.end(span, err)
+	return err
+}
+
+func (c *spansCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	ctx, span := c.start(ctx, "SetIfAbsent", key)
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	c.end(span, err)
+	return stored, err
+}
+
+func (c *spansCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	ctx, span := c.start(ctx, "CompareAndSwap", key)
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	c.end(span, err)
+	return swapped, err
+}
+
+func (c *spansCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	ctx, span := c.start(ctx, "GetDel", key)
+	value, found, err := GetDel(ctx, c.next, key)
+	c.endRead(span, found, err)
+	return value, found, err
+}
+
+func (c *spansCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	ctx, span := c.start(ctx, "Set", key)
+	err := c.next.Set(ctx, key, value, ttl)
+	c.end(span, err)
+	return err
+}
+
+func (c *spansCache[T]) Delete(ctx context.Context, key string) error {
+	ctx, span := c.start(ctx, "Delete", key)
+	err := c.next.Delete(ctx, key)
+	c.end(span, err)
+	return err
+}
+
+func (c *spansCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	ctx, span := c.start(ctx, "DeleteMulti", keys...)
+	err := deleteMulti(ctx, c.next, keys)
+	c.end(span, err)
+	return err
+}
+
+func (c *spansCache[T]) Clear(ctx context.Context) error {
+	ctx, span := c.start(ctx, "Clear")
+	err := Clear(ctx, c.next)
+	c.end(span, err)
+	return err
+}
+
+func (c *spansCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *spansCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *spansCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// serializerNameOf returns the serializer caches created by New from config
+// report in their spans.
+func serializerNameOf[T any](config *Config) string {
+	var serializer Serializer
+	var serialization SerializationType
+	switch config.Type {
+	case TypeMemory:
+		if config.Memory == nil || config.Memory.Engine != MemoryEngineFreeCache {
+			return "none"
+		}
+		serializer = config.Memory.Serializer
+	case TypeDistributed, TypeTiered:
+		if config.Distributed != nil {
+			serializer, serialization = config.Distributed.Serializer, config.Distributed.SerializationType
+		}
+	case TypeDisk:
+		if config.Disk != nil {
+			serializer = config.Disk.Serializer
+		}
+	case TypeSQL:
+		if config.SQL != nil {
+			serializer = config.SQL.Serializer
+		}
+	case TypeDynamoDB:
+		if config.DynamoDB != nil {
+			serializer = config.DynamoDB.Serializer
+		}
+	default:
+		return "none"
+	}
+
+	switch {
+	case config.ForceSerialization != "":
+		return string(config.ForceSerialization)
+	case serializer != nil:
+		return "custom"
+	case serialization != "":
+		return string(serialization)
+	case isProtoType[T]():
+		return string(SerializationProtobuf)
+	default:
+		return string(SerializationJSON)
+	}
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpansFromNew(t *testing.T) {
+	ctx := context.Background()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	cache, err := New[TestUser](&Config{
+		Type:            TypeMemory,
+		Name:            "users",
+		TraceOperations: true,
+		TracerProvider:  provider,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "user:1", TestUser{ID: "1"}, time.Minute)
+	cache.Get(ctx, "user:1")
+	cache.Get(ctx, "user:2")
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	for i, want := range []struct {
+		name string
+		hit  attribute.Value
+	}{
+		{"cache.Set", attribute.Value{}},
+		{"cache.Get", attribute.BoolValue(true)},
+		{"cache.Get", attribute.BoolValue(false)},
+	} {
+		span := spans[i]
+		if span.Name() != want.name {
+			t.Errorf("Span %d: expected %s, got %s", i, want.name, span.Name())
+		}
+		attrs := attribute.NewSet(span.Attributes()...)
+		if hit, _ := attrs.Value("cache.hit"); hit != want.hit {
+			t.Errorf("Span %d: expected cache.hit %v, got %v", i, want.hit.Emit(), hit.Emit())
+		}
+		for key, value := range map[attribute.Key]string{
+			"cache.name":       "users",
+			"cache.backend":    "memory",
+			"cache.serializer": "none",
+		} {
+			if got, _ := attrs.Value(key); got.AsString() != value {
+				t.Errorf("Span %d: expected %s %q, got %q", i, key, value, got.AsString())
+			}
+		}
+		hash, _ := attrs.Value("cache.key_hash")
+		if hash.AsString() == "" || hash.AsString() == "user:1" {
+			t.Errorf("Span %d: expected a key hash, got %q", i, hash.AsString())
+		}
+	}
+}
+
+func TestSpansRecordErrors(t *testing.T) {
+	ctx := context.Background()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	cache := WithSpans(NewMemory[TestUser](nil), &SpanConfig{TracerProvider: provider})
+	_ = cache.Close()
+	_, _, err := GetWithError(ctx, cache, "1")
+	if !errors.Is(err, ErrClosed) {
+		t.Fatalf("Expected ErrClosed, got %v", err)
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error || len(span.Events()) != 1 {
+		t.Errorf("Expected the error to be recorded, got %v with %d events", span.Status(), len(span.Events()))
+	}
+	if _, found := attribute.NewSet(span.Attributes()...).Value("cache.hit"); found {
+		t.Error("Expected no cache.hit attribute on failed reads")
+	}
+}
+
+func TestSpansParentedByCaller(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	cache := WithSpans(NewMemory[TestUser](nil), &SpanConfig{TracerProvider: provider})
+	defer cache.Close()
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "request")
+	_ = deleteMulti(ctx, cache, []string{"1", "2"})
+	request.End()
+
+	span := recorder.Ended()[0]
+	if span.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Error("Expected the cache span to be a child of the caller's span")
+	}
+	if keys, _ := attribute.NewSet(span.Attributes()...).Value("cache.keys"); keys.AsInt64() != 2 {
+		t.Errorf("Expected cache.keys 2, got %v", keys.Emit())
+	}
+}
+
+func TestSerializerNameOf(t *testing.T) {
+	for _, tc := range []struct {
+		config *Config
+		want   string
+	}{
+		{&Config{Type: TypeMemory}, "none"},
+		{&Config{Type: TypeMemory, Memory: &MemoryConfig{Engine: MemoryEngineFreeCache}}, "json"},
+		{&Config{Type: TypeDistributed, Distributed: &DistributedConfig{SerializationType: SerializationGob}}, "gob"},
+		{&Config{Type: TypeDistributed, Distributed: &DistributedConfig{Serializer: NewJSONSerializer()}}, "custom"},
+		{&Config{Type: TypeSQL, ForceSerialization: SerializationJSON, SQL: &SQLConfig{Serializer: NewJSONSerializer()}}, "json"},
+	} {
+		if got := serializerNameOf[TestUser](tc.config); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.config.Type, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,571 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLDialect selects the SQL flavor used by the SQL cache.
+type SQLDialect string
+
+const (
+	// SQLDialectPostgres stores entries in an UNLOGGED Postgres table, which
+	// skips the write-ahead log: faster writes, emptied after a crash.
+	SQLDialectPostgres SQLDialect = "postgres"
+
+	// SQLDialectMySQL stores entries in a MySQL/MariaDB table.
+	SQLDialectMySQL SQLDialect = "mysql"
+)
+
+// tableNamePattern restricts table names to plain identifiers, since they are
+// interpolated into statements.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlStatements holds the statements for one dialect and table.
+type sqlStatements struct {
+	dialect SQLDialect
+	create  []string
+	get     string
+	exists  string
+	ttl     string
+	expire  string
+	upsert  string
+	insert  string
+	swap    string
+	take    string
+	keys    string
+	count   string
+	purge   string
+	clear   string
+	table   string
+}
+
+func newSQLStatements(dialect SQLDialect, table string) (*sqlStatements, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid SQL cache table name: %q", table)
+	}
+
+	s := &sqlStatements{dialect: dialect, table: table}
+	switch dialect {
+	case SQLDialectPostgres:
+		s.create = []string{
+			fmt.Sprintf(`CREATE UNLOGGED TABLE IF NOT EXISTS %s (cache_key TEXT PRIMARY KEY, value BYTEA NOT NULL, expires_at TIMESTAMPTZ)`, table),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_expires_at_idx ON %s (expires_at)`, table, table),
+		}
+		s.upsert = fmt.Sprintf(`INSERT INTO %s (cache_key, value, expires_at) VALUES ($1, $2, $3) `+
+			`ON CONFLICT (cache_key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`, table)
+		// Expired rows not yet purged are replaced; live ones are left alone
+		s.insert = fmt.Sprintf(`INSERT INTO %s (cache_key, value, expires_at) VALUES ($1, $2, $3) `+
+			`ON CONFLICT (cache_key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at `+
+			`WHERE %s.expires_at IS NOT NULL AND %s.expires_at <= $4`, table, table, table)
+	case SQLDialectMySQL:
+		s.create = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (cache_key VARCHAR(255) PRIMARY KEY, value LONGBLOB NOT NULL, expires_at DATETIME(6) NULL, INDEX (expires_at))`, table),
+		}
+		s.upsert = fmt.Sprintf(`INSERT INTO %s (cache_key, value, expires_at) VALUES (?, ?, ?) `+
+			`ON DUPLICATE KEY UPDATE value = VALUES(value), expires_at = VALUES(expires_at)`, table)
+		// Assignments run in order, so both conditions see the old expiry
+		s.insert = fmt.Sprintf(`INSERT INTO %s (cache_key, value, expires_at) VALUES (?, ?, ?) `+
+			`ON DUPLICATE KEY UPDATE `+
+			`value = IF(expires_at IS NOT NULL AND expires_at <= ?, VALUES(value), value), `+
+			`expires_at = IF(expires_at IS NOT NULL AND expires_at <= ?, VALUES(expires_at), expires_at)`, table)
+	default:
+		return nil, fmt.Errorf("unknown SQL dialect: %s", dialect)
+	}
+
+	s.get = fmt.Sprintf(`SELECT value FROM %s WHERE cache_key = %s AND (expires_at IS NULL OR expires_at > %s)`,
+		table, s.placeholder(1), s.placeholder(2))
+	s.exists = fmt.Sprintf(`SELECT 1 FROM %s WHERE cache_key = %s AND (expires_at IS NULL OR expires_at > %s)`,
+		table, s.placeholder(1), s.placeholder(2))
+	// Remaining lifetimes are computed by the database in microseconds, so
+	// MySQL DSNs work without parseTime
+	remaining := fmt.Sprintf(`TIMESTAMPDIFF(MICROSECOND, %s, expires_at)`, s.placeholder(2))
+	if dialect == SQLDialectPostgres {
+		remaining = `CAST(EXTRACT(EPOCH FROM expires_at - $2::timestamptz) * 1000000 AS BIGINT)`
+	}
+	s.ttl = fmt.Sprintf(`SELECT %s FROM %s WHERE cache_key = %s AND (expires_at IS NULL OR expires_at > %s)`,
+		remaining, table, s.placeholder(1), s.placeholder(3))
+	s.expire = fmt.Sprintf(`UPDATE %s SET expires_at = %s WHERE cache_key = %s AND (expires_at IS NULL OR expires_at > %s)`,
+		table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	s.swap = fmt.Sprintf(`UPDATE %s SET value = %s, expires_at = %s WHERE cache_key = %s AND value = %s AND (expires_at IS NULL OR expires_at > %s)`,
+		table, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5))
+	// Keys are paged in key order, after the last key of the previous page;
+	// placeholders are numbered in the order they appear for MySQL
+	keysRemaining := `TIMESTAMPDIFF(MICROSECOND, ?, expires_at)`
+	if dialect == SQLDialectPostgres {
+		keysRemaining = `CAST(EXTRACT(EPOCH FROM expires_at - $1::timestamptz) * 1000000 AS BIGINT)`
+	}
+	s.keys = fmt.Sprintf(`SELECT cache_key, LENGTH(value), %s FROM %s WHERE cache_key > %s AND (expires_at IS NULL OR expires_at > %s) ORDER BY cache_key LIMIT %s`,
+		keysRemaining, table, s.placeholder(2), s.placeholder(3), s.placeholder(4))
+	s.count = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE expires_at IS NULL OR expires_at > %s`,
+		table, s.placeholder(1))
+	// Locking the row makes concurrent GetDel calls wait for the first to
+	// delete it
+	s.take = s.get + ` FOR UPDATE`
+	s.purge = fmt.Sprintf(`DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at <= %s`,
+		table, s.placeholder(1))
+	s.clear = fmt.Sprintf(`DELETE FROM %s`, table)
+	return s, nil
+}
+
+// placeholder returns the n-th (1-based) bind parameter.
+func (s *sqlStatements) placeholder(n int) string {
+	if s.dialect == SQLDialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// delete returns a statement deleting n keys.
+func (s *sqlStatements) delete(n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(i + 1)
+	}
+	return fmt.Sprintf(`DELETE FROM %s WHERE cache_key IN (%s)`, s.table, strings.Join(placeholders, ", "))
+}
+
+// sqlCache stores entries in a relational table with an expiry column.
+// Expired rows are misses and are purged periodically.
+type sqlCache[T any] struct {
+	db     *sql.DB
+	stmts  *sqlStatements
+	codec  valueCodec[T]
+	closed closeGuard
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewSQL creates a cache stored in a Postgres or MySQL table. The database
+// handle is owned by the caller and left open by Close.
+func NewSQL[T any](config *SQLConfig) (Cache[T], error) {
+	if config == nil {
+		return nil, errors.New("config cannot be nil")
+	}
+	if config.DB == nil {
+		return nil, errors.New("SQL cache DB is required")
+	}
+	if err := checkValueType[T](config.Serializer); err != nil {
+		return nil, err
+	}
+
+	dialect := config.Dialect
+	if dialect == "" {
+		dialect = SQLDialectPostgres
+	}
+	table := config.Table
+	if table == "" {
+		table = "cache_entries"
+	}
+	purgeInterval := config.PurgeInterval
+	if purgeInterval == 0 {
+		purgeInterval = time.Minute
+	}
+
+	stmts, err := newSQLStatements(dialect, table)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.CreateTable {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, stmt := range stmts.create {
+			if _, err := config.DB.ExecContext(ctx, stmt); err != nil {
+				return nil, fmt.Errorf("failed to create SQL cache table: %w", err)
+			}
+		}
+	}
+
+	c := &sqlCache[T]{
+		db:    config.DB,
+		stmts: stmts,
+		codec: newValueCodec[T](config.Serializer),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if purgeInterval > 0 {
+		go c.purgeLoop(purgeInterval)
+	} else {
+		close(c.done)
+	}
+	return c, nil
+}
+
+func (c *sqlCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *sqlCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	var data []byte
+	err := c.db.QueryRowContext(ctx, c.stmts.get, key, time.Now().UTC()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, err := c.codec.decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+func (c *sqlCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	var one int
+	err := c.db.QueryRowContext(ctx, c.stmts.exists, key, time.Now().UTC()).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *sqlCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	if c.closed.isClosed() {
+		return 0, false, ErrClosed
+	}
+
+	now := time.Now().UTC()
+	var micros sql.NullInt64
+	err := c.db.QueryRowContext(ctx, c.stmts.ttl, key, now, now).Scan(&micros)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if !micros.Valid {
+		return 0, true, nil
+	}
+	// Never report an entry that hasn't expired yet as not expiring
+	return max(time.Duration(micros.Int64)*time.Microsecond, time.Microsecond), true, nil
+}
+
+func (c *sqlCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	now := time.Now().UTC()
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: now.Add(ttl), Valid: true}
+	}
+	result, err := c.db.ExecContext(ctx, c.stmts.expire, expiresAt, key, now)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	// MySQL doesn't count rows the update left unchanged, e.g. when
+	// removing the expiry of an entry without one
+	found, err := c.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+func (c *sqlCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().UTC().Add(ttl), Valid: true}
+	}
+	_, err = c.db.ExecContext(ctx, c.stmts.upsert, key, data, expiresAt)
+	return err
+}
+
+// SetIfAbsent inserts the row unless a live one exists for key, replacing an
+// expired row that hasn't been purged yet.
+func (c *sqlCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	data, err := c.codec.encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: now.Add(ttl), Valid: true}
+	}
+	args := []any{key, data, expiresAt, now}
+	if c.stmts.dialect == SQLDialectMySQL {
+		args = append(args, now)
+	}
+	result, err := c.db.ExecContext(ctx, c.stmts.insert, args...)
+	if err != nil {
+		return false, err
+	}
+	// Skipped rows count 0; MySQL counts replaced rows 2
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// CompareAndSwap updates the row of key if its value equals the serialized
+// old value.
+func (c *sqlCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if c.closed.isClosed() {
+		return false, ErrClosed
+	}
+
+	oldData, err := c.codec.encode(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.encode(new)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: now.Add(ttl), Valid: true}
+	}
+	result, err := c.db.ExecContext(ctx, c.stmts.swap, newData, expiresAt, key, oldData, now)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err == nil, err
+	}
+	if !bytes.Equal(oldData, newData) {
+		return false, nil
+	}
+
+	// MySQL doesn't count rows the update left unchanged, e.g. when swapping
+	// a value without expiry for itself
+	current, found, err := c.GetWithError(ctx, key)
+	if err != nil || !found {
+		return false, err
+	}
+	currentData, err := c.codec.encode(current)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(currentData, oldData), nil
+}
+
+// GetDel selects the live row of key for update and deletes it in one
+// transaction.
+func (c *sqlCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.closed.isClosed() {
+		return zero, false, ErrClosed
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return zero, false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var data []byte
+	err = tx.QueryRowContext(ctx, c.stmts.take, key, time.Now().UTC()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+	if _, err := tx.ExecContext(ctx, c.stmts.delete(1), key); err != nil {
+		return zero, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return zero, false, err
+	}
+
+	value, err := c.codec.decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// scanKeys pages through live rows in key order. Patterns are matched in
+// Go, so the table is read in full; keep scans for operational tooling.
+func (c *sqlCache[T]) scanKeys(ctx context.Context, cfg ScanConfig, fn func([]KeyInfo) error) error {
+	after := ""
+	for {
+		if c.closed.isClosed() {
+			return ErrClosed
+		}
+
+		now := time.Now().UTC()
+		rows, err := c.db.QueryContext(ctx, c.stmts.keys, now, after, now, cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		var (
+			batch []KeyInfo
+			read  int
+		)
+		for rows.Next() {
+			var (
+				key    string
+				size   int64
+				micros sql.NullInt64
+			)
+			if err := rows.Scan(&key, &size, &micros); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			read++
+			after = key
+
+			matched, err := path.Match(cfg.Pattern, key)
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if !matched {
+				continue
+			}
+			info := KeyInfo{Key: key, Size: size}
+			if micros.Valid {
+				info.TTL = max(time.Duration(micros.Int64)*time.Microsecond, time.Microsecond)
+			}
+			batch = append(batch, info)
+		}
+		if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+			return err
+		}
+
+		if len(batch) > 0 {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		if read < cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// countKeys counts the live rows.
+func (c *sqlCache[T]) countKeys(ctx context.Context) (int64, error) {
+	if c.closed.isClosed() {
+		return 0, ErrClosed
+	}
+
+	var n int64
+	if err := c.db.QueryRowContext(ctx, c.stmts.count, time.Now().UTC()).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (c *sqlCache[T]) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, key)
+}
+
+func (c *sqlCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]any, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+	_, err := c.db.ExecContext(ctx, c.stmts.delete(len(keys)), args...)
+	return err
+}
+
+// Clear deletes every row of the table.
+func (c *sqlCache[T]) Clear(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+
+	_, err := c.db.ExecContext(ctx, c.stmts.clear)
+	return err
+}
+
+func (c *sqlCache[T]) Ping(ctx context.Context) error {
+	if c.closed.isClosed() {
+		return ErrClosed
+	}
+	return c.db.PingContext(ctx)
+}
+
+// Close stops the purge loop. The database handle is left open.
+func (c *sqlCache[T]) Close() error {
+	if !c.closed.close() {
+		return nil
+	}
+	close(c.stop)
+	<-c.done
+	return nil
+}
+
+// purgeLoop deletes expired rows until the cache is closed.
+func (c *sqlCache[T]) purgeLoop(interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			// Best effort - a failed purge is retried on the next tick
+			_ = c.purgeExpired(context.Background())
+		}
+	}
+}
+
+// purgeExpired deletes every expired row.
+func (c *sqlCache[T]) purgeExpired(ctx context.Context) error {
+	_, err := c.db.ExecContext(ctx, c.stmts.purge, time.Now().UTC())
+	return err
+}
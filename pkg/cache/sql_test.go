@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestSQLStatements(t *testing.T) {
+	postgres, err := newSQLStatements(SQLDialectPostgres, "cache_entries")
+	if err != nil {
+		t.Fatalf("newSQLStatements failed: %v", err)
+	}
+	if want := "DELETE FROM cache_entries WHERE cache_key IN ($1, $2, $3)"; postgres.delete(3) != want {
+		t.Errorf("Expected %q, got %q", want, postgres.delete(3))
+	}
+
+	mysql, err := newSQLStatements(SQLDialectMySQL, "cache_entries")
+	if err != nil {
+		t.Fatalf("newSQLStatements failed: %v", err)
+	}
+	if want := "DELETE FROM cache_entries WHERE cache_key IN (?, ?)"; mysql.delete(2) != want {
+		t.Errorf("Expected %q, got %q", want, mysql.delete(2))
+	}
+
+	if want := "SELECT value FROM cache_entries WHERE cache_key = ? AND (expires_at IS NULL OR expires_at > ?) FOR UPDATE"; mysql.take != want {
+		t.Errorf("Expected %q, got %q", want, mysql.take)
+	}
+
+	if _, err := newSQLStatements(SQLDialectPostgres, "cache; DROP TABLE users"); err == nil {
+		t.Error("Expected error for invalid table name")
+	}
+	if _, err := newSQLStatements(SQLDialect("oracle"), "cache_entries"); err == nil {
+		t.Error("Expected error for unknown dialect")
+	}
+}
+
+func TestNewSQLRequiresDB(t *testing.T) {
+	if _, err := NewSQL[TestUser](&SQLConfig{}); err == nil {
+		t.Error("Expected error for missing DB")
+	}
+	if _, err := NewSQL[TestUser](nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+}
+
+func TestSQLCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	db := startPostgres(t)
+
+	cache, err := NewSQL[TestUser](&SQLConfig{DB: db, CreateTable: true, PurgeInterval: -1})
+	if err != nil {
+		t.Fatalf("NewSQL failed: %v", err)
+	}
+	defer cache.Close()
+
+	user := TestUser{ID: "123", Name: "John"}
+	if err := cache.Set(ctx, "key1", user, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got, found := cache.Get(ctx, "key1"); !found || got != user {
+		t.Errorf("Expected %+v, got %+v (found=%v)", user, got, found)
+	}
+
+	if found, err := Exists(ctx, cache, "key1"); err != nil || !found {
+		t.Errorf("Expected key1 to exist, got %v, %v", found, err)
+	}
+	if ttl, found, err := TTL(ctx, cache, "key1"); err != nil || !found || ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("Expected about a minute left for key1, got %v, %v, %v", ttl, found, err)
+	}
+
+	// Test overwrite
+	updated := TestUser{ID: "123", Name: "Jane"}
+	_ = cache.Set(ctx, "key1", updated, 0)
+	if got, _ := cache.Get(ctx, "key1"); got != updated {
+		t.Errorf("Expected %+v after overwrite, got %+v", updated, got)
+	}
+	if ttl, found, err := TTL(ctx, cache, "key1"); err != nil || !found || ttl != 0 {
+		t.Errorf("Expected key1 not to expire, got %v, %v, %v", ttl, found, err)
+	}
+	if err := Expire(ctx, cache, "key1", time.Hour); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if ttl, _, _ := TTL(ctx, cache, "key1"); ttl <= 59*time.Minute {
+		t.Errorf("Expected about an hour left for key1, got %v", ttl)
+	}
+	if err := Expire(ctx, cache, "missing", time.Hour); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+
+	// Test SetIfAbsent, replacing an expired row that hasn't been purged
+	if stored, err := SetIfAbsent(ctx, cache, "key1", user, time.Minute); err != nil || stored {
+		t.Errorf("Expected SetIfAbsent to keep key1, got %v, %v", stored, err)
+	}
+	if got, _ := cache.Get(ctx, "key1"); got != updated {
+		t.Errorf("Expected %+v after SetIfAbsent, got %+v", updated, got)
+	}
+	_ = cache.Set(ctx, "lease", user, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if stored, err := SetIfAbsent(ctx, cache, "lease", updated, time.Minute); err != nil || !stored {
+		t.Errorf("Expected SetIfAbsent to replace the expired lease, got %v, %v", stored, err)
+	}
+	if got, _ := cache.Get(ctx, "lease"); got != updated {
+		t.Errorf("Expected %+v for lease, got %+v", updated, got)
+	}
+	_ = cache.Delete(ctx, "lease")
+
+	// Test CompareAndSwap
+	if swapped, err := CompareAndSwap(ctx, cache, "key1", user, updated, time.Minute); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to fail for a stale value, got %v, %v", swapped, err)
+	}
+	if swapped, err := CompareAndSwap(ctx, cache, "key1", updated, user, 0); err != nil || !swapped {
+		t.Errorf("Expected CompareAndSwap to swap key1, got %v, %v", swapped, err)
+	}
+	if got, _ := cache.Get(ctx, "key1"); got != user {
+		t.Errorf("Expected %+v after CompareAndSwap, got %+v", user, got)
+	}
+	if swapped, err := CompareAndSwap(ctx, cache, "missing", user, updated, 0); err != nil || swapped {
+		t.Errorf("Expected CompareAndSwap to skip a missing key, got %v, %v", swapped, err)
+	}
+
+	// Test GetDel
+	_ = cache.Set(ctx, "token", user, time.Minute)
+	if got, found, err := GetDel(ctx, cache, "token"); err != nil || !found || got != user {
+		t.Errorf("Expected GetDel to return %+v, got %+v (found=%v, err=%v)", user, got, found, err)
+	}
+	if _, found, err := GetDel(ctx, cache, "token"); err != nil || found {
+		t.Errorf("Expected the token to be consumed, got found=%v, err=%v", found, err)
+	}
+
+	// Test Keys, paging through more rows than a batch
+	for i := range 3 {
+		_ = cache.Set(ctx, "scan:"+strconv.Itoa(i), user, time.Minute)
+	}
+	infos := collectKeys(t, cache, &ScanConfig{Pattern: "scan:*", BatchSize: 2})
+	if len(infos) != 3 || infos[0].Size <= 0 || infos[0].TTL <= 0 || infos[0].TTL > time.Minute {
+		t.Errorf("Expected 3 scan keys with sizes and TTLs, got %+v", infos)
+	}
+	var keys []string
+	for key, err := range Keys(ctx, cache, "scan:*") {
+		if err != nil {
+			t.Fatalf("Keys failed: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) != 3 {
+		t.Errorf("Expected 3 keys, got %v", keys)
+	}
+
+	// Test Len
+	before, err := Len(ctx, cache)
+	if err != nil {
+		t.Fatalf("Len failed: %v", err)
+	}
+	_ = cache.Set(ctx, "scan:3", user, time.Minute)
+	if n, err := Len(ctx, cache); err != nil || n != before+1 {
+		t.Errorf("Expected Len to grow to %d, got %d (err=%v)", before+1, n, err)
+	}
+
+	// Test DeleteMulti
+	_ = cache.Set(ctx, "key2", user, time.Minute)
+	if err := deleteMulti(ctx, cache, []string{"key1", "key2"}); err != nil {
+		t.Fatalf("DeleteMulti failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key2"); found {
+		t.Error("Expected key2 to be deleted")
+	}
+
+	// Test expiry and purge
+	_ = cache.Set(ctx, "short", user, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, found := cache.Get(ctx, "short"); found {
+		t.Error("Expected expired entry to be a miss")
+	}
+	if err := cache.(*sqlCache[TestUser]).purgeExpired(ctx); err != nil {
+		t.Fatalf("purgeExpired failed: %v", err)
+	}
+	var rows int
+	_ = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM cache_entries").Scan(&rows)
+	if rows != 0 {
+		t.Errorf("Expected expired row to be purged, got %d rows", rows)
+	}
+
+	// Test Clear
+	_ = cache.Set(ctx, "key1", user, time.Minute)
+	if err := Clear(ctx, cache); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be cleared")
+	}
+
+	// Test Close leaves the DB open
+	_ = cache.Close()
+	if err := cache.Set(ctx, "key1", user, time.Minute); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got: %v", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		t.Errorf("Expected DB to stay open, got: %v", err)
+	}
+}
+
+// startPostgres starts a Postgres container and returns a handle to it.
+func startPostgres(t *testing.T) *sql.DB {
+	t.Helper()
+
+	// Skip if Docker is not available
+	if !isDockerAvailable() {
+		t.Skip("Docker not available, skipping testcontainers test")
+	}
+
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:16-alpine",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_PASSWORD": "postgres",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	postgresContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = postgresContainer.Terminate(ctx)
+	})
+
+	host, err := postgresContainer.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+	port, err := postgresContainer.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	db, err := sql.Open("pgx", fmt.Sprintf("postgres://postgres:postgres@%s:%s/postgres?sslmode=disable", host, port.Port()))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
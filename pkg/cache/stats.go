@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoStats is returned by StatsOf for caches that don't count their
+// traffic.
+var ErrNoStats = errors.New("cache does not report statistics")
+
+// CacheStats reports the traffic a backend served since it was created.
+// Operations rejected before reaching the backend, e.g. on a closed cache or
+// a cancelled context, aren't counted.
+type CacheStats struct {
+	// Hits counts reads that found their key.
+	Hits uint64
+	// Misses counts reads that didn't.
+	Misses uint64
+	// Sets counts stored writes.
+	Sets uint64
+	// Deletes counts keys removed, found or not.
+	Deletes uint64
+	// Errors counts reads, writes and deletes that failed, including
+	// values that couldn't be decoded.
+	Errors uint64
+	// Evictions counts entries the cache removed itself, to stay within
+	// its size or on expiry; always 0 for distributed caches, whose server
+	// evicts on its own.
+	Evictions uint64
+}
+
+// HitRatio returns the fraction of reads that found their key, or 0 before
+// the first read.
+func (s CacheStats) HitRatio() float64 {
+	reads := s.Hits + s.Misses
+	if reads == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(reads)
+}
+
+// StatsProvider is an optional interface for caches that count their
+// traffic. Use the StatsOf function to reach it through decorators.
+type StatsProvider interface {
+	// Stats returns a snapshot of the traffic counters.
+	Stats() CacheStats
+}
+
+// StatsOf returns the statistics of the memory or distributed backend behind
+// cache, looking through decorators (the L2 of tiered caches). Returns
+// ErrNoStats for other caches.
+func StatsOf[T any](cache Cache[T]) (CacheStats, error) {
+	for cache != nil {
+		if sp, ok := cache.(StatsProvider); ok {
+			return sp.Stats(), nil
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return CacheStats{}, ErrNoStats
+}
+
+// statsCounters accumulates CacheStats; safe for concurrent use.
+type statsCounters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	sets      atomic.Uint64
+	deletes   atomic.Uint64
+	errors    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// read counts one read that reached the backend.
+func (c *statsCounters) read(found bool, err error) {
+	switch {
+	case err != nil:
+		c.errors.Add(1)
+	case found:
+		c.hits.Add(1)
+	default:
+		c.misses.Add(1)
+	}
+}
+
+// wrote counts one write that reached the backend.
+func (c *statsCounters) wrote(err error) {
+	if err != nil {
+		c.errors.Add(1)
+		return
+	}
+	c.sets.Add(1)
+}
+
+// deleted counts a removal of n keys that reached the backend.
+func (c *statsCounters) deleted(n int, err error) {
+	if err != nil {
+		c.errors.Add(1)
+		return
+	}
+	c.deletes.Add(uint64(n))
+}
+
+func (c *statsCounters) snapshot() CacheStats {
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Sets:      c.sets.Load(),
+		Deletes:   c.deletes.Load(),
+		Errors:    c.errors.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+			_ = flushAll(ctx, cache)
+			cache.Get(ctx, "a")
+			cache.Get(ctx, "a")
+			cache.Get(ctx, "missing")
+			_ = deleteMulti(ctx, cache, []string{"a", "b"})
+
+			stats, err := StatsOf(cache)
+			if err != nil {
+				t.Fatalf("StatsOf failed: %v", err)
+			}
+			if stats.Hits != 2 || stats.Misses != 1 || stats.Sets != 1 || stats.Deletes != 2 || stats.Errors != 0 {
+				t.Errorf("Unexpected stats: %+v", stats)
+			}
+			if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+				t.Errorf("Expected a hit ratio of 2/3, got %v", ratio)
+			}
+		})
+	}
+}
+
+func TestStatsCountsEvictions(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{MaxEntries: 1})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{}, time.Minute)
+	_ = cache.Set(ctx, "b", TestUser{}, time.Minute)
+
+	waitFor(t, func() bool {
+		stats, _ := StatsOf(cache)
+		return stats.Evictions == 1
+	})
+}
+
+func TestStatsOfThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := WithPrefix(NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute}), "p:")
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{}, time.Minute)
+	stats, err := StatsOf(cache)
+	if err != nil || stats.Sets != 1 {
+		t.Errorf("Expected the L2 stats, got %+v (err=%v)", stats, err)
+	}
+
+	if _, err := StatsOf(NewNoOp[TestUser]()); !errors.Is(err, ErrNoStats) {
+		t.Errorf("Expected ErrNoStats, got %v", err)
+	}
+}
+
+func TestStatsHitRatioWithoutReads(t *testing.T) {
+	if ratio := (CacheStats{}).HitRatio(); ratio != 0 {
+		t.Errorf("Expected 0 without reads, got %v", ratio)
+	}
+}
+
+func TestStatsDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "missing")
+	GetMulti(ctx, cache, "a", "missing")
+	_ = cache.Delete(ctx, "a")
+
+	stats, err := StatsOf(cache)
+	if err != nil {
+		t.Fatalf("StatsOf failed: %v", err)
+	}
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Sets != 1 || stats.Deletes != 1 || stats.Errors != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TagIndexConfig holds configuration for a TagIndex.
+type TagIndexConfig struct {
+	// Prefix is prepended to tag names to form the Redis set keys (default: "cache:tag:")
+	Prefix string
+}
+
+// addTagScript adds a key to a tag set and extends the set's TTL to cover it,
+// keeping the set without expiry while any of its keys has none.
+//
+// KEYS[1] tag set, ARGV[1] key, ARGV[2] TTL in ms (0 for none)
+var addTagScript = redis.NewScript(`
+local existed = redis.call('EXISTS', KEYS[1]) == 1
+redis.call('SADD', KEYS[1], ARGV[1])
+local ttl = tonumber(ARGV[2])
+if ttl == 0 then
+	redis.call('PERSIST', KEYS[1])
+elseif not existed then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	local current = redis.call('PTTL', KEYS[1])
+	if current >= 0 and current < ttl then
+		redis.call('PEXPIRE', KEYS[1], ttl)
+	end
+end
+return 1
+`)
+
+// TagIndex groups keys of a distributed cache under tags so they can be
+// inspected and invalidated together. Each tag is a Redis set of member keys;
+// a tag set expires no earlier than the longest-lived key added to it.
+//
+// Keys that expire on their own stay listed until the tag is invalidated, so
+// KeysForTag may report keys that are no longer cached.
+type TagIndex[T any] struct {
+	cache  Cache[T]
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewTagIndex creates a tag index for cache.
+// Returns an error if cache is not backed by a distributed backend.
+func NewTagIndex[T any](cache Cache[T], config *TagIndexConfig) (*TagIndex[T], error) {
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := "cache:tag:"
+	if config != nil && config.Prefix != "" {
+		prefix = config.Prefix
+	}
+
+	return &TagIndex[T]{
+		cache:  cache,
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// SetWithTags stores value in the cache and adds key to each of tags.
+func (t *TagIndex[T]) SetWithTags(ctx context.Context, key string, value T, ttl time.Duration, tags ...string) error {
+	if err := t.cache.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	// One script per tag, as tag sets may live on different cluster nodes
+	var ttlMillis int64
+	if ttl > 0 {
+		ttlMillis = max(ttl.Milliseconds(), 1)
+	}
+	for _, tag := range tags {
+		if err := addTagScript.Run(ctx, t.client, []string{t.tagKey(tag)}, key, ttlMillis).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeysForTag returns the keys currently indexed under tag, i.e. the keys an
+// InvalidateTag call would remove.
+func (t *TagIndex[T]) KeysForTag(ctx context.Context, tag string) ([]string, error) {
+	return t.client.SMembers(ctx, t.tagKey(tag)).Result()
+}
+
+// KeysForAllTags returns the keys indexed under every one of tags.
+func (t *TagIndex[T]) KeysForAllTags(ctx context.Context, tags ...string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		tagKeys[i] = t.tagKey(tag)
+	}
+	return t.client.SInter(ctx, tagKeys...).Result()
+}
+
+// InvalidateTag removes every key indexed under tag from the cache, then the tag itself.
+func (t *TagIndex[T]) InvalidateTag(ctx context.Context, tag string) error {
+	keys, err := t.KeysForTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		if err := deleteMulti(ctx, t.cache, keys); err != nil {
+			return err
+		}
+	}
+	return t.client.Del(ctx, t.tagKey(tag)).Err()
+}
+
+func (t *TagIndex[T]) tagKey(tag string) string {
+	return t.prefix + tag
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTagIndexRequiresDistributedCache(t *testing.T) {
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_, err := NewTagIndex(cache, nil)
+	if !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestTagIndexWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer cache.Close()
+
+	tags, err := NewTagIndex(cache, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tag index: %v", err)
+	}
+
+	ctx := context.Background()
+	user := TestUser{ID: "123"}
+	_ = tags.SetWithTags(ctx, "user:1", user, time.Hour, "tenant:a", "role:admin")
+	_ = tags.SetWithTags(ctx, "user:2", user, time.Hour, "tenant:a")
+	_ = tags.SetWithTags(ctx, "user:3", user, time.Hour, "tenant:b", "role:admin")
+
+	// Test KeysForTag lists the members before invalidation
+	keys, err := tags.KeysForTag(ctx, "tenant:a")
+	if err != nil {
+		t.Fatalf("KeysForTag failed: %v", err)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"user:1", "user:2"}) {
+		t.Errorf("Unexpected keys for tenant:a: %v", keys)
+	}
+
+	// Test KeysForAllTags intersects tags
+	keys, err = tags.KeysForAllTags(ctx, "tenant:a", "role:admin")
+	if err != nil {
+		t.Fatalf("KeysForAllTags failed: %v", err)
+	}
+	if !slices.Equal(keys, []string{"user:1"}) {
+		t.Errorf("Unexpected keys for tenant:a and role:admin: %v", keys)
+	}
+
+	// Test InvalidateTag removes the members and the tag
+	if err := tags.InvalidateTag(ctx, "tenant:a"); err != nil {
+		t.Fatalf("InvalidateTag failed: %v", err)
+	}
+	for _, key := range []string{"user:1", "user:2"} {
+		if _, found := cache.Get(ctx, key); found {
+			t.Errorf("Expected %s to be invalidated", key)
+		}
+	}
+	if _, found := cache.Get(ctx, "user:3"); !found {
+		t.Error("Expected user:3 to survive")
+	}
+	if keys, _ := tags.KeysForTag(ctx, "tenant:a"); len(keys) != 0 {
+		t.Errorf("Expected tenant:a to be empty, got: %v", keys)
+	}
+}
+
+func TestTagIndexKeepsPersistentTagsWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create distributed cache: %v", err)
+	}
+	defer cache.Close()
+
+	tags, err := NewTagIndex(cache, nil)
+	if err != nil {
+		t.Fatalf("Failed to create tag index: %v", err)
+	}
+	client, _ := redisClientOf(cache)
+
+	ctx := context.Background()
+	_ = tags.SetWithTags(ctx, "user:1", TestUser{ID: "1"}, time.Minute, "tenant:a")
+	_ = tags.SetWithTags(ctx, "user:2", TestUser{ID: "2"}, time.Hour, "tenant:a")
+	if ttl := client.PTTL(ctx, "cache:tag:tenant:a").Val(); ttl <= 59*time.Minute {
+		t.Errorf("Expected the tag TTL extended to the longest key, got %v", ttl)
+	}
+
+	// Test a key without expiry keeps the tag, and shorter keys don't expire it
+	_ = tags.SetWithTags(ctx, "user:3", TestUser{ID: "3"}, 0, "tenant:a")
+	_ = tags.SetWithTags(ctx, "user:4", TestUser{ID: "4"}, time.Minute, "tenant:a")
+	if ttl := client.PTTL(ctx, "cache:tag:tenant:a").Val(); ttl != -1 {
+		t.Errorf("Expected the tag to stay without expiry, got %v", ttl)
+	}
+}
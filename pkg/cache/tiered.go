@@ -0,0 +1,539 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotTiered is returned by helpers that require a tiered cache.
+var ErrNotTiered = errors.New("cache is not a tiered cache")
+
+// TieredConfig holds configuration for tiered caches.
+type TieredConfig struct {
+	// L1TTL caps how long entries stay in the in-memory tier, bounding how
+	// stale a value can get after another instance changes it (default: 1m)
+	L1TTL time.Duration
+
+	// ReadRepair verifies a sample of L1 hits against L2 and repairs
+	// divergent tiers (default: disabled)
+	ReadRepair *ReadRepairConfig
+
+	// Invalidation publishes the keys this instance writes and deletes so
+	// other instances evict them from their L1, keeping the tiers coherent
+	// across instances within the pub/sub delivery delay (default: disabled)
+	Invalidation *InvalidationBusConfig
+}
+
+// ReadRepairConfig holds configuration for read-repair between tiers.
+type ReadRepairConfig struct {
+	// SampleRate is the fraction of L1 hits verified against L2 in the
+	// background, from 0 to 1 (default: 0.01)
+	SampleRate float64
+
+	// Authoritative is the tier whose value wins: "l2" overwrites or drops the
+	// L1 copy, "l1" writes the L1 value back to L2 with L1TTL (default: "l2")
+	Authoritative string
+
+	// Timeout bounds each verification (default: 1s)
+	Timeout time.Duration
+
+	// MeterProvider receives the "cache.tiered.divergences" counter
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+}
+
+// TieredStats describes the in-flight L2 reads and loads of a tiered cache.
+type TieredStats struct {
+	// Inflight is the number of L2 reads and loads currently running.
+	Inflight int64
+
+	// Shared counts Get and GetOrSet calls whose L2 read or load was shared
+	// with at least one other call.
+	Shared uint64
+
+	// Invalidations counts keys evicted from L1 because another instance
+	// changed them.
+	Invalidations uint64
+}
+
+// tieredCache composes an in-memory L1 in front of a distributed L2.
+type tieredCache[T any] struct {
+	l1    Cache[T]
+	l2    Cache[T]
+	l1TTL time.Duration
+	// repair is nil when read-repair is disabled
+	repair *readRepair
+	// bus is nil when cross-instance invalidation is disabled
+	bus           *InvalidationBus
+	invalidations atomic.Uint64
+
+	// flights deduplicates L2 reads and loads of a key across Get and GetOrSet
+	flights  singleflight.Group
+	inflight atomic.Int64
+	shared   atomic.Uint64
+}
+
+// tieredFlight is the outcome of an L2 read or load shared between callers.
+type tieredFlight[T any] struct {
+	value  T
+	found  bool
+	loaded bool // whether a load was attempted
+}
+
+// NewTiered composes l1 (typically a memory cache) in front of l2 (typically a
+// distributed cache). Reads try L1 first and promote L2 hits into L1; writes
+// and deletes go to L2 first, then L1. Entries are kept in L1 for at most
+// L1TTL, as other instances' writes only reach their own L1.
+//
+// With Invalidation, writes, deletes and Clear are published over Redis pub/sub and
+// every other instance on the channel evicts the keys from its L1. Messages
+// lost while an instance reconnects are covered by L1TTL. The bus uses the
+// L2 client unless Invalidation.Client is set; without either it is disabled
+// with a warning.
+//
+// With ReadRepair, a sample of L1 hits is compared with L2 in the background;
+// when the tiers disagree the authoritative tier's value is copied to the
+// other and the divergence is counted, bounding staleness between tiers.
+//
+// L1 misses of the same key share one L2 read, and GetOrSet misses share one
+// load, across Get, GetWithError and GetOrSet: a Get arriving while a load of
+// its key runs waits for and returns the loaded value. Callers sharing a read
+// or load also share the context and directives of the caller that started
+// it. TieredStatsOf reports the reads and loads in flight.
+//
+// WithNoStore and WithMaxAge directives on the context skip or cap
+// promotions and loaded values in both tiers; a positive WithMaxAge also caps
+// the TTL of Set.
+//
+// Close closes both tiers.
+func NewTiered[T any](l1, l2 Cache[T], config *TieredConfig) Cache[T] {
+	l1TTL := time.Minute
+	if config != nil && config.L1TTL > 0 {
+		l1TTL = config.L1TTL
+	}
+
+	c := &tieredCache[T]{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: l1TTL,
+	}
+	if config != nil && config.ReadRepair != nil {
+		c.repair = newReadRepair(config.ReadRepair)
+	}
+	if config != nil && config.Invalidation != nil {
+		c.bus = c.newBus(config.Invalidation)
+	}
+	return c
+}
+
+// newBus subscribes to invalidations of other instances, returning nil when
+// no client is available.
+func (c *tieredCache[T]) newBus(config *InvalidationBusConfig) *InvalidationBus {
+	cfg := *config
+	if cfg.Client == nil {
+		client, err := redisClientOf(c.l2)
+		if err != nil {
+			slog.Default().Warn("cache: tiered invalidation disabled: L2 is not Redis/Valkey and no Client is set")
+			return nil
+		}
+		cfg.Client = client
+	}
+
+	bus, _ := NewInvalidationBus(&cfg, func(keys []string) {
+		// Best effort: a key left behind expires with L1TTL
+		if keys == nil {
+			_ = Clear(context.Background(), c.l1)
+			traceAllKeys(KeyEvent{Operation: OperationClear, Source: "tiered", Reason: "invalidation"})
+			return
+		}
+		_ = deleteMulti(context.Background(), c.l1, keys)
+		c.invalidations.Add(uint64(len(keys)))
+		for _, key := range keys {
+			traceKey(KeyEvent{Key: key, Operation: OperationDelete, Source: "tiered", Reason: "invalidation"})
+		}
+	})
+	return bus
+}
+
+// publish tells other instances to evict keys from their L1.
+func (c *tieredCache[T]) publish(ctx context.Context, keys ...string) {
+	if c.bus != nil {
+		c.bus.Publish(ctx, keys...)
+	}
+}
+
+func (c *tieredCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := c.GetWithError(ctx, key)
+	return value, found
+}
+
+func (c *tieredCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if value, found := c.l1.Get(ctx, key); found {
+		noteTier(ctx, "l1")
+		c.maybeRepair(ctx, key, value)
+		return value, true, nil
+	}
+
+	flight, err := c.do(key, func() (tieredFlight[T], error) {
+		return c.readL2(ctx, key)
+	})
+	if flight.found {
+		noteTier(ctx, "l2")
+	}
+	return flight.value, flight.found, err
+}
+
+// GetMulti reads the keys from L1 and the ones L1 misses from L2 in one
+// batch per tier, promoting the L2 hits into L1.
+func (c *tieredCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	results := GetMulti(ctx, c.l1, keys...)
+
+	var missed []string
+	var at []int
+	for i, r := range results {
+		if r.Found {
+			c.maybeRepair(ctx, r.Key, r.Value)
+			continue
+		}
+		missed = append(missed, keys[i])
+		at = append(at, i)
+	}
+	if len(missed) < len(keys) {
+		noteTier(ctx, "l1")
+	}
+	if len(missed) == 0 {
+		return results
+	}
+
+	for j, r := range GetMulti(ctx, c.l2, missed...) {
+		if r.Found {
+			noteTier(ctx, "l2")
+			c.promote(ctx, r.Key, r.Value)
+		}
+		results[at[j]] = r
+	}
+	return results
+}
+
+// GetOrSet returns the value of key from L1 or L2, loading and storing it in
+// both tiers on a miss.
+func (c *tieredCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	if value, found := c.l1.Get(ctx, key); found {
+		noteTier(ctx, "l1")
+		return value, nil
+	}
+
+	// The read may be one started by Get, which doesn't load on a miss
+	flight, err := c.do(key, func() (tieredFlight[T], error) {
+		// L2 errors are treated as misses, like the package GetOrSet does
+		if flight, _ := c.readL2(ctx, key); flight.found {
+			return flight, nil
+		}
+		return c.load(ctx, key, ttl, load)
+	})
+	if !flight.found && !flight.loaded {
+		flight, err = c.do(key, func() (tieredFlight[T], error) {
+			return c.load(ctx, key, ttl, load)
+		})
+	}
+	return flight.value, err
+}
+
+// load calls load and stores its result in both tiers.
+func (c *tieredCache[T]) load(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (tieredFlight[T], error) {
+	value, err := loadAndStore(ctx, c, key, ttl, load)
+	return tieredFlight[T]{value: value, found: err == nil, loaded: true}, err
+}
+
+// readL2 reads key from L2 and promotes a hit into L1.
+func (c *tieredCache[T]) readL2(ctx context.Context, key string) (tieredFlight[T], error) {
+	value, found, err := GetWithError(ctx, c.l2, key)
+	if found {
+		c.promote(ctx, key, value)
+	}
+	return tieredFlight[T]{value: value, found: found}, err
+}
+
+// promote copies an L2 hit into L1, for no longer than the L2 entry has left
+// when L2 reports it.
+func (c *tieredCache[T]) promote(ctx context.Context, key string, value T) {
+	ttl, store := directiveTTL(ctx, c.l1TTL)
+	if !store {
+		return
+	}
+	// Don't let the L1 copy outlive the L2 entry
+	if remaining, ok, err := TTL(ctx, c.l2, key); err == nil && ok && remaining > 0 && remaining < ttl {
+		ttl = remaining
+	}
+	// Promotion is best effort - the value was served either way
+	_ = c.l1.Set(ctx, key, value, ttl)
+}
+
+// do runs fn for key unless a read or load of key is already in flight, in
+// which case it waits for that one's outcome.
+func (c *tieredCache[T]) do(key string, fn func() (tieredFlight[T], error)) (tieredFlight[T], error) {
+	result, err, shared := c.flights.Do(key, func() (interface{}, error) {
+		c.inflight.Add(1)
+		defer c.inflight.Add(-1)
+		return fn()
+	})
+	if shared {
+		c.shared.Add(1)
+	}
+	flight, _ := result.(tieredFlight[T])
+	return flight, err
+}
+
+func (c *tieredCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	if found, err := Exists(ctx, c.l1, key); err == nil && found {
+		return true, nil
+	}
+	return Exists(ctx, c.l2, key)
+}
+
+// TTL reports the lifetime left in L2, which outlives the capped L1 copies,
+// and falls back to L1 for entries L2 doesn't have. It doesn't promote.
+func (c *tieredCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ttl, found, err := TTL(ctx, c.l2, key)
+	if err == nil && found {
+		return ttl, true, nil
+	}
+	if l1TTL, l1Found, l1Err := TTL(ctx, c.l1, key); l1Err == nil && l1Found {
+		return l1TTL, true, nil
+	}
+	return ttl, found, err
+}
+
+// Expire changes the lifetime in L2 and of the L1 copy, capped like Set caps
+// it, and invalidates the copies of other instances.
+func (c *tieredCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := Expire(ctx, c.l2, key, ttl); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			// Don't let L1 outlive the entry
+			_ = deleteMulti(ctx, c.l1, []string{key})
+		}
+		return err
+	}
+	c.publish(ctx, key)
+	// L1 often doesn't hold the key
+	if err := Expire(ctx, c.l1, key, c.capL1TTL(ttl)); err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	return nil
+}
+
+// SetIfAbsent claims key in L2, shared by every instance, and only then
+// stores the L1 copy and invalidates the copies of other instances.
+func (c *tieredCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.l2, key, value, ttl)
+	if err != nil || !stored {
+		return false, err
+	}
+	c.publish(ctx, key)
+	return true, c.l1.Set(ctx, key, value, c.capL1TTL(ttl))
+}
+
+// CompareAndSwap swaps key in L2, shared by every instance. A failed swap
+// means the L1 copy may be stale, so it is removed.
+func (c *tieredCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.l2, key, old, new, ttl)
+	if err != nil || !swapped {
+		_ = c.l1.Delete(ctx, key)
+		return false, err
+	}
+	c.publish(ctx, key)
+	return true, c.l1.Set(ctx, key, new, c.capL1TTL(ttl))
+}
+
+// GetDel consumes the value from L2, which is shared between instances, so
+// an L1 copy can't hand out the value twice.
+func (c *tieredCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	value, found, err := GetDel(ctx, c.l2, key)
+	if err != nil {
+		return value, false, err
+	}
+	_ = deleteMulti(ctx, c.l1, []string{key})
+	c.publish(ctx, key)
+	return value, found, nil
+}
+
+// Set writes both tiers. WithNoStore only keeps the tiers from storing values
+// on the caller's behalf, so an explicit Set still writes, rather than leave
+// the previous value behind; a positive WithMaxAge caps ttl.
+func (c *tieredCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if capped, store := directiveTTL(ctx, ttl); store {
+		ttl = capped
+	}
+
+	if err := c.l2.Set(ctx, key, value, ttl); err != nil {
+		// Don't let L1 serve a value L2 never accepted
+		_ = c.l1.Delete(ctx, key)
+		return err
+	}
+	c.publish(ctx, key)
+	return c.l1.Set(ctx, key, value, c.capL1TTL(ttl))
+}
+
+func (c *tieredCache[T]) Delete(ctx context.Context, key string) error {
+	err := errors.Join(
+		c.l2.Delete(ctx, key),
+		// L1 often doesn't hold the key; DeleteMulti ignores missing keys
+		deleteMulti(ctx, c.l1, []string{key}),
+	)
+	c.publish(ctx, key)
+	return err
+}
+
+func (c *tieredCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	err := errors.Join(
+		deleteMulti(ctx, c.l2, keys),
+		deleteMulti(ctx, c.l1, keys),
+	)
+	c.publish(ctx, keys...)
+	return err
+}
+
+// Clear clears L2, then L1 so it can't be refilled from L2 meanwhile, and has
+// other instances clear their L1.
+func (c *tieredCache[T]) Clear(ctx context.Context) error {
+	err := errors.Join(Clear(ctx, c.l2), Clear(ctx, c.l1))
+	if c.bus != nil {
+		c.bus.PublishAll(ctx)
+	}
+	return err
+}
+
+func (c *tieredCache[T]) Close() error {
+	var busErr error
+	if c.bus != nil {
+		busErr = c.bus.Close()
+	}
+	return errors.Join(busErr, c.l1.Close(), c.l2.Close())
+}
+
+// unwrap exposes L2 so helpers needing the Redis client keep working.
+func (c *tieredCache[T]) unwrap() Cache[T] {
+	return c.l2
+}
+
+func (c *tieredCache[T]) Ping(ctx context.Context) error {
+	return errors.Join(pingNext(ctx, c.l1), pingNext(ctx, c.l2))
+}
+
+// maybeRepair verifies a sample of L1 hits against L2 in the background.
+func (c *tieredCache[T]) maybeRepair(ctx context.Context, key string, l1Value T) {
+	if c.repair == nil || rand.Float64() >= c.repair.config.SampleRate {
+		return
+	}
+
+	// The verification outlives the caller, who was already answered
+	repairCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.repair.config.Timeout)
+	go func() {
+		defer cancel()
+		c.verify(repairCtx, key, l1Value)
+	}()
+}
+
+// verify compares the L1 value of key with L2 and repairs the tier that is
+// not authoritative. Repairs are best effort - the next sample tries again.
+func (c *tieredCache[T]) verify(ctx context.Context, key string, l1Value T) {
+	l2Value, found, err := GetWithError(ctx, c.l2, key)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case !found:
+		c.repair.recordDivergence(ctx, "missing-l2")
+		if c.repair.config.Authoritative == "l1" {
+			_ = c.l2.Set(ctx, key, l1Value, c.l1TTL)
+		} else {
+			_ = deleteMulti(ctx, c.l1, []string{key})
+			traceKey(KeyEvent{Key: key, Operation: OperationDelete, Source: "tiered", Reason: "repair"})
+		}
+	case !valuesEqual(l1Value, l2Value):
+		c.repair.recordDivergence(ctx, "value")
+		if c.repair.config.Authoritative == "l1" {
+			_ = c.l2.Set(ctx, key, l1Value, c.l1TTL)
+		} else {
+			_ = c.l1.Set(ctx, key, l2Value, c.l1TTL)
+			traceKey(KeyEvent{Key: key, Operation: OperationSet, Source: "tiered", TTL: c.l1TTL, Reason: "repair"})
+		}
+	}
+}
+
+// TieredStatsOf returns the in-flight statistics of the tiered cache behind
+// cache, looking through decorators. Returns ErrNotTiered for other caches.
+func TieredStatsOf[T any](cache Cache[T]) (TieredStats, error) {
+	for cache != nil {
+		if tc, ok := cache.(*tieredCache[T]); ok {
+			return TieredStats{
+				Inflight:      tc.inflight.Load(),
+				Shared:        tc.shared.Load(),
+				Invalidations: tc.invalidations.Load(),
+			}, nil
+		}
+		w, ok := cache.(wrapper[T])
+		if !ok {
+			break
+		}
+		cache = w.unwrap()
+	}
+	return TieredStats{}, ErrNotTiered
+}
+
+// capL1TTL bounds ttl to the L1 TTL; 0 (no expiry) is capped as well.
+func (c *tieredCache[T]) capL1TTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.l1TTL {
+		return c.l1TTL
+	}
+	return ttl
+}
+
+// readRepair holds the read-repair settings and divergence counter of a tiered cache.
+type readRepair struct {
+	config      ReadRepairConfig
+	divergences metric.Int64Counter
+}
+
+func newReadRepair(config *ReadRepairConfig) *readRepair {
+	cfg := *config
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 0.01
+	}
+	if cfg.Authoritative == "" {
+		cfg.Authoritative = "l2"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	provider := cfg.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	divergences, _ := provider.Meter(instrumentationName).Int64Counter("cache.tiered.divergences",
+		metric.WithDescription("Number of sampled L1 hits that disagreed with L2"))
+
+	return &readRepair{config: cfg, divergences: divergences}
+}
+
+func (r *readRepair) recordDivergence(ctx context.Context, kind string) {
+	if r.divergences == nil {
+		return
+	}
+	r.divergences.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("divergence", kind),
+		attribute.String("authoritative", r.config.Authoritative),
+	))
+}
@@ -0,0 +1,301 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTieredCache(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	// Test Set writes both tiers, capping the L1 TTL
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, found := l2.Get(ctx, "key1"); !found {
+		t.Error("Expected key1 in L2")
+	}
+	mc, err := memoryCacheOf(cache)
+	if err != nil {
+		t.Fatalf("memoryCacheOf failed: %v", err)
+	}
+	entries := mc.hottest(1)
+	if len(entries) != 1 || entries[0].ttl > time.Minute {
+		t.Errorf("Expected L1 entry with TTL capped at 1m, got: %+v", entries)
+	}
+
+	// Test L2 hits are promoted into L1
+	_ = l2.Set(ctx, "key2", TestUser{ID: "456"}, time.Hour)
+	if user, found := cache.Get(ctx, "key2"); !found || user.ID != "456" {
+		t.Fatalf("Expected key2 from L2, got %v, %v", user, found)
+	}
+	if _, found := l1.Get(ctx, "key2"); !found {
+		t.Error("Expected key2 to be promoted into L1")
+	}
+
+	// Test L1 serves without reaching L2
+	_ = l2.Delete(ctx, "key2")
+	if _, found := cache.Get(ctx, "key2"); !found {
+		t.Error("Expected key2 to be served from L1")
+	}
+
+	// Test Delete removes from both tiers, even when L1 lacks the key
+	_ = l1.Delete(ctx, "key1")
+	if err := cache.Delete(ctx, "key1"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, found := cache.Get(ctx, "key1"); found {
+		t.Error("Expected key1 to be deleted")
+	}
+}
+
+func TestTieredCachePromotionKeepsL2TTL(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{L1TTL: time.Minute})
+	defer cache.Close()
+
+	_ = l2.Set(ctx, "key1", TestUser{ID: "1"}, 100*time.Millisecond)
+	if _, found := cache.Get(ctx, "key1"); !found {
+		t.Fatal("Expected key1 from L2")
+	}
+	ttl, found, err := TTL(ctx, l1, "key1")
+	if err != nil || !found {
+		t.Fatalf("Expected key1 to be promoted, got %v, %v", found, err)
+	}
+	if ttl > 100*time.Millisecond {
+		t.Errorf("Expected the L1 copy to expire with L2, got %v", ttl)
+	}
+}
+
+func TestTieredCacheSetFailure(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, nil)
+
+	_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Hour)
+
+	// Test a failed L2 write drops the L1 copy
+	_ = l2.Close()
+	if err := cache.Set(ctx, "key1", TestUser{ID: "456"}, time.Hour); err == nil {
+		t.Fatal("Expected Set to fail when L2 is closed")
+	}
+	if _, found := l1.Get(ctx, "key1"); found {
+		t.Error("Expected L1 copy to be dropped after a failed L2 write")
+	}
+	_ = l1.Close()
+}
+
+// gatedCache blocks Get until gate is closed and counts calls.
+type gatedCache[T any] struct {
+	Cache[T]
+	gate  chan struct{}
+	reads atomic.Int32
+}
+
+func (c *gatedCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	c.reads.Add(1)
+	<-c.gate
+	return c.Cache.Get(ctx, key)
+}
+
+func TestTieredCacheSharesL2Reads(t *testing.T) {
+	ctx := context.Background()
+	l2 := &gatedCache[TestUser]{Cache: NewMemory[TestUser](nil), gate: make(chan struct{})}
+	cache := NewTiered(NewMemory[TestUser](nil), l2, nil)
+	defer cache.Close()
+	_ = l2.Cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if user, found := cache.Get(ctx, "key1"); !found || user.ID != "123" {
+				t.Errorf("Expected key1, got %v, %v", user, found)
+			}
+		}()
+	}
+
+	waitFor(t, func() bool {
+		stats, _ := TieredStatsOf(cache)
+		return stats.Inflight == 1
+	})
+	time.Sleep(20 * time.Millisecond) // let the other Gets join
+	close(l2.gate)
+	wg.Wait()
+
+	if reads := l2.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 L2 read, got %d", reads)
+	}
+	if stats, _ := TieredStatsOf(cache); stats.Inflight != 0 || stats.Shared == 0 {
+		t.Errorf("Unexpected stats after reads: %+v", stats)
+	}
+}
+
+func TestTieredCacheGetJoinsLoad(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTiered(NewMemory[TestUser](nil), NewMemory[TestUser](nil), nil)
+	defer cache.Close()
+
+	release := make(chan struct{})
+	var loads atomic.Int32
+	load := func(context.Context) (TestUser, error) {
+		loads.Add(1)
+		<-release
+		return TestUser{ID: "loaded"}, nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if user, err := GetOrSet(ctx, cache, "key1", time.Hour, load); err != nil || user.ID != "loaded" {
+			t.Errorf("Expected loaded value, got %v, %v", user, err)
+		}
+	}()
+	waitFor(t, func() bool { return loads.Load() == 1 })
+
+	// A plain Get during the load waits for it instead of missing
+	got := make(chan TestUser, 1)
+	go func() {
+		user, _ := cache.Get(ctx, "key1")
+		got <- user
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	<-done
+
+	if user := <-got; user.ID != "loaded" {
+		t.Errorf("Expected Get to return the loaded value, got %v", user)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("Expected 1 load, got %d", n)
+	}
+}
+
+func TestTieredCacheReadRepair(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{
+		ReadRepair: &ReadRepairConfig{SampleRate: 1, MeterProvider: provider},
+	})
+	defer cache.Close()
+
+	// Test a stale L1 copy is replaced by the L2 value
+	_ = l1.Set(ctx, "key1", TestUser{ID: "stale"}, time.Hour)
+	_ = l2.Set(ctx, "key1", TestUser{ID: "fresh"}, time.Hour)
+	if user, _ := cache.Get(ctx, "key1"); user.ID != "stale" {
+		t.Errorf("Expected the L1 copy to be served, got %v", user)
+	}
+	waitFor(t, func() bool {
+		user, _ := l1.Get(ctx, "key1")
+		return user.ID == "fresh"
+	})
+
+	// Test an L1 copy missing from L2 is dropped
+	_ = l1.Set(ctx, "key2", TestUser{ID: "orphan"}, time.Hour)
+	cache.Get(ctx, "key2")
+	waitFor(t, func() bool {
+		_, found := l1.Get(ctx, "key2")
+		return !found
+	})
+
+	// Test matching tiers are left alone and not counted
+	_ = cache.Set(ctx, "key3", TestUser{ID: "same"}, time.Hour)
+	cache.Get(ctx, "key3")
+
+	var rm metricdata.ResourceMetrics
+	waitFor(t, func() bool {
+		if err := reader.Collect(ctx, &rm); err != nil {
+			t.Fatalf("Collect failed: %v", err)
+		}
+		return divergenceCount(rm) == 2
+	})
+}
+
+func TestTieredCacheReadRepairL1Authoritative(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, &TieredConfig{
+		ReadRepair: &ReadRepairConfig{SampleRate: 1, Authoritative: "l1"},
+	})
+	defer cache.Close()
+
+	// Test the L1 value is written back to L2
+	_ = l1.Set(ctx, "key1", TestUser{ID: "local"}, time.Hour)
+	_ = l2.Set(ctx, "key1", TestUser{ID: "remote"}, time.Hour)
+	cache.Get(ctx, "key1")
+	waitFor(t, func() bool {
+		user, _ := l2.Get(ctx, "key1")
+		return user.ID == "local"
+	})
+}
+
+// divergenceCount sums the "cache.tiered.divergences" data points.
+func divergenceCount(rm metricdata.ResourceMetrics) int64 {
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "cache.tiered.divergences" {
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+func TestTieredStatsOfNonTiered(t *testing.T) {
+	if _, err := TieredStatsOf(NewMemory[TestUser](nil)); !errors.Is(err, ErrNotTiered) {
+		t.Errorf("Expected ErrNotTiered, got: %v", err)
+	}
+}
+
+func TestFactoryTieredRequiresDistributedConfig(t *testing.T) {
+	_, err := New[TestUser](&Config{Type: TypeTiered})
+	if err == nil {
+		t.Error("Expected error without distributed config")
+	}
+}
+
+func TestTieredCacheWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	cache, err := New[TestUser](&Config{
+		Type:        TypeTiered,
+		Distributed: &DistributedConfig{Addr: addr},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create tiered cache: %v", err)
+	}
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, err := redisClientOf(cache); errors.Is(err, ErrNotDistributed) {
+		t.Error("Expected the Redis client to be reachable through the tiered cache")
+	}
+	if user, found := cache.Get(ctx, "key1"); !found || user.ID != "123" {
+		t.Errorf("Expected key1, got %v, %v", user, found)
+	}
+}
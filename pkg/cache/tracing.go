@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Decision describes how the cache answered a read.
+type Decision string
+
+const (
+	// DecisionHit means the value was served from the cache.
+	DecisionHit Decision = "hit"
+	// DecisionMiss means the value was not cached.
+	DecisionMiss Decision = "miss"
+	// DecisionStale means a stale copy was served because the backend failed.
+	DecisionStale Decision = "stale"
+	// DecisionBypass means the read skipped the backend, e.g. a rejected key.
+	DecisionBypass Decision = "bypass"
+	// DecisionRefresh means the value was served while a refresh was triggered.
+	DecisionRefresh Decision = "refresh"
+	// DecisionError means the read failed.
+	DecisionError Decision = "error"
+	// DecisionTimeout means the read exceeded its latency budget and was
+	// answered as a miss.
+	DecisionTimeout Decision = "timeout"
+)
+
+// decisionEventName is the span event added for every traced read.
+const decisionEventName = "cache.decision"
+
+// decisionNote collects what inner layers learned about one read.
+// Layers may read concurrently (e.g. racing caches), hence the mutex.
+type decisionNote struct {
+	mu       sync.Mutex
+	decision Decision
+	tier     string
+}
+
+type decisionNoteKey struct{}
+
+// noteDecision lets an inner layer override the decision of the traced read in ctx.
+func noteDecision(ctx context.Context, decision Decision) {
+	if n, ok := ctx.Value(decisionNoteKey{}).(*decisionNote); ok {
+		n.mu.Lock()
+		n.decision = decision
+		n.mu.Unlock()
+	}
+}
+
+// noteTier records which tier answered the traced read in ctx.
+func noteTier(ctx context.Context, tier string) {
+	if n, ok := ctx.Value(decisionNoteKey{}).(*decisionNote); ok {
+		n.mu.Lock()
+		n.tier = tier
+		n.mu.Unlock()
+	}
+}
+
+// tracingCache annotates the caller's span with the decision of every read.
+type tracingCache[T any] struct {
+	next Cache[T]
+	name string
+	tier string
+}
+
+// WithDecisionTracing wraps a cache so that every read adds a "cache.decision"
+// event to the span in the caller's context, with the decision (hit, miss,
+// stale, bypass, refresh, timeout or error), the tier that answered and the cache name.
+// Latency investigations then show whether the cache helped on that request.
+// No spans are created; reads without a recording span are not annotated.
+//
+// tier names the tier reported when no inner layer (e.g. a tiered cache)
+// reports a more specific one, typically the cache type.
+func WithDecisionTracing[T any](cache Cache[T], name, tier string) Cache[T] {
+	return &tracingCache[T]{
+		next: cache,
+		name: name,
+		tier: tier,
+	}
+}
+
+// begin returns ctx carrying a fresh decision note if the caller's span is recording.
+func (c *tracingCache[T]) begin(ctx context.Context) (context.Context, *decisionNote) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx, nil
+	}
+	note := &decisionNote{tier: c.tier}
+	return context.WithValue(ctx, decisionNoteKey{}, note), note
+}
+
+// end adds the decision event; inner notes take precedence over decision.
+func (c *tracingCache[T]) end(ctx context.Context, note *decisionNote, decision Decision) {
+	if note == nil {
+		return
+	}
+
+	note.mu.Lock()
+	if note.decision != "" {
+		decision = note.decision
+	}
+	tier := note.tier
+	note.mu.Unlock()
+
+	trace.SpanFromContext(ctx).AddEvent(decisionEventName, trace.WithAttributes(
+		attribute.String("cache.decision", string(decision)),
+		attribute.String("cache.tier", tier),
+		attribute.String("cache.name", c.name),
+	))
+}
+
+func (c *tracingCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	tctx, note := c.begin(ctx)
+	value, found := c.next.Get(tctx, key)
+	c.end(ctx, note, decisionOf(found, nil))
+	return value, found
+}
+
+func (c *tracingCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	tctx, note := c.begin(ctx)
+	value, found, err := GetWithError(tctx, c.next, key)
+	c.end(ctx, note, decisionOf(found, err))
+	return value, found, err
+}
+
+// GetMulti adds one decision event for the batch: an error if any key
+// failed, a hit if every key was found, and a miss otherwise.
+func (c *tracingCache[T]) GetMulti(ctx context.Context, keys ...string) []KeyResult[T] {
+	tctx, note := c.begin(ctx)
+	results := GetMulti(tctx, c.next, keys...)
+	found := true
+	var err error
+	for _, r := range results {
+		found = found && r.Found
+		if r.Err != nil {
+			err = r.Err
+		}
+	}
+	c.end(ctx, note, decisionOf(found, err))
+	return results
+}
+
+func (c *tracingCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	tctx, note := c.begin(ctx)
+	loaded := false
+	value, err := GetOrSet(tctx, c.next, key, ttl, func(ctx context.Context) (T, error) {
+		loaded = true
+		return load(ctx)
+	})
+	c.end(ctx, note, decisionOf(!loaded && err == nil, err))
+	return value, err
+}
+
+func (c *tracingCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *tracingCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *tracingCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *tracingCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	return SetIfAbsent(ctx, c.next, key, value, ttl)
+}
+
+func (c *tracingCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	return CompareAndSwap(ctx, c.next, key, old, new, ttl)
+}
+
+func (c *tracingCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	tctx, note := c.begin(ctx)
+	value, found, err := GetDel(tctx, c.next, key)
+	c.end(ctx, note, decisionOf(found, err))
+	return value, found, err
+}
+
+func (c *tracingCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.next.Set(ctx, key, value, ttl)
+}
+
+func (c *tracingCache[T]) Delete(ctx context.Context, key string) error {
+	return c.next.Delete(ctx, key)
+}
+
+func (c *tracingCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	return deleteMulti(ctx, c.next, keys)
+}
+
+func (c *tracingCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *tracingCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *tracingCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *tracingCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+func decisionOf(found bool, err error) Decision {
+	switch {
+	case err != nil:
+		return DecisionError
+	case found:
+		return DecisionHit
+	default:
+		return DecisionMiss
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// decisionEvents runs fn inside a recorded span and returns the decision and
+// tier of every cache.decision event added to it.
+func decisionEvents(t *testing.T, fn func(ctx context.Context)) [][2]string {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	fn(ctx)
+	span.End()
+
+	var events [][2]string
+	for _, event := range recorder.Ended()[0].Events() {
+		if event.Name != decisionEventName {
+			continue
+		}
+		attrs := attribute.NewSet(event.Attributes...)
+		decision, _ := attrs.Value("cache.decision")
+		tier, _ := attrs.Value("cache.tier")
+		events = append(events, [2]string{decision.AsString(), tier.AsString()})
+	}
+	return events
+}
+
+func TestDecisionTracing(t *testing.T) {
+	cache, err := New[TestUser](&Config{Type: TypeMemory, Name: "users", TraceDecisions: true})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	events := decisionEvents(t, func(ctx context.Context) {
+		_ = cache.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+		cache.Get(ctx, "key1")
+		cache.Get(ctx, "missing")
+		_, _ = GetOrSet(ctx, cache, "key2", time.Minute, func(context.Context) (TestUser, error) {
+			return TestUser{}, errors.New("origin down")
+		})
+	})
+
+	want := [][2]string{{"hit", "memory"}, {"miss", "memory"}, {"error", "memory"}}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("Event %d: expected %v, got %v", i, want[i], events[i])
+		}
+	}
+}
+
+func TestDecisionTracingReportsInnerLayers(t *testing.T) {
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := WithDecisionTracing(WithMaxKeyLength(NewTiered(l1, l2, nil), 8, KeyLengthReject), "users", "tiered")
+	defer cache.Close()
+
+	ctx := context.Background()
+	_ = l2.Set(ctx, "key1", TestUser{ID: "123"}, time.Minute)
+
+	events := decisionEvents(t, func(ctx context.Context) {
+		cache.Get(ctx, "key1")              // promoted from L2
+		cache.Get(ctx, "key1")              // served from L1
+		cache.Get(ctx, "much-too-long-key") // rejected before reaching a tier
+	})
+
+	want := [][2]string{{"hit", "l2"}, {"hit", "l1"}, {"bypass", "tiered"}}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("Event %d: expected %v, got %v", i, want[i], events[i])
+		}
+	}
+}
+
+func TestDecisionTracingWithoutSpan(t *testing.T) {
+	cache := WithDecisionTracing(NewMemory[TestUser](nil), "users", "memory")
+	defer cache.Close()
+
+	// Test reads without a recording span work and don't panic
+	if _, found := cache.Get(context.Background(), "key1"); found {
+		t.Error("Expected miss")
+	}
+}
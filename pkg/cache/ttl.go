@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// UntilEndOfDay returns the TTL until the next midnight in loc (time.Local when
+// nil), for entries that must not outlive the day they were computed for.
+// Daylight saving transitions are taken into account.
+func UntilEndOfDay(loc *time.Location) time.Duration {
+	return untilEndOfDay(time.Now(), loc)
+}
+
+func untilEndOfDay(now time.Time, loc *time.Location) time.Duration {
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+	return midnight.Sub(now)
+}
+
+// JitteredTTL returns base randomly spread by up to pct percent either way,
+// e.g. JitteredTTL(time.Hour, 10) is between 54 and 66 minutes, so entries
+// written together don't expire together and stampede the origin.
+func JitteredTTL(base time.Duration, pct float64) time.Duration {
+	if base <= 0 || pct <= 0 {
+		return base
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	spread := float64(base) * pct / 100
+	jittered := time.Duration(float64(base) + (rand.Float64()*2-1)*spread)
+	if jittered <= 0 {
+		// Zero means no expiry in most backends; expire as soon as possible instead
+		return time.Nanosecond
+	}
+	return jittered
+}
+
+// AlignedTTL returns a TTL of at least d that expires on the next multiple of
+// boundary (since the Unix epoch, so in UTC), e.g. AlignedTTL(time.Minute,
+// 5*time.Minute) expires on the next 5-minute mark at least a minute away.
+// Entries refreshed on a schedule then expire together across instances.
+func AlignedTTL(d, boundary time.Duration) time.Duration {
+	return alignedTTL(time.Now(), d, boundary)
+}
+
+func alignedTTL(now time.Time, d, boundary time.Duration) time.Duration {
+	if boundary <= 0 {
+		return d
+	}
+	earliest := now.Add(d).UnixNano()
+	expiry := earliest - earliest%int64(boundary)
+	if expiry < earliest {
+		expiry += int64(boundary)
+	}
+	return time.Duration(expiry - now.UnixNano())
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUntilEndOfDay(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+
+	now := time.Date(2026, 3, 10, 22, 30, 0, 0, loc)
+	if ttl := untilEndOfDay(now, loc); ttl != 90*time.Minute {
+		t.Errorf("Expected 1h30m until midnight, got %v", ttl)
+	}
+
+	// Test the day before the spring-forward transition is an hour shorter
+	now = time.Date(2026, 3, 29, 0, 0, 0, 0, loc)
+	if ttl := untilEndOfDay(now, loc); ttl != 23*time.Hour {
+		t.Errorf("Expected 23h on the day clocks spring forward, got %v", ttl)
+	}
+}
+
+func TestJitteredTTL(t *testing.T) {
+	for range 1000 {
+		ttl := JitteredTTL(time.Hour, 10)
+		if ttl < 54*time.Minute || ttl > 66*time.Minute {
+			t.Fatalf("Expected TTL within 10%% of 1h, got %v", ttl)
+		}
+	}
+
+	if ttl := JitteredTTL(time.Hour, 0); ttl != time.Hour {
+		t.Errorf("Expected no jitter at 0%%, got %v", ttl)
+	}
+	if ttl := JitteredTTL(0, 10); ttl != 0 {
+		t.Errorf("Expected a zero TTL to stay zero, got %v", ttl)
+	}
+	for range 100 {
+		if ttl := JitteredTTL(time.Second, 100); ttl <= 0 {
+			t.Fatalf("Expected a positive TTL, got %v", ttl)
+		}
+	}
+}
+
+func TestAlignedTTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 3, 30, 0, time.UTC)
+
+	if ttl := alignedTTL(now, time.Minute, 5*time.Minute); ttl != 90*time.Second {
+		t.Errorf("Expected expiry at 12:05, got %v", ttl)
+	}
+
+	// Test a minimum TTL past the next boundary moves to the one after
+	if ttl := alignedTTL(now, 2*time.Minute, 5*time.Minute); ttl != 390*time.Second {
+		t.Errorf("Expected expiry at 12:10, got %v", ttl)
+	}
+
+	// Test an expiry already on a boundary is kept
+	if ttl := alignedTTL(now, 90*time.Second, 5*time.Minute); ttl != 90*time.Second {
+		t.Errorf("Expected expiry at 12:05, got %v", ttl)
+	}
+
+	if ttl := alignedTTL(now, time.Minute, 0); ttl != time.Minute {
+		t.Errorf("Expected no alignment without a boundary, got %v", ttl)
+	}
+}
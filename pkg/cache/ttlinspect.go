@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTTLNotSupported is returned by TTL for caches that can't report the
+// lifetime left to their entries.
+var ErrTTLNotSupported = errors.New("cache does not report remaining TTLs")
+
+// TTL reports how long the entry under key has left before it expires, e.g.
+// to refresh entries close to expiry or to find out why one expired earlier
+// than expected. A remaining TTL of 0 means the entry doesn't expire; found
+// is false when the key isn't stored. Returns ErrTTLNotSupported for caches
+// that don't implement TTLInspector.
+//
+// Like Get, TTL may extend the TTL of memory cache entries when
+// MemoryConfig.SkipTTLExtensionOnHit is false, and reports the extended one.
+func TTL[T any](ctx context.Context, cache Cache[T], key string) (time.Duration, bool, error) {
+	if ti, ok := cache.(TTLInspector); ok {
+		return ti.TTL(ctx, key)
+	}
+	return 0, false, ErrTTLNotSupported
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTTLMemory(t *testing.T) {
+	ctx := context.Background()
+
+	for _, engine := range []MemoryEngine{MemoryEngineTTLCache, MemoryEngineRistretto, MemoryEngineFreeCache} {
+		t.Run(string(engine), func(t *testing.T) {
+			cache := NewMemory[TestUser](&MemoryConfig{Engine: engine})
+			defer cache.Close()
+
+			_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+			_ = cache.Set(ctx, "forever", TestUser{ID: "f"}, 0)
+			_ = flushAll(ctx, cache)
+
+			// FreeCache has second precision
+			if ttl, found, err := TTL(ctx, cache, "a"); err != nil || !found || ttl < 58*time.Second || ttl > time.Minute {
+				t.Errorf("Expected about a minute left for a, got %v, %v, %v", ttl, found, err)
+			}
+			if ttl, found, err := TTL(ctx, cache, "forever"); err != nil || !found || ttl != 0 {
+				t.Errorf("Expected forever not to expire, got %v, %v, %v", ttl, found, err)
+			}
+			if _, found, err := TTL(ctx, cache, "missing"); err != nil || found {
+				t.Errorf("Expected missing not to be found, got %v, %v", found, err)
+			}
+		})
+	}
+}
+
+func TestTTLMemoryReportsRemainingLifetime(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](nil)
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Second)
+	time.Sleep(200 * time.Millisecond)
+
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl > 900*time.Millisecond {
+		t.Errorf("Expected less than 900ms left, got %v", ttl)
+	}
+}
+
+func TestTTLMemoryExtendedOnHit(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemory[TestUser](&MemoryConfig{SkipTTLExtensionOnHit: false})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Second)
+	time.Sleep(200 * time.Millisecond)
+	_, _ = cache.Get(ctx, "a")
+
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl < 900*time.Millisecond {
+		t.Errorf("Expected the hit to extend the TTL, got %v", ttl)
+	}
+}
+
+func TestTTLThroughDecorators(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[*TestUser](nil)
+	defer backend.Close()
+
+	cache := WithNilValuePolicy(WithPrefix(backend, "p:"), NilValueMiss)
+	_ = cache.Set(ctx, "a", &TestUser{ID: "a"}, time.Minute)
+	_ = backend.Set(ctx, "p:nil", nil, time.Minute)
+
+	if ttl, found, _ := TTL(ctx, cache, "a"); !found || ttl <= 0 {
+		t.Errorf("Expected a TTL for a under the prefix, got %v, %v", ttl, found)
+	}
+	if _, found, _ := TTL(ctx, cache, "nil"); found {
+		t.Error("Expected a stored nil not to be found under NilValueMiss")
+	}
+}
+
+func TestTTLNotSupported(t *testing.T) {
+	ctx := context.Background()
+	backend := &unclearableCache[TestUser]{Cache: NewMemory[TestUser](nil)}
+	defer backend.Close()
+
+	if _, _, err := TTL[TestUser](ctx, backend, "a"); !errors.Is(err, ErrTTLNotSupported) {
+		t.Errorf("Expected ErrTTLNotSupported, got %v", err)
+	}
+}
+
+func TestTTLTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemory[TestUser](nil)
+	l2 := NewMemory[TestUser](nil)
+	cache := NewTiered(l1, l2, nil)
+	defer cache.Close()
+
+	_ = l1.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = l2.Set(ctx, "a", TestUser{ID: "a"}, time.Hour)
+	_ = l1.Set(ctx, "l1only", TestUser{ID: "b"}, time.Minute)
+
+	if ttl, _, _ := TTL(ctx, cache, "a"); ttl <= time.Minute {
+		t.Errorf("Expected the L2 lifetime, got %v", ttl)
+	}
+	if ttl, found, _ := TTL(ctx, cache, "l1only"); !found || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected the L1 lifetime, got %v, %v", ttl, found)
+	}
+}
+
+func TestTTLDisk(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestDiskCache(t, &DiskConfig{})
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "forever", TestUser{ID: "f"}, 0)
+	_ = cache.Set(ctx, "short", TestUser{ID: "s"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if ttl, found, err := TTL(ctx, cache, "a"); err != nil || !found || ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("Expected about a minute left for a, got %v, %v, %v", ttl, found, err)
+	}
+	if ttl, found, _ := TTL(ctx, cache, "forever"); !found || ttl != 0 {
+		t.Errorf("Expected forever not to expire, got %v, %v", ttl, found)
+	}
+	if _, found, _ := TTL(ctx, cache, "short"); found {
+		t.Error("Expected an expired entry not to be found")
+	}
+}
+
+func TestTTLDistributedWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "a", TestUser{ID: "a"}, time.Minute)
+	_ = cache.Set(ctx, "forever", TestUser{ID: "f"}, 0)
+	if ttl, found, err := TTL(ctx, cache, "a"); err != nil || !found || ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("Expected about a minute left for a, got %v, %v, %v", ttl, found, err)
+	}
+	if ttl, found, err := TTL(ctx, cache, "forever"); err != nil || !found || ttl != 0 {
+		t.Errorf("Expected forever not to expire, got %v, %v, %v", ttl, found, err)
+	}
+	if _, found, err := TTL(ctx, cache, "missing"); err != nil || found {
+		t.Errorf("Expected missing not to be found, got %v, %v", found, err)
+	}
+}
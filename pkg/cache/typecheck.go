@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrTypeMismatch is returned when a stored value was written as a different
+// type or schema than the reader expects.
+var ErrTypeMismatch = errors.New("cached value type mismatch")
+
+// Typed payloads start with typedEnvelopeMagic and a version byte, followed by
+// the writer's 8-byte schema hash, its length-prefixed type name and the
+// inner serializer's output. The magic starts with a zero byte, which JSON,
+// gob and zstd payloads never do.
+var typedEnvelopeMagic = []byte{0, 'c', 't'}
+
+const typedEnvelopeVersion byte = 1
+
+// TypeCheckConfig holds configuration for a type-checking serializer.
+type TypeCheckConfig struct {
+	// Name identifies the cache in metrics (optional, e.g. "users")
+	Name string
+
+	// MeterProvider receives the mismatch counter
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+
+	// DecodeOnSchemaMismatch still decodes values written with the same type
+	// but a different schema hash, e.g. after adding a field that older
+	// readers can ignore. Mismatches are counted either way (default: false)
+	DecodeOnSchemaMismatch bool
+}
+
+// TypeCheckingSerializer records the writer's type identity (package.Type and
+// a schema hash) next to each payload, and counts and rejects payloads
+// written as another type, so mixed-version fleets see incompatible cache
+// payloads in the "cache.serializer.type_mismatches" metric instead of
+// silently missing. Mismatches fail Deserialize with ErrTypeMismatch, which
+// reads treat according to DegradedPolicy.OnSerializerError.
+//
+// Payloads written without an envelope (e.g. before the serializer was
+// introduced) are passed to the inner serializer unchecked.
+type TypeCheckingSerializer struct {
+	inner                  Serializer
+	name                   string
+	mismatches             metric.Int64Counter
+	decodeOnSchemaMismatch bool
+}
+
+// NewTypeCheckingSerializer creates a serializer that wraps the output of
+// inner in an envelope recording the writer's type identity.
+func NewTypeCheckingSerializer(inner Serializer, config *TypeCheckConfig) (*TypeCheckingSerializer, error) {
+	if inner == nil {
+		return nil, errors.New("inner serializer cannot be nil")
+	}
+
+	var cfg TypeCheckConfig
+	if config != nil {
+		cfg = *config
+	}
+	provider := cfg.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	mismatches, _ := provider.Meter(instrumentationName).Int64Counter("cache.serializer.type_mismatches",
+		metric.WithDescription("Number of cached values written as a different type or schema than read"))
+
+	return &TypeCheckingSerializer{
+		inner:                  inner,
+		name:                   cfg.Name,
+		mismatches:             mismatches,
+		decodeOnSchemaMismatch: cfg.DecodeOnSchemaMismatch,
+	}, nil
+}
+
+// Serialize converts a value to bytes with the inner serializer, prefixed by
+// the value's type identity.
+func (s *TypeCheckingSerializer) Serialize(v interface{}) ([]byte, error) {
+	data, err := s.inner.Serialize(v)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := typeIdentityOf(reflect.TypeOf(v))
+	out := make([]byte, 0, len(typedEnvelopeMagic)+1+8+binary.MaxVarintLen64+len(identity.name)+len(data))
+	out = append(out, typedEnvelopeMagic...)
+	out = append(out, typedEnvelopeVersion)
+	out = binary.BigEndian.AppendUint64(out, identity.schema)
+	out = binary.AppendUvarint(out, uint64(len(identity.name)))
+	out = append(out, identity.name...)
+	return append(out, data...), nil
+}
+
+// Deserialize checks the writer's type identity against v and converts the
+// payload back to a value with the inner serializer.
+func (s *TypeCheckingSerializer) Deserialize(data []byte, v interface{}) error {
+	if !bytes.HasPrefix(data, typedEnvelopeMagic) {
+		return s.inner.Deserialize(data, v)
+	}
+
+	written, payload, err := parseTypedEnvelope(data[len(typedEnvelopeMagic):])
+	if err != nil {
+		return err
+	}
+
+	read := typeIdentityOf(reflect.TypeOf(v))
+	switch {
+	case written.name != read.name:
+		s.recordMismatch("type", written, read)
+		return fmt.Errorf("%w: written as %s, read as %s", ErrTypeMismatch, written.name, read.name)
+	case written.schema != read.schema:
+		s.recordMismatch("schema", written, read)
+		if !s.decodeOnSchemaMismatch {
+			return fmt.Errorf("%w: %s written with schema %016x, read with %016x",
+				ErrTypeMismatch, read.name, written.schema, read.schema)
+		}
+	}
+	return s.inner.Deserialize(payload, v)
+}
+
+func (s *TypeCheckingSerializer) recordMismatch(kind string, written, read typeIdentity) {
+	if s.mismatches == nil {
+		return
+	}
+	s.mismatches.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("cache.name", s.name),
+		attribute.String("mismatch", kind),
+		attribute.String("writer.type", written.name),
+		attribute.String("reader.type", read.name),
+	))
+}
+
+func parseTypedEnvelope(data []byte) (typeIdentity, []byte, error) {
+	if len(data) < 1+8 || data[0] != typedEnvelopeVersion {
+		return typeIdentity{}, nil, errors.New("unsupported typed envelope")
+	}
+	schema := binary.BigEndian.Uint64(data[1:9])
+	data = data[9:]
+
+	nameLen, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < nameLen {
+		return typeIdentity{}, nil, errors.New("truncated typed envelope")
+	}
+	name := string(data[n : n+int(nameLen)])
+	return typeIdentity{name: name, schema: schema}, data[n+int(nameLen):], nil
+}
+
+// typeIdentity names a type and hashes its shape, so readers can tell a
+// different type from a different version of the same type.
+type typeIdentity struct {
+	name   string
+	schema uint64
+}
+
+var typeIdentities sync.Map // reflect.Type -> typeIdentity
+
+// typeIdentityOf returns the identity of t, looking through pointers so a
+// value and a pointer to it (as passed to Deserialize) match.
+func typeIdentityOf(t reflect.Type) typeIdentity {
+	if t == nil {
+		return typeIdentity{name: "nil"}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if identity, ok := typeIdentities.Load(t); ok {
+		return identity.(typeIdentity)
+	}
+
+	var b strings.Builder
+	describeType(&b, t, map[reflect.Type]bool{})
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(b.String()))
+
+	identity := typeIdentity{name: typeName(t), schema: h.Sum64()}
+	typeIdentities.Store(t, identity)
+	return identity
+}
+
+func typeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// describeType writes a canonical description of t's shape: field names,
+// tags and types for structs, field numbers and kinds for proto messages.
+func describeType(b *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	if msg, ok := reflect.New(t).Interface().(proto.Message); ok {
+		describeMessage(b, msg.ProtoReflect().Descriptor())
+		return
+	}
+	if t.Name() != "" && t.Kind() == reflect.Struct {
+		if seen[t] {
+			b.WriteString(typeName(t))
+			return
+		}
+		seen[t] = true
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		b.WriteString("*")
+		describeType(b, t.Elem(), seen)
+	case reflect.Slice:
+		b.WriteString("[]")
+		describeType(b, t.Elem(), seen)
+	case reflect.Array:
+		b.WriteString("[" + strconv.Itoa(t.Len()) + "]")
+		describeType(b, t.Elem(), seen)
+	case reflect.Map:
+		b.WriteString("map[")
+		describeType(b, t.Key(), seen)
+		b.WriteString("]")
+		describeType(b, t.Elem(), seen)
+	case reflect.Struct:
+		b.WriteString("struct{")
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			b.WriteString(field.Name + " ")
+			describeType(b, field.Type, seen)
+			if field.Tag != "" {
+				b.WriteString(" " + strconv.Quote(string(field.Tag)))
+			}
+			b.WriteString(";")
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString(t.Kind().String())
+	}
+}
+
+// describeMessage writes the fields of a proto message. Nested messages are
+// named rather than expanded, since their own changes are wire compatible.
+func describeMessage(b *strings.Builder, desc protoreflect.MessageDescriptor) {
+	b.WriteString("proto " + string(desc.FullName()) + "{")
+	fields := desc.Fields()
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		fmt.Fprintf(b, "%d %s %s %s", field.Number(), field.Name(), field.Cardinality(), field.Kind())
+		if field.Message() != nil {
+			b.WriteString(" " + string(field.Message().FullName()))
+		}
+		b.WriteString(";")
+	}
+	b.WriteString("}")
+}
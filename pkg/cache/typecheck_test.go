@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// collectMismatches returns the total of the type mismatch counter.
+func collectMismatches(t *testing.T, reader *sdkmetric.ManualReader) int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "cache.serializer.type_mismatches" {
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
+
+func newTestTypeCheckingSerializer(t *testing.T, config *TypeCheckConfig) (*TypeCheckingSerializer, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	if config == nil {
+		config = &TypeCheckConfig{}
+	}
+	config.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	serializer, err := NewTypeCheckingSerializer(NewJSONSerializer(), config)
+	if err != nil {
+		t.Fatalf("NewTypeCheckingSerializer failed: %v", err)
+	}
+	return serializer, reader
+}
+
+func TestTypeCheckingSerializerRoundTrip(t *testing.T) {
+	serializer, reader := newTestTypeCheckingSerializer(t, nil)
+
+	user := &TestUser{ID: "123", Name: "John"}
+	data, err := serializer.Serialize(user)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	// Pointer and value readers match the same writer
+	var byValue TestUser
+	if err := serializer.Deserialize(data, &byValue); err != nil || byValue != *user {
+		t.Errorf("Expected %+v, got %+v (err=%v)", *user, byValue, err)
+	}
+	var byPointer *TestUser
+	if err := serializer.Deserialize(data, &byPointer); err != nil || *byPointer != *user {
+		t.Errorf("Expected %+v, got %+v (err=%v)", *user, byPointer, err)
+	}
+
+	if got := collectMismatches(t, reader); got != 0 {
+		t.Errorf("Expected no mismatches, got %d", got)
+	}
+}
+
+func TestTypeCheckingSerializerTypeMismatch(t *testing.T) {
+	serializer, reader := newTestTypeCheckingSerializer(t, &TypeCheckConfig{DecodeOnSchemaMismatch: true})
+
+	data, _ := serializer.Serialize(TestUser{ID: "123"})
+
+	var other wrapperspb.StringValue
+	if err := serializer.Deserialize(data, &other); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got: %v", err)
+	}
+	if got := collectMismatches(t, reader); got != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", got)
+	}
+}
+
+func TestTypeCheckingSerializerSchemaMismatch(t *testing.T) {
+	// Same package and name, different fields - like two releases of one type
+	data := func() []byte {
+		type versioned struct {
+			ID string
+		}
+		serializer, _ := newTestTypeCheckingSerializer(t, nil)
+		data, _ := serializer.Serialize(versioned{ID: "123"})
+		return data
+	}()
+
+	type versioned struct {
+		ID  string
+		Age int
+	}
+
+	t.Run("Reject", func(t *testing.T) {
+		serializer, reader := newTestTypeCheckingSerializer(t, nil)
+
+		var v versioned
+		if err := serializer.Deserialize(data, &v); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch, got: %v", err)
+		}
+		if got := collectMismatches(t, reader); got != 1 {
+			t.Errorf("Expected 1 mismatch, got %d", got)
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		serializer, reader := newTestTypeCheckingSerializer(t, &TypeCheckConfig{DecodeOnSchemaMismatch: true})
+
+		var v versioned
+		if err := serializer.Deserialize(data, &v); err != nil || v.ID != "123" {
+			t.Errorf("Expected decoded value, got %+v (err=%v)", v, err)
+		}
+		if got := collectMismatches(t, reader); got != 1 {
+			t.Errorf("Expected 1 mismatch, got %d", got)
+		}
+	})
+}
+
+func TestTypeCheckingSerializerLegacyPayload(t *testing.T) {
+	serializer, _ := newTestTypeCheckingSerializer(t, nil)
+
+	var user TestUser
+	if err := serializer.Deserialize([]byte(`{"id":"123"}`), &user); err != nil || user.ID != "123" {
+		t.Errorf("Expected unwrapped payload to decode, got %+v (err=%v)", user, err)
+	}
+}
+
+func TestTypeIdentityProtoSchema(t *testing.T) {
+	a := typeIdentityOf(reflect.TypeOf(wrapperspb.String("")))
+	b := typeIdentityOf(reflect.TypeOf(wrapperspb.Bytes(nil)))
+	if a.name == b.name || a.schema == b.schema {
+		t.Errorf("Expected distinct identities, got %+v and %+v", a, b)
+	}
+}
@@ -33,6 +33,116 @@ type HealthChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// Flusher is an optional interface for caches that buffer writes.
+type Flusher interface {
+	// Flush blocks until all writes accepted so far have been applied,
+	// or until ctx is done.
+	Flush(ctx context.Context) error
+}
+
+// Clearer is an optional interface for caches that can remove every entry
+// at once. Use the Clear function to call it on any cache.
+type Clearer interface {
+	// Clear removes every entry of the cache, e.g. to reset it between tests
+	// or from admin tooling, without re-creating it.
+	Clear(ctx context.Context) error
+}
+
+// BatchDeleter is an optional interface for caches that can remove several
+// keys in a single backend round trip.
+type BatchDeleter interface {
+	// DeleteMulti removes all keys. Keys that are not present are ignored.
+	DeleteMulti(ctx context.Context, keys ...string) error
+}
+
+// MultiGetter is an optional interface for caches that can read several keys
+// in a single backend round trip. Use the GetMulti function to call it on any
+// cache.
+type MultiGetter[T any] interface {
+	// GetMulti reads keys, reporting the outcome of each key in the order
+	// given.
+	GetMulti(ctx context.Context, keys ...string) []KeyResult[T]
+}
+
+// ErrorGetter is an optional interface for caches that can tell a miss apart
+// from a failed read. Use the GetWithError function to call it on any cache.
+type ErrorGetter[T any] interface {
+	// GetWithError retrieves a value from the cache by key. A miss returns
+	// the zero value, false and a nil error; a failed read returns a non-nil
+	// error, as decided by the cache's DegradedPolicy for distributed caches.
+	GetWithError(ctx context.Context, key string) (T, bool, error)
+}
+
+// ExistenceChecker is an optional interface for caches that can tell whether
+// a key is stored without reading and decoding its value. Use the Exists
+// function to call it on any cache.
+type ExistenceChecker interface {
+	// Exists reports whether key is stored and not expired.
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// TTLInspector is an optional interface for caches that can report how long
+// an entry has left. Use the TTL function to call it on any cache.
+type TTLInspector interface {
+	// TTL returns the lifetime left to key (0 for entries without expiry)
+	// and whether it is stored.
+	TTL(ctx context.Context, key string) (time.Duration, bool, error)
+}
+
+// Expirer is an optional interface for caches that can change the lifetime
+// of an entry without rewriting its value. Use the Expire function to call
+// it on any cache.
+type Expirer interface {
+	// Expire sets the lifetime left to key to ttl (0 for no expiry),
+	// returning ErrKeyNotFound when key isn't stored.
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// AbsentSetter is an optional interface for caches that can store a value
+// only when its key isn't stored, atomically. Use the SetIfAbsent function to
+// call it on any cache.
+type AbsentSetter[T any] interface {
+	// SetIfAbsent stores value with ttl unless key is stored and not
+	// expired, reporting whether it was stored.
+	SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error)
+}
+
+// Swapper is an optional interface for caches that can replace a value only
+// while it is still the one the caller read, atomically. Use the
+// CompareAndSwap function to call it on any cache.
+type Swapper[T any] interface {
+	// CompareAndSwap stores new with ttl if key holds old, reporting whether
+	// it was stored.
+	CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error)
+}
+
+// GetDeleter is an optional interface for caches that can read a value and
+// remove it in one step, so only one caller gets it. Use the GetDel function
+// to call it on any cache.
+type GetDeleter[T any] interface {
+	// GetDel removes key and returns the value it held, reporting whether it
+	// was stored and not expired.
+	GetDel(ctx context.Context, key string) (T, bool, error)
+}
+
+// LoadFunc loads a value that is missing from the cache.
+type LoadFunc[T any] func(ctx context.Context) (T, error)
+
+// GetOrSetter is an optional interface for caches with a native read-through
+// path, e.g. one that deduplicates concurrent loads. Use the GetOrSet function
+// to call it on any cache.
+type GetOrSetter[T any] interface {
+	// GetOrSet returns the cached value for key, or calls load and stores its
+	// result with ttl on a miss.
+	GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error)
+}
+
+// wrapper is implemented by decorators so that helpers needing a specific
+// backend (e.g. the Redis client) can reach the wrapped cache.
+type wrapper[T any] interface {
+	unwrap() Cache[T]
+}
+
 // CacheType represents the type of cache implementation to use.
 type CacheType string
 
@@ -43,6 +153,18 @@ const (
 	// TypeDistributed is a distributed cache backend.
 	TypeDistributed CacheType = "distributed"
 
+	// TypeTiered is an in-memory cache in front of a distributed cache backend.
+	TypeTiered CacheType = "tiered"
+
+	// TypeDisk is a cache persisted to a local file, surviving restarts.
+	TypeDisk CacheType = "disk"
+
+	// TypeSQL is a cache stored in a Postgres or MySQL table.
+	TypeSQL CacheType = "sql"
+
+	// TypeDynamoDB is a cache stored in a DynamoDB table.
+	TypeDynamoDB CacheType = "dynamodb"
+
 	// TypeNoOp is a no-op cache that does nothing (useful for testing).
 	TypeNoOp CacheType = "noop"
 )
@@ -57,4 +179,48 @@ const (
 	SerializationJSON SerializationType = "json"
 	// SerializationGob uses Go's gob encoding for serialization.
 	SerializationGob SerializationType = "gob"
+	// SerializationProtoJSON uses protojson for proto messages, storing
+	// human-readable entries at the cost of size and speed.
+	SerializationProtoJSON SerializationType = "protojson"
+)
+
+// CompressionType represents the algorithm compressing serialized values.
+type CompressionType string
+
+const (
+	// CompressionNone stores serialized values as they are.
+	CompressionNone CompressionType = ""
+	// CompressionGzip compresses with gzip: slowest, and smallest for text.
+	CompressionGzip CompressionType = "gzip"
+	// CompressionSnappy compresses with snappy: fastest, with the least reduction.
+	CompressionSnappy CompressionType = "snappy"
+	// CompressionZstd compresses with zstd: a good balance of speed and size.
+	CompressionZstd CompressionType = "zstd"
+)
+
+// Operation identifies a cache operation in hooks, recorders and events.
+type Operation string
+
+const (
+	// OperationGet is a read of a single key.
+	OperationGet Operation = "get"
+	// OperationSet is a write of a single key.
+	OperationSet Operation = "set"
+	// OperationDelete is a removal of a single key.
+	OperationDelete Operation = "delete"
+	// OperationClear is a removal of every entry.
+	OperationClear Operation = "clear"
+	// OperationExpire is a change of the lifetime of a single key.
+	OperationExpire Operation = "expire"
+	// OperationSetIfAbsent is a write of a single key that isn't stored.
+	OperationSetIfAbsent Operation = "set_if_absent"
+	// OperationCompareAndSwap is a write of a single key holding a given value.
+	OperationCompareAndSwap Operation = "compare_and_swap"
+	// OperationGetDel is a read of a single key that removes it.
+	OperationGetDel Operation = "get_del"
+	// OperationEvict is a removal of a single key by the cache itself, e.g.
+	// on expiry.
+	OperationEvict Operation = "evict"
+	// OperationRefresh is a background reload of a single key.
+	OperationRefresh Operation = "refresh"
 )
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VariantConfig holds configuration for a VariantCache.
+type VariantConfig struct {
+	// Serializer encodes values; use the serializer of the backend so both
+	// agree (default: proto.Marshal for proto messages, JSON otherwise)
+	Serializer Serializer
+}
+
+// variantExpirySize is the size of the expiry prefixed to every variant.
+const variantExpirySize = 8
+
+// setVariantScript stores a variant and extends the key's TTL to cover it,
+// keeping the key without expiry while any variant has none.
+//
+// KEYS[1] key, ARGV[1] variant, ARGV[2] value, ARGV[3] TTL in ms (0 for none)
+var setVariantScript = redis.NewScript(`
+local existed = redis.call('EXISTS', KEYS[1]) == 1
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+local ttl = tonumber(ARGV[3])
+if ttl == 0 then
+	redis.call('PERSIST', KEYS[1])
+elseif not existed then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+else
+	local current = redis.call('PTTL', KEYS[1])
+	if current >= 0 and current < ttl then
+		redis.call('PEXPIRE', KEYS[1], ttl)
+	end
+end
+return 1
+`)
+
+// VariantCache stores several variants of one logical entry, such as an
+// entity rendered per locale or currency, under a single Redis hash:
+//
+//	products, err := cache.NewVariantCache(productCache, nil)
+//	err = products.Set(ctx, "product:1", "en-US", page, time.Hour)
+//	page, found, err := products.Get(ctx, "product:1", "sv-SE")
+//	err = products.Delete(ctx, "product:1") // every locale at once
+//
+// Each variant has its own TTL, kept with its value, while the hash expires
+// with its longest-lived variant, so invalidating an entity is one DEL
+// however many variants it has. The cache's key prefix applies to keys.
+type VariantCache[T any] struct {
+	client redis.UniversalClient
+	prefix string
+	codec  valueCodec[T]
+	now    func() time.Time
+}
+
+// NewVariantCache creates a VariantCache on the Redis/Valkey client behind
+// cache. Returns ErrNotDistributed for other caches.
+func NewVariantCache[T any](cache Cache[T], config *VariantConfig) (*VariantCache[T], error) {
+	client, err := redisClientOf(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg VariantConfig
+	if config != nil {
+		cfg = *config
+	}
+	return &VariantCache[T]{
+		client: client,
+		prefix: keyPrefixOf(cache),
+		codec:  newValueCodec[T](cfg.Serializer),
+		now:    time.Now,
+	}, nil
+}
+
+// Get returns the variant of key, and false if it is missing or expired.
+func (v *VariantCache[T]) Get(ctx context.Context, key, variant string) (T, bool, error) {
+	var zero T
+
+	data, err := v.client.HGet(ctx, v.prefix+key, variant).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, closedErr(err)
+	}
+
+	value, live, err := v.decode(data)
+	if err == nil && !live {
+		// Expired variants are removed lazily; a failure only leaves garbage until the key expires
+		_ = v.client.HDel(ctx, v.prefix+key, variant).Err()
+	}
+	return value, live && err == nil, err
+}
+
+// GetAll returns every live variant of key.
+func (v *VariantCache[T]) GetAll(ctx context.Context, key string) (map[string]T, error) {
+	fields, err := v.client.HGetAll(ctx, v.prefix+key).Result()
+	if err != nil {
+		return nil, closedErr(err)
+	}
+
+	values := make(map[string]T, len(fields))
+	var errs []error
+	for variant, data := range fields {
+		value, live, err := v.decode([]byte(data))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if live {
+			values[variant] = value
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+// Set stores the variant of key with ttl (0 for no expiry).
+func (v *VariantCache[T]) Set(ctx context.Context, key, variant string, value T, ttl time.Duration) error {
+	data, err := v.codec.encode(value)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt int64
+	ttlMillis := ttl.Milliseconds()
+	if ttl > 0 {
+		ttlMillis = max(ttlMillis, 1)
+		expiresAt = v.now().Add(ttl).UnixMilli()
+	}
+	field := binary.BigEndian.AppendUint64(make([]byte, 0, variantExpirySize+len(data)), uint64(expiresAt))
+	field = append(field, data...)
+
+	return closedErr(setVariantScript.Run(ctx, v.client, []string{v.prefix + key},
+		variant, field, ttlMillis).Err())
+}
+
+// DeleteVariant removes one variant of key.
+func (v *VariantCache[T]) DeleteVariant(ctx context.Context, key, variant string) error {
+	return closedErr(v.client.HDel(ctx, v.prefix+key, variant).Err())
+}
+
+// Delete removes every variant of key.
+func (v *VariantCache[T]) Delete(ctx context.Context, key string) error {
+	return closedErr(v.client.Del(ctx, v.prefix+key).Err())
+}
+
+// decode returns the value of a stored variant and whether it is still live.
+func (v *VariantCache[T]) decode(data []byte) (T, bool, error) {
+	var zero T
+	if len(data) < variantExpirySize {
+		return zero, false, errors.New("truncated variant")
+	}
+
+	expiresAt := int64(binary.BigEndian.Uint64(data))
+	if expiresAt != 0 && v.now().UnixMilli() >= expiresAt {
+		return zero, false, nil
+	}
+	value, err := v.codec.decode(data[variantExpirySize:])
+	return value, err == nil, err
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewVariantCacheRequiresRedis(t *testing.T) {
+	memory := NewMemory[TestUser](nil)
+	defer memory.Close()
+
+	if _, err := NewVariantCache(memory, nil); !errors.Is(err, ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestVariantDecodeExpiry(t *testing.T) {
+	now := time.Now()
+	v := &VariantCache[TestUser]{codec: newValueCodec[TestUser](nil), now: func() time.Time { return now }}
+
+	field := func(expiresAt int64) []byte {
+		return append(binary.BigEndian.AppendUint64(nil, uint64(expiresAt)), `{"id":"1"}`...)
+	}
+
+	if value, live, err := v.decode(field(now.Add(time.Second).UnixMilli())); !live || err != nil || value.ID != "1" {
+		t.Errorf("Expected a live variant, got %+v, %v, %v", value, live, err)
+	}
+	if _, live, err := v.decode(field(0)); !live || err != nil {
+		t.Errorf("Expected a variant without expiry to be live, got %v, %v", live, err)
+	}
+	if _, live, err := v.decode(field(now.UnixMilli())); live || err != nil {
+		t.Errorf("Expected an expired variant, got %v, %v", live, err)
+	}
+	if _, _, err := v.decode([]byte{1, 2}); err == nil {
+		t.Error("Expected an error for a truncated variant")
+	}
+}
+
+func TestVariantCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	base, err := New[TestUser](&Config{
+		Type:        TypeDistributed,
+		KeyPrefix:   "shop:",
+		Distributed: &DistributedConfig{Addr: addr},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer base.Close()
+
+	variants, err := NewVariantCache(base, nil)
+	if err != nil {
+		t.Fatalf("Failed to create variant cache: %v", err)
+	}
+
+	_ = variants.Set(ctx, "product:1", "en-US", TestUser{Name: "Chair"}, time.Hour)
+	_ = variants.Set(ctx, "product:1", "sv-SE", TestUser{Name: "Stol"}, 50*time.Millisecond)
+
+	if value, found, err := variants.Get(ctx, "product:1", "en-US"); err != nil || !found || value.Name != "Chair" {
+		t.Errorf("Expected Chair, got %+v (found=%v, err=%v)", value, found, err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	if ttl := client.PTTL(ctx, "shop:product:1").Val(); ttl < 59*time.Minute {
+		t.Errorf("Expected the key to live as long as its longest variant, got %v", ttl)
+	}
+
+	// Test variants expire on their own
+	time.Sleep(100 * time.Millisecond)
+	if _, found, _ := variants.Get(ctx, "product:1", "sv-SE"); found {
+		t.Error("Expected sv-SE to have expired")
+	}
+	all, err := variants.GetAll(ctx, "product:1")
+	if err != nil || len(all) != 1 || all["en-US"].Name != "Chair" {
+		t.Errorf("Expected only en-US, got %+v (err=%v)", all, err)
+	}
+
+	// Test a variant without expiry keeps the key
+	_ = variants.Set(ctx, "product:1", "de-DE", TestUser{Name: "Stuhl"}, 0)
+	if ttl := client.PTTL(ctx, "shop:product:1").Val(); ttl != -1 {
+		t.Errorf("Expected no expiry on the key, got %v", ttl)
+	}
+
+	_ = variants.DeleteVariant(ctx, "product:1", "de-DE")
+	if _, found, _ := variants.Get(ctx, "product:1", "de-DE"); found {
+		t.Error("Expected de-DE deleted")
+	}
+	_ = variants.Delete(ctx, "product:1")
+	if n := client.Exists(ctx, "shop:product:1").Val(); n != 0 {
+		t.Error("Expected every variant deleted")
+	}
+}
@@ -0,0 +1,606 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchStore is an optional interface for stores that write many keys in one
+// call. Write-behind caches use it to flush their batches, and Save and
+// Remove key by key otherwise.
+type BatchStore[T any] interface {
+	// SaveMulti writes the values of their keys.
+	SaveMulti(ctx context.Context, values map[string]T) error
+	// RemoveMulti deletes the keys; removing missing keys is not an error.
+	RemoveMulti(ctx context.Context, keys []string) error
+}
+
+// WriteBehindConfig holds configuration for write-behind caches.
+type WriteBehindConfig struct {
+	// FlushInterval is how often pending writes are flushed to the store
+	// (default: 1s)
+	FlushInterval time.Duration
+
+	// BatchSize is the maximum number of keys written to the store at once;
+	// reaching it flushes before FlushInterval (default: 100)
+	BatchSize int
+
+	// MaxPending caps the keys waiting to be flushed; writes of other keys
+	// fail with ErrQueueFull beyond it (default: 10000)
+	MaxPending int
+
+	// MaxAttempts is the number of attempts of a batch, the first one
+	// included (default: 3)
+	MaxAttempts int
+
+	// RetryBackoff is the wait before the first retry of a batch, doubling
+	// with each retry (default: 100ms)
+	RetryBackoff time.Duration
+
+	// WriteTimeout bounds each attempt of a batch; an attempt running out of
+	// it is retried like a failed one (default: 0, no timeout)
+	WriteTimeout time.Duration
+
+	// OnError is called with the keys of a batch that could not be written
+	// once its attempts are exhausted (optional). Writes interrupted by the
+	// context of Flush are kept for the next flush instead.
+	OnError func(keys []string, err error)
+
+	// Journal records each write before it is acknowledged to the caller,
+	// acknowledges it once the store write is done, and replays on startup
+	// the writes left unacknowledged by crashed instances (optional). Writes
+	// reported to OnError stay unacknowledged, so they are replayed too. Its
+	// ClaimIdle must exceed the time writes may stay pending.
+	Journal *WriteJournal
+
+	// Serializer encodes values in the journal (default: proto.Marshal for
+	// proto messages, JSON otherwise)
+	Serializer Serializer
+}
+
+// writeBehindEntry is the pending write of a key: the latest value set, or
+// its removal.
+type writeBehindEntry[T any] struct {
+	value  T
+	remove bool
+	// ids are the journal entries of the writes of the key it replaced and its own
+	ids []string
+}
+
+// WriteBehindCache writes to the wrapped cache synchronously and to a Store
+// in the background, in batches. Writes of a key waiting to be flushed
+// replace each other, so only its latest value reaches the store.
+type WriteBehindCache[T any] struct {
+	next   Cache[T]
+	store  Store[T]
+	config WriteBehindConfig
+	codec  valueCodec[T]
+
+	mu      sync.Mutex
+	pending map[string]writeBehindEntry[T]
+	closed  bool
+
+	flushing chan struct{} // held while a flush runs
+	kick     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+
+	stats struct {
+		enqueued, written, failed, rejected atomic.Uint64
+	}
+}
+
+// NewWriteBehind wraps a cache so that writes are propagated to store in the
+// background, for write-heavy workloads such as tracking where the store
+// can't keep up with a write per call. Set and Delete apply to the cache at
+// once, so reads see them, and queue the key for store; pending keys are
+// written every FlushInterval, or as soon as BatchSize of them are waiting,
+// with SaveMulti and RemoveMulti when store implements BatchStore. Failed
+// batches are retried MaxAttempts times before their keys are reported to
+// OnError and dropped; writes a flush gives up on because its context is done
+// stay pending.
+//
+// Writes are lost if the process stops before they are flushed, unless a
+// Journal is configured: use NewWriteThrough for data that must not be.
+// SetIfAbsent and CompareAndSwap queue the value they stored; Expire and
+// Clear only affect the cache, and GetOrSet caches loaded values without
+// queuing them. Close flushes pending writes before closing the wrapped
+// cache.
+func NewWriteBehind[T any](cache Cache[T], store Store[T], config *WriteBehindConfig) *WriteBehindCache[T] {
+	var cfg WriteBehindConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxPending == 0 {
+		cfg.MaxPending = 10000
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = 100 * time.Millisecond
+	}
+
+	c := &WriteBehindCache[T]{
+		next:     cache,
+		store:    store,
+		config:   cfg,
+		codec:    newValueCodec[T](cfg.Serializer),
+		pending:  make(map[string]writeBehindEntry[T]),
+		flushing: make(chan struct{}, 1),
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+func (c *WriteBehindCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *WriteBehindCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+// GetOrSet caches loaded values without queuing them: they come from the
+// source of truth.
+func (c *WriteBehindCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, load)
+}
+
+func (c *WriteBehindCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *WriteBehindCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *WriteBehindCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+// SetIfAbsent queues the value if the cache stored it. When it can't be
+// queued, it is removed from the cache and the error returned.
+func (c *WriteBehindCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	if err != nil || !stored {
+		return stored, err
+	}
+	if err := c.enqueue(ctx, key, writeBehindEntry[T]{value: value}); err != nil {
+		_ = c.next.Delete(ctx, key)
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwap queues the new value if the cache swapped it, removing it
+// from the cache when it can't be queued, like SetIfAbsent.
+func (c *WriteBehindCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	if err != nil || !swapped {
+		return swapped, err
+	}
+	if err := c.enqueue(ctx, key, writeBehindEntry[T]{value: new}); err != nil {
+		_ = c.next.Delete(ctx, key)
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *WriteBehindCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	if err := c.enqueue(ctx, key, writeBehindEntry[T]{remove: true}); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return GetDel(ctx, c.next, key)
+}
+
+// Set writes value to the cache and queues it for the store once the cache
+// has it, so a failed cache write never reaches the store. A write that
+// can't be queued (ErrQueueFull, ErrWriterClosed) is removed from the cache
+// again, like SetIfAbsent does.
+func (c *WriteBehindCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	if err := c.enqueue(ctx, key, writeBehindEntry[T]{value: value}); err != nil {
+		_ = c.next.Delete(ctx, key)
+		return err
+	}
+	return nil
+}
+
+// Delete queues the removal of key from the store and deletes it from the cache.
+func (c *WriteBehindCache[T]) Delete(ctx context.Context, key string) error {
+	if err := c.enqueue(ctx, key, writeBehindEntry[T]{remove: true}); err != nil {
+		return err
+	}
+	return c.next.Delete(ctx, key)
+}
+
+// DeleteMulti queues the removal of the keys and deletes from the cache those
+// it queued, reporting the others with a *BatchError.
+func (c *WriteBehindCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	errs := make([]error, len(keys))
+	queued := make([]string, 0, len(keys))
+	for i, key := range keys {
+		if errs[i] = c.enqueue(ctx, key, writeBehindEntry[T]{remove: true}); errs[i] == nil {
+			queued = append(queued, key)
+		}
+	}
+	var err error
+	if len(queued) > 0 {
+		err = deleteMulti(ctx, c.next, queued)
+	}
+	return errors.Join(batchError(keys, errs), err)
+}
+
+// Clear only clears the cache: the store keeps its data and pending writes
+// are still flushed.
+func (c *WriteBehindCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+// Flush writes the pending writes to the store and blocks until they are
+// written, or until ctx is done. It returns the errors of batches that
+// failed, whose keys are also reported to OnError.
+func (c *WriteBehindCache[T]) Flush(ctx context.Context) error {
+	return c.flush(ctx)
+}
+
+// QueueStats returns a snapshot of the pending keys and write counters.
+// Written and Failed count keys written to the store; writes of a key that
+// replaced each other while pending are written once.
+func (c *WriteBehindCache[T]) QueueStats() QueueStats {
+	c.mu.Lock()
+	depth := len(c.pending)
+	c.mu.Unlock()
+
+	return QueueStats{
+		Depth:    depth,
+		Capacity: c.config.MaxPending,
+		Enqueued: c.stats.enqueued.Load(),
+		Written:  c.stats.written.Load(),
+		Failed:   c.stats.failed.Load(),
+		Rejected: c.stats.rejected.Load(),
+	}
+}
+
+// Close stops accepting writes, flushes the pending ones and closes the
+// wrapped cache, returning the errors of batches that could not be written.
+func (c *WriteBehindCache[T]) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	close(c.stop)
+	<-c.done
+	return errors.Join(c.flush(context.Background()), c.next.Close())
+}
+
+func (c *WriteBehindCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *WriteBehindCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
+
+// enqueue records the pending write of key in the journal and in memory,
+// replacing any write of key not flushed yet, and wakes the flusher once a
+// batch is full.
+func (c *WriteBehindCache[T]) enqueue(ctx context.Context, key string, entry writeBehindEntry[T]) error {
+	if c.config.Journal != nil {
+		id, err := c.record(ctx, key, entry)
+		if err != nil {
+			return err
+		}
+		entry.ids = []string{id}
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		c.ack(ctx, entry.ids)
+		return ErrWriterClosed
+	}
+	replaced, ok := c.pending[key]
+	if !ok && len(c.pending) >= c.config.MaxPending {
+		c.mu.Unlock()
+		c.stats.rejected.Add(1)
+		c.ack(ctx, entry.ids)
+		return ErrQueueFull
+	}
+	entry.ids = append(replaced.ids, entry.ids...)
+	c.pending[key] = entry
+	full := len(c.pending) >= c.config.BatchSize
+	c.mu.Unlock()
+
+	c.stats.enqueued.Add(1)
+	if full {
+		select {
+		case c.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// run replays the journal, then flushes pending writes every FlushInterval,
+// or when a batch is full, until Close.
+func (c *WriteBehindCache[T]) run() {
+	defer close(c.done)
+
+	if c.config.Journal != nil {
+		c.replay()
+	}
+
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		case <-c.kick:
+		}
+		// Failed batches are reported to OnError
+		_ = c.flush(context.Background())
+	}
+}
+
+// flush writes the writes pending so far in batches of BatchSize. Flushes
+// run one at a time, so later writes of a key reach the store after earlier
+// ones.
+func (c *WriteBehindCache[T]) flush(ctx context.Context) error {
+	select {
+	case c.flushing <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-c.flushing }()
+
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]writeBehindEntry[T])
+	c.mu.Unlock()
+
+	var errs []error
+	batch := make(map[string]writeBehindEntry[T], min(len(pending), c.config.BatchSize))
+	for key, entry := range pending {
+		batch[key] = entry
+		if len(batch) == c.config.BatchSize {
+			errs = append(errs, c.writeBatch(ctx, batch))
+			batch = make(map[string]writeBehindEntry[T], c.config.BatchSize)
+		}
+	}
+	if len(batch) > 0 {
+		errs = append(errs, c.writeBatch(ctx, batch))
+	}
+	return errors.Join(errs...)
+}
+
+// writeBatch writes a batch to the store, retrying the keys that failed with
+// a doubling backoff until MaxAttempts is exhausted or ctx is done. The
+// journal entries of the keys written are acknowledged. Keys left when ctx
+// is done go back to pending; keys that exhausted their attempts are
+// reported to OnError and dropped, their journal entries left for replay.
+func (c *WriteBehindCache[T]) writeBatch(ctx context.Context, batch map[string]writeBehindEntry[T]) error {
+	if err := ctx.Err(); err != nil {
+		c.requeue(batch)
+		return err
+	}
+
+	ids := make(map[string][]string, len(batch))
+	for key, entry := range batch {
+		ids[key] = entry.ids
+	}
+
+	size := len(batch)
+	err := c.write(ctx, batch)
+	wait := c.config.RetryBackoff
+	// An attempt running out of WriteTimeout is retried; only ctx ends the
+	// retries early
+	for n := 1; n < c.config.MaxAttempts && len(batch) > 0 && ctx.Err() == nil && !errors.Is(err, ErrClosed); n++ {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			// Ends the loop
+			timer.Stop()
+			err = errors.Join(err, ctx.Err())
+		case <-timer.C:
+			wait *= 2
+			err = c.write(ctx, batch)
+		}
+	}
+
+	var written []string
+	for key, keyIDs := range ids {
+		if _, failed := batch[key]; !failed {
+			written = append(written, keyIDs...)
+		}
+	}
+	c.ack(context.WithoutCancel(ctx), written)
+
+	c.stats.written.Add(uint64(size - len(batch)))
+	if len(batch) == 0 {
+		return nil
+	}
+	if ctx.Err() != nil {
+		// The flush gave up, not the store: keep the writes for the next one
+		c.requeue(batch)
+		return err
+	}
+	c.stats.failed.Add(uint64(len(batch)))
+	if c.config.OnError != nil {
+		keys := make([]string, 0, len(batch))
+		for key := range batch {
+			keys = append(keys, key)
+		}
+		c.config.OnError(keys, err)
+	}
+	return err
+}
+
+// requeue puts the writes of batch back in pending. A key written again
+// meanwhile keeps its newer write, which takes over the journal entries of
+// the older one.
+func (c *WriteBehindCache[T]) requeue(batch map[string]writeBehindEntry[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range batch {
+		if newer, ok := c.pending[key]; ok {
+			newer.ids = append(entry.ids, newer.ids...)
+			entry = newer
+		}
+		c.pending[key] = entry
+	}
+}
+
+// write makes one attempt at writing batch to the store, removing the keys
+// it wrote from batch.
+func (c *WriteBehindCache[T]) write(ctx context.Context, batch map[string]writeBehindEntry[T]) error {
+	if c.config.WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.WriteTimeout)
+		defer cancel()
+	}
+
+	store, ok := c.store.(BatchStore[T])
+	if !ok {
+		var errs []error
+		for key, entry := range batch {
+			var err error
+			if entry.remove {
+				err = c.store.Remove(ctx, key)
+			} else {
+				err = c.store.Save(ctx, key, entry.value)
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			delete(batch, key)
+		}
+		return errors.Join(errs...)
+	}
+
+	values := make(map[string]T)
+	var removed []string
+	for key, entry := range batch {
+		if entry.remove {
+			removed = append(removed, key)
+		} else {
+			values[key] = entry.value
+		}
+	}
+	var errs []error
+	if len(values) > 0 {
+		if err := store.SaveMulti(ctx, values); err != nil {
+			errs = append(errs, err)
+		} else {
+			for key := range values {
+				delete(batch, key)
+			}
+		}
+	}
+	if len(removed) > 0 {
+		if err := store.RemoveMulti(ctx, removed); err != nil {
+			errs = append(errs, err)
+		} else {
+			for _, key := range removed {
+				delete(batch, key)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// record appends the write of key to the journal and returns its entry ID.
+func (c *WriteBehindCache[T]) record(ctx context.Context, key string, entry writeBehindEntry[T]) (string, error) {
+	if entry.remove {
+		return c.config.Journal.Append(ctx, JournalEntry{Operation: OperationDelete, Key: key})
+	}
+	data, err := c.codec.encode(entry.value)
+	if err != nil {
+		return "", err
+	}
+	return c.config.Journal.Append(ctx, JournalEntry{Operation: OperationSet, Key: key, Value: data})
+}
+
+// ack acknowledges the journal entries of writes that were handled. Entries
+// that fail to be acknowledged are replayed after ClaimIdle: delivery is
+// at-least-once.
+func (c *WriteBehindCache[T]) ack(ctx context.Context, ids []string) {
+	if c.config.Journal == nil || len(ids) == 0 {
+		return
+	}
+	if err := c.config.Journal.Ack(ctx, ids...); err != nil {
+		slog.Default().WarnContext(ctx, "cache: acknowledging journal entries failed", "error", err)
+	}
+}
+
+// replay writes to the store the journal entries left unacknowledged by
+// crashed instances, holding off flushes so the writes of this instance
+// reach the store after them.
+func (c *WriteBehindCache[T]) replay() {
+	c.flushing <- struct{}{}
+	defer func() { <-c.flushing }()
+
+	ctx := context.Background()
+	for {
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+
+		entries, err := c.config.Journal.Read(ctx, int64(c.config.BatchSize), 0)
+		if err != nil {
+			slog.Default().WarnContext(ctx, "cache: reading the write journal failed", "error", err)
+			return
+		}
+		if len(entries) == 0 {
+			return
+		}
+		c.stats.enqueued.Add(uint64(len(entries)))
+
+		// Entries are in append order, so the latest write of a key wins
+		batch := make(map[string]writeBehindEntry[T], len(entries))
+		for _, journaled := range entries {
+			entry := writeBehindEntry[T]{remove: journaled.Operation == OperationDelete}
+			if !entry.remove {
+				if entry.value, err = c.codec.decode(journaled.Value); err != nil {
+					c.stats.failed.Add(1)
+					if c.config.OnError != nil {
+						c.config.OnError([]string{journaled.Key}, err)
+					}
+					c.ack(ctx, []string{journaled.ID})
+					continue
+				}
+			}
+			entry.ids = append(batch[journaled.Key].ids, journaled.ID)
+			batch[journaled.Key] = entry
+		}
+		// Failed batches are reported to OnError
+		_ = c.writeBatch(ctx, batch)
+	}
+}
@@ -0,0 +1,335 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// batchMapStore is a mapStore that also writes batches, counting them.
+type batchMapStore[T any] struct {
+	*mapStore[T]
+	batches atomic.Int32
+}
+
+func (s *batchMapStore[T]) SaveMulti(ctx context.Context, values map[string]T) error {
+	s.batches.Add(1)
+	for key, value := range values {
+		if err := s.Save(ctx, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *batchMapStore[T]) RemoveMulti(ctx context.Context, keys []string) error {
+	s.batches.Add(1)
+	for _, key := range keys {
+		if err := s.Remove(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWriteBehind(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]()
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{FlushInterval: time.Hour})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "1", TestUser{ID: "2"}, time.Minute)
+	_ = cache.Set(ctx, "2", TestUser{ID: "3"}, time.Minute)
+
+	if value, found := cache.Get(ctx, "1"); !found || value.ID != "2" {
+		t.Errorf("Expected the write to be cached at once, got %+v, %v", value, found)
+	}
+	if _, ok := store.get("1"); ok {
+		t.Error("Expected nothing saved before the flush")
+	}
+	if stats := cache.QueueStats(); stats.Depth != 2 || stats.Enqueued != 3 {
+		t.Errorf("Expected 2 pending keys from 3 writes, got %+v", stats)
+	}
+
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if value, ok := store.get("1"); !ok || value.ID != "2" {
+		t.Errorf("Expected the latest value to be saved, got %+v, %v", value, ok)
+	}
+	if stats := cache.QueueStats(); stats.Depth != 0 || stats.Written != 2 {
+		t.Errorf("Expected the 2 keys written, got %+v", stats)
+	}
+
+	_ = cache.Delete(ctx, "1")
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Error("Expected the key to be deleted from the cache at once")
+	}
+	_ = cache.Flush(ctx)
+	if _, ok := store.get("1"); ok {
+		t.Error("Expected the key to be removed from the store")
+	}
+}
+
+func TestWriteBehindFlushesInBackground(t *testing.T) {
+	ctx := context.Background()
+	store := &batchMapStore[TestUser]{mapStore: newMapStore[TestUser]()}
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	waitFor(t, func() bool {
+		_, ok := store.get("1")
+		return ok
+	})
+	if store.batches.Load() == 0 {
+		t.Error("Expected the batch store to be used")
+	}
+}
+
+func TestWriteBehindBatchSize(t *testing.T) {
+	ctx := context.Background()
+	store := &batchMapStore[TestUser]{mapStore: newMapStore[TestUser]()}
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		BatchSize:     2,
+	})
+	defer cache.Close()
+
+	// A full batch is flushed without waiting for the interval
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "2", TestUser{ID: "2"}, time.Minute)
+	waitFor(t, func() bool {
+		_, ok := store.get("2")
+		return ok
+	})
+
+	for _, key := range []string{"3", "4", "5"} {
+		_ = cache.Set(ctx, key, TestUser{ID: key}, time.Minute)
+	}
+	_ = cache.Flush(ctx)
+	if n := store.batches.Load(); n < 3 {
+		t.Errorf("Expected batches of at most 2 keys, got %d batches", n)
+	}
+}
+
+func TestWriteBehindRetries(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]("broken")
+
+	var mu sync.Mutex
+	var failed []string
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		MaxAttempts:   2,
+		RetryBackoff:  time.Millisecond,
+		OnError: func(keys []string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, keys...)
+		},
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	_ = cache.Set(ctx, "broken", TestUser{ID: "x"}, time.Minute)
+
+	if err := cache.Flush(ctx); !errors.Is(err, errStoreDown) {
+		t.Errorf("Expected the store's error, got %v", err)
+	}
+	if _, ok := store.get("1"); !ok {
+		t.Error("Expected the healthy key to be saved")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 1 || failed[0] != "broken" {
+		t.Errorf("Expected broken to be reported, got %v", failed)
+	}
+	if stats := cache.QueueStats(); stats.Written != 1 || stats.Failed != 1 || stats.Depth != 0 {
+		t.Errorf("Expected 1 written and 1 failed key, got %+v", stats)
+	}
+}
+
+// timeoutOnceStore is a mapStore whose first Save hangs until its context
+// is done.
+type timeoutOnceStore[T any] struct {
+	*mapStore[T]
+	saves atomic.Int32
+}
+
+func (s *timeoutOnceStore[T]) Save(ctx context.Context, key string, value T) error {
+	if s.saves.Add(1) == 1 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.mapStore.Save(ctx, key, value)
+}
+
+func TestWriteBehindRetriesTimedOutAttempts(t *testing.T) {
+	ctx := context.Background()
+	store := &timeoutOnceStore[TestUser]{mapStore: newMapStore[TestUser]()}
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		MaxAttempts:   2,
+		RetryBackoff:  time.Millisecond,
+		WriteTimeout:  10 * time.Millisecond,
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Expected the timed out attempt to be retried, got %v", err)
+	}
+	if _, ok := store.get("1"); !ok || store.saves.Load() != 2 {
+		t.Errorf("Expected the key to be saved on the second attempt, got %d saves", store.saves.Load())
+	}
+}
+
+func TestWriteBehindKeepsWritesOfCancelledFlushes(t *testing.T) {
+	ctx := context.Background()
+	store := &timeoutOnceStore[TestUser]{mapStore: newMapStore[TestUser]()}
+	var reported atomic.Int32
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		OnError:       func([]string, error) { reported.Add(1) },
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	flushCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := cache.Flush(flushCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the flush to give up with its context, got %v", err)
+	}
+	if stats := cache.QueueStats(); stats.Depth != 1 || stats.Failed != 0 || reported.Load() != 0 {
+		t.Errorf("Expected the write to stay pending without being reported, got %+v", stats)
+	}
+
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if _, ok := store.get("1"); !ok {
+		t.Error("Expected the kept write to be saved by the next flush")
+	}
+}
+
+func TestWriteBehindSkipsFailedCacheWrites(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory[TestUser](nil)
+	store := newMapStore[TestUser]()
+	cache := NewWriteBehind[TestUser](backend, store, &WriteBehindConfig{FlushInterval: time.Hour})
+	defer cache.Close()
+
+	_ = backend.Close()
+	if err := cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute); err == nil {
+		t.Fatal("Expected the cache write to fail")
+	}
+	if stats := cache.QueueStats(); stats.Depth != 0 {
+		t.Errorf("Expected a failed cache write not to be queued, got %+v", stats)
+	}
+}
+
+func TestWriteBehindMaxPending(t *testing.T) {
+	ctx := context.Background()
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), newMapStore[TestUser](), &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		MaxPending:    1,
+	})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	if err := cache.Set(ctx, "1", TestUser{ID: "2"}, time.Minute); err != nil {
+		t.Errorf("Expected a pending key to be rewritable, got %v", err)
+	}
+	if err := cache.Set(ctx, "2", TestUser{ID: "2"}, time.Minute); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+	if _, found := cache.Get(ctx, "2"); found {
+		t.Error("Expected a rejected write to be removed from the cache")
+	}
+	if stats := cache.QueueStats(); stats.Rejected != 1 {
+		t.Errorf("Expected 1 rejected write, got %+v", stats)
+	}
+}
+
+func TestWriteBehindCloseFlushes(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]()
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{FlushInterval: time.Hour})
+
+	_ = cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, ok := store.get("1"); !ok {
+		t.Error("Expected Close to flush pending writes")
+	}
+	if err := cache.Set(ctx, "2", TestUser{}, time.Minute); !errors.Is(err, ErrWriterClosed) {
+		t.Errorf("Expected ErrWriterClosed after Close, got %v", err)
+	}
+}
+
+func TestWriteBehindJournalWithTestcontainers(t *testing.T) {
+	addr := startValkey(t)
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	// An instance journaled two writes of a key and crashed before flushing them
+	crashed, err := NewWriteJournal(ctx, client, &WriteJournalConfig{Stream: "journal", Consumer: "crashed"})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+	for _, id := range []string{"1", "2"} {
+		data, _ := NewJSONSerializer().Serialize(TestUser{ID: id})
+		if _, err := crashed.Append(ctx, JournalEntry{Operation: OperationSet, Key: "replayed", Value: data}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	journal, err := NewWriteJournal(ctx, client, &WriteJournalConfig{
+		Stream:    "journal",
+		Consumer:  "successor",
+		ClaimIdle: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create journal: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	store := newMapStore[TestUser]()
+	cache := NewWriteBehind[TestUser](NewMemory[TestUser](nil), store, &WriteBehindConfig{
+		FlushInterval: time.Hour,
+		Journal:       journal,
+	})
+	defer cache.Close()
+
+	// The successor replays the latest journaled write on startup
+	waitFor(t, func() bool {
+		value, ok := store.get("replayed")
+		return ok && value.ID == "2"
+	})
+
+	// Writes are journaled until they reach the store
+	if err := cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if pending, _ := journal.Pending(ctx); pending != 1 {
+		t.Errorf("Expected the write to be journaled, got %d pending entries", pending)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if pending, _ := journal.Pending(ctx); pending != 0 {
+		t.Errorf("Expected written entries to be acknowledged, got %d pending", pending)
+	}
+}
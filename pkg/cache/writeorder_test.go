@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStampValue(t *testing.T) {
+	at := time.UnixMicro(1_700_000_000_123_456)
+	stamped := stampValue(at, []byte(`{"id":"1"}`))
+
+	if len(stamped) != timestampedSize+10 {
+		t.Fatalf("Unexpected stamped size %d", len(stamped))
+	}
+	if got := unstampValue(stamped); string(got) != `{"id":"1"}` {
+		t.Errorf("Expected the payload back, got %q", got)
+	}
+	if got := unstampValue([]byte(`{"id":"1"}`)); string(got) != `{"id":"1"}` {
+		t.Errorf("Expected unstamped values as they are, got %q", got)
+	}
+}
+
+func TestWriteTimestampOf(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := writeTimestampOf(WithWriteTimestamp(context.Background(), at)); !got.Equal(at) {
+		t.Errorf("Expected the context timestamp, got %v", got)
+	}
+	if got := writeTimestampOf(context.Background()); time.Since(got) > time.Second {
+		t.Errorf("Expected now without a context timestamp, got %v", got)
+	}
+}
+
+// timestampRecorder records the write timestamps its Sets see.
+type timestampRecorder[T any] struct {
+	Cache[T]
+	stamps []time.Time
+}
+
+func (r *timestampRecorder[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	r.stamps = append(r.stamps, writeTimestampOf(ctx))
+	return r.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestAsyncStampsWritesAtEnqueue(t *testing.T) {
+	ctx := context.Background()
+	recorder := &timestampRecorder[TestUser]{Cache: NewMemory[TestUser](nil)}
+	async := NewAsync[TestUser](recorder, nil)
+	defer async.Close()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = async.Set(WithWriteTimestamp(ctx, at), "a", TestUser{}, time.Minute)
+	before := time.Now()
+	_ = async.Set(ctx, "b", TestUser{}, time.Minute)
+	if err := async.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if len(recorder.stamps) != 2 || !recorder.stamps[0].Equal(at) {
+		t.Fatalf("Expected the caller's timestamp kept, got %v", recorder.stamps)
+	}
+	if recorder.stamps[1].Before(before.Add(-time.Second)) || recorder.stamps[1].After(time.Now()) {
+		t.Errorf("Expected the enqueue time, got %v", recorder.stamps[1])
+	}
+}
+
+func TestRejectOlderWritesWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	cache, err := NewDistributedGeneric[TestUser](&DistributedConfig{Addr: addr, RejectOlderWrites: true})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer cache.Close()
+
+	now := time.Now()
+	if err := cache.Set(WithWriteTimestamp(ctx, now), "user:1", TestUser{Name: "newer"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Test a write decided earlier but applied later is rejected
+	err = cache.Set(WithWriteTimestamp(ctx, now.Add(-time.Second)), "user:1", TestUser{Name: "older"}, time.Minute)
+	if !errors.Is(err, ErrOlderWrite) {
+		t.Errorf("Expected ErrOlderWrite, got: %v", err)
+	}
+	if user, found := cache.Get(ctx, "user:1"); !found || user.Name != "newer" {
+		t.Errorf("Expected the newer value kept, got %+v (found=%v)", user, found)
+	}
+
+	// Test later writes and durable writes still go through
+	if err := cache.Set(ctx, "user:1", TestUser{Name: "latest"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if user, _ := cache.Get(ctx, "user:1"); user.Name != "latest" {
+		t.Errorf("Expected latest, got %+v", user)
+	}
+	err = SetDurable(WithWriteTimestamp(ctx, now), cache, "user:1", TestUser{Name: "stale"}, time.Minute, &DurabilityOptions{Replicas: 0})
+	if !errors.Is(err, ErrOlderWrite) {
+		t.Errorf("Expected durable older write rejected, got: %v", err)
+	}
+}
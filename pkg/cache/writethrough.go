@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Store is the durable store behind a write-through cache, typically an
+// entity repository.
+type Store[T any] interface {
+	// Save writes the value of key.
+	Save(ctx context.Context, key string, value T) error
+	// Remove deletes key; removing a missing key is not an error.
+	Remove(ctx context.Context, key string) error
+}
+
+// writeThroughCache writes to a Store before the cache.
+type writeThroughCache[T any] struct {
+	next  Cache[T]
+	store Store[T]
+}
+
+// NewWriteThrough wraps a cache so that writes are propagated synchronously
+// to store, making the cache the single write path of a repository: Set saves
+// the value and Delete removes the key from store first, and only touch the
+// cache once store succeeded. When store fails, its error is returned and
+// the cache is left unchanged.
+//
+// An error from the cache after store accepted a write is returned too, the
+// key having been removed from the cache (best effort) so it can't serve the
+// previous value. SetIfAbsent and CompareAndSwap decide on the cached value
+// and save the value they stored; a failed save removes it from the cache.
+// Expire and Clear only affect the cache. Combine with NewReadThrough, with
+// a Loader reading from the same store, for reads.
+func NewWriteThrough[T any](cache Cache[T], store Store[T]) Cache[T] {
+	return &writeThroughCache[T]{next: cache, store: store}
+}
+
+// invalidate removes key from the cache after a write that reached the
+// store but failed in the cache, returning err.
+func (c *writeThroughCache[T]) invalidate(ctx context.Context, key string, err error) error {
+	_ = c.next.Delete(ctx, key)
+	return err
+}
+
+// save saves a value the cache stored conditionally, removing it from the
+// cache when store fails.
+func (c *writeThroughCache[T]) save(ctx context.Context, key string, value T) error {
+	if err := c.store.Save(ctx, key, value); err != nil {
+		return c.invalidate(ctx, key, err)
+	}
+	return nil
+}
+
+func (c *writeThroughCache[T]) Get(ctx context.Context, key string) (T, bool) {
+	return c.next.Get(ctx, key)
+}
+
+func (c *writeThroughCache[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	return GetWithError(ctx, c.next, key)
+}
+
+// GetOrSet caches loaded values without saving them: they come from the
+// source of truth.
+func (c *writeThroughCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	return GetOrSet(ctx, c.next, key, ttl, load)
+}
+
+func (c *writeThroughCache[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.next, key)
+}
+
+func (c *writeThroughCache[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return TTL(ctx, c.next, key)
+}
+
+func (c *writeThroughCache[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return Expire(ctx, c.next, key, ttl)
+}
+
+func (c *writeThroughCache[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	stored, err := SetIfAbsent(ctx, c.next, key, value, ttl)
+	if err != nil || !stored {
+		return stored, err
+	}
+	if err := c.save(ctx, key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *writeThroughCache[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	swapped, err := CompareAndSwap(ctx, c.next, key, old, new, ttl)
+	if err != nil || !swapped {
+		return swapped, err
+	}
+	if err := c.save(ctx, key, new); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *writeThroughCache[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	if err := c.store.Remove(ctx, key); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return GetDel(ctx, c.next, key)
+}
+
+func (c *writeThroughCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.store.Save(ctx, key, value); err != nil {
+		return err
+	}
+	if err := c.next.Set(ctx, key, value, ttl); err != nil {
+		return c.invalidate(ctx, key, err)
+	}
+	return nil
+}
+
+func (c *writeThroughCache[T]) Delete(ctx context.Context, key string) error {
+	if err := c.store.Remove(ctx, key); err != nil {
+		return err
+	}
+	return c.next.Delete(ctx, key)
+}
+
+// DeleteMulti removes the keys from the store one by one and deletes from
+// the cache those the store removed, reporting the others with a
+// *BatchError.
+func (c *writeThroughCache[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	errs := make([]error, len(keys))
+	removed := make([]string, 0, len(keys))
+	for i, key := range keys {
+		if errs[i] = c.store.Remove(ctx, key); errs[i] == nil {
+			removed = append(removed, key)
+		}
+	}
+	var err error
+	if len(removed) > 0 {
+		err = deleteMulti(ctx, c.next, removed)
+	}
+	return errors.Join(batchError(keys, errs), err)
+}
+
+// Clear only clears the cache: the store keeps its data.
+func (c *writeThroughCache[T]) Clear(ctx context.Context) error {
+	return Clear(ctx, c.next)
+}
+
+func (c *writeThroughCache[T]) Close() error {
+	return c.next.Close()
+}
+
+func (c *writeThroughCache[T]) unwrap() Cache[T] {
+	return c.next
+}
+
+func (c *writeThroughCache[T]) Ping(ctx context.Context) error {
+	return pingNext(ctx, c.next)
+}
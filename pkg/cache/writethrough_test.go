@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is an in-memory Store failing the keys in failing.
+type mapStore[T any] struct {
+	mu      sync.Mutex
+	values  map[string]T
+	failing map[string]bool
+}
+
+var errStoreDown = errors.New("store down")
+
+func newMapStore[T any](failing ...string) *mapStore[T] {
+	s := &mapStore[T]{values: make(map[string]T), failing: make(map[string]bool)}
+	for _, key := range failing {
+		s.failing[key] = true
+	}
+	return s
+}
+
+func (s *mapStore[T]) Save(_ context.Context, key string, value T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing[key] {
+		return errStoreDown
+	}
+	s.values[key] = value
+	return nil
+}
+
+func (s *mapStore[T]) Remove(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing[key] {
+		return errStoreDown
+	}
+	delete(s.values, key)
+	return nil
+}
+
+func (s *mapStore[T]) get(key string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func TestWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]("broken")
+	cache := NewWriteThrough[TestUser](NewMemory[TestUser](nil), store)
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "1", TestUser{ID: "1"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, ok := store.get("1"); !ok || value.ID != "1" {
+		t.Error("Expected the value to be saved")
+	}
+	if _, found := cache.Get(ctx, "1"); !found {
+		t.Error("Expected the value to be cached")
+	}
+
+	if err := cache.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := store.get("1"); ok {
+		t.Error("Expected the value to be removed from the store")
+	}
+	if _, found := cache.Get(ctx, "1"); found {
+		t.Error("Expected the value to be removed from the cache")
+	}
+
+	// A failed save leaves the cache unchanged
+	if err := cache.Set(ctx, "broken", TestUser{ID: "x"}, time.Minute); !errors.Is(err, errStoreDown) {
+		t.Errorf("Expected the store's error, got %v", err)
+	}
+	if _, found := cache.Get(ctx, "broken"); found {
+		t.Error("Expected nothing cached when the store fails")
+	}
+}
+
+func TestWriteThroughConditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]("broken")
+	cache := NewWriteThrough[TestUser](NewMemory[TestUser](nil), store)
+	defer cache.Close()
+
+	if stored, err := SetIfAbsent(ctx, cache, "1", TestUser{ID: "1"}, time.Minute); !stored || err != nil {
+		t.Fatalf("Expected SetIfAbsent to store, got %v, %v", stored, err)
+	}
+	if stored, _ := SetIfAbsent(ctx, cache, "1", TestUser{ID: "2"}, time.Minute); stored {
+		t.Error("Expected SetIfAbsent not to overwrite")
+	}
+	if value, _ := store.get("1"); value.ID != "1" {
+		t.Errorf("Expected the stored value only to be saved, got %+v", value)
+	}
+
+	stored, err := SetIfAbsent(ctx, cache, "broken", TestUser{ID: "x"}, time.Minute)
+	if stored || !errors.Is(err, errStoreDown) {
+		t.Errorf("Expected the store's error, got %v, %v", stored, err)
+	}
+	if _, found := cache.Get(ctx, "broken"); found {
+		t.Error("Expected the value to be removed from the cache when the save fails")
+	}
+}
+
+func TestWriteThroughDeleteMulti(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore[TestUser]("broken")
+	backend := NewMemory[TestUser](nil)
+	cache := NewWriteThrough[TestUser](backend, store)
+	defer cache.Close()
+
+	_ = backend.Set(ctx, "1", TestUser{}, time.Minute)
+	_ = backend.Set(ctx, "broken", TestUser{}, time.Minute)
+
+	err := deleteMulti(ctx, cache, []string{"1", "broken"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 1 || batchErr.Failed[0].Key != "broken" {
+		t.Fatalf("Expected broken to fail, got %v", err)
+	}
+	if _, found := backend.Get(ctx, "1"); found {
+		t.Error("Expected the removed key to be deleted from the cache")
+	}
+	if _, found := backend.Get(ctx, "broken"); !found {
+		t.Error("Expected the key the store kept to stay cached")
+	}
+}
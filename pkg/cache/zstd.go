@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressed payloads start with a one-byte header identifying how the rest
+// of the payload is encoded, so the format can evolve without breaking readers.
+const (
+	compressedHeaderRaw  byte = 'r'
+	compressedHeaderZstd byte = 'z'
+)
+
+// ZstdConfig holds configuration for a zstd-compressing serializer.
+type ZstdConfig struct {
+	// Dictionary is a zstd dictionary, e.g. from TrainZstdDictionary (optional).
+	// Writers and readers must use the same dictionary. Dictionaries cut the
+	// stored size of small, similar payloads (such as JSON documents) significantly.
+	Dictionary []byte
+
+	// Level is the zstd compression level, 1 (fastest) to 22 (best) (default: 3)
+	Level int
+
+	// MinCompressionRatio is the minimum original/compressed size ratio worth
+	// storing compressed, e.g. 1.2 requires at least a 1/6 size reduction.
+	// Payloads that compress worse are stored uncompressed (default: 1, any reduction)
+	MinCompressionRatio float64
+
+	// MinSize is the payload size in bytes below which compression is not
+	// attempted at all (default: 0, always compress)
+	MinSize int
+}
+
+// ZstdSerializer compresses the output of another serializer with zstd.
+type ZstdSerializer struct {
+	inner    Serializer
+	encoder  *zstd.Encoder
+	decoder  *zstd.Decoder
+	minRatio float64
+	minSize  int
+}
+
+// NewZstdSerializer creates a serializer that compresses the output of inner with zstd.
+func NewZstdSerializer(inner Serializer, config *ZstdConfig) (*ZstdSerializer, error) {
+	if inner == nil {
+		return nil, errors.New("inner serializer cannot be nil")
+	}
+
+	var cfg ZstdConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Level == 0 {
+		cfg.Level = 3
+	}
+	if cfg.MinCompressionRatio == 0 {
+		cfg.MinCompressionRatio = 1
+	}
+
+	encoderOptions := []zstd.EOption{
+		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(cfg.Level)),
+	}
+	decoderOptions := []zstd.DOption{
+		zstd.WithDecoderConcurrency(0),
+	}
+	if len(cfg.Dictionary) > 0 {
+		encoderOptions = append(encoderOptions, zstd.WithEncoderDict(cfg.Dictionary))
+		decoderOptions = append(decoderOptions, zstd.WithDecoderDicts(cfg.Dictionary))
+	}
+
+	encoder, err := zstd.NewWriter(nil, encoderOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd configuration: %w", err)
+	}
+	decoder, err := zstd.NewReader(nil, decoderOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd configuration: %w", err)
+	}
+
+	return &ZstdSerializer{
+		inner:    inner,
+		encoder:  encoder,
+		decoder:  decoder,
+		minRatio: cfg.MinCompressionRatio,
+		minSize:  cfg.MinSize,
+	}, nil
+}
+
+// Serialize converts a value to bytes with the inner serializer and compresses them.
+// Payloads smaller than MinSize, or that don't reach MinCompressionRatio, are
+// stored uncompressed behind a header marking them as raw.
+func (z *ZstdSerializer) Serialize(v interface{}) ([]byte, error) {
+	data, err := z.inner.Serialize(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) >= z.minSize {
+		out := make([]byte, 1, len(data)/2+1)
+		out[0] = compressedHeaderZstd
+		out = z.encoder.EncodeAll(data, out)
+		if float64(len(data)) >= z.minRatio*float64(len(out)-1) {
+			return out, nil
+		}
+	}
+
+	out := make([]byte, 1+len(data))
+	out[0] = compressedHeaderRaw
+	copy(out[1:], data)
+	return out, nil
+}
+
+// Deserialize decompresses bytes and converts them back to a value with the inner serializer.
+func (z *ZstdSerializer) Deserialize(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return errors.New("data is empty")
+	}
+
+	switch data[0] {
+	case compressedHeaderRaw:
+		return z.inner.Deserialize(data[1:], v)
+	case compressedHeaderZstd:
+		raw, err := z.decoder.DecodeAll(data[1:], nil)
+		if err != nil {
+			return err
+		}
+		return z.inner.Deserialize(raw, v)
+	default:
+		return errors.New("data is not zstd compressed")
+	}
+}
+
+// TrainZstdDictionary builds a zstd dictionary of at most maxSize bytes from
+// sample payloads, e.g. serialized values captured from production. A few
+// hundred representative samples usually suffice; 16-64KiB is a good size.
+func TrainZstdDictionary(samples [][]byte, maxSize int) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples provided")
+	}
+	if maxSize <= 0 {
+		maxSize = 64 << 10
+	}
+
+	return dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: maxSize,
+		HashBytes:   6,
+	})
+}
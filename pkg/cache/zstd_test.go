@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestZstdSerializer(t *testing.T) {
+	serializer, err := NewZstdSerializer(&JSONSerializer{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+
+	user := TestUser{ID: "123", Name: strings.Repeat("John ", 100)}
+
+	data, err := serializer.Serialize(user)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if data[0] != compressedHeaderZstd {
+		t.Errorf("Expected zstd header, got %q", data[0])
+	}
+
+	var retrieved TestUser
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved != user {
+		t.Errorf("Expected %+v, got %+v", user, retrieved)
+	}
+
+	// Test uncompressed input is rejected
+	if err := serializer.Deserialize([]byte(`{"id":"123"}`), &retrieved); err == nil {
+		t.Error("Expected error for uncompressed input")
+	}
+}
+
+func TestZstdSerializerWithDictionary(t *testing.T) {
+	samples := make([][]byte, 0, 500)
+	for i := 0; i < 500; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(
+			`{"id":"user-%d","name":"Customer number %d","email":"customer%d@example.com","active":true}`, i, i, i)))
+	}
+
+	dictionary, err := TrainZstdDictionary(samples, 4<<10)
+	if err != nil {
+		t.Fatalf("TrainZstdDictionary failed: %v", err)
+	}
+
+	plain, err := NewZstdSerializer(&JSONSerializer{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+	withDict, err := NewZstdSerializer(&JSONSerializer{}, &ZstdConfig{Dictionary: dictionary})
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+
+	user := TestUser{ID: "user-1234", Name: "Customer number 1234"}
+	plainData, _ := plain.Serialize(user)
+	dictData, err := withDict.Serialize(user)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if len(dictData) >= len(plainData) {
+		t.Errorf("Expected dictionary to shrink payload, got %d bytes vs %d without", len(dictData), len(plainData))
+	}
+
+	var retrieved TestUser
+	if err := withDict.Deserialize(dictData, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved != user {
+		t.Errorf("Expected %+v, got %+v", user, retrieved)
+	}
+
+	// Test readers without the dictionary can't decode
+	if err := plain.Deserialize(dictData, &retrieved); err == nil {
+		t.Error("Expected error when decoding without the dictionary")
+	}
+}
+
+func TestZstdSerializerBailOut(t *testing.T) {
+	user := TestUser{ID: "123", Name: "John"}
+
+	// Test tiny payloads are stored raw
+	serializer, err := NewZstdSerializer(&JSONSerializer{}, &ZstdConfig{MinSize: 1024})
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+	data, err := serializer.Serialize(user)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if data[0] != compressedHeaderRaw {
+		t.Errorf("Expected raw header for payload below MinSize, got %q", data[0])
+	}
+	var retrieved TestUser
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved != user {
+		t.Errorf("Expected %+v, got %+v", user, retrieved)
+	}
+
+	// Test payloads that don't compress well enough are stored raw
+	serializer, err = NewZstdSerializer(&JSONSerializer{}, &ZstdConfig{MinCompressionRatio: 10})
+	if err != nil {
+		t.Fatalf("Failed to create serializer: %v", err)
+	}
+	data, _ = serializer.Serialize(user)
+	if data[0] != compressedHeaderRaw {
+		t.Errorf("Expected raw header below MinCompressionRatio, got %q", data[0])
+	}
+
+	// Test compressible payloads are still compressed
+	large := TestUser{ID: "123", Name: strings.Repeat("John ", 200)}
+	data, _ = serializer.Serialize(large)
+	if data[0] != compressedHeaderZstd {
+		t.Errorf("Expected zstd header for compressible payload, got %q", data[0])
+	}
+	if err := serializer.Deserialize(data, &retrieved); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if retrieved != large {
+		t.Error("Expected large payload to round-trip")
+	}
+}
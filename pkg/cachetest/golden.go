@@ -0,0 +1,128 @@
+// Package cachetest provides test helpers for code storing values in caches,
+// such as golden files guarding the serialized form of cached types against
+// silent wire-format changes.
+package cachetest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// UpdateEnv is the environment variable that, set to "1", makes the golden
+// helpers write the current output instead of comparing against it:
+//
+//	CACHETEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "CACHETEST_UPDATE_GOLDEN"
+
+// Serializer is the method set of cache.Serializer, so any of the cache
+// package's serializers can be checked.
+type Serializer interface {
+	Serialize(v interface{}) ([]byte, error)
+	Deserialize(data []byte, v interface{}) error
+}
+
+// AssertGolden fails t unless serializer encodes value into exactly the bytes
+// of the golden file at path, and the golden file decodes back into value.
+// Commit golden files next to the tests: a failure after upgrading the cache
+// package, a serializer or a cached type means entries written by the
+// previous release no longer match, which is what turns a deploy into a cache
+// wipe. Missing golden files fail the test unless UpdateEnv is set.
+//
+// Use AssertGoldenDecodes for encodings that aren't byte-for-byte
+// deterministic, such as gob or protobuf values containing maps, protojson,
+// or compressed output.
+func AssertGolden(t testing.TB, serializer Serializer, value any, path string) {
+	t.Helper()
+
+	data, err := serializer.Serialize(value)
+	if err != nil {
+		t.Fatalf("cachetest: serializing %T: %v", value, err)
+		return
+	}
+
+	golden, ok := readGolden(t, path, data)
+	if !ok {
+		return
+	}
+	if !bytes.Equal(data, golden) {
+		t.Errorf("cachetest: serialized %T differs from %s\n got: %s\nwant: %s",
+			value, path, hex.EncodeToString(data), hex.EncodeToString(golden))
+	}
+	assertDecodes(t, serializer, golden, value, path)
+}
+
+// AssertGoldenDecodes fails t unless the golden file at path decodes into a
+// value equal to want, checking that entries written in the golden format
+// stay readable without requiring byte-identical output. A missing golden
+// file fails the test unless UpdateEnv is set.
+func AssertGoldenDecodes(t testing.TB, serializer Serializer, want any, path string) {
+	t.Helper()
+
+	data, err := serializer.Serialize(want)
+	if err != nil {
+		t.Fatalf("cachetest: serializing %T: %v", want, err)
+		return
+	}
+
+	golden, ok := readGolden(t, path, data)
+	if !ok {
+		return
+	}
+	assertDecodes(t, serializer, golden, want, path)
+}
+
+// readGolden returns the golden file at path, writing current to it first
+// when updating.
+func readGolden(t testing.TB, path string, current []byte) ([]byte, bool) {
+	t.Helper()
+
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cachetest: %v", err)
+			return nil, false
+		}
+		if err := os.WriteFile(path, current, 0o644); err != nil {
+			t.Fatalf("cachetest: %v", err)
+			return nil, false
+		}
+		t.Logf("cachetest: updated %s", path)
+	}
+
+	golden, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("cachetest: golden file %s is missing; run the test with %s=1 to create it", path, UpdateEnv)
+		return nil, false
+	}
+	if err != nil {
+		t.Fatalf("cachetest: %v", err)
+		return nil, false
+	}
+	return golden, true
+}
+
+// assertDecodes fails t unless golden decodes into a value equal to want.
+func assertDecodes(t testing.TB, serializer Serializer, golden []byte, want any, path string) {
+	t.Helper()
+
+	target := reflect.New(reflect.TypeOf(want))
+	if err := serializer.Deserialize(golden, target.Interface()); err != nil {
+		t.Errorf("cachetest: %s no longer decodes into %T: %v", path, want, err)
+		return
+	}
+
+	got := target.Elem().Interface()
+	if wantMsg, ok := want.(proto.Message); ok {
+		if gotMsg, ok := got.(proto.Message); ok && proto.Equal(gotMsg, wantMsg) {
+			return
+		}
+	} else if reflect.DeepEqual(got, want) {
+		return
+	}
+	t.Errorf("cachetest: %s decodes into %+v, want %+v", path, got, want)
+}
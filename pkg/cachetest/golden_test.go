@@ -0,0 +1,98 @@
+package cachetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordingTB records failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Logf(string, ...any) {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Serialize(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonSerializer) Deserialize(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden", "user.json")
+	value := user{ID: "1", Name: "Ada"}
+
+	// Test a missing golden file fails until it is created
+	rec := &recordingTB{TB: t}
+	AssertGolden(rec, jsonSerializer{}, value, path)
+	if len(rec.failures) != 1 {
+		t.Fatalf("Expected a failure for a missing golden file, got %v", rec.failures)
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	rec = &recordingTB{TB: t}
+	AssertGolden(rec, jsonSerializer{}, value, path)
+	if len(rec.failures) != 0 {
+		t.Fatalf("Expected the golden file to be written, got %v", rec.failures)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"id":"1","name":"Ada"}` {
+		t.Errorf("Unexpected golden file: %s", data)
+	}
+	t.Setenv(UpdateEnv, "")
+
+	rec = &recordingTB{TB: t}
+	AssertGolden(rec, jsonSerializer{}, value, path)
+	if len(rec.failures) != 0 {
+		t.Errorf("Expected matching output to pass, got %v", rec.failures)
+	}
+
+	// Test changed output fails, both on bytes and decoding
+	rec = &recordingTB{TB: t}
+	AssertGolden(rec, jsonSerializer{}, user{ID: "2", Name: "Ada"}, path)
+	if len(rec.failures) != 2 {
+		t.Errorf("Expected byte and decode failures, got %v", rec.failures)
+	}
+}
+
+func TestAssertGoldenDecodes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(path, []byte(`{ "name": "Ada", "id": "1" }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test differently formatted but equivalent output passes
+	rec := &recordingTB{TB: t}
+	AssertGoldenDecodes(rec, jsonSerializer{}, user{ID: "1", Name: "Ada"}, path)
+	if len(rec.failures) != 0 {
+		t.Errorf("Expected equivalent golden file to pass, got %v", rec.failures)
+	}
+
+	// Test a golden file that no longer decodes fails
+	if err := os.WriteFile(path, []byte(`{"id":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec = &recordingTB{TB: t}
+	AssertGoldenDecodes(rec, jsonSerializer{}, user{ID: "1", Name: "Ada"}, path)
+	if len(rec.failures) != 1 {
+		t.Errorf("Expected a decode failure, got %v", rec.failures)
+	}
+}
@@ -0,0 +1,202 @@
+// Package pagedcache caches paginated list responses, such as gRPC List RPCs,
+// page by page under standardized keys, and invalidates every cached page of
+// a query or of all queries at once.
+package pagedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+// instrumentationName identifies this package to OpenTelemetry.
+const instrumentationName = "github.com/dentech-floss/cache/pkg/pagedcache"
+
+// firstPage stands in for the empty token of a query's first page.
+const firstPage = "first"
+
+// pageTokenField is the page token field of standard list requests.
+const pageTokenField = "page_token"
+
+// maxTokenLength is the length above which page tokens are hashed in keys.
+const maxTokenLength = 64
+
+// Config holds configuration for a paged cache.
+type Config struct {
+	// Prefix namespaces the pages of one list endpoint, e.g. "orders:list" (required)
+	Prefix string
+
+	// TagPrefix is prepended to the tags grouping pages (default: "cache:tag:")
+	TagPrefix string
+
+	// MeterProvider receives the "cache.pages.requests" counter
+	// (default: the global OpenTelemetry meter provider)
+	MeterProvider metric.MeterProvider
+}
+
+// Cache caches the pages of a list endpoint. Pages are stored under keys of
+// the form "prefix:query-hash:page-token" and tagged by query and by prefix.
+type Cache[T any] struct {
+	cache    cache.Cache[T]
+	tags     *cache.TagIndex[T]
+	prefix   string
+	requests metric.Int64Counter
+}
+
+// New creates a paged cache on top of c, which must be backed by Redis/Valkey
+// for tag-based invalidation.
+func New[T any](c cache.Cache[T], config *Config) (*Cache[T], error) {
+	if config == nil || config.Prefix == "" {
+		return nil, errors.New("pagedcache prefix is required")
+	}
+
+	tags, err := cache.NewTagIndex(c, &cache.TagIndexConfig{Prefix: config.TagPrefix})
+	if err != nil {
+		return nil, err
+	}
+
+	provider := config.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	// Instrument creation only fails on invalid names; fall back to a no-op
+	requests, _ := provider.Meter(instrumentationName).Int64Counter("cache.pages.requests",
+		metric.WithDescription("Number of page reads, by page position and outcome"))
+
+	return &Cache[T]{
+		cache:    c,
+		tags:     tags,
+		prefix:   config.Prefix,
+		requests: requests,
+	}, nil
+}
+
+// GetPage returns the cached page of query at pageToken, an empty token being
+// the first page.
+func (p *Cache[T]) GetPage(ctx context.Context, query any, pageToken string) (T, bool, error) {
+	key, err := p.Key(query, pageToken)
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+
+	page, found, err := cache.GetWithError(ctx, p.cache, key)
+	p.record(ctx, pageToken, found, err)
+	return page, found, err
+}
+
+// SetPage caches the page of query at pageToken, tagging it so it can be
+// invalidated with its query or prefix.
+func (p *Cache[T]) SetPage(ctx context.Context, query any, pageToken string, page T, ttl time.Duration) error {
+	hash, err := QueryHash(query)
+	if err != nil {
+		return err
+	}
+	return p.tags.SetWithTags(ctx, p.key(hash, pageToken), page, ttl, p.queryTag(hash), p.prefixTag())
+}
+
+// InvalidateQuery removes every cached page of query.
+func (p *Cache[T]) InvalidateQuery(ctx context.Context, query any) error {
+	hash, err := QueryHash(query)
+	if err != nil {
+		return err
+	}
+	return p.tags.InvalidateTag(ctx, p.queryTag(hash))
+}
+
+// InvalidateAll removes every cached page of every query under the prefix,
+// e.g. after a write that can change any listing.
+func (p *Cache[T]) InvalidateAll(ctx context.Context) error {
+	return p.tags.InvalidateTag(ctx, p.prefixTag())
+}
+
+// Key returns the key the page of query at pageToken is cached under.
+func (p *Cache[T]) Key(query any, pageToken string) (string, error) {
+	hash, err := QueryHash(query)
+	if err != nil {
+		return "", err
+	}
+	return p.key(hash, pageToken), nil
+}
+
+func (p *Cache[T]) key(hash, pageToken string) string {
+	switch {
+	case pageToken == "":
+		pageToken = firstPage
+	case len(pageToken) > maxTokenLength:
+		sum := sha256.Sum256([]byte(pageToken))
+		pageToken = hex.EncodeToString(sum[:16])
+	}
+	return p.prefix + ":" + hash + ":" + pageToken
+}
+
+func (p *Cache[T]) queryTag(hash string) string {
+	return p.prefix + ":" + hash
+}
+
+func (p *Cache[T]) prefixTag() string {
+	return p.prefix
+}
+
+func (p *Cache[T]) record(ctx context.Context, pageToken string, found bool, err error) {
+	if p.requests == nil {
+		return
+	}
+	position := "next"
+	if pageToken == "" {
+		position = firstPage
+	}
+	outcome := "miss"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case found:
+		outcome = "hit"
+	}
+	p.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("cache.prefix", p.prefix),
+		attribute.String("page", position),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// QueryHash returns a stable hash identifying a query, the request minus its
+// page token. Proto messages are hashed by their deterministic encoding,
+// ignoring a "page_token" field as in AIP-158 list requests; other values are
+// hashed by their JSON encoding, so clear their page token before hashing.
+func QueryHash(query any) (string, error) {
+	var data []byte
+	var err error
+	if msg, ok := query.(proto.Message); ok {
+		data, err = proto.MarshalOptions{Deterministic: true}.Marshal(withoutPageToken(msg))
+	} else {
+		data, err = json.Marshal(query)
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// withoutPageToken returns msg, or a copy of it without its page token.
+func withoutPageToken(msg proto.Message) proto.Message {
+	field := msg.ProtoReflect().Descriptor().Fields().ByName(pageTokenField)
+	if field == nil || !msg.ProtoReflect().Has(field) {
+		return msg
+	}
+	clone := proto.Clone(msg)
+	clone.ProtoReflect().Clear(field)
+	return clone
+}
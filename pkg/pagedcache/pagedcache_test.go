@@ -0,0 +1,196 @@
+package pagedcache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+type listOrders struct {
+	Customer string `json:"customer"`
+	Status   string `json:"status"`
+}
+
+// newListRequest builds a dynamic list request message with filter and page_token fields.
+func newListRequest(t *testing.T, filter, pageToken string) *dynamicpb.Message {
+	t.Helper()
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    strPtr("list.proto"),
+		Package: strPtr("test"),
+		Syntax:  strPtr("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: strPtr("ListRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				stringField("filter", 1),
+				stringField("page_token", 2),
+			},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build descriptor: %v", err)
+	}
+
+	desc := file.Messages().Get(0)
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("filter"), protoreflect.ValueOfString(filter))
+	msg.Set(desc.Fields().ByName("page_token"), protoreflect.ValueOfString(pageToken))
+	return msg
+}
+
+func TestQueryHash(t *testing.T) {
+	a, err := QueryHash(listOrders{Customer: "c1", Status: "open"})
+	if err != nil {
+		t.Fatalf("QueryHash failed: %v", err)
+	}
+	b, _ := QueryHash(listOrders{Customer: "c1", Status: "open"})
+	c, _ := QueryHash(listOrders{Customer: "c1", Status: "closed"})
+	if a != b {
+		t.Errorf("Expected equal queries to hash equally, got %s and %s", a, b)
+	}
+	if a == c {
+		t.Error("Expected different queries to hash differently")
+	}
+
+	// Test the page token of proto list requests is ignored
+	first, err := QueryHash(newListRequest(t, "open", ""))
+	if err != nil {
+		t.Fatalf("QueryHash failed: %v", err)
+	}
+	second, _ := QueryHash(newListRequest(t, "open", "token-2"))
+	other, _ := QueryHash(newListRequest(t, "closed", ""))
+	if first != second {
+		t.Errorf("Expected pages of one query to share a hash, got %s and %s", first, second)
+	}
+	if first == other {
+		t.Error("Expected different filters to hash differently")
+	}
+}
+
+func TestKey(t *testing.T) {
+	p := &Cache[string]{prefix: "orders:list"}
+	hash, _ := QueryHash(listOrders{Customer: "c1"})
+
+	if key, _ := p.Key(listOrders{Customer: "c1"}, ""); key != "orders:list:"+hash+":first" {
+		t.Errorf("Unexpected first page key: %s", key)
+	}
+	if key, _ := p.Key(listOrders{Customer: "c1"}, "abc"); key != "orders:list:"+hash+":abc" {
+		t.Errorf("Unexpected page key: %s", key)
+	}
+
+	// Test long page tokens are hashed
+	key, _ := p.Key(listOrders{Customer: "c1"}, strings.Repeat("t", 500))
+	if len(key) > len("orders:list:"+hash+":")+maxTokenLength {
+		t.Errorf("Expected long token to be hashed, got %s", key)
+	}
+}
+
+func TestNewRequiresDistributedCache(t *testing.T) {
+	memory := cache.NewMemory[string](nil)
+	defer memory.Close()
+
+	if _, err := New(memory, nil); err == nil {
+		t.Error("Expected error without a prefix")
+	}
+	if _, err := New(memory, &Config{Prefix: "orders"}); !errors.Is(err, cache.ErrNotDistributed) {
+		t.Errorf("Expected ErrNotDistributed, got: %v", err)
+	}
+}
+
+func TestPagedCacheWithTestcontainers(t *testing.T) {
+	ctx := context.Background()
+	addr := startValkey(t)
+
+	c, err := cache.NewDistributedGeneric[[]string](&cache.DistributedConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	defer c.Close()
+
+	pages, err := New(c, &Config{Prefix: "orders:list"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	open := listOrders{Status: "open"}
+	closed := listOrders{Status: "closed"}
+	_ = pages.SetPage(ctx, open, "", []string{"o1", "o2"}, time.Minute)
+	_ = pages.SetPage(ctx, open, "page-2", []string{"o3"}, time.Minute)
+	_ = pages.SetPage(ctx, closed, "", []string{"o4"}, time.Minute)
+
+	if page, found, err := pages.GetPage(ctx, open, "page-2"); err != nil || !found || page[0] != "o3" {
+		t.Fatalf("Expected page-2, got %v, %v, %v", page, found, err)
+	}
+
+	// Test invalidating a query removes all of its pages only
+	if err := pages.InvalidateQuery(ctx, open); err != nil {
+		t.Fatalf("InvalidateQuery failed: %v", err)
+	}
+	for _, token := range []string{"", "page-2"} {
+		if _, found, _ := pages.GetPage(ctx, open, token); found {
+			t.Errorf("Expected page %q of the query to be invalidated", token)
+		}
+	}
+	if _, found, _ := pages.GetPage(ctx, closed, ""); !found {
+		t.Error("Expected other queries to stay cached")
+	}
+
+	// Test invalidating the prefix removes every query
+	if err := pages.InvalidateAll(ctx); err != nil {
+		t.Fatalf("InvalidateAll failed: %v", err)
+	}
+	if _, found, _ := pages.GetPage(ctx, closed, ""); found {
+		t.Error("Expected all queries to be invalidated")
+	}
+}
+
+func startValkey(t *testing.T) string {
+	t.Helper()
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "valkey/valkey:7.2-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Valkey container: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = container.Terminate(ctx)
+	})
+
+	endpoint, err := container.Endpoint(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to get container endpoint: %v", err)
+	}
+	return endpoint
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func stringField(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     strPtr(name),
+		JsonName: strPtr(name),
+		Number:   &number,
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+}
@@ -0,0 +1,304 @@
+// Package replay records a sample of cache traffic to a file and replays it
+// against another cache configuration or backend, for capacity tests of new
+// cache topologies with real workloads.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+// Event is one recorded cache operation. Recordings are JSON lines of events.
+type Event struct {
+	// Offset is the time since recording started.
+	Offset time.Duration `json:"offset"`
+
+	// Operation is the operation performed.
+	Operation cache.Operation `json:"op"`
+
+	// Key is the key the operation touched.
+	Key string `json:"key"`
+
+	// Size is the serialized size of the value written or read (writes and
+	// hits only).
+	Size int `json:"size,omitempty"`
+
+	// TTL is the TTL the value was written with (writes) or the new lifetime
+	// (Expire).
+	TTL time.Duration `json:"ttl,omitempty"`
+
+	// Hit reports whether a Get found the key (Get only).
+	Hit bool `json:"hit,omitempty"`
+
+	// Latency is how long the operation took.
+	Latency time.Duration `json:"latency"`
+
+	// Err is set when the operation failed.
+	Err string `json:"err,omitempty"`
+}
+
+// RecorderConfig holds configuration for a Recorder.
+type RecorderConfig struct {
+	// SampleRate is the fraction of keys whose traffic is recorded, from 0 to 1.
+	// Keys are sampled by hash, so all operations on a sampled key are kept
+	// and hit patterns survive sampling (default: 0.01)
+	SampleRate float64
+
+	// Serializer measures value sizes (default: JSON)
+	Serializer cache.Serializer
+}
+
+// Recorder is a cache that records a sample of the operations it forwards.
+type Recorder[T any] struct {
+	next       cache.Cache[T]
+	serializer cache.Serializer
+	threshold  uint64
+	start      time.Time
+
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewRecorder wraps a cache so that a sample of its operations is written to
+// w as JSON lines. Write errors stop the recording and are returned by Close;
+// operations keep being forwarded either way.
+func NewRecorder[T any](next cache.Cache[T], w io.Writer, config *RecorderConfig) *Recorder[T] {
+	var cfg RecorderConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = 0.01
+	}
+	if cfg.Serializer == nil {
+		cfg.Serializer = cache.NewJSONSerializer()
+	}
+
+	threshold := uint64(math.MaxUint64)
+	if cfg.SampleRate < 1 {
+		threshold = uint64(cfg.SampleRate * math.MaxUint64)
+	}
+
+	return &Recorder[T]{
+		next:       next,
+		serializer: cfg.Serializer,
+		threshold:  threshold,
+		start:      time.Now(),
+		encoder:    json.NewEncoder(w),
+	}
+}
+
+func (r *Recorder[T]) Get(ctx context.Context, key string) (T, bool) {
+	value, found, _ := r.GetWithError(ctx, key)
+	return value, found
+}
+
+func (r *Recorder[T]) GetWithError(ctx context.Context, key string) (T, bool, error) {
+	if !r.sampled(key) {
+		return cache.GetWithError(ctx, r.next, key)
+	}
+
+	start := time.Now()
+	value, found, err := cache.GetWithError(ctx, r.next, key)
+	event := r.event(cache.OperationGet, key, start, err)
+	event.Hit = found
+	if found {
+		event.Size = r.size(value)
+	}
+	r.record(event)
+	return value, found, err
+}
+
+// Exists forwards to the wrapped cache without recording: replays exercise
+// reads and writes.
+func (r *Recorder[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return cache.Exists(ctx, r.next, key)
+}
+
+func (r *Recorder[T]) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	return cache.TTL(ctx, r.next, key)
+}
+
+func (r *Recorder[T]) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if !r.sampled(key) {
+		return cache.Expire(ctx, r.next, key, ttl)
+	}
+
+	start := time.Now()
+	err := cache.Expire(ctx, r.next, key, ttl)
+	event := r.event(cache.OperationExpire, key, start, err)
+	event.TTL = ttl
+	r.record(event)
+	return err
+}
+
+func (r *Recorder[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if !r.sampled(key) {
+		return r.next.Set(ctx, key, value, ttl)
+	}
+
+	start := time.Now()
+	err := r.next.Set(ctx, key, value, ttl)
+	event := r.event(cache.OperationSet, key, start, err)
+	event.Size = r.size(value)
+	event.TTL = ttl
+	r.record(event)
+	return err
+}
+
+func (r *Recorder[T]) SetIfAbsent(ctx context.Context, key string, value T, ttl time.Duration) (bool, error) {
+	if !r.sampled(key) {
+		return cache.SetIfAbsent(ctx, r.next, key, value, ttl)
+	}
+
+	start := time.Now()
+	stored, err := cache.SetIfAbsent(ctx, r.next, key, value, ttl)
+	event := r.event(cache.OperationSetIfAbsent, key, start, err)
+	event.Size = r.size(value)
+	event.TTL = ttl
+	r.record(event)
+	return stored, err
+}
+
+func (r *Recorder[T]) CompareAndSwap(ctx context.Context, key string, old, new T, ttl time.Duration) (bool, error) {
+	if !r.sampled(key) {
+		return cache.CompareAndSwap(ctx, r.next, key, old, new, ttl)
+	}
+
+	start := time.Now()
+	swapped, err := cache.CompareAndSwap(ctx, r.next, key, old, new, ttl)
+	event := r.event(cache.OperationCompareAndSwap, key, start, err)
+	event.Size = r.size(new)
+	event.TTL = ttl
+	r.record(event)
+	return swapped, err
+}
+
+func (r *Recorder[T]) GetDel(ctx context.Context, key string) (T, bool, error) {
+	if !r.sampled(key) {
+		return cache.GetDel(ctx, r.next, key)
+	}
+
+	start := time.Now()
+	value, found, err := cache.GetDel(ctx, r.next, key)
+	event := r.event(cache.OperationGetDel, key, start, err)
+	event.Hit = found
+	if found {
+		event.Size = r.size(value)
+	}
+	r.record(event)
+	return value, found, err
+}
+
+func (r *Recorder[T]) Delete(ctx context.Context, key string) error {
+	if !r.sampled(key) {
+		return r.next.Delete(ctx, key)
+	}
+
+	start := time.Now()
+	err := r.next.Delete(ctx, key)
+	r.record(r.event(cache.OperationDelete, key, start, err))
+	return err
+}
+
+// DeleteMulti forwards the batch and records a Delete for each sampled key.
+func (r *Recorder[T]) DeleteMulti(ctx context.Context, keys ...string) error {
+	start := time.Now()
+	var err error
+	if bd, ok := r.next.(cache.BatchDeleter); ok {
+		err = bd.DeleteMulti(ctx, keys...)
+	} else {
+		var errs []error
+		for _, key := range keys {
+			errs = append(errs, r.next.Delete(ctx, key))
+		}
+		err = errors.Join(errs...)
+	}
+
+	for _, key := range keys {
+		if r.sampled(key) {
+			r.record(r.event(cache.OperationDelete, key, start, err))
+		}
+	}
+	return err
+}
+
+// Clear clears the wrapped cache. It is not recorded, as replaying it would
+// wipe the cache under test.
+func (r *Recorder[T]) Clear(ctx context.Context) error {
+	return cache.Clear(ctx, r.next)
+}
+
+// Close closes the wrapped cache and reports the first recording error.
+func (r *Recorder[T]) Close() error {
+	err := r.next.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(err, r.err)
+}
+
+func (r *Recorder[T]) Ping(ctx context.Context) error {
+	if hc, ok := r.next.(cache.HealthChecker); ok {
+		return hc.Ping(ctx)
+	}
+	return nil
+}
+
+// sampled reports whether key falls within the sample.
+func (r *Recorder[T]) sampled(key string) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return mix64(h.Sum64()) <= r.threshold
+}
+
+// mix64 spreads FNV's weakly mixed high bits, which keys differing only in
+// their last characters would otherwise share (murmur3's finalizer).
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func (r *Recorder[T]) event(op cache.Operation, key string, start time.Time, err error) Event {
+	event := Event{
+		Offset:    start.Sub(r.start),
+		Operation: op,
+		Key:       key,
+		Latency:   time.Since(start),
+	}
+	if err != nil {
+		event.Err = err.Error()
+	}
+	return event
+}
+
+// size returns the serialized size of value, or 0 if it can't be serialized.
+func (r *Recorder[T]) size(value T) int {
+	data, err := r.serializer.Serialize(value)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+func (r *Recorder[T]) record(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.err = r.encoder.Encode(event)
+	}
+}
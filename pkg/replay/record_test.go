@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+type testUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func decodeEvents(t *testing.T, data []byte) []Event {
+	t.Helper()
+
+	var events []Event
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRecorderRecordsOperations(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	recorder := NewRecorder[testUser](cache.NewMemory[testUser](nil), &buf, &RecorderConfig{SampleRate: 1})
+
+	user := testUser{ID: "123", Name: "John"}
+	_ = recorder.Set(ctx, "key1", user, time.Minute)
+	_, _ = recorder.Get(ctx, "key1")
+	_, _ = recorder.Get(ctx, "missing")
+	_ = cache.Expire(ctx, recorder, "key1", time.Hour)
+	_ = recorder.Delete(ctx, "key1")
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	events := decodeEvents(t, buf.Bytes())
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events, got %d", len(events))
+	}
+
+	size := len(`{"id":"123","name":"John"}`)
+	set, hit, miss, expire, del := events[0], events[1], events[2], events[3], events[4]
+	if set.Operation != cache.OperationSet || set.Size != size || set.TTL != time.Minute {
+		t.Errorf("Unexpected set event: %+v", set)
+	}
+	if hit.Operation != cache.OperationGet || !hit.Hit || hit.Size != size {
+		t.Errorf("Unexpected hit event: %+v", hit)
+	}
+	if miss.Operation != cache.OperationGet || miss.Hit || miss.Key != "missing" {
+		t.Errorf("Unexpected miss event: %+v", miss)
+	}
+	if expire.Operation != cache.OperationExpire || expire.TTL != time.Hour || expire.Err != "" {
+		t.Errorf("Unexpected expire event: %+v", expire)
+	}
+	if del.Operation != cache.OperationDelete || del.Key != "key1" {
+		t.Errorf("Unexpected delete event: %+v", del)
+	}
+}
+
+func TestRecorderSamplesByKey(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	recorder := NewRecorder[testUser](cache.NewMemory[testUser](nil), &buf, &RecorderConfig{SampleRate: 0.1})
+	defer recorder.Close()
+
+	for i := range 1000 {
+		key := "key" + strconv.Itoa(i)
+		_ = recorder.Set(ctx, key, testUser{ID: key}, time.Minute)
+		_, _ = recorder.Get(ctx, key)
+	}
+
+	events := decodeEvents(t, buf.Bytes())
+	if len(events) < 100 || len(events) > 300 {
+		t.Errorf("Expected about 200 events at a 10%% sample, got %d", len(events))
+	}
+
+	// Every sampled key keeps both of its operations
+	perKey := make(map[string]int)
+	for _, event := range events {
+		perKey[event.Key]++
+	}
+	for key, n := range perKey {
+		if n != 2 {
+			t.Errorf("Expected 2 events for %s, got %d", key, n)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+// Config holds configuration for Replay.
+type Config struct {
+	// Speed scales the recorded pace, e.g. 2 replays twice as fast (default: 1)
+	Speed float64
+
+	// Unpaced ignores recorded timings and replays as fast as possible
+	// (default: false)
+	Unpaced bool
+
+	// Concurrency is the number of operations in flight at once. Operations
+	// on the same key are issued in recorded order (default: 16)
+	Concurrency int
+}
+
+// LatencySummary summarizes operation latencies.
+type LatencySummary struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Stats describes how the target handled a replayed workload.
+type Stats struct {
+	// Gets, Sets, Deletes and Expires count replayed operations by type;
+	// SetIfAbsent and CompareAndSwap count as Sets, GetDel as a Get.
+	Gets    int
+	Sets    int
+	Deletes int
+	Expires int
+
+	// Hits counts Gets that found their key.
+	Hits int
+
+	// Errors counts operations that failed.
+	Errors int
+
+	// Duration is the wall time of the replay.
+	Duration time.Duration
+
+	// GetLatency and SetLatency summarize the target's latencies.
+	GetLatency LatencySummary
+	SetLatency LatencySummary
+}
+
+// HitRatio returns the fraction of Gets that hit.
+func (s Stats) HitRatio() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// Replay issues the events recorded in r against target, pacing them as
+// recorded (scaled by Speed). Set writes a zeroed payload of the recorded
+// size, so the target sees the same key space, sizes, TTLs and operation mix
+// as the recorded cache without the original values.
+func Replay(ctx context.Context, r io.Reader, target cache.Cache[[]byte], config *Config) (Stats, error) {
+	var cfg Config
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.Speed <= 0 {
+		cfg.Speed = 1
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 16
+	}
+
+	workers := make([]*worker, cfg.Concurrency)
+	var wg sync.WaitGroup
+	for i := range workers {
+		workers[i] = &worker{events: make(chan Event, 64)}
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			w.run(ctx, target)
+		}(workers[i])
+	}
+
+	start := time.Now()
+	err := dispatch(ctx, r, workers, start, cfg)
+	for _, w := range workers {
+		close(w.events)
+	}
+	wg.Wait()
+
+	stats := Stats{Duration: time.Since(start)}
+	var getLatencies, setLatencies []time.Duration
+	for _, w := range workers {
+		stats.Gets += w.stats.Gets
+		stats.Sets += w.stats.Sets
+		stats.Deletes += w.stats.Deletes
+		stats.Expires += w.stats.Expires
+		stats.Hits += w.stats.Hits
+		stats.Errors += w.stats.Errors
+		getLatencies = append(getLatencies, w.getLatencies...)
+		setLatencies = append(setLatencies, w.setLatencies...)
+	}
+	stats.GetLatency = summarize(getLatencies)
+	stats.SetLatency = summarize(setLatencies)
+	return stats, err
+}
+
+// dispatch reads events and hands each to the worker owning its key once
+// its recorded offset is due.
+func dispatch(ctx context.Context, r io.Reader, workers []*worker, start time.Time, cfg Config) error {
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var event Event
+		if err := decoder.Decode(&event); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("invalid event %d: %w", line, err)
+		}
+
+		if !cfg.Unpaced {
+			due := start.Add(time.Duration(float64(event.Offset) / cfg.Speed))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				}
+			}
+		}
+
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(event.Key))
+		select {
+		case workers[h.Sum32()%uint32(len(workers))].events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// worker issues the events of its share of the keys in order.
+type worker struct {
+	events       chan Event
+	stats        Stats
+	getLatencies []time.Duration
+	setLatencies []time.Duration
+}
+
+func (w *worker) run(ctx context.Context, target cache.Cache[[]byte]) {
+	for event := range w.events {
+		start := time.Now()
+		var err error
+
+		switch event.Operation {
+		case cache.OperationGet:
+			var found bool
+			_, found, err = cache.GetWithError(ctx, target, event.Key)
+			w.stats.Gets++
+			if found {
+				w.stats.Hits++
+			}
+			w.getLatencies = append(w.getLatencies, time.Since(start))
+		case cache.OperationGetDel:
+			var found bool
+			_, found, err = cache.GetDel(ctx, target, event.Key)
+			w.stats.Gets++
+			if found {
+				w.stats.Hits++
+			}
+			w.getLatencies = append(w.getLatencies, time.Since(start))
+		case cache.OperationSet:
+			err = target.Set(ctx, event.Key, make([]byte, event.Size), event.TTL)
+			w.stats.Sets++
+			w.setLatencies = append(w.setLatencies, time.Since(start))
+		case cache.OperationSetIfAbsent:
+			_, err = cache.SetIfAbsent(ctx, target, event.Key, make([]byte, event.Size), event.TTL)
+			w.stats.Sets++
+			w.setLatencies = append(w.setLatencies, time.Since(start))
+		case cache.OperationCompareAndSwap:
+			// The compared value isn't recorded, so the swap is replayed as
+			// the write it makes
+			err = target.Set(ctx, event.Key, make([]byte, event.Size), event.TTL)
+			w.stats.Sets++
+			w.setLatencies = append(w.setLatencies, time.Since(start))
+		case cache.OperationDelete:
+			err = target.Delete(ctx, event.Key)
+			w.stats.Deletes++
+		case cache.OperationExpire:
+			err = cache.Expire(ctx, target, event.Key, event.TTL)
+			if errors.Is(err, cache.ErrKeyNotFound) {
+				// A missing key is a miss, not a failure
+				err = nil
+			}
+			w.stats.Expires++
+		default:
+			continue
+		}
+
+		if err != nil {
+			w.stats.Errors++
+		}
+	}
+}
+
+func summarize(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	slices.Sort(latencies)
+	at := func(q float64) time.Duration {
+		return latencies[int(q*float64(len(latencies)-1))]
+	}
+	return LatencySummary{
+		P50: at(0.50),
+		P90: at(0.90),
+		P99: at(0.99),
+		Max: latencies[len(latencies)-1],
+	}
+}
@@ -0,0 +1,98 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dentech-floss/cache/pkg/cache"
+)
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	// Record a workload against one cache...
+	var buf bytes.Buffer
+	recorder := NewRecorder[testUser](cache.NewMemory[testUser](nil), &buf, &RecorderConfig{SampleRate: 1})
+	_, _ = recorder.Get(ctx, "key1")
+	_ = recorder.Set(ctx, "key1", testUser{ID: "123"}, time.Minute)
+	_, _ = recorder.Get(ctx, "key1")
+	_ = cache.Expire(ctx, recorder, "key1", time.Hour)
+	_, _ = recorder.Get(ctx, "key1")
+	_ = recorder.Close()
+
+	// ...and replay it against another
+	target := cache.NewMemory[[]byte](nil)
+	defer target.Close()
+
+	stats, err := Replay(ctx, &buf, target, &Config{Unpaced: true, Concurrency: 4})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if stats.Gets != 3 || stats.Sets != 1 || stats.Expires != 1 || stats.Hits != 2 || stats.Errors != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Errorf("Expected hit ratio 2/3, got %v", ratio)
+	}
+
+	payload, found := target.Get(ctx, "key1")
+	if !found || len(payload) != len(`{"id":"123","name":""}`) {
+		t.Errorf("Expected payload of the recorded size, got %d bytes (found=%v)", len(payload), found)
+	}
+}
+
+func TestReplayPacing(t *testing.T) {
+	ctx := context.Background()
+	events := `{"offset":0,"op":"get","key":"a","latency":0}
+{"offset":200000000,"op":"get","key":"b","latency":0}
+`
+	target := cache.NewMemory[[]byte](nil)
+	defer target.Close()
+
+	// 200ms recorded at 4x speed
+	stats, err := Replay(ctx, strings.NewReader(events), target, &Config{Speed: 4})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if stats.Duration < 50*time.Millisecond || stats.Duration >= 200*time.Millisecond {
+		t.Errorf("Expected replay to take about 50ms, took %v", stats.Duration)
+	}
+}
+
+func TestReplayInvalidEvent(t *testing.T) {
+	target := cache.NewMemory[[]byte](nil)
+	defer target.Close()
+
+	if _, err := Replay(context.Background(), strings.NewReader("not json"), target, nil); err == nil {
+		t.Error("Expected error for invalid event")
+	}
+}
+
+func TestReplayGetDel(t *testing.T) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	recorder := NewRecorder[testUser](cache.NewMemory[testUser](nil), &buf, &RecorderConfig{SampleRate: 1})
+	_ = recorder.Set(ctx, "token", testUser{ID: "123"}, time.Minute)
+	_, _, _ = cache.GetDel(ctx, recorder, "token")
+	_, _, _ = cache.GetDel(ctx, recorder, "token")
+	_ = recorder.Close()
+
+	// Both GetDel calls are replayed, so only the first finds the token
+	target := cache.NewMemory[[]byte](nil)
+	defer target.Close()
+
+	stats, err := Replay(ctx, &buf, target, &Config{Unpaced: true})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if stats.Gets != 2 || stats.Sets != 1 || stats.Hits != 1 || stats.Errors != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if _, found := target.Get(ctx, "token"); found {
+		t.Error("Expected the token to be consumed")
+	}
+}